@@ -1,44 +1,83 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/gadhittana-01/book-go/config"
 	"github.com/gadhittana-01/book-go/handler"
+	"github.com/gadhittana-01/book-go/metrics"
+	"github.com/gadhittana-01/book-go/middleware"
 	"github.com/gadhittana01/go-modules/utils"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/cors"
 	openApiMiddleware "github.com/go-openapi/runtime/middleware"
+	"github.com/redis/go-redis/v9"
 )
 
 type App interface {
-	Start()
+	Start() error
 }
 
 type AppImpl struct {
-	route        *chi.Mux
-	config       *utils.BaseConfig
-	userHandler  handler.UserHandler
-	orderHandler handler.OrderHandler
-	bookHandler  handler.BookHandler
+	route         *chi.Mux
+	config        *utils.BaseConfig
+	appConfig     *config.AppConfig
+	db            utils.PGXPool
+	redisClient   *redis.Client
+	userHandler   handler.UserHandler
+	orderHandler  handler.OrderHandler
+	bookHandler   handler.BookHandler
+	cartHandler   handler.CartHandler
+	healthHandler handler.HealthHandler
 }
 
 func NewApp(route *chi.Mux,
 	config *utils.BaseConfig,
+	appConfig *config.AppConfig,
+	db utils.PGXPool,
+	redisClient *redis.Client,
 	userHandler handler.UserHandler,
 	orderHandler handler.OrderHandler,
 	bookHandler handler.BookHandler,
+	cartHandler handler.CartHandler,
+	healthHandler handler.HealthHandler,
 ) App {
 	return &AppImpl{
-		route:        route,
-		config:       config,
-		userHandler:  userHandler,
-		orderHandler: orderHandler,
-		bookHandler:  bookHandler,
+		route:         route,
+		config:        config,
+		appConfig:     appConfig,
+		db:            db,
+		redisClient:   redisClient,
+		userHandler:   userHandler,
+		orderHandler:  orderHandler,
+		bookHandler:   bookHandler,
+		cartHandler:   cartHandler,
+		healthHandler: healthHandler,
 	}
 }
 
-func (s *AppImpl) Start() {
+// Start serves the API until SIGINT or SIGTERM, then stops accepting
+// connections, waits up to SERVER_SHUTDOWN_TIMEOUT for in-flight requests and
+// closes the database pool and the Redis client.
+func (s *AppImpl) Start() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	return s.serve(ctx)
+}
+
+func (s *AppImpl) setupRoutes() {
+	s.route.Use(middleware.RequestID)
+	s.route.Use(middleware.AccessLog(slog.New(slog.NewJSONHandler(os.Stdout, nil))))
+	s.route.Use(middleware.Tracing)
+	s.route.Use(middleware.Metrics)
 	s.route.Use(utils.Recovery)
 	s.route.Use(cors.Handler(cors.Options{
 		AllowedOrigins: []string{"*"},
@@ -56,15 +95,99 @@ func (s *AppImpl) Start() {
 	s.userHandler.SetupUserRoutes(s.route)
 	s.orderHandler.SetupOrderRoutes(s.route)
 	s.bookHandler.SetupBookRoutes(s.route)
+	s.cartHandler.SetupCartRoutes(s.route)
+	s.healthHandler.SetupHealthRoutes(s.route)
 
 	s.route.NotFound(func(w http.ResponseWriter, r *http.Request) {
 		utils.GenerateErrorResp[any](w, nil, 404)
 	})
+}
+
+// serve runs the API and the metrics server until ctx is done and shuts both
+// down gracefully. Metrics get their own port so per-route traffic, error
+// rates and cache state are not served to API clients.
+func (s *AppImpl) serve(ctx context.Context) error {
+	s.setupRoutes()
+	defer s.close()
+
+	// the timeouts keep slow or idle clients from holding connections open
+	servers := []*http.Server{
+		{
+			Addr:              fmt.Sprintf(":%d", s.config.ServerPort),
+			Handler:           s.route,
+			ReadTimeout:       s.appConfig.ServerReadTimeout,
+			ReadHeaderTimeout: s.appConfig.ServerReadHeaderTimeout,
+			WriteTimeout:      s.appConfig.ServerWriteTimeout,
+			IdleTimeout:       s.appConfig.ServerIdleTimeout,
+		},
+		{
+			Addr:              fmt.Sprintf(":%d", s.appConfig.MetricsPort),
+			Handler:           metricsRoute(),
+			ReadTimeout:       s.appConfig.ServerReadTimeout,
+			ReadHeaderTimeout: s.appConfig.ServerReadHeaderTimeout,
+			WriteTimeout:      s.appConfig.ServerWriteTimeout,
+			IdleTimeout:       s.appConfig.ServerIdleTimeout,
+		},
+	}
 
-	utils.LogInfo(fmt.Sprintf("server started on port %d", s.config.ServerPort))
-	port := fmt.Sprintf(":%d", s.config.ServerPort)
-	err := http.ListenAndServe(port, s.route)
+	serverErr := make(chan error, len(servers))
+	for _, server := range servers {
+		go func() {
+			utils.LogInfo(fmt.Sprintf("server started on %s", server.Addr))
+			serverErr <- server.ListenAndServe()
+		}()
+	}
+
+	select {
+	case err := <-serverErr:
+		// the other server may be up already and must not outlive this one
+		s.shutdown(servers)
+		return err
+	case <-ctx.Done():
+	}
+
+	utils.LogInfo("shutting down server")
+	err := s.shutdown(servers)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("failed to shut down server: %w", err)
+	}
+
+	// ListenAndServe returns ErrServerClosed as soon as Shutdown starts
+	for range servers {
+		if err := <-serverErr; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// shutdown gives the in-flight requests of every server up to
+// SERVER_SHUTDOWN_TIMEOUT in total to finish.
+func (s *AppImpl) shutdown(servers []*http.Server) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.appConfig.ServerShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, server := range servers {
+		errs = append(errs, server.Shutdown(ctx))
+	}
+
+	return errors.Join(errs...)
+}
+
+func metricsRoute() *chi.Mux {
+	route := chi.NewRouter()
+	route.Handle("/metrics", metrics.Handler())
+
+	return route
+}
+
+// close releases the connections once no request can use them anymore.
+func (s *AppImpl) close() {
+	s.db.Close()
+
+	if err := s.redisClient.Close(); err != nil {
+		utils.LogInfo(fmt.Sprintf("failed to close redis client: %v", err))
 	}
 }
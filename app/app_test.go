@@ -1,51 +1,305 @@
 package app
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis"
+	"github.com/gadhittana-01/book-go/cache"
+	appConfig "github.com/gadhittana-01/book-go/config"
+	querier "github.com/gadhittana-01/book-go/db/repository"
+	mockrepo "github.com/gadhittana-01/book-go/db/repository/mock"
 	"github.com/gadhittana-01/book-go/handler"
+	"github.com/gadhittana-01/book-go/middleware"
+	mockmdw "github.com/gadhittana-01/book-go/middleware/mock"
+	"github.com/gadhittana-01/book-go/service"
 	mocksvc "github.com/gadhittana-01/book-go/service/mock"
 	"github.com/gadhittana01/go-modules/utils"
 	mockutl "github.com/gadhittana01/go-modules/utils/mock"
 	"github.com/go-chi/chi"
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
-func initApp(ctrl *gomock.Controller) App {
+// initApp returns the app together with the database pool mock and the Redis
+// client, so the tests can check both are closed on shutdown.
+func initApp(t *testing.T, ctrl *gomock.Controller) (*AppImpl, *mockrepo.MockPGXPool, *redis.Client) {
 	r := chi.NewRouter()
 	config := &utils.BaseConfig{}
 	utils.LoadBaseConfig("../config", "test", config)
+	appCfg := &appConfig.AppConfig{}
+	appConfig.LoadAppConfig("../config", "test", appCfg)
+
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(redisServer.Close)
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: redisServer.Addr(),
+	})
+	mockDB := mockrepo.NewMockPGXPool(ctrl)
 
 	mockToken := mockutl.NewMockTokenClient(ctrl)
 	authMiddleware := utils.NewAuthMiddleware(config, mockToken)
 	userSvc := mocksvc.NewMockUserSvc(ctrl)
 	orderSvc := mocksvc.NewMockOrderSvc(ctrl)
 	bookSvc := mocksvc.NewMockBookSvc(ctrl)
-	userHandler := handler.NewUserHandler(userSvc)
-	orderHandler := handler.NewOrderHandler(orderSvc, authMiddleware)
-	bookHandler := handler.NewBookHandler(bookSvc, authMiddleware)
+	cartSvc := mocksvc.NewMockCartSvc(ctrl)
+	rateLimitMiddleware := mockmdw.NewMockRateLimitMiddleware(ctrl)
+	rateLimitMiddleware.EXPECT().Limit(gomock.Any(), gomock.Any()).AnyTimes()
+	userHandler := handler.NewUserHandler(userSvc, authMiddleware, rateLimitMiddleware, &appConfig.AppConfig{})
+	roleMiddleware := mockmdw.NewMockRoleMiddleware(ctrl)
+	roleMiddleware.EXPECT().CheckHasRole(gomock.Any(), gomock.Any()).AnyTimes()
+	emailVerificationMiddleware := mockmdw.NewMockEmailVerificationMiddleware(ctrl)
+	emailVerificationMiddleware.EXPECT().CheckIsVerified(gomock.Any()).AnyTimes()
+	orderStatusSvc := mocksvc.NewMockOrderStatusSvc(ctrl)
+	orderHandler := handler.NewOrderHandler(orderSvc, orderStatusSvc, authMiddleware, roleMiddleware, emailVerificationMiddleware, rateLimitMiddleware)
+	bookHandler := handler.NewBookHandler(bookSvc, authMiddleware, roleMiddleware, rateLimitMiddleware)
+	cartHandler := handler.NewCartHandler(cartSvc, authMiddleware, emailVerificationMiddleware, rateLimitMiddleware)
+	healthHandler := handler.NewHealthHandler(mocksvc.NewMockHealthSvc(ctrl))
+
+	return NewApp(r, config, appCfg, mockDB, redisClient, userHandler, orderHandler, bookHandler, cartHandler, healthHandler).(*AppImpl), mockDB, redisClient
+}
 
-	return NewApp(r, config, userHandler, orderHandler, bookHandler)
+// waitForServer polls the health check and the metrics endpoint until both
+// servers answer.
+func waitForServer(t *testing.T, app *AppImpl) {
+	urls := []string{
+		fmt.Sprintf("http://localhost:%d/livez", app.config.ServerPort),
+		fmt.Sprintf("http://localhost:%d/metrics", app.appConfig.MetricsPort),
+	}
+
+	for _, url := range urls {
+		waitForURL(t, url)
+	}
+}
+
+func waitForURL(t *testing.T, url string) {
+	for i := 0; i < 50; i++ {
+		resp, err := http.Get(url)
+		if err == nil {
+			resp.Body.Close()
+			return
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	t.Fatalf("%s did not answer", url)
 }
 
 func TestNewApp(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	app := initApp(ctrl)
+	app, _, _ := initApp(t, ctrl)
 	assert.NotNil(t, app)
-	assert.IsType(t, &AppImpl{}, app)
+	assert.Equal(t, 15*time.Second, app.appConfig.ServerReadTimeout)
 }
 
 func TestStartApp(t *testing.T) {
+	t.Run("shuts down on SIGTERM", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		app, mockDB, redisClient := initApp(t, ctrl)
+		mockDB.EXPECT().Close().Times(1)
+
+		done := make(chan error, 1)
+		go func() {
+			done <- app.Start()
+		}()
+		waitForServer(t, app)
+
+		process, err := os.FindProcess(os.Getpid())
+		assert.NoError(t, err)
+		assert.NoError(t, process.Signal(syscall.SIGTERM))
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("server did not shut down")
+		}
+		assert.ErrorIs(t, redisClient.Ping(context.Background()).Err(), redis.ErrClosed)
+	})
+
+	t.Run("waits for in-flight requests", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		app, mockDB, _ := initApp(t, ctrl)
+		mockDB.EXPECT().Close().Times(1)
+
+		started := make(chan struct{})
+		// routes are added by serve, so the slow endpoint is a middleware
+		app.route.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/slow" {
+					next.ServeHTTP(w, r)
+					return
+				}
+
+				close(started)
+				time.Sleep(200 * time.Millisecond)
+				w.WriteHeader(http.StatusOK)
+			})
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			done <- app.serve(ctx)
+		}()
+		waitForServer(t, app)
+
+		slowResp := make(chan int, 1)
+		go func() {
+			resp, err := http.Get(fmt.Sprintf("http://localhost:%d/slow", app.config.ServerPort))
+			if err != nil {
+				slowResp <- 0
+				return
+			}
+			resp.Body.Close()
+			slowResp <- resp.StatusCode
+		}()
+
+		<-started
+		cancel()
+
+		assert.Equal(t, http.StatusOK, <-slowResp)
+		assert.NoError(t, <-done)
+	})
+
+	t.Run("port already in use", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		app, mockDB, _ := initApp(t, ctrl)
+		mockDB.EXPECT().Close().Times(1)
+
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", app.config.ServerPort))
+		assert.NoError(t, err)
+		defer listener.Close()
+
+		assert.Error(t, app.serve(context.Background()))
+	})
+
+	t.Run("serves metrics only on the metrics port", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		app, mockDB, _ := initApp(t, ctrl)
+		mockDB.EXPECT().Close().Times(1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			done <- app.serve(ctx)
+		}()
+		waitForServer(t, app)
+
+		resp, err := http.Get(fmt.Sprintf("http://localhost:%d/metrics", app.appConfig.MetricsPort))
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp, err = http.Get(fmt.Sprintf("http://localhost:%d/metrics", app.config.ServerPort))
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		cancel()
+		assert.NoError(t, <-done)
+	})
+
+	t.Run("metrics port already in use", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		app, mockDB, _ := initApp(t, ctrl)
+		mockDB.EXPECT().Close().Times(1)
+
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", app.appConfig.MetricsPort))
+		assert.NoError(t, err)
+		defer listener.Close()
+
+		assert.Error(t, app.serve(context.Background()))
+
+		// the API server must not be left running
+		_, err = http.Get(fmt.Sprintf("http://localhost:%d/livez", app.config.ServerPort))
+		assert.Error(t, err)
+	})
+}
+
+// TestRedisOutage runs book requests through the real middleware chain while
+// Redis is down. The revocation check, the rate limit and the cache all have
+// to give way for a read, so it is served from the database, while a write is
+// rejected because a revoked token could not be told apart.
+func TestRedisOutage(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
+	app, _, _ := initApp(t, ctrl)
+
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:       redisServer.Addr(),
+		MaxRetries: -1,
+	})
+	redisServer.Close()
+
+	bookID := uuid.New()
+	mockRepo := mockrepo.NewMockRepository(ctrl)
+	mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(querier.Book{
+		ID:    bookID,
+		Title: "Hello",
+		Price: decimal.NewFromInt(10),
+		Stock: 5,
+	}, nil).Times(1)
+
+	mockToken := mockutl.NewMockTokenClient(ctrl)
+	mockToken.EXPECT().ValidateToken("dmytoken").Return(utils.GenerateTokenReq{
+		UserID: uuid.New().String(),
+	}, nil).Times(2)
+
+	cacheSvc := cache.NewResilientCacheSvc(app.config, app.appConfig, redisClient)
+	app.bookHandler = handler.NewBookHandler(
+		service.NewBookSvc(mockRepo, app.config, cacheSvc),
+		middleware.NewAuthMiddleware(app.config, mockToken, service.NewTokenRevocationSvc(redisClient, app.config)),
+		middleware.NewRoleMiddleware(mockRepo),
+		middleware.NewRateLimitMiddleware(service.NewRateLimitSvc(redisClient), app.appConfig),
+	)
+	app.setupRoutes()
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/v1/book/%s", bookID), nil)
+	req.Header.Set("Authorization", "Bearer dmytoken")
+	resp := httptest.NewRecorder()
+
+	assert.NotPanics(t, func() {
+		app.route.ServeHTTP(resp, req)
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"title":"Hello"`)
 
-	app := initApp(ctrl)
+	req = httptest.NewRequest("POST", "/v1/book", strings.NewReader(`{"title":"Hello"}`))
+	req.Header.Set("Authorization", "Bearer dmytoken")
+	resp = httptest.NewRecorder()
 
-	// START SERVER ON BACKGROUND
-	go app.Start()
-	time.Sleep(500 * time.Millisecond)
+	app.route.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
 }
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// breaker is a circuit breaker for Redis. After failureThreshold errors in a
+// row it opens and lets no call through for openDuration. Then a single probe
+// call decides whether it closes again or stays open for another round.
+type breaker struct {
+	mu               sync.Mutex
+	failureThreshold int
+	openDuration     time.Duration
+	now              func() time.Time
+
+	failures  int
+	openUntil time.Time
+	probing   bool
+	// onChange is called with the new state when the breaker opens or closes.
+	onChange func(open bool)
+}
+
+func newBreaker(failureThreshold int, openDuration time.Duration, onChange func(open bool)) *breaker {
+	return &breaker{
+		failureThreshold: max(failureThreshold, 1),
+		openDuration:     openDuration,
+		now:              time.Now,
+		onChange:         onChange,
+	}
+}
+
+// allow reports whether a call may go to Redis. Every allowed call has to be
+// followed by record.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.failureThreshold {
+		return true
+	}
+
+	if b.probing || b.now().Before(b.openUntil) {
+		return false
+	}
+
+	b.probing = true
+	return true
+}
+
+func (b *breaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasOpen := b.failures >= b.failureThreshold
+	b.probing = false
+
+	if success {
+		b.failures = 0
+		if wasOpen {
+			b.onChange(false)
+		}
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.failureThreshold {
+		b.openUntil = b.now().Add(b.openDuration)
+		if !wasOpen {
+			b.onChange(true)
+		}
+	}
+}
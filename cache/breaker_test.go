@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	var changes []bool
+	b := newBreaker(3, 10*time.Second, func(open bool) {
+		changes = append(changes, open)
+	})
+	b.now = func() time.Time { return now }
+
+	t.Run("stays closed below the threshold", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			assert.True(t, b.allow())
+			b.record(false)
+		}
+		assert.True(t, b.allow())
+		b.record(true)
+
+		for i := 0; i < 2; i++ {
+			assert.True(t, b.allow())
+			b.record(false)
+		}
+		assert.True(t, b.allow())
+		assert.Empty(t, changes)
+	})
+
+	t.Run("opens at the threshold", func(t *testing.T) {
+		b.record(false)
+
+		assert.False(t, b.allow())
+		assert.Equal(t, []bool{true}, changes)
+	})
+
+	t.Run("lets a single probe through after the open duration", func(t *testing.T) {
+		now = now.Add(10 * time.Second)
+
+		assert.True(t, b.allow())
+		assert.False(t, b.allow())
+		b.record(false)
+
+		assert.False(t, b.allow())
+		assert.Equal(t, []bool{true}, changes)
+	})
+
+	t.Run("closes after a successful probe", func(t *testing.T) {
+		now = now.Add(10 * time.Second)
+
+		assert.True(t, b.allow())
+		b.record(true)
+
+		assert.True(t, b.allow())
+		assert.Equal(t, []bool{true, false}, changes)
+	})
+}
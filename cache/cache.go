@@ -0,0 +1,208 @@
+// Package cache keeps cached reads working while Redis is unavailable.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gadhittana-01/book-go/config"
+	"github.com/gadhittana-01/book-go/metrics"
+	"github.com/gadhittana01/go-modules/utils"
+)
+
+// ResilientCacheSvc wraps the Redis backed utils.CacheSvc, which reports
+// Redis errors by panicking. It recovers them, so a failed lookup is a miss
+// and the caller loads the data itself, and logs them instead of failing the
+// request. Repeated errors open a circuit breaker that skips Redis for a
+// while, so requests do not each wait for Redis to time out.
+//
+// Values are also kept in a small in-process LRU, which answers reads while
+// Redis cannot. It is not read while Redis works, because other instances
+// only invalidate their own copy.
+type ResilientCacheSvc struct {
+	redis        utils.CacheSvc
+	local        *lru
+	breaker      *breaker
+	flushTimeout time.Duration
+
+	mu sync.Mutex
+	// pending holds the invalidations Redis missed, by prefix and user. They
+	// are replayed before Redis is read again, or it would serve data that
+	// changed during the outage. Keeping one entry per prefix and user rather
+	// than each call bounds it by the caches and users touched, however long
+	// the outage lasts, and a pending clear of a whole prefix absorbs the
+	// entries of its users.
+	pending map[pendingInvalidation]struct{}
+}
+
+// pendingInvalidation is a missed ClearCaches for one user, or a missed
+// clear of the whole prefix when userID is empty.
+type pendingInvalidation struct {
+	prefix string
+	userID string
+}
+
+func NewResilientCacheSvc(
+	config *utils.BaseConfig,
+	appConfig *config.AppConfig,
+	client utils.RedisClient,
+) utils.CacheSvc {
+	return newResilientCacheSvc(utils.NewCacheSvc(config, client), appConfig)
+}
+
+func newResilientCacheSvc(cacheSvc utils.CacheSvc, appConfig *config.AppConfig) *ResilientCacheSvc {
+	return &ResilientCacheSvc{
+		redis:        cacheSvc,
+		local:        newLRU(appConfig.LocalCacheSize, appConfig.LocalCacheDuration),
+		breaker:      newBreaker(appConfig.CacheBreakerFailureThreshold, appConfig.CacheBreakerOpenDuration, onBreakerChange),
+		flushTimeout: appConfig.CacheFlushTimeout,
+		pending:      map[pendingInvalidation]struct{}{},
+	}
+}
+
+func (c *ResilientCacheSvc) Get(key string) (string, bool) {
+	var value string
+	var ok bool
+	if c.call(func(cache utils.CacheSvc) { value, ok = cache.Get(key) }) {
+		if ok {
+			c.local.set(key, value)
+		} else {
+			// expired or invalidated in Redis, so the local copy is stale
+			c.local.remove(key)
+		}
+
+		return value, ok
+	}
+
+	return c.local.get(key)
+}
+
+func (c *ResilientCacheSvc) Set(key string, value string) {
+	c.local.set(key, value)
+	c.call(func(cache utils.CacheSvc) { cache.Set(key, value) })
+}
+
+func (c *ResilientCacheSvc) ClearCaches(prefixes []string, userID string) {
+	c.local.removePrefixes(prefixes)
+	c.invalidate(prefixes, userID, func(cache utils.CacheSvc) { cache.ClearCaches(prefixes, userID) })
+}
+
+func (c *ResilientCacheSvc) DelByPrefix(ctx context.Context, prefix string) {
+	c.local.removePrefixes([]string{prefix})
+	c.invalidate([]string{prefix}, "", func(cache utils.CacheSvc) { cache.DelByPrefix(ctx, prefix) })
+}
+
+// invalidate runs fn against Redis, or remembers the prefixes of userID when
+// it cannot, so the same invalidation is replayed later.
+func (c *ResilientCacheSvc) invalidate(prefixes []string, userID string, fn func(utils.CacheSvc)) {
+	if c.call(fn) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, prefix := range prefixes {
+		if _, ok := c.pending[pendingInvalidation{prefix: prefix}]; ok {
+			continue
+		}
+
+		if userID == "" {
+			for pending := range c.pending {
+				if pending.prefix == prefix {
+					delete(c.pending, pending)
+				}
+			}
+		}
+
+		c.pending[pendingInvalidation{prefix: prefix, userID: userID}] = struct{}{}
+	}
+}
+
+// call runs fn against Redis unless the breaker is open, and reports whether
+// it succeeded.
+func (c *ResilientCacheSvc) call(fn func(utils.CacheSvc)) bool {
+	if !c.breaker.allow() {
+		return false
+	}
+
+	err := c.flushPending()
+	if err == nil {
+		err = c.try(fn)
+	}
+
+	c.breaker.record(err == nil)
+	if err != nil {
+		utils.LogInfo(fmt.Sprintf("cache unavailable, falling back: %v", err))
+		return false
+	}
+
+	return true
+}
+
+// flushPending replays the missed invalidations within flushTimeout, so a
+// slow Redis fails the call that probes it instead of stalling it. What is
+// left when the time runs out stays pending for the next call.
+func (c *ResilientCacheSvc) flushPending() error {
+	c.mu.Lock()
+	pending := make([]pendingInvalidation, 0, len(c.pending))
+	for invalidation := range c.pending {
+		pending = append(pending, invalidation)
+	}
+	c.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.flushTimeout)
+	defer cancel()
+
+	for _, invalidation := range pending {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("replay missed invalidations: %w", err)
+		}
+
+		err := c.try(func(cache utils.CacheSvc) {
+			if invalidation.userID == "" {
+				cache.DelByPrefix(ctx, invalidation.prefix)
+				return
+			}
+
+			cache.ClearCaches([]string{invalidation.prefix}, invalidation.userID)
+		})
+		if err != nil {
+			return err
+		}
+
+		c.mu.Lock()
+		delete(c.pending, invalidation)
+		c.mu.Unlock()
+	}
+
+	return nil
+}
+
+func (c *ResilientCacheSvc) try(fn func(utils.CacheSvc)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	fn(c.redis)
+	return nil
+}
+
+func onBreakerChange(open bool) {
+	metrics.SetCacheCircuitOpen(open)
+
+	if open {
+		utils.LogInfo("cache circuit opened, skipping redis")
+		return
+	}
+
+	utils.LogInfo("cache circuit closed, using redis again")
+}
@@ -0,0 +1,249 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gadhittana-01/book-go/config"
+	"github.com/gadhittana01/go-modules/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCacheSvc stands in for the Redis backed CacheSvc, which panics when
+// Redis fails.
+type fakeCacheSvc struct {
+	values    map[string]string
+	down      bool
+	calls     int
+	deadlines []bool
+}
+
+func newFakeCacheSvc() *fakeCacheSvc {
+	return &fakeCacheSvc{values: map[string]string{}}
+}
+
+func (c *fakeCacheSvc) check() {
+	c.calls++
+	if c.down {
+		panic("dial tcp: connection refused")
+	}
+}
+
+func (c *fakeCacheSvc) Get(key string) (string, bool) {
+	c.check()
+	value, ok := c.values[key]
+	return value, ok
+}
+
+func (c *fakeCacheSvc) Set(key string, value string) {
+	c.check()
+	c.values[key] = value
+}
+
+func (c *fakeCacheSvc) ClearCaches(prefixes []string, userID string) {
+	c.check()
+	for key := range c.values {
+		for _, prefix := range prefixes {
+			if userID != "" {
+				prefix = prefix + ":" + userID
+			}
+
+			if strings.HasPrefix(key, prefix) {
+				delete(c.values, key)
+			}
+		}
+	}
+}
+
+func (c *fakeCacheSvc) DelByPrefix(ctx context.Context, prefix string) {
+	_, ok := ctx.Deadline()
+	c.deadlines = append(c.deadlines, ok)
+	c.ClearCaches([]string{prefix}, "")
+}
+
+func initResilientCacheSvc() (*ResilientCacheSvc, *fakeCacheSvc, *time.Time) {
+	redis := newFakeCacheSvc()
+	cacheSvc := newResilientCacheSvc(redis, &config.AppConfig{
+		CacheBreakerFailureThreshold: 2,
+		CacheBreakerOpenDuration:     30 * time.Second,
+		LocalCacheSize:               10,
+		LocalCacheDuration:           time.Minute,
+		CacheFlushTimeout:            time.Second,
+	})
+
+	now := time.Now()
+	cacheSvc.breaker.now = func() time.Time { return now }
+	cacheSvc.local.now = func() time.Time { return now }
+
+	return cacheSvc, redis, &now
+}
+
+func TestResilientCacheSvc(t *testing.T) {
+	cacheSvc, redis, now := initResilientCacheSvc()
+
+	t.Run("reads and writes go to redis", func(t *testing.T) {
+		cacheSvc.Set("book:1", "a")
+		assert.Equal(t, "a", redis.values["book:1"])
+
+		value, ok := cacheSvc.Get("book:1")
+		assert.True(t, ok)
+		assert.Equal(t, "a", value)
+	})
+
+	t.Run("a redis miss drops the local copy", func(t *testing.T) {
+		delete(redis.values, "book:1")
+
+		_, ok := cacheSvc.Get("book:1")
+		assert.False(t, ok)
+		_, ok = cacheSvc.local.get("book:1")
+		assert.False(t, ok)
+	})
+
+	t.Run("redis errors fall back to the local copy", func(t *testing.T) {
+		cacheSvc.Set("book:2", "b")
+		redis.down = true
+
+		var value string
+		var ok bool
+		assert.NotPanics(t, func() {
+			value, ok = cacheSvc.Get("book:2")
+		})
+		assert.True(t, ok)
+		assert.Equal(t, "b", value)
+
+		assert.NotPanics(t, func() {
+			_, ok = cacheSvc.Get("book:3")
+		})
+		assert.False(t, ok)
+	})
+
+	t.Run("the open breaker skips redis", func(t *testing.T) {
+		calls := redis.calls
+
+		cacheSvc.Set("book:4", "d")
+		cacheSvc.ClearCaches([]string{"order"}, "user-1")
+		value, ok := cacheSvc.Get("book:4")
+
+		assert.Equal(t, calls, redis.calls)
+		assert.True(t, ok)
+		assert.Equal(t, "d", value)
+	})
+
+	t.Run("a failed probe keeps the breaker open", func(t *testing.T) {
+		*now = now.Add(31 * time.Second)
+		calls := redis.calls
+
+		cacheSvc.Get("book:4")
+		cacheSvc.Get("book:4")
+		assert.Equal(t, calls+1, redis.calls)
+	})
+
+	t.Run("missed invalidations run once redis is back", func(t *testing.T) {
+		redis.values["order:user-1:GetOrder"] = "stale"
+		redis.values["order:user-2:GetOrder"] = "fresh"
+		redis.down = false
+		*now = now.Add(31 * time.Second)
+
+		_, ok := cacheSvc.Get("order:user-1:GetOrder")
+		assert.False(t, ok)
+		assert.NotContains(t, redis.values, "order:user-1:GetOrder")
+		assert.Empty(t, cacheSvc.pending)
+
+		// the replay is scoped to the user of the missed call
+		value, ok := cacheSvc.Get("order:user-2:GetOrder")
+		assert.True(t, ok)
+		assert.Equal(t, "fresh", value)
+
+		cacheSvc.Set("book:5", "e")
+		assert.Equal(t, "e", redis.values["book:5"])
+	})
+}
+
+func TestResilientCacheSvcInvalidation(t *testing.T) {
+	cacheSvc, redis, _ := initResilientCacheSvc()
+
+	cacheSvc.Set("book:1", "a")
+	cacheSvc.Set("order:user-1:GetOrder", "b")
+	redis.down = true
+
+	cacheSvc.DelByPrefix(context.Background(), "book")
+	_, ok := cacheSvc.Get("book:1")
+	assert.False(t, ok)
+
+	value, ok := cacheSvc.Get("order:user-1:GetOrder")
+	assert.True(t, ok)
+	assert.Equal(t, "b", value)
+	assert.Len(t, cacheSvc.pending, 1)
+
+	// a long outage keeps one entry per prefix and user, not one per call,
+	// and the pending clear of the whole book prefix covers its users
+	for i := 0; i < 100; i++ {
+		cacheSvc.ClearCaches([]string{"order", "book"}, "user-1")
+	}
+	cacheSvc.ClearCaches([]string{"order"}, "user-2")
+	assert.Equal(t, map[pendingInvalidation]struct{}{
+		{prefix: "book"}:                    {},
+		{prefix: "order", userID: "user-1"}: {},
+		{prefix: "order", userID: "user-2"}: {},
+	}, cacheSvc.pending)
+
+	cacheSvc.ClearCaches([]string{"order"}, "")
+	assert.Equal(t, map[pendingInvalidation]struct{}{
+		{prefix: "book"}:  {},
+		{prefix: "order"}: {},
+	}, cacheSvc.pending)
+}
+
+func TestResilientCacheSvcFlushPending(t *testing.T) {
+	t.Run("replays a whole prefix with a deadline", func(t *testing.T) {
+		cacheSvc, redis, _ := initResilientCacheSvc()
+		redis.values["book:1"] = "stale"
+		cacheSvc.pending[pendingInvalidation{prefix: "book"}] = struct{}{}
+
+		_, ok := cacheSvc.Get("book:1")
+		assert.False(t, ok)
+		assert.Empty(t, cacheSvc.pending)
+		assert.Equal(t, []bool{true}, redis.deadlines)
+	})
+
+	t.Run("running out of time keeps the rest pending", func(t *testing.T) {
+		cacheSvc, redis, _ := initResilientCacheSvc()
+		cacheSvc.flushTimeout = 0
+		redis.values["book:1"] = "stale"
+		cacheSvc.local.set("book:1", "local")
+		cacheSvc.pending[pendingInvalidation{prefix: "book"}] = struct{}{}
+		calls := redis.calls
+
+		value, ok := cacheSvc.Get("book:1")
+		assert.True(t, ok)
+		assert.Equal(t, "local", value)
+		assert.Equal(t, calls, redis.calls)
+		assert.Len(t, cacheSvc.pending, 1)
+	})
+}
+
+func TestResilientCacheSvcGetOrSetData(t *testing.T) {
+	cacheSvc, redis, _ := initResilientCacheSvc()
+	redis.down = true
+
+	loads := 0
+	load := func() (string, error) {
+		loads++
+		return "from database", nil
+	}
+
+	for i := 0; i < 3; i++ {
+		var resp string
+		var err error
+		assert.NotPanics(t, func() {
+			resp, err = utils.GetOrSetData[string](cacheSvc, "book:1", load)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "from database", resp)
+	}
+
+	// the first load fills the local tier, which serves the other reads
+	assert.Equal(t, 1, loads)
+}
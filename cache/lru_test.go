@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	now := time.Now()
+	cache := newLRU(2, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	t.Run("evicts the least recently used entry", func(t *testing.T) {
+		cache.set("a", "1")
+		cache.set("b", "2")
+		cache.get("a")
+		cache.set("c", "3")
+
+		_, ok := cache.get("b")
+		assert.False(t, ok)
+
+		value, ok := cache.get("a")
+		assert.True(t, ok)
+		assert.Equal(t, "1", value)
+	})
+
+	t.Run("updates an existing entry", func(t *testing.T) {
+		cache.set("a", "4")
+
+		value, ok := cache.get("a")
+		assert.True(t, ok)
+		assert.Equal(t, "4", value)
+		assert.Equal(t, 2, cache.order.Len())
+	})
+
+	t.Run("entries expire", func(t *testing.T) {
+		now = now.Add(time.Minute)
+
+		_, ok := cache.get("a")
+		assert.False(t, ok)
+		assert.NotContains(t, cache.entries, "a")
+	})
+
+	t.Run("removes by prefix", func(t *testing.T) {
+		cache.set("book:1", "1")
+		cache.set("order:1", "2")
+		cache.removePrefixes([]string{"book"})
+
+		_, ok := cache.get("book:1")
+		assert.False(t, ok)
+		_, ok = cache.get("order:1")
+		assert.True(t, ok)
+	})
+
+	t.Run("a size of 0 disables it", func(t *testing.T) {
+		disabled := newLRU(0, time.Minute)
+		disabled.set("a", "1")
+
+		_, ok := disabled.get("a")
+		assert.False(t, ok)
+	})
+}
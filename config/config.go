@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// AppConfig holds the settings owned by this service. Shared settings such as
+// the database, Redis and token configuration live in utils.BaseConfig.
+type AppConfig struct {
+	AdminName     string `mapstructure:"ADMIN_NAME"`
+	AdminEmail    string `mapstructure:"ADMIN_EMAIL"`
+	AdminPassword string `mapstructure:"ADMIN_PASSWORD"`
+
+	ServerReadTimeout       time.Duration `mapstructure:"SERVER_READ_TIMEOUT"`
+	ServerReadHeaderTimeout time.Duration `mapstructure:"SERVER_READ_HEADER_TIMEOUT"`
+	ServerWriteTimeout      time.Duration `mapstructure:"SERVER_WRITE_TIMEOUT"`
+	ServerIdleTimeout       time.Duration `mapstructure:"SERVER_IDLE_TIMEOUT"`
+	ServerShutdownTimeout   time.Duration `mapstructure:"SERVER_SHUTDOWN_TIMEOUT"`
+
+	// MetricsPort serves /metrics apart from the API, so only what can reach
+	// the port from inside the deployment can scrape it.
+	MetricsPort int `mapstructure:"METRICS_PORT"`
+
+	HealthCheckTimeout time.Duration `mapstructure:"HEALTH_CHECK_TIMEOUT"`
+
+	// TrustedProxies lists the CIDRs of the load balancers in front of the
+	// server. X-Forwarded-For is only read when the peer is one of them.
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
+
+	CacheBreakerFailureThreshold int           `mapstructure:"CACHE_BREAKER_FAILURE_THRESHOLD"`
+	CacheBreakerOpenDuration     time.Duration `mapstructure:"CACHE_BREAKER_OPEN_DURATION"`
+	LocalCacheSize               int           `mapstructure:"LOCAL_CACHE_SIZE"`
+	LocalCacheDuration           time.Duration `mapstructure:"LOCAL_CACHE_DURATION"`
+	CacheFlushTimeout            time.Duration `mapstructure:"CACHE_FLUSH_TIMEOUT"`
+
+	TracingExporter    string  `mapstructure:"TRACING_EXPORTER"`
+	TracingServiceName string  `mapstructure:"TRACING_SERVICE_NAME"`
+	TracingSampleRatio float64 `mapstructure:"TRACING_SAMPLE_RATIO"`
+	OTLPEndpoint       string  `mapstructure:"OTLP_ENDPOINT"`
+	OTLPInsecure       bool    `mapstructure:"OTLP_INSECURE"`
+
+	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+
+	PasswordMinLength        int  `mapstructure:"PASSWORD_MIN_LENGTH"`
+	PasswordRequireUppercase bool `mapstructure:"PASSWORD_REQUIRE_UPPERCASE"`
+	PasswordRequireLowercase bool `mapstructure:"PASSWORD_REQUIRE_LOWERCASE"`
+	PasswordRequireDigit     bool `mapstructure:"PASSWORD_REQUIRE_DIGIT"`
+	PasswordRequireSymbol    bool `mapstructure:"PASSWORD_REQUIRE_SYMBOL"`
+
+	LoginMaxAttemptsPerEmail int           `mapstructure:"LOGIN_MAX_ATTEMPTS_PER_EMAIL"`
+	LoginMaxAttemptsPerIP    int           `mapstructure:"LOGIN_MAX_ATTEMPTS_PER_IP"`
+	LoginAttemptWindow       time.Duration `mapstructure:"LOGIN_ATTEMPT_WINDOW"`
+	LoginLockoutDuration     time.Duration `mapstructure:"LOGIN_LOCKOUT_DURATION"`
+	LoginMaxLockoutDuration  time.Duration `mapstructure:"LOGIN_MAX_LOCKOUT_DURATION"`
+
+	RateLimitSignUpRequests int           `mapstructure:"RATE_LIMIT_SIGN_UP_REQUESTS"`
+	RateLimitSignUpWindow   time.Duration `mapstructure:"RATE_LIMIT_SIGN_UP_WINDOW"`
+	RateLimitBookRequests   int           `mapstructure:"RATE_LIMIT_BOOK_REQUESTS"`
+	RateLimitBookWindow     time.Duration `mapstructure:"RATE_LIMIT_BOOK_WINDOW"`
+	RateLimitOrderRequests  int           `mapstructure:"RATE_LIMIT_ORDER_REQUESTS"`
+	RateLimitOrderWindow    time.Duration `mapstructure:"RATE_LIMIT_ORDER_WINDOW"`
+
+	TOTPIssuer                 string        `mapstructure:"TOTP_ISSUER"`
+	TwoFactorChallengeDuration time.Duration `mapstructure:"TWO_FACTOR_CHALLENGE_DURATION"`
+	TwoFactorMaxAttempts       int           `mapstructure:"TWO_FACTOR_MAX_ATTEMPTS"`
+
+	AppURL                         string        `mapstructure:"APP_URL"`
+	PasswordResetTokenDuration     time.Duration `mapstructure:"PASSWORD_RESET_TOKEN_DURATION"`
+	EmailVerificationTokenDuration time.Duration `mapstructure:"EMAIL_VERIFICATION_TOKEN_DURATION"`
+
+	MailDriver   string `mapstructure:"MAIL_DRIVER"`
+	MailFrom     string `mapstructure:"MAIL_FROM"`
+	MailLogPath  string `mapstructure:"MAIL_LOG_PATH"`
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+}
+
+func LoadAppConfig(path string, configName string, config *AppConfig) error {
+	v := viper.New()
+	v.AddConfigPath(path)
+	v.SetConfigName(configName)
+	v.SetConfigType("env")
+	v.AutomaticEnv()
+
+	if err := v.ReadInConfig(); err != nil {
+		return err
+	}
+
+	if err := v.Unmarshal(config); err != nil {
+		return err
+	}
+
+	for _, cidr := range config.TrustedProxies {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("invalid TRUSTED_PROXIES entry %q: %w", cidr, err)
+		}
+	}
+
+	return nil
+}
+
+func CheckAndSetAppConfig(path string, configName string) *AppConfig {
+	config := &AppConfig{}
+	if err := LoadAppConfig(path, configName, config); err != nil {
+		panic(err)
+	}
+
+	return config
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadAppConfig(t *testing.T) {
+	t.Run("success load app config", func(t *testing.T) {
+		config := &AppConfig{}
+
+		err := LoadAppConfig(".", "test", config)
+		assert.NoError(t, err)
+		assert.Equal(t, "admin@book.com", config.AdminEmail)
+		assert.Equal(t, 720*time.Hour, config.RefreshTokenDuration)
+		assert.Equal(t, 30*time.Second, config.ServerShutdownTimeout)
+		assert.Equal(t, 9091, config.MetricsPort)
+		assert.Equal(t, []string{"10.0.0.0/8", "192.168.0.0/16"}, config.TrustedProxies)
+		assert.Equal(t, 30, config.RateLimitOrderRequests)
+		assert.Equal(t, time.Minute, config.RateLimitOrderWindow)
+		assert.Equal(t, 30*time.Second, config.CacheBreakerOpenDuration)
+		assert.Equal(t, 2*time.Second, config.CacheFlushTimeout)
+	})
+
+	t.Run("invalid trusted proxy", func(t *testing.T) {
+		t.Setenv("TRUSTED_PROXIES", "10.0.0.1")
+		config := &AppConfig{}
+
+		err := LoadAppConfig(".", "test", config)
+		assert.ErrorContains(t, err, "invalid TRUSTED_PROXIES entry")
+	})
+
+	t.Run("config file not found", func(t *testing.T) {
+		config := &AppConfig{}
+
+		err := LoadAppConfig(".", "unknown", config)
+		assert.Error(t, err)
+	})
+}
+
+func TestCheckAndSetAppConfig(t *testing.T) {
+	assert.NotPanics(t, func() {
+		config := CheckAndSetAppConfig(".", "test")
+		assert.NotNil(t, config)
+	})
+
+	assert.Panics(t, func() {
+		CheckAndSetAppConfig(".", "unknown")
+	})
+}
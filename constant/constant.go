@@ -8,8 +8,107 @@ const (
 	BookCacheKey  = "book"
 )
 
+// redis keys for access token revocation
+const (
+	RevokedAccessTokenKey = "revoked_access_token"
+	UserAccessTokensKey   = "user_access_tokens"
+)
+
+// redis keys for sign-in brute-force protection
+const (
+	LoginAttemptKey = "login_attempt"
+	LoginLockoutKey = "login_lockout"
+)
+
+// redis key for request rate limiting
+const (
+	RateLimitKey = "rate_limit"
+)
+
+// rate limited route groups
+const (
+	RateLimitSignUp = "sign_up"
+	RateLimitBook   = "book"
+	RateLimitOrder  = "order"
+)
+
+// redis keys for two-factor sign-in challenges
+const (
+	TwoFactorChallengeKey = "two_factor_challenge"
+	TwoFactorAttemptKey   = "two_factor_attempt"
+)
+
+// user token purposes
+const (
+	UserTokenPasswordReset     = "password_reset"
+	UserTokenEmailVerification = "email_verification"
+)
+
+// user roles
+const (
+	RoleCustomer = "customer"
+	RoleStaff    = "staff"
+	RoleAdmin    = "admin"
+)
+
+// order statuses
+const (
+	OrderStatusPending   = "pending"
+	OrderStatusConfirmed = "confirmed"
+	OrderStatusShipped   = "shipped"
+	OrderStatusDelivered = "delivered"
+	OrderStatusCancelled = "cancelled"
+)
+
+// stock movement reasons
+const (
+	StockReasonRestock        = "restock"
+	StockReasonDamaged        = "damaged"
+	StockReasonCorrection     = "correction"
+	StockReasonReturned       = "returned"
+	StockReasonOrderPlaced    = "order_placed"
+	StockReasonOrderCancelled = "order_cancelled"
+)
+
+// book sort fields
+const (
+	BookSortCreatedAt = "created_at"
+	BookSortPrice     = "price"
+	BookSortTitle     = "title"
+)
+
+// sort directions
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// health statuses
+const (
+	HealthStatusUp       = "UP"
+	HealthStatusDegraded = "DEGRADED"
+	HealthStatusDown     = "DOWN"
+)
+
+// metric label values
+const (
+	OrderSourceAPI  = "api"
+	OrderSourceCart = "cart"
+
+	LoginFailureInvalidCredentials = "invalid_credentials"
+	LoginFailureLockedOut          = "locked_out"
+	LoginFailureInvalidTwoFactor   = "invalid_two_factor"
+)
+
+// request headers
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	RequestIDHeader      = "X-Request-ID"
+)
+
 const (
 	TimeFormat                  = "2006-01-02 15:04:05"
 	UserSession  ContextKeyType = "user-session"
+	RequestInfo  ContextKeyType = "request-info"
 	DefaultLimit                = 30
 )
@@ -0,0 +1 @@
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "role" VARCHAR NOT NULL DEFAULT 'customer'; -- e.g., customer, staff, admin
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS "order_status_history" (
+  "id" UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+  "order_id" UUID NOT NULL,
+  "from_status" TEXT NOT NULL,
+  "to_status" TEXT NOT NULL,
+  "changed_by" UUID NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (NOW())
+);
+
+ALTER TABLE "order_status_history" ADD FOREIGN KEY ("order_id") REFERENCES "order" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "order_status_history" ADD FOREIGN KEY ("changed_by") REFERENCES "user" ("id");
+
+CREATE INDEX IF NOT EXISTS "order_status_history_order_id_idx" ON "order_status_history" ("order_id");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "stock_movement";
+ALTER TABLE "book" DROP COLUMN IF EXISTS "stock";
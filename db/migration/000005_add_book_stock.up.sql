@@ -0,0 +1,20 @@
+ALTER TABLE "book" ADD COLUMN IF NOT EXISTS "stock" INT NOT NULL DEFAULT 0 CHECK ("stock" >= 0);
+
+-- books that existed before stock tracking, including the seeded ones, start
+-- out of stock; an admin records the real count through a restock movement so
+-- the stock_movement ledger always sums to the book's stock
+
+CREATE TABLE IF NOT EXISTS "stock_movement" (
+  "id" UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+  "book_id" UUID NOT NULL,
+  "quantity" INT NOT NULL, -- positive adds stock, negative removes it
+  "reason" TEXT NOT NULL, -- e.g., restock, damaged, correction, returned, order_placed, order_cancelled
+  "created_by" UUID NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (NOW())
+);
+
+ALTER TABLE "stock_movement" ADD FOREIGN KEY ("book_id") REFERENCES "book" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "stock_movement" ADD FOREIGN KEY ("created_by") REFERENCES "user" ("id");
+
+CREATE INDEX IF NOT EXISTS "stock_movement_book_id_idx" ON "stock_movement" ("book_id");
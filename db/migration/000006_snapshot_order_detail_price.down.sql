@@ -0,0 +1,4 @@
+ALTER TABLE "order_detail" DROP COLUMN IF EXISTS "author";
+ALTER TABLE "order_detail" DROP COLUMN IF EXISTS "title";
+ALTER TABLE "order_detail" DROP COLUMN IF EXISTS "line_total";
+ALTER TABLE "order_detail" DROP COLUMN IF EXISTS "unit_price";
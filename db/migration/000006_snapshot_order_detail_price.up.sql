@@ -0,0 +1,14 @@
+ALTER TABLE "order_detail" ADD COLUMN IF NOT EXISTS "unit_price" DECIMAL NOT NULL DEFAULT 0;
+ALTER TABLE "order_detail" ADD COLUMN IF NOT EXISTS "line_total" DECIMAL NOT NULL DEFAULT 0;
+ALTER TABLE "order_detail" ADD COLUMN IF NOT EXISTS "title" VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE "order_detail" ADD COLUMN IF NOT EXISTS "author" VARCHAR NOT NULL DEFAULT '';
+
+-- prices were never stored per line, so existing rows take the book's current
+-- values, which is the closest record of what was charged
+UPDATE "order_detail" AS od
+SET "unit_price" = b."price",
+    "line_total" = b."price" * od."quantity",
+    "title" = b."title",
+    "author" = b."author"
+FROM "book" AS b
+WHERE od."book_id" = b."id";
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS "idempotency_key" (
+  "user_id" UUID NOT NULL,
+  "key" VARCHAR NOT NULL,
+  "request_hash" VARCHAR NOT NULL,
+  "response" JSONB NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (NOW()),
+  PRIMARY KEY ("user_id", "key")
+);
+
+ALTER TABLE "idempotency_key" ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id") ON DELETE CASCADE;
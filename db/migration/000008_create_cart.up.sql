@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS "cart" (
+  "id" UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+  "user_id" UUID UNIQUE NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (NOW()),
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT (NOW())
+);
+
+CREATE TABLE IF NOT EXISTS "cart_item" (
+  "id" UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+  "cart_id" UUID NOT NULL,
+  "book_id" UUID NOT NULL,
+  "quantity" INT NOT NULL CHECK ("quantity" > 0),
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (NOW()),
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT (NOW()),
+  UNIQUE ("cart_id", "book_id")
+);
+
+ALTER TABLE "cart" ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "cart_item" ADD FOREIGN KEY ("cart_id") REFERENCES "cart" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "cart_item" ADD FOREIGN KEY ("book_id") REFERENCES "book" ("id") ON DELETE CASCADE;
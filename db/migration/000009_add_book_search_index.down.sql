@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "book_price_idx";
+DROP INDEX IF EXISTS "book_search_idx";
@@ -0,0 +1,6 @@
+-- the expression must match the one used by FindBook and GetBookCount for
+-- the planner to pick this index
+CREATE INDEX IF NOT EXISTS "book_search_idx" ON "book"
+USING GIN (to_tsvector('english', "title" || ' ' || "description" || ' ' || "author"));
+
+CREATE INDEX IF NOT EXISTS "book_price_idx" ON "book" ("price");
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "order_user_id_date_id_idx";
+DROP INDEX IF EXISTS "book_created_at_id_idx";
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS "book_created_at_id_idx" ON "book" ("created_at", "id");
+
+CREATE INDEX IF NOT EXISTS "order_user_id_date_id_idx" ON "order" ("user_id", "date", "id");
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS "user_session" (
+  "id" UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+  "user_id" UUID NOT NULL,
+  "refresh_token_hash" VARCHAR UNIQUE NOT NULL,
+  "expires_at" TIMESTAMPTZ NOT NULL,
+  "revoked_at" TIMESTAMPTZ,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (NOW()),
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT (NOW())
+);
+
+CREATE INDEX IF NOT EXISTS "user_session_user_id_idx" ON "user_session" ("user_id");
+
+ALTER TABLE "user_session" ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id") ON DELETE CASCADE;
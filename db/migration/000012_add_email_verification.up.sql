@@ -0,0 +1,18 @@
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "email_verified_at" TIMESTAMPTZ;
+
+-- accounts created before verification existed keep being able to order
+UPDATE "user" SET "email_verified_at" = NOW() WHERE "email_verified_at" IS NULL;
+
+CREATE TABLE IF NOT EXISTS "user_token" (
+  "id" UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+  "user_id" UUID NOT NULL,
+  "purpose" VARCHAR NOT NULL, -- e.g., password_reset, email_verification
+  "token_hash" VARCHAR UNIQUE NOT NULL,
+  "expires_at" TIMESTAMPTZ NOT NULL,
+  "used_at" TIMESTAMPTZ,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (NOW())
+);
+
+CREATE INDEX IF NOT EXISTS "user_token_user_id_purpose_idx" ON "user_token" ("user_id", "purpose");
+
+ALTER TABLE "user_token" ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id") ON DELETE CASCADE;
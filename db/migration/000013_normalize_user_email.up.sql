@@ -0,0 +1,6 @@
+-- emails are lowercased and trimmed by the user queries from now on, so
+-- addresses differing only in case belong to the same account. This fails on
+-- existing accounts that only differ that way; merge those by hand first.
+UPDATE "user" SET "email" = LOWER(TRIM("email")) WHERE "email" <> LOWER(TRIM("email"));
+
+ALTER TABLE "user" ADD CONSTRAINT "user_email_normalized_check" CHECK ("email" = LOWER(TRIM("email")));
@@ -0,0 +1,16 @@
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "totp_secret" VARCHAR;
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "totp_enabled_at" TIMESTAMPTZ;
+-- last accepted time step, so a code can't be replayed while it is still valid
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "totp_last_step" BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS "user_recovery_code" (
+  "id" UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+  "user_id" UUID NOT NULL,
+  "code_hash" VARCHAR NOT NULL,
+  "used_at" TIMESTAMPTZ,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (NOW())
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "user_recovery_code_user_id_code_hash_idx" ON "user_recovery_code" ("user_id", "code_hash");
+
+ALTER TABLE "user_recovery_code" ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id") ON DELETE CASCADE;
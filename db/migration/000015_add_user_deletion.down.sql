@@ -0,0 +1,4 @@
+ALTER TABLE "order" DROP CONSTRAINT IF EXISTS "order_user_id_fkey";
+ALTER TABLE "order" ADD CONSTRAINT "order_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "user" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "user" DROP COLUMN IF EXISTS "deleted_at";
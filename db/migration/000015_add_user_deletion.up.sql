@@ -0,0 +1,7 @@
+-- set when the account is deleted and its personal fields are anonymized
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "deleted_at" TIMESTAMPTZ;
+
+-- orders are kept for accounting, so a user with orders is anonymized instead
+-- of deleted and removing the row must not silently wipe their history
+ALTER TABLE "order" DROP CONSTRAINT IF EXISTS "order_user_id_fkey";
+ALTER TABLE "order" ADD CONSTRAINT "order_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "user" ("id") ON DELETE RESTRICT;
@@ -1,6 +1,6 @@
 -- name: CreateBook :one
-INSERT INTO "book"(title, description, author, price) VALUES
-($1, $2, $3, $4) RETURNING *;
+INSERT INTO "book"(title, description, author, price, stock) VALUES
+($1, $2, $3, $4, $5) RETURNING *;
 
 -- name: CheckBookExists :one
 SELECT EXISTS(SELECT id FROM "book" WHERE id=$1);
@@ -8,16 +8,88 @@ SELECT EXISTS(SELECT id FROM "book" WHERE id=$1);
 -- name: FindBookByID :one
 SELECT * FROM "book" WHERE id=$1;
 
+-- name: UpdateBookByID :one
+UPDATE "book"
+SET title=$2, description=$3, author=$4, price=$5, updated_at=NOW()
+WHERE id=$1 RETURNING *;
+
+-- name: DeleteBookByID :exec
+DELETE FROM "book" WHERE id=$1;
+
+-- name: CheckBookOrdered :one
+SELECT EXISTS(SELECT id FROM "order_detail" WHERE book_id=$1);
+
 -- name: FindBook :many
 SELECT * FROM "book" AS b
-ORDER BY b.created_at DESC
-LIMIT $1 OFFSET $2;
+WHERE (sqlc.arg(search)::TEXT = '' OR to_tsvector('english', b.title || ' ' || b.description || ' ' || b.author)
+    @@ websearch_to_tsquery('english', sqlc.arg(search)::TEXT))
+AND (sqlc.arg(author)::TEXT = '' OR b.author ILIKE '%' || sqlc.arg(author)::TEXT || '%')
+AND (sqlc.narg(min_price)::DECIMAL IS NULL OR b.price >= sqlc.narg(min_price)::DECIMAL)
+AND (sqlc.narg(max_price)::DECIMAL IS NULL OR b.price <= sqlc.narg(max_price)::DECIMAL)
+ORDER BY
+    CASE WHEN sqlc.arg(sort_by)::TEXT = 'price' AND sqlc.arg(sort_dir)::TEXT = 'asc' THEN b.price END ASC,
+    CASE WHEN sqlc.arg(sort_by)::TEXT = 'price' AND sqlc.arg(sort_dir)::TEXT = 'desc' THEN b.price END DESC,
+    CASE WHEN sqlc.arg(sort_by)::TEXT = 'title' AND sqlc.arg(sort_dir)::TEXT = 'asc' THEN b.title END ASC,
+    CASE WHEN sqlc.arg(sort_by)::TEXT = 'title' AND sqlc.arg(sort_dir)::TEXT = 'desc' THEN b.title END DESC,
+    CASE WHEN sqlc.arg(sort_by)::TEXT = 'created_at' AND sqlc.arg(sort_dir)::TEXT = 'asc' THEN b.created_at END ASC,
+    b.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: GetBookCount :one
-SELECT COUNT(o.*) FROM (SELECT * FROM "book" AS b) AS o;
+SELECT COUNT(b.*) FROM "book" AS b
+WHERE (sqlc.arg(search)::TEXT = '' OR to_tsvector('english', b.title || ' ' || b.description || ' ' || b.author)
+    @@ websearch_to_tsquery('english', sqlc.arg(search)::TEXT))
+AND (sqlc.arg(author)::TEXT = '' OR b.author ILIKE '%' || sqlc.arg(author)::TEXT || '%')
+AND (sqlc.narg(min_price)::DECIMAL IS NULL OR b.price >= sqlc.narg(min_price)::DECIMAL)
+AND (sqlc.narg(max_price)::DECIMAL IS NULL OR b.price <= sqlc.narg(max_price)::DECIMAL);
 
 -- name: GetBookPurchasedByUserID :many
 SELECT DISTINCT book_id, b.title, b. description from "order" o join "order_detail" od
 on o.id = od.order_id join book b
 on od.book_id = b.id
-where user_id = $1;
\ No newline at end of file
+where user_id = $1;
+
+-- name: AdjustBookStock :one
+UPDATE "book"
+SET stock=stock + sqlc.arg(quantity)::INT, updated_at=NOW()
+WHERE id=sqlc.arg(id) AND stock + sqlc.arg(quantity)::INT >= 0 RETURNING *;
+
+-- name: CreateStockMovement :one
+INSERT INTO "stock_movement"(book_id, quantity, reason, created_by) VALUES
+($1, $2, $3, $4) RETURNING *;
+
+-- name: FindBooksByIDs :many
+SELECT * FROM "book" WHERE id = ANY(sqlc.arg(ids)::UUID[]);
+
+-- name: ReserveBooksStock :many
+UPDATE "book" AS b
+SET stock=b.stock - v.quantity, updated_at=NOW()
+FROM (SELECT UNNEST(sqlc.arg(ids)::UUID[]) AS id, UNNEST(sqlc.arg(quantities)::INT[]) AS quantity) AS v
+WHERE b.id=v.id AND b.stock >= v.quantity RETURNING b.*;
+
+-- name: CreateStockMovements :exec
+INSERT INTO "stock_movement"(book_id, quantity, reason, created_by)
+SELECT UNNEST(sqlc.arg(book_ids)::UUID[]), UNNEST(sqlc.arg(quantities)::INT[]), sqlc.arg(reason)::TEXT, sqlc.arg(created_by)::UUID;
+
+-- name: FindBookAfterCursor :many
+SELECT * FROM "book" AS b
+WHERE (sqlc.arg(search)::TEXT = '' OR to_tsvector('english', b.title || ' ' || b.description || ' ' || b.author)
+    @@ websearch_to_tsquery('english', sqlc.arg(search)::TEXT))
+AND (sqlc.arg(author)::TEXT = '' OR b.author ILIKE '%' || sqlc.arg(author)::TEXT || '%')
+AND (sqlc.narg(min_price)::DECIMAL IS NULL OR b.price >= sqlc.narg(min_price)::DECIMAL)
+AND (sqlc.narg(max_price)::DECIMAL IS NULL OR b.price <= sqlc.narg(max_price)::DECIMAL)
+AND (sqlc.narg(cursor_created_at)::TIMESTAMPTZ IS NULL
+    OR (b.created_at, b.id) < (sqlc.narg(cursor_created_at)::TIMESTAMPTZ, sqlc.narg(cursor_id)::UUID))
+ORDER BY b.created_at DESC, b.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: FindBookBeforeCursor :many
+SELECT * FROM "book" AS b
+WHERE (sqlc.arg(search)::TEXT = '' OR to_tsvector('english', b.title || ' ' || b.description || ' ' || b.author)
+    @@ websearch_to_tsquery('english', sqlc.arg(search)::TEXT))
+AND (sqlc.arg(author)::TEXT = '' OR b.author ILIKE '%' || sqlc.arg(author)::TEXT || '%')
+AND (sqlc.narg(min_price)::DECIMAL IS NULL OR b.price >= sqlc.narg(min_price)::DECIMAL)
+AND (sqlc.narg(max_price)::DECIMAL IS NULL OR b.price <= sqlc.narg(max_price)::DECIMAL)
+AND (b.created_at, b.id) > (sqlc.arg(cursor_created_at)::TIMESTAMPTZ, sqlc.arg(cursor_id)::UUID)
+ORDER BY b.created_at ASC, b.id ASC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,38 @@
+-- name: UpsertCart :one
+INSERT INTO "cart"(user_id) VALUES
+($1) ON CONFLICT (user_id) DO UPDATE SET updated_at=NOW() RETURNING *;
+
+-- name: FindCartByUserID :one
+SELECT * FROM "cart" WHERE user_id=$1;
+
+-- name: FindCartByUserIDForUpdate :one
+SELECT * FROM "cart" WHERE user_id=$1 FOR UPDATE;
+
+-- name: FindCartItemsByCartID :many
+SELECT
+    ci.book_id, ci.quantity, b.title,
+    b.author, b.price, b.stock
+FROM "cart_item" AS ci
+JOIN "book" AS b
+ON ci.book_id = b.id
+WHERE ci.cart_id=$1
+ORDER BY ci.created_at;
+
+-- name: UpsertCartItem :one
+INSERT INTO "cart_item"(cart_id, book_id, quantity) VALUES
+($1, $2, $3) ON CONFLICT (cart_id, book_id)
+DO UPDATE SET quantity="cart_item".quantity + EXCLUDED.quantity, updated_at=NOW() RETURNING *;
+
+-- name: UpdateCartItemQuantity :one
+UPDATE "cart_item"
+SET quantity=$3, updated_at=NOW()
+WHERE cart_id=$1 AND book_id=$2 RETURNING *;
+
+-- name: DeleteCartItem :one
+DELETE FROM "cart_item" WHERE cart_id=$1 AND book_id=$2 RETURNING *;
+
+-- name: DeleteCartItemsByCartID :exec
+DELETE FROM "cart_item" WHERE cart_id=$1;
+
+-- name: DeleteCartByUserID :exec
+DELETE FROM "cart" WHERE user_id=$1;
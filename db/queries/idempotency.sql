@@ -0,0 +1,6 @@
+-- name: FindIdempotencyKey :one
+SELECT * FROM "idempotency_key" WHERE user_id=$1 AND key=$2;
+
+-- name: CreateIdempotencyKey :one
+INSERT INTO "idempotency_key"(user_id, key, request_hash, response) VALUES
+($1, $2, $3, $4) RETURNING *;
@@ -7,10 +7,6 @@ UPDATE "order"
 SET total_price=$2
 WHERE id=$1 RETURNING *;
 
--- name: CreateOrderDetail :one
-INSERT INTO "order_detail"(order_id, book_id, quantity) VALUES
-($1, $2, $3) RETURNING *;
-
 -- name: FindOrderByUserID :many
 SELECT * FROM "order" AS o
 WHERE o.user_id=$1
@@ -30,11 +26,62 @@ SELECT EXISTS(SELECT id FROM "order" WHERE id=$1);
 
 -- name: FindOrderDetailByOrderID :many
 SELECT 
-    o.id, o.date, od.book_id, b.title,
+    o.id, o.date, od.book_id, od.title,
     o.total_price, o.status, b.description, 
-    b.author, od.quantity, b.price
+    od.author, od.quantity, od.unit_price, od.line_total
 FROM "order" AS o
 JOIN "order_detail" od 
 ON o.id = od.order_id JOIN "book" AS b
 ON od.book_id = b.id
-WHERE o.user_id=$1 AND o.id=$2;
\ No newline at end of file
+WHERE o.user_id=$1 AND o.id=$2;
+
+-- name: FindOrderByIDForUpdate :one
+SELECT * FROM "order" WHERE id=$1 FOR UPDATE;
+
+-- name: UpdateOrderStatusByID :one
+UPDATE "order"
+SET status=$2, updated_at=NOW()
+WHERE id=$1 RETURNING *;
+
+-- name: CreateOrderStatusHistory :one
+INSERT INTO "order_status_history"(order_id, from_status, to_status, changed_by) VALUES
+($1, $2, $3, $4) RETURNING *;
+
+-- name: FindOrderItemsByOrderID :many
+SELECT * FROM "order_detail" WHERE order_id=$1;
+
+-- name: CreateOrderDetails :exec
+INSERT INTO "order_detail"(order_id, book_id, quantity, unit_price, line_total, title, author)
+SELECT sqlc.arg(order_id)::UUID, UNNEST(sqlc.arg(book_ids)::UUID[]), UNNEST(sqlc.arg(quantities)::INT[]),
+    UNNEST(sqlc.arg(unit_prices)::DECIMAL[]), UNNEST(sqlc.arg(line_totals)::DECIMAL[]),
+    UNNEST(sqlc.arg(titles)::VARCHAR[]), UNNEST(sqlc.arg(authors)::VARCHAR[]);
+
+-- name: FindOrderByUserIDAfterCursor :many
+SELECT * FROM "order" AS o
+WHERE o.user_id=sqlc.arg(user_id)
+AND (sqlc.narg(cursor_date)::TIMESTAMPTZ IS NULL
+    OR (o.date, o.id) < (sqlc.narg(cursor_date)::TIMESTAMPTZ, sqlc.narg(cursor_id)::UUID))
+ORDER BY o.date DESC, o.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: FindOrderByUserIDBeforeCursor :many
+SELECT * FROM "order" AS o
+WHERE o.user_id=sqlc.arg(user_id)
+AND (o.date, o.id) > (sqlc.arg(cursor_date)::TIMESTAMPTZ, sqlc.arg(cursor_id)::UUID)
+ORDER BY o.date ASC, o.id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: FindAllOrderByUserID :many
+SELECT * FROM "order"
+WHERE user_id=$1
+ORDER BY date DESC, id DESC;
+
+-- name: FindOrderItemsByUserID :many
+SELECT
+    od.id, od.order_id, od.book_id, od.title, b.description,
+    od.author, od.quantity, od.unit_price, od.line_total
+FROM "order_detail" AS od
+JOIN "order" AS o ON o.id = od.order_id
+JOIN "book" AS b ON b.id = od.book_id
+WHERE o.user_id=$1
+ORDER BY od.created_at, od.id;
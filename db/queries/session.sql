@@ -0,0 +1,18 @@
+-- name: CreateUserSession :one
+INSERT INTO "user_session"(user_id, refresh_token_hash, expires_at) VALUES
+($1, $2, $3) RETURNING *;
+
+-- name: FindUserSessionByRefreshTokenHashForUpdate :one
+SELECT * FROM "user_session" WHERE refresh_token_hash=$1 FOR UPDATE;
+
+-- name: RevokeUserSessionByID :exec
+UPDATE "user_session" SET revoked_at=NOW(), updated_at=NOW()
+WHERE id=$1 AND revoked_at IS NULL;
+
+-- name: RevokeUserSessionByRefreshTokenHash :exec
+UPDATE "user_session" SET revoked_at=NOW(), updated_at=NOW()
+WHERE user_id=$1 AND refresh_token_hash=$2 AND revoked_at IS NULL;
+
+-- name: RevokeUserSessionsByUserID :exec
+UPDATE "user_session" SET revoked_at=NOW(), updated_at=NOW()
+WHERE user_id=$1 AND revoked_at IS NULL;
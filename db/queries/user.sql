@@ -1,9 +1,63 @@
 -- name: CreateUser :one
-INSERT INTO "user"(name, email, password) VALUES
-($1, $2, $3) RETURNING *;
+INSERT INTO "user"(name, email, password, role) VALUES
+(sqlc.arg(name), LOWER(TRIM(sqlc.arg(email)::TEXT)), sqlc.arg(password), sqlc.arg(role)) RETURNING *;
 
 -- name: FindUserByEmail :one
-SELECT * FROM "user" WHERE email=$1;
+SELECT * FROM "user" WHERE email=LOWER(TRIM(sqlc.arg(email)::TEXT));
 
 -- name: CheckEmailExists :one
-SELECT EXISTS(SELECT id FROM "user" WHERE email=$1);
\ No newline at end of file
+SELECT EXISTS(SELECT id FROM "user" WHERE email=LOWER(TRIM(sqlc.arg(email)::TEXT)));
+
+-- name: FindUserRoleByID :one
+SELECT role FROM "user" WHERE id=$1;
+
+-- name: UpdateUserRoleByID :exec
+UPDATE "user"
+SET role=$2, updated_at=NOW()
+WHERE id=$1;
+
+-- name: FindUserByID :one
+SELECT * FROM "user" WHERE id=$1;
+
+-- name: UpdateUserPasswordByID :exec
+UPDATE "user"
+SET password=$2, updated_at=NOW()
+WHERE id=$1;
+
+-- name: UpdateUserProfileByID :one
+UPDATE "user"
+SET name=sqlc.arg(name),
+    email=LOWER(TRIM(sqlc.arg(email)::TEXT)),
+    email_verified_at=CASE WHEN email=LOWER(TRIM(sqlc.arg(email)::TEXT)) THEN email_verified_at END,
+    updated_at=NOW()
+WHERE id=sqlc.arg(id) RETURNING *;
+
+-- name: VerifyUserEmailByID :exec
+UPDATE "user"
+SET email_verified_at=NOW(), updated_at=NOW()
+WHERE id=$1 AND email_verified_at IS NULL;
+
+-- name: IsUserEmailVerified :one
+SELECT (email_verified_at IS NOT NULL)::boolean AS is_verified FROM "user" WHERE id=$1;
+
+-- name: SetUserTotpSecretByID :exec
+UPDATE "user"
+SET totp_secret=$2, updated_at=NOW()
+WHERE id=$1 AND totp_enabled_at IS NULL;
+
+-- name: EnableUserTotpByID :exec
+UPDATE "user"
+SET totp_enabled_at=NOW(), totp_last_step=$2, updated_at=NOW()
+WHERE id=$1;
+
+-- name: UpdateUserTotpLastStepByID :execrows
+UPDATE "user"
+SET totp_last_step=$2
+WHERE id=$1 AND totp_last_step < $2;
+
+-- name: AnonymizeUserByID :exec
+UPDATE "user"
+SET name='Deleted user', email='deleted-' || id::TEXT || '@deleted.invalid', password='',
+    email_verified_at=NULL, totp_secret=NULL, totp_enabled_at=NULL,
+    deleted_at=NOW(), updated_at=NOW()
+WHERE id=$1 AND deleted_at IS NULL;
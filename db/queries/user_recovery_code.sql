@@ -0,0 +1,10 @@
+-- name: CreateUserRecoveryCodes :exec
+INSERT INTO "user_recovery_code"(user_id, code_hash)
+SELECT sqlc.arg(user_id)::UUID, UNNEST(sqlc.arg(code_hashes)::VARCHAR[]);
+
+-- name: DeleteUserRecoveryCodesByUserID :exec
+DELETE FROM "user_recovery_code" WHERE user_id=$1;
+
+-- name: UseUserRecoveryCode :execrows
+UPDATE "user_recovery_code" SET used_at=NOW()
+WHERE user_id=$1 AND code_hash=$2 AND used_at IS NULL;
@@ -0,0 +1,13 @@
+-- name: CreateUserToken :one
+INSERT INTO "user_token"(user_id, purpose, token_hash, expires_at) VALUES
+($1, $2, $3, $4) RETURNING *;
+
+-- name: FindUserTokenByHashForUpdate :one
+SELECT * FROM "user_token" WHERE token_hash=$1 AND purpose=$2 FOR UPDATE;
+
+-- name: MarkUserTokenUsedByID :exec
+UPDATE "user_token" SET used_at=NOW() WHERE id=$1;
+
+-- name: InvalidateUserTokens :exec
+UPDATE "user_token" SET used_at=NOW()
+WHERE user_id=$1 AND purpose=$2 AND used_at IS NULL;
@@ -7,10 +7,40 @@ package querier
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
+const adjustBookStock = `-- name: AdjustBookStock :one
+UPDATE "book"
+SET stock=stock + $1::INT, updated_at=NOW()
+WHERE id=$2 AND stock + $1::INT >= 0 RETURNING id, title, description, author, price, created_at, updated_at, stock
+`
+
+type AdjustBookStockParams struct {
+	Quantity int32     `json:"quantity"`
+	ID       uuid.UUID `json:"id"`
+}
+
+func (q *Queries) AdjustBookStock(ctx context.Context, arg AdjustBookStockParams) (Book, error) {
+	row := q.db.QueryRow(ctx, adjustBookStock, arg.Quantity, arg.ID)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Author,
+		&i.Price,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Stock,
+	)
+	return i, err
+}
+
 const checkBookExists = `-- name: CheckBookExists :one
 SELECT EXISTS(SELECT id FROM "book" WHERE id=$1)
 `
@@ -22,16 +52,28 @@ func (q *Queries) CheckBookExists(ctx context.Context, id uuid.UUID) (bool, erro
 	return exists, err
 }
 
+const checkBookOrdered = `-- name: CheckBookOrdered :one
+SELECT EXISTS(SELECT id FROM "order_detail" WHERE book_id=$1)
+`
+
+func (q *Queries) CheckBookOrdered(ctx context.Context, bookID uuid.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, checkBookOrdered, bookID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const createBook = `-- name: CreateBook :one
-INSERT INTO "book"(title, description, author, price) VALUES
-($1, $2, $3, $4) RETURNING id, title, description, author, price, created_at, updated_at
+INSERT INTO "book"(title, description, author, price, stock) VALUES
+($1, $2, $3, $4, $5) RETURNING id, title, description, author, price, created_at, updated_at, stock
 `
 
 type CreateBookParams struct {
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	Author      string  `json:"author"`
-	Price       float64 `json:"price"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Author      string          `json:"author"`
+	Price       decimal.Decimal `json:"price"`
+	Stock       int32           `json:"stock"`
 }
 
 func (q *Queries) CreateBook(ctx context.Context, arg CreateBookParams) (Book, error) {
@@ -40,6 +82,7 @@ func (q *Queries) CreateBook(ctx context.Context, arg CreateBookParams) (Book, e
 		arg.Description,
 		arg.Author,
 		arg.Price,
+		arg.Stock,
 	)
 	var i Book
 	err := row.Scan(
@@ -50,23 +93,112 @@ func (q *Queries) CreateBook(ctx context.Context, arg CreateBookParams) (Book, e
 		&i.Price,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Stock,
+	)
+	return i, err
+}
+
+const createStockMovement = `-- name: CreateStockMovement :one
+INSERT INTO "stock_movement"(book_id, quantity, reason, created_by) VALUES
+($1, $2, $3, $4) RETURNING id, book_id, quantity, reason, created_by, created_at
+`
+
+type CreateStockMovementParams struct {
+	BookID    uuid.UUID `json:"book_id"`
+	Quantity  int32     `json:"quantity"`
+	Reason    string    `json:"reason"`
+	CreatedBy uuid.UUID `json:"created_by"`
+}
+
+func (q *Queries) CreateStockMovement(ctx context.Context, arg CreateStockMovementParams) (StockMovement, error) {
+	row := q.db.QueryRow(ctx, createStockMovement,
+		arg.BookID,
+		arg.Quantity,
+		arg.Reason,
+		arg.CreatedBy,
+	)
+	var i StockMovement
+	err := row.Scan(
+		&i.ID,
+		&i.BookID,
+		&i.Quantity,
+		&i.Reason,
+		&i.CreatedBy,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
+const createStockMovements = `-- name: CreateStockMovements :exec
+INSERT INTO "stock_movement"(book_id, quantity, reason, created_by)
+SELECT UNNEST($1::UUID[]), UNNEST($2::INT[]), $3::TEXT, $4::UUID
+`
+
+type CreateStockMovementsParams struct {
+	BookIds    []uuid.UUID `json:"book_ids"`
+	Quantities []int32     `json:"quantities"`
+	Reason     string      `json:"reason"`
+	CreatedBy  uuid.UUID   `json:"created_by"`
+}
+
+func (q *Queries) CreateStockMovements(ctx context.Context, arg CreateStockMovementsParams) error {
+	_, err := q.db.Exec(ctx, createStockMovements,
+		arg.BookIds,
+		arg.Quantities,
+		arg.Reason,
+		arg.CreatedBy,
+	)
+	return err
+}
+
+const deleteBookByID = `-- name: DeleteBookByID :exec
+DELETE FROM "book" WHERE id=$1
+`
+
+func (q *Queries) DeleteBookByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteBookByID, id)
+	return err
+}
+
 const findBook = `-- name: FindBook :many
-SELECT id, title, description, author, price, created_at, updated_at FROM "book" AS b
-ORDER BY b.created_at DESC
-LIMIT $1 OFFSET $2
+SELECT id, title, description, author, price, created_at, updated_at, stock FROM "book" AS b
+WHERE ($1::TEXT = '' OR to_tsvector('english', b.title || ' ' || b.description || ' ' || b.author)
+    @@ websearch_to_tsquery('english', $1::TEXT))
+AND ($2::TEXT = '' OR b.author ILIKE '%' || $2::TEXT || '%')
+AND ($3::DECIMAL IS NULL OR b.price >= $3::DECIMAL)
+AND ($4::DECIMAL IS NULL OR b.price <= $4::DECIMAL)
+ORDER BY
+    CASE WHEN $5::TEXT = 'price' AND $6::TEXT = 'asc' THEN b.price END ASC,
+    CASE WHEN $5::TEXT = 'price' AND $6::TEXT = 'desc' THEN b.price END DESC,
+    CASE WHEN $5::TEXT = 'title' AND $6::TEXT = 'asc' THEN b.title END ASC,
+    CASE WHEN $5::TEXT = 'title' AND $6::TEXT = 'desc' THEN b.title END DESC,
+    CASE WHEN $5::TEXT = 'created_at' AND $6::TEXT = 'asc' THEN b.created_at END ASC,
+    b.created_at DESC
+LIMIT $8 OFFSET $7
 `
 
 type FindBookParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	Search   string              `json:"search"`
+	Author   string              `json:"author"`
+	MinPrice decimal.NullDecimal `json:"min_price"`
+	MaxPrice decimal.NullDecimal `json:"max_price"`
+	SortBy   string              `json:"sort_by"`
+	SortDir  string              `json:"sort_dir"`
+	Offset   int32               `json:"offset"`
+	Limit    int32               `json:"limit"`
 }
 
 func (q *Queries) FindBook(ctx context.Context, arg FindBookParams) ([]Book, error) {
-	rows, err := q.db.Query(ctx, findBook, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, findBook,
+		arg.Search,
+		arg.Author,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.SortBy,
+		arg.SortDir,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +214,126 @@ func (q *Queries) FindBook(ctx context.Context, arg FindBookParams) ([]Book, err
 			&i.Price,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Stock,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findBookAfterCursor = `-- name: FindBookAfterCursor :many
+SELECT id, title, description, author, price, created_at, updated_at, stock FROM "book" AS b
+WHERE ($1::TEXT = '' OR to_tsvector('english', b.title || ' ' || b.description || ' ' || b.author)
+    @@ websearch_to_tsquery('english', $1::TEXT))
+AND ($2::TEXT = '' OR b.author ILIKE '%' || $2::TEXT || '%')
+AND ($3::DECIMAL IS NULL OR b.price >= $3::DECIMAL)
+AND ($4::DECIMAL IS NULL OR b.price <= $4::DECIMAL)
+AND ($5::TIMESTAMPTZ IS NULL
+    OR (b.created_at, b.id) < ($5::TIMESTAMPTZ, $6::UUID))
+ORDER BY b.created_at DESC, b.id DESC
+LIMIT $7
+`
+
+type FindBookAfterCursorParams struct {
+	Search          string              `json:"search"`
+	Author          string              `json:"author"`
+	MinPrice        decimal.NullDecimal `json:"min_price"`
+	MaxPrice        decimal.NullDecimal `json:"max_price"`
+	CursorCreatedAt sql.NullTime        `json:"cursor_created_at"`
+	CursorID        uuid.NullUUID       `json:"cursor_id"`
+	Limit           int32               `json:"limit"`
+}
+
+func (q *Queries) FindBookAfterCursor(ctx context.Context, arg FindBookAfterCursorParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, findBookAfterCursor,
+		arg.Search,
+		arg.Author,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Book{}
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Author,
+			&i.Price,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Stock,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findBookBeforeCursor = `-- name: FindBookBeforeCursor :many
+SELECT id, title, description, author, price, created_at, updated_at, stock FROM "book" AS b
+WHERE ($1::TEXT = '' OR to_tsvector('english', b.title || ' ' || b.description || ' ' || b.author)
+    @@ websearch_to_tsquery('english', $1::TEXT))
+AND ($2::TEXT = '' OR b.author ILIKE '%' || $2::TEXT || '%')
+AND ($3::DECIMAL IS NULL OR b.price >= $3::DECIMAL)
+AND ($4::DECIMAL IS NULL OR b.price <= $4::DECIMAL)
+AND (b.created_at, b.id) > ($5::TIMESTAMPTZ, $6::UUID)
+ORDER BY b.created_at ASC, b.id ASC
+LIMIT $7
+`
+
+type FindBookBeforeCursorParams struct {
+	Search          string              `json:"search"`
+	Author          string              `json:"author"`
+	MinPrice        decimal.NullDecimal `json:"min_price"`
+	MaxPrice        decimal.NullDecimal `json:"max_price"`
+	CursorCreatedAt time.Time           `json:"cursor_created_at"`
+	CursorID        uuid.UUID           `json:"cursor_id"`
+	Limit           int32               `json:"limit"`
+}
+
+func (q *Queries) FindBookBeforeCursor(ctx context.Context, arg FindBookBeforeCursorParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, findBookBeforeCursor,
+		arg.Search,
+		arg.Author,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Book{}
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Author,
+			&i.Price,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Stock,
 		); err != nil {
 			return nil, err
 		}
@@ -94,7 +346,7 @@ func (q *Queries) FindBook(ctx context.Context, arg FindBookParams) ([]Book, err
 }
 
 const findBookByID = `-- name: FindBookByID :one
-SELECT id, title, description, author, price, created_at, updated_at FROM "book" WHERE id=$1
+SELECT id, title, description, author, price, created_at, updated_at, stock FROM "book" WHERE id=$1
 `
 
 func (q *Queries) FindBookByID(ctx context.Context, id uuid.UUID) (Book, error) {
@@ -108,16 +360,67 @@ func (q *Queries) FindBookByID(ctx context.Context, id uuid.UUID) (Book, error)
 		&i.Price,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Stock,
 	)
 	return i, err
 }
 
+const findBooksByIDs = `-- name: FindBooksByIDs :many
+SELECT id, title, description, author, price, created_at, updated_at, stock FROM "book" WHERE id = ANY($1::UUID[])
+`
+
+func (q *Queries) FindBooksByIDs(ctx context.Context, ids []uuid.UUID) ([]Book, error) {
+	rows, err := q.db.Query(ctx, findBooksByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Book{}
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Author,
+			&i.Price,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Stock,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getBookCount = `-- name: GetBookCount :one
-SELECT COUNT(o.*) FROM (SELECT id, title, description, author, price, created_at, updated_at FROM "book" AS b) AS o
+SELECT COUNT(b.*) FROM "book" AS b
+WHERE ($1::TEXT = '' OR to_tsvector('english', b.title || ' ' || b.description || ' ' || b.author)
+    @@ websearch_to_tsquery('english', $1::TEXT))
+AND ($2::TEXT = '' OR b.author ILIKE '%' || $2::TEXT || '%')
+AND ($3::DECIMAL IS NULL OR b.price >= $3::DECIMAL)
+AND ($4::DECIMAL IS NULL OR b.price <= $4::DECIMAL)
 `
 
-func (q *Queries) GetBookCount(ctx context.Context) (int64, error) {
-	row := q.db.QueryRow(ctx, getBookCount)
+type GetBookCountParams struct {
+	Search   string              `json:"search"`
+	Author   string              `json:"author"`
+	MinPrice decimal.NullDecimal `json:"min_price"`
+	MaxPrice decimal.NullDecimal `json:"max_price"`
+}
+
+func (q *Queries) GetBookCount(ctx context.Context, arg GetBookCountParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getBookCount,
+		arg.Search,
+		arg.Author,
+		arg.MinPrice,
+		arg.MaxPrice,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -155,3 +458,80 @@ func (q *Queries) GetBookPurchasedByUserID(ctx context.Context, userID uuid.UUID
 	}
 	return items, nil
 }
+
+const reserveBooksStock = `-- name: ReserveBooksStock :many
+UPDATE "book" AS b
+SET stock=b.stock - v.quantity, updated_at=NOW()
+FROM (SELECT UNNEST($1::UUID[]) AS id, UNNEST($2::INT[]) AS quantity) AS v
+WHERE b.id=v.id AND b.stock >= v.quantity RETURNING b.id, b.title, b.description, b.author, b.price, b.created_at, b.updated_at, b.stock
+`
+
+type ReserveBooksStockParams struct {
+	Ids        []uuid.UUID `json:"ids"`
+	Quantities []int32     `json:"quantities"`
+}
+
+func (q *Queries) ReserveBooksStock(ctx context.Context, arg ReserveBooksStockParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, reserveBooksStock, arg.Ids, arg.Quantities)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Book{}
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Author,
+			&i.Price,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Stock,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateBookByID = `-- name: UpdateBookByID :one
+UPDATE "book"
+SET title=$2, description=$3, author=$4, price=$5, updated_at=NOW()
+WHERE id=$1 RETURNING id, title, description, author, price, created_at, updated_at, stock
+`
+
+type UpdateBookByIDParams struct {
+	ID          uuid.UUID       `json:"id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Author      string          `json:"author"`
+	Price       decimal.Decimal `json:"price"`
+}
+
+func (q *Queries) UpdateBookByID(ctx context.Context, arg UpdateBookByIDParams) (Book, error) {
+	row := q.db.QueryRow(ctx, updateBookByID,
+		arg.ID,
+		arg.Title,
+		arg.Description,
+		arg.Author,
+		arg.Price,
+	)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Author,
+		&i.Price,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Stock,
+	)
+	return i, err
+}
@@ -2,6 +2,7 @@ package querier
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"regexp"
 	"testing"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/pashagolub/pgxmock/v3"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -49,7 +51,7 @@ func TestCreateBook(t *testing.T) {
 	title := "Hello"
 	description := "World"
 	author := "Giri Putra Adhittana"
-	price := float64(20)
+	price := decimal.NewFromInt(20)
 	now := time.Now()
 
 	req := CreateBookParams{
@@ -57,6 +59,7 @@ func TestCreateBook(t *testing.T) {
 		Description: description,
 		Author:      author,
 		Price:       price,
+		Stock:       10,
 	}
 
 	expected := Book{
@@ -67,11 +70,12 @@ func TestCreateBook(t *testing.T) {
 		Price:       price,
 		CreatedAt:   now,
 		UpdatedAt:   now,
+		Stock:       10,
 	}
 
 	t.Run("success query create book", func(t *testing.T) {
 		mockDB.ExpectQuery(regexp.QuoteMeta(createBook)).
-			WithArgs(req.Title, req.Description, req.Author, req.Price).
+			WithArgs(req.Title, req.Description, req.Author, req.Price, req.Stock).
 			WillReturnRows(pgxmock.NewRows([]string{
 				"id",
 				"title",
@@ -79,7 +83,8 @@ func TestCreateBook(t *testing.T) {
 				"author",
 				"price",
 				"created_at",
-				"updated_at"}).AddRow(
+				"updated_at",
+				"stock"}).AddRow(
 				expected.ID,
 				expected.Title,
 				expected.Description,
@@ -87,6 +92,7 @@ func TestCreateBook(t *testing.T) {
 				expected.Price,
 				expected.CreatedAt,
 				expected.UpdatedAt,
+				expected.Stock,
 			))
 
 		res, err := q.CreateBook(context.Background(), req)
@@ -96,7 +102,7 @@ func TestCreateBook(t *testing.T) {
 
 	t.Run("failed query create book", func(t *testing.T) {
 		mockDB.ExpectQuery(regexp.QuoteMeta(createBook)).
-			WithArgs(req.Title, req.Description, req.Author, req.Price).
+			WithArgs(req.Title, req.Description, req.Author, req.Price, req.Stock).
 			WillReturnError(errQuery)
 
 		res, err := q.CreateBook(context.Background(), req)
@@ -112,12 +118,18 @@ func TestFindBook(t *testing.T) {
 	title := "Hello"
 	description := "World"
 	author := "Giri Putra Adhittana"
-	price := float64(20)
+	price := decimal.NewFromInt(20)
 	now := time.Now()
 
 	req := FindBookParams{
-		Limit:  10,
-		Offset: 0,
+		Search:   "hello",
+		Author:   "Giri",
+		MinPrice: decimal.NewNullDecimal(decimal.NewFromInt(10)),
+		MaxPrice: decimal.NullDecimal{},
+		SortBy:   "price",
+		SortDir:  "asc",
+		Limit:    10,
+		Offset:   0,
 	}
 
 	expected := []Book{
@@ -129,12 +141,13 @@ func TestFindBook(t *testing.T) {
 			Price:       price,
 			CreatedAt:   now,
 			UpdatedAt:   now,
+			Stock:       10,
 		},
 	}
 
 	t.Run("success query find book", func(t *testing.T) {
 		mockDB.ExpectQuery(regexp.QuoteMeta(findBook)).
-			WithArgs(req.Limit, req.Offset).
+			WithArgs(req.Search, req.Author, req.MinPrice, req.MaxPrice, req.SortBy, req.SortDir, req.Offset, req.Limit).
 			WillReturnRows(pgxmock.NewRows([]string{
 				"id",
 				"title",
@@ -142,7 +155,8 @@ func TestFindBook(t *testing.T) {
 				"author",
 				"price",
 				"created_at",
-				"updated_at"}).AddRow(
+				"updated_at",
+				"stock"}).AddRow(
 				expected[0].ID,
 				expected[0].Title,
 				expected[0].Description,
@@ -150,6 +164,7 @@ func TestFindBook(t *testing.T) {
 				expected[0].Price,
 				expected[0].CreatedAt,
 				expected[0].UpdatedAt,
+				expected[0].Stock,
 			))
 
 		res, err := q.FindBook(context.Background(), req)
@@ -159,7 +174,7 @@ func TestFindBook(t *testing.T) {
 
 	t.Run("failed query find book", func(t *testing.T) {
 		mockDB.ExpectQuery(regexp.QuoteMeta(findBook)).
-			WithArgs(req.Limit, req.Offset).
+			WithArgs(req.Search, req.Author, req.MinPrice, req.MaxPrice, req.SortBy, req.SortDir, req.Offset, req.Limit).
 			WillReturnError(errQuery)
 
 		res, err := q.FindBook(context.Background(), req)
@@ -169,7 +184,7 @@ func TestFindBook(t *testing.T) {
 
 	t.Run("failed scan find book", func(t *testing.T) {
 		mockDB.ExpectQuery(regexp.QuoteMeta(findBook)).
-			WithArgs(req.Limit, req.Offset).
+			WithArgs(req.Search, req.Author, req.MinPrice, req.MaxPrice, req.SortBy, req.SortDir, req.Offset, req.Limit).
 			WillReturnRows(pgxmock.NewRows([]string{
 				"id",
 				"title",
@@ -177,7 +192,8 @@ func TestFindBook(t *testing.T) {
 				"author",
 				"price",
 				"created_at",
-				"updated_at"}).AddRow(
+				"updated_at",
+				"stock"}).AddRow(
 				1,
 				expected[0].Title,
 				expected[0].Description,
@@ -185,6 +201,7 @@ func TestFindBook(t *testing.T) {
 				expected[0].Price,
 				expected[0].CreatedAt,
 				expected[0].UpdatedAt,
+				expected[0].Stock,
 			))
 
 		res, err := q.FindBook(context.Background(), req)
@@ -201,7 +218,7 @@ func TestFindBookByID(t *testing.T) {
 	title := "Hello"
 	description := "World"
 	author := "Giri Putra Adhittana"
-	price := float64(20)
+	price := decimal.NewFromInt(20)
 	now := time.Now()
 
 	req := uuid.New()
@@ -214,6 +231,7 @@ func TestFindBookByID(t *testing.T) {
 		Price:       price,
 		CreatedAt:   now,
 		UpdatedAt:   now,
+		Stock:       10,
 	}
 
 	t.Run("success query find book by ID", func(t *testing.T) {
@@ -226,7 +244,8 @@ func TestFindBookByID(t *testing.T) {
 				"author",
 				"price",
 				"created_at",
-				"updated_at"}).AddRow(
+				"updated_at",
+				"stock"}).AddRow(
 				expected.ID,
 				expected.Title,
 				expected.Description,
@@ -234,6 +253,7 @@ func TestFindBookByID(t *testing.T) {
 				expected.Price,
 				expected.CreatedAt,
 				expected.UpdatedAt,
+				expected.Stock,
 			))
 
 		res, err := q.FindBookByID(context.Background(), req)
@@ -258,28 +278,557 @@ func TestGetBookCount(t *testing.T) {
 	defer mockDB.Close()
 	q := NewRepository(mockDB)
 
+	req := GetBookCountParams{
+		Search:   "hello",
+		Author:   "Giri",
+		MinPrice: decimal.NullDecimal{},
+		MaxPrice: decimal.NewNullDecimal(decimal.NewFromInt(50)),
+	}
 	expected := int64(10)
 
 	t.Run("success get book count", func(t *testing.T) {
 		mockDB.ExpectQuery(regexp.QuoteMeta(getBookCount)).
+			WithArgs(req.Search, req.Author, req.MinPrice, req.MaxPrice).
 			WillReturnRows(pgxmock.NewRows([]string{
 				"total",
 			}).AddRow(
 				expected,
 			))
 
-		res, err := q.GetBookCount(context.Background())
+		res, err := q.GetBookCount(context.Background(), req)
 		assert.NoError(t, err)
 		assert.Equal(t, expected, res)
 	})
 
 	t.Run("failed query find book by ID", func(t *testing.T) {
 		mockDB.ExpectQuery(regexp.QuoteMeta(getBookCount)).
+			WithArgs(req.Search, req.Author, req.MinPrice, req.MaxPrice).
+			WillReturnError(errQuery)
+
+		res, err := q.GetBookCount(context.Background(), req)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+
+}
+
+func TestUpdateBookByID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	title := "Hello"
+	description := "World"
+	author := "Giri Putra Adhittana"
+	price := decimal.NewFromInt(20)
+	now := time.Now()
+
+	req := UpdateBookByIDParams{
+		ID:          uuid.New(),
+		Title:       title,
+		Description: description,
+		Author:      author,
+		Price:       price,
+	}
+
+	expected := Book{
+		ID:          req.ID,
+		Title:       title,
+		Description: description,
+		Author:      author,
+		Price:       price,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Stock:       10,
+	}
+
+	t.Run("success query update book by ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(updateBookByID)).
+			WithArgs(req.ID, req.Title, req.Description, req.Author, req.Price).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"title",
+				"description",
+				"author",
+				"price",
+				"created_at",
+				"updated_at",
+				"stock"}).AddRow(
+				expected.ID,
+				expected.Title,
+				expected.Description,
+				expected.Author,
+				expected.Price,
+				expected.CreatedAt,
+				expected.UpdatedAt,
+				expected.Stock,
+			))
+
+		res, err := q.UpdateBookByID(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query update book by ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(updateBookByID)).
+			WithArgs(req.ID, req.Title, req.Description, req.Author, req.Price).
+			WillReturnError(errQuery)
+
+		res, err := q.UpdateBookByID(context.Background(), req)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestDeleteBookByID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	bookID := uuid.New()
+
+	t.Run("success query delete book by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(deleteBookByID)).
+			WithArgs(bookID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+		err := q.DeleteBookByID(context.Background(), bookID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query delete book by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(deleteBookByID)).
+			WithArgs(bookID).
+			WillReturnError(errQuery)
+
+		err := q.DeleteBookByID(context.Background(), bookID)
+		assert.Error(t, err)
+	})
+}
+
+func TestCheckBookOrdered(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	bookID := uuid.New()
+
+	t.Run("success query check book ordered", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(checkBookOrdered)).
+			WithArgs(bookID).
+			WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+
+		exists, err := q.CheckBookOrdered(context.Background(), bookID)
+		assert.NoError(t, err)
+		assert.Equal(t, true, exists)
+	})
+
+	t.Run("failed query check book ordered", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(checkBookOrdered)).
+			WithArgs(bookID).
 			WillReturnError(errQuery)
 
-		res, err := q.GetBookCount(context.Background())
+		exists, err := q.CheckBookOrdered(context.Background(), bookID)
+		assert.Error(t, err)
+		assert.Empty(t, exists)
+	})
+}
+
+func TestAdjustBookStock(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	req := AdjustBookStockParams{
+		ID:       uuid.New(),
+		Quantity: -2,
+	}
+
+	expected := Book{
+		ID:          req.ID,
+		Title:       "Hello",
+		Description: "World",
+		Author:      "Giri Putra Adhittana",
+		Price:       decimal.NewFromInt(20),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Stock:       8,
+	}
+
+	t.Run("success query adjust book stock", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(adjustBookStock)).
+			WithArgs(req.Quantity, req.ID).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"title",
+				"description",
+				"author",
+				"price",
+				"created_at",
+				"updated_at",
+				"stock"}).AddRow(
+				expected.ID,
+				expected.Title,
+				expected.Description,
+				expected.Author,
+				expected.Price,
+				expected.CreatedAt,
+				expected.UpdatedAt,
+				expected.Stock,
+			))
+
+		res, err := q.AdjustBookStock(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query adjust book stock", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(adjustBookStock)).
+			WithArgs(req.Quantity, req.ID).
+			WillReturnError(errQuery)
+
+		res, err := q.AdjustBookStock(context.Background(), req)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestCreateStockMovement(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	req := CreateStockMovementParams{
+		BookID:    uuid.New(),
+		Quantity:  5,
+		Reason:    "restock",
+		CreatedBy: uuid.New(),
+	}
+
+	expected := StockMovement{
+		ID:        uuid.New(),
+		BookID:    req.BookID,
+		Quantity:  req.Quantity,
+		Reason:    req.Reason,
+		CreatedBy: req.CreatedBy,
+		CreatedAt: now,
+	}
+
+	t.Run("success query create stock movement", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(createStockMovement)).
+			WithArgs(req.BookID, req.Quantity, req.Reason, req.CreatedBy).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"book_id",
+				"quantity",
+				"reason",
+				"created_by",
+				"created_at"}).AddRow(
+				expected.ID,
+				expected.BookID,
+				expected.Quantity,
+				expected.Reason,
+				expected.CreatedBy,
+				expected.CreatedAt,
+			))
+
+		res, err := q.CreateStockMovement(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query create stock movement", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(createStockMovement)).
+			WithArgs(req.BookID, req.Quantity, req.Reason, req.CreatedBy).
+			WillReturnError(errQuery)
+
+		res, err := q.CreateStockMovement(context.Background(), req)
 		assert.Error(t, err)
 		assert.Empty(t, res)
 	})
+}
 
+func TestFindBooksByIDs(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	expected := []Book{
+		{
+			ID:          uuid.New(),
+			Title:       "Hello",
+			Description: "World",
+			Author:      "Giri Putra Adhittana",
+			Price:       decimal.NewFromInt(20),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			Stock:       10,
+		},
+	}
+	ids := []uuid.UUID{expected[0].ID}
+
+	t.Run("success query find books by IDs", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findBooksByIDs)).
+			WithArgs(ids).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"title",
+				"description",
+				"author",
+				"price",
+				"created_at",
+				"updated_at",
+				"stock"}).AddRow(
+				expected[0].ID,
+				expected[0].Title,
+				expected[0].Description,
+				expected[0].Author,
+				expected[0].Price,
+				expected[0].CreatedAt,
+				expected[0].UpdatedAt,
+				expected[0].Stock,
+			))
+
+		res, err := q.FindBooksByIDs(context.Background(), ids)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query find books by IDs", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findBooksByIDs)).
+			WithArgs(ids).
+			WillReturnError(errQuery)
+
+		res, err := q.FindBooksByIDs(context.Background(), ids)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestReserveBooksStock(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	expected := []Book{
+		{
+			ID:          uuid.New(),
+			Title:       "Hello",
+			Description: "World",
+			Author:      "Giri Putra Adhittana",
+			Price:       decimal.NewFromInt(20),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			Stock:       8,
+		},
+	}
+	req := ReserveBooksStockParams{
+		Ids:        []uuid.UUID{expected[0].ID},
+		Quantities: []int32{2},
+	}
+
+	t.Run("success query reserve books stock", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(reserveBooksStock)).
+			WithArgs(req.Ids, req.Quantities).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"title",
+				"description",
+				"author",
+				"price",
+				"created_at",
+				"updated_at",
+				"stock"}).AddRow(
+				expected[0].ID,
+				expected[0].Title,
+				expected[0].Description,
+				expected[0].Author,
+				expected[0].Price,
+				expected[0].CreatedAt,
+				expected[0].UpdatedAt,
+				expected[0].Stock,
+			))
+
+		res, err := q.ReserveBooksStock(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query reserve books stock", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(reserveBooksStock)).
+			WithArgs(req.Ids, req.Quantities).
+			WillReturnError(errQuery)
+
+		res, err := q.ReserveBooksStock(context.Background(), req)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestCreateStockMovements(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	req := CreateStockMovementsParams{
+		BookIds:    []uuid.UUID{uuid.New(), uuid.New()},
+		Quantities: []int32{-1, -2},
+		Reason:     "order_placed",
+		CreatedBy:  uuid.New(),
+	}
+
+	t.Run("success query create stock movements", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(createStockMovements)).
+			WithArgs(req.BookIds, req.Quantities, req.Reason, req.CreatedBy).
+			WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+		err := q.CreateStockMovements(context.Background(), req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query create stock movements", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(createStockMovements)).
+			WithArgs(req.BookIds, req.Quantities, req.Reason, req.CreatedBy).
+			WillReturnError(errQuery)
+
+		err := q.CreateStockMovements(context.Background(), req)
+		assert.Error(t, err)
+	})
+}
+
+func TestFindBookAfterCursor(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	req := FindBookAfterCursorParams{
+		Search: "hello",
+		CursorCreatedAt: sql.NullTime{
+			Time:  now,
+			Valid: true,
+		},
+		CursorID: uuid.NullUUID{
+			UUID:  uuid.New(),
+			Valid: true,
+		},
+		Limit: 11,
+	}
+
+	expected := []Book{
+		{
+			ID:          uuid.New(),
+			Title:       "Hello",
+			Description: "World",
+			Author:      "Giri Putra Adhittana",
+			Price:       decimal.NewFromInt(20),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			Stock:       10,
+		},
+	}
+
+	t.Run("success query find book after cursor", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findBookAfterCursor)).
+			WithArgs(req.Search, req.Author, req.MinPrice, req.MaxPrice, req.CursorCreatedAt, req.CursorID, req.Limit).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"title",
+				"description",
+				"author",
+				"price",
+				"created_at",
+				"updated_at",
+				"stock"}).AddRow(
+				expected[0].ID,
+				expected[0].Title,
+				expected[0].Description,
+				expected[0].Author,
+				expected[0].Price,
+				expected[0].CreatedAt,
+				expected[0].UpdatedAt,
+				expected[0].Stock,
+			))
+
+		res, err := q.FindBookAfterCursor(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query find book after cursor", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findBookAfterCursor)).
+			WithArgs(req.Search, req.Author, req.MinPrice, req.MaxPrice, req.CursorCreatedAt, req.CursorID, req.Limit).
+			WillReturnError(errQuery)
+
+		res, err := q.FindBookAfterCursor(context.Background(), req)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestFindBookBeforeCursor(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	req := FindBookBeforeCursorParams{
+		Author:          "Giri",
+		CursorCreatedAt: now,
+		CursorID:        uuid.New(),
+		Limit:           11,
+	}
+
+	expected := []Book{
+		{
+			ID:          uuid.New(),
+			Title:       "Hello",
+			Description: "World",
+			Author:      "Giri Putra Adhittana",
+			Price:       decimal.NewFromInt(20),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			Stock:       10,
+		},
+	}
+
+	t.Run("success query find book before cursor", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findBookBeforeCursor)).
+			WithArgs(req.Search, req.Author, req.MinPrice, req.MaxPrice, req.CursorCreatedAt, req.CursorID, req.Limit).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"title",
+				"description",
+				"author",
+				"price",
+				"created_at",
+				"updated_at",
+				"stock"}).AddRow(
+				expected[0].ID,
+				expected[0].Title,
+				expected[0].Description,
+				expected[0].Author,
+				expected[0].Price,
+				expected[0].CreatedAt,
+				expected[0].UpdatedAt,
+				expected[0].Stock,
+			))
+
+		res, err := q.FindBookBeforeCursor(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query find book before cursor", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findBookBeforeCursor)).
+			WithArgs(req.Search, req.Author, req.MinPrice, req.MaxPrice, req.CursorCreatedAt, req.CursorID, req.Limit).
+			WillReturnError(errQuery)
+
+		res, err := q.FindBookBeforeCursor(context.Background(), req)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
 }
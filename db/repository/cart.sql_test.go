@@ -0,0 +1,412 @@
+package querier
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpsertCart(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	userID := uuid.New()
+	expected := Cart{
+		ID:        uuid.New(),
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	t.Run("success query upsert cart", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(upsertCart)).
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"user_id",
+				"created_at",
+				"updated_at"}).AddRow(
+				expected.ID,
+				expected.UserID,
+				expected.CreatedAt,
+				expected.UpdatedAt,
+			))
+
+		res, err := q.UpsertCart(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query upsert cart", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(upsertCart)).
+			WithArgs(userID).
+			WillReturnError(errQuery)
+
+		res, err := q.UpsertCart(context.Background(), userID)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestFindCartByUserID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	userID := uuid.New()
+	expected := Cart{
+		ID:        uuid.New(),
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	t.Run("success query find cart by user ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findCartByUserID)).
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"user_id",
+				"created_at",
+				"updated_at"}).AddRow(
+				expected.ID,
+				expected.UserID,
+				expected.CreatedAt,
+				expected.UpdatedAt,
+			))
+
+		res, err := q.FindCartByUserID(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query find cart by user ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findCartByUserID)).
+			WithArgs(userID).
+			WillReturnError(errQuery)
+
+		res, err := q.FindCartByUserID(context.Background(), userID)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestFindCartByUserIDForUpdate(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	userID := uuid.New()
+	expected := Cart{
+		ID:        uuid.New(),
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	t.Run("success query find cart by user ID for update", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findCartByUserIDForUpdate)).
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"user_id",
+				"created_at",
+				"updated_at"}).AddRow(
+				expected.ID,
+				expected.UserID,
+				expected.CreatedAt,
+				expected.UpdatedAt,
+			))
+
+		res, err := q.FindCartByUserIDForUpdate(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query find cart by user ID for update", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findCartByUserIDForUpdate)).
+			WithArgs(userID).
+			WillReturnError(errQuery)
+
+		res, err := q.FindCartByUserIDForUpdate(context.Background(), userID)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestFindCartItemsByCartID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	cartID := uuid.New()
+	expected := []FindCartItemsByCartIDRow{
+		{
+			BookID:   uuid.New(),
+			Quantity: 2,
+			Title:    "Hello",
+			Author:   "Giri Putra Adhittana",
+			Price:    decimal.NewFromInt(10),
+			Stock:    5,
+		},
+	}
+
+	t.Run("success query find cart items by cart ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findCartItemsByCartID)).
+			WithArgs(cartID).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"book_id",
+				"quantity",
+				"title",
+				"author",
+				"price",
+				"stock"}).AddRow(
+				expected[0].BookID,
+				expected[0].Quantity,
+				expected[0].Title,
+				expected[0].Author,
+				expected[0].Price,
+				expected[0].Stock,
+			))
+
+		res, err := q.FindCartItemsByCartID(context.Background(), cartID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query find cart items by cart ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findCartItemsByCartID)).
+			WithArgs(cartID).
+			WillReturnError(errQuery)
+
+		res, err := q.FindCartItemsByCartID(context.Background(), cartID)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestUpsertCartItem(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	req := UpsertCartItemParams{
+		CartID:   uuid.New(),
+		BookID:   uuid.New(),
+		Quantity: 2,
+	}
+
+	expected := CartItem{
+		ID:        uuid.New(),
+		CartID:    req.CartID,
+		BookID:    req.BookID,
+		Quantity:  req.Quantity,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	t.Run("success query upsert cart item", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(upsertCartItem)).
+			WithArgs(req.CartID, req.BookID, req.Quantity).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"cart_id",
+				"book_id",
+				"quantity",
+				"created_at",
+				"updated_at"}).AddRow(
+				expected.ID,
+				expected.CartID,
+				expected.BookID,
+				expected.Quantity,
+				expected.CreatedAt,
+				expected.UpdatedAt,
+			))
+
+		res, err := q.UpsertCartItem(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query upsert cart item", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(upsertCartItem)).
+			WithArgs(req.CartID, req.BookID, req.Quantity).
+			WillReturnError(errQuery)
+
+		res, err := q.UpsertCartItem(context.Background(), req)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestUpdateCartItemQuantity(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	req := UpdateCartItemQuantityParams{
+		CartID:   uuid.New(),
+		BookID:   uuid.New(),
+		Quantity: 2,
+	}
+
+	expected := CartItem{
+		ID:        uuid.New(),
+		CartID:    req.CartID,
+		BookID:    req.BookID,
+		Quantity:  req.Quantity,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	t.Run("success query update cart item quantity", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(updateCartItemQuantity)).
+			WithArgs(req.CartID, req.BookID, req.Quantity).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"cart_id",
+				"book_id",
+				"quantity",
+				"created_at",
+				"updated_at"}).AddRow(
+				expected.ID,
+				expected.CartID,
+				expected.BookID,
+				expected.Quantity,
+				expected.CreatedAt,
+				expected.UpdatedAt,
+			))
+
+		res, err := q.UpdateCartItemQuantity(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query update cart item quantity", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(updateCartItemQuantity)).
+			WithArgs(req.CartID, req.BookID, req.Quantity).
+			WillReturnError(errQuery)
+
+		res, err := q.UpdateCartItemQuantity(context.Background(), req)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestDeleteCartItem(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	req := DeleteCartItemParams{
+		CartID: uuid.New(),
+		BookID: uuid.New(),
+	}
+
+	expected := CartItem{
+		ID:        uuid.New(),
+		CartID:    req.CartID,
+		BookID:    req.BookID,
+		Quantity:  2,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	t.Run("success query delete cart item", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(deleteCartItem)).
+			WithArgs(req.CartID, req.BookID).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"cart_id",
+				"book_id",
+				"quantity",
+				"created_at",
+				"updated_at"}).AddRow(
+				expected.ID,
+				expected.CartID,
+				expected.BookID,
+				expected.Quantity,
+				expected.CreatedAt,
+				expected.UpdatedAt,
+			))
+
+		res, err := q.DeleteCartItem(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query delete cart item", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(deleteCartItem)).
+			WithArgs(req.CartID, req.BookID).
+			WillReturnError(errQuery)
+
+		res, err := q.DeleteCartItem(context.Background(), req)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestDeleteCartItemsByCartID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	cartID := uuid.New()
+
+	t.Run("success query delete cart items by cart ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(deleteCartItemsByCartID)).
+			WithArgs(cartID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 2))
+
+		err := q.DeleteCartItemsByCartID(context.Background(), cartID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query delete cart items by cart ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(deleteCartItemsByCartID)).
+			WithArgs(cartID).
+			WillReturnError(errQuery)
+
+		err := q.DeleteCartItemsByCartID(context.Background(), cartID)
+		assert.Error(t, err)
+	})
+}
+
+func TestDeleteCartByUserID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	userID := uuid.New()
+
+	t.Run("success query delete cart by user ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(deleteCartByUserID)).
+			WithArgs(userID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+		err := q.DeleteCartByUserID(context.Background(), userID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query delete cart by user ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(deleteCartByUserID)).
+			WithArgs(userID).
+			WillReturnError(errQuery)
+
+		err := q.DeleteCartByUserID(context.Background(), userID)
+		assert.Error(t, err)
+	})
+}
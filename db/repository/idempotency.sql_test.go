@@ -0,0 +1,116 @@
+package querier
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindIdempotencyKey(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	req := FindIdempotencyKeyParams{
+		UserID: uuid.New(),
+		Key:    "retry-key",
+	}
+
+	expected := IdempotencyKey{
+		UserID:      req.UserID,
+		Key:         req.Key,
+		RequestHash: "hash",
+		Response:    []byte(`{"orderId":"1"}`),
+		CreatedAt:   now,
+	}
+
+	t.Run("success query find idempotency key", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findIdempotencyKey)).
+			WithArgs(req.UserID, req.Key).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"user_id",
+				"key",
+				"request_hash",
+				"response",
+				"created_at"}).AddRow(
+				expected.UserID,
+				expected.Key,
+				expected.RequestHash,
+				expected.Response,
+				expected.CreatedAt,
+			))
+
+		res, err := q.FindIdempotencyKey(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query find idempotency key", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findIdempotencyKey)).
+			WithArgs(req.UserID, req.Key).
+			WillReturnError(errQuery)
+
+		res, err := q.FindIdempotencyKey(context.Background(), req)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestCreateIdempotencyKey(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	req := CreateIdempotencyKeyParams{
+		UserID:      uuid.New(),
+		Key:         "retry-key",
+		RequestHash: "hash",
+		Response:    []byte(`{"orderId":"1"}`),
+	}
+
+	expected := IdempotencyKey{
+		UserID:      req.UserID,
+		Key:         req.Key,
+		RequestHash: req.RequestHash,
+		Response:    req.Response,
+		CreatedAt:   now,
+	}
+
+	t.Run("success query create idempotency key", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(createIdempotencyKey)).
+			WithArgs(req.UserID, req.Key, req.RequestHash, req.Response).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"user_id",
+				"key",
+				"request_hash",
+				"response",
+				"created_at"}).AddRow(
+				expected.UserID,
+				expected.Key,
+				expected.RequestHash,
+				expected.Response,
+				expected.CreatedAt,
+			))
+
+		res, err := q.CreateIdempotencyKey(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query create idempotency key", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(createIdempotencyKey)).
+			WithArgs(req.UserID, req.Key, req.RequestHash, req.Response).
+			WillReturnError(errQuery)
+
+		res, err := q.CreateIdempotencyKey(context.Background(), req)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
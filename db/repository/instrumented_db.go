@@ -0,0 +1,137 @@
+package querier
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gadhittana-01/book-go/metrics"
+	"github.com/gadhittana-01/book-go/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const unknownQueryName = "unknown"
+
+// instrumentedDB times and traces every query under the name sqlc writes on
+// its first line, so each Querier method gets its own latency series and
+// span, inside a transaction too.
+type instrumentedDB struct {
+	db DBTX
+}
+
+func newInstrumentedDB(db DBTX) DBTX {
+	return &instrumentedDB{db: db}
+}
+
+func (d *instrumentedDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	ctx, observer := startQuery(ctx, sql)
+	tag, err := d.db.Exec(ctx, sql, args...)
+	observer.finish(err)
+
+	return tag, err
+}
+
+func (d *instrumentedDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	ctx, observer := startQuery(ctx, sql)
+	rows, err := d.db.Query(ctx, sql, args...)
+	if err != nil {
+		observer.finish(err)
+		return nil, err
+	}
+
+	return &instrumentedRows{Rows: rows, observer: observer}, nil
+}
+
+func (d *instrumentedDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	ctx, observer := startQuery(ctx, sql)
+
+	return &instrumentedRow{
+		row:      d.db.QueryRow(ctx, sql, args...),
+		observer: observer,
+	}
+}
+
+// queryObserver follows one query from the call until its result is read.
+type queryObserver struct {
+	name  string
+	start time.Time
+	span  trace.Span
+}
+
+func startQuery(ctx context.Context, sql string) (context.Context, *queryObserver) {
+	name := queryName(sql)
+	ctx, span := tracing.Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationName(name),
+		),
+	)
+
+	return ctx, &queryObserver{
+		name:  name,
+		start: time.Now(),
+		span:  span,
+	}
+}
+
+// finish records the query. A missing row is an expected answer, not a
+// failure.
+func (o *queryObserver) finish(err error) {
+	metrics.ObserveDBQuery(o.name, err, time.Since(o.start))
+
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		tracing.RecordError(o.span, err)
+	}
+	o.span.End()
+}
+
+// instrumentedRows finishes the query when the rows are closed, since the
+// result is streamed while the caller reads it.
+type instrumentedRows struct {
+	pgx.Rows
+	observer *queryObserver
+	once     sync.Once
+}
+
+func (r *instrumentedRows) Close() {
+	r.Rows.Close()
+	r.once.Do(func() {
+		r.observer.finish(r.Rows.Err())
+	})
+}
+
+// instrumentedRow finishes the query on Scan, which is when pgx reads the
+// row.
+type instrumentedRow struct {
+	row      pgx.Row
+	observer *queryObserver
+}
+
+func (r *instrumentedRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	r.observer.finish(err)
+
+	return err
+}
+
+// queryName reads the name from the "-- name: FindUserByID :one" header of a
+// sqlc query.
+func queryName(sql string) string {
+	header, ok := strings.CutPrefix(sql, "-- name: ")
+	if !ok {
+		return unknownQueryName
+	}
+
+	fields := strings.Fields(header)
+	if len(fields) == 0 {
+		return unknownQueryName
+	}
+
+	return fields[0]
+}
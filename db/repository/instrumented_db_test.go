@@ -0,0 +1,106 @@
+package querier
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestQueryName(t *testing.T) {
+	assert.Equal(t, "FindUserByID", queryName(findUserByID))
+	assert.Equal(t, "CreateOrderDetails", queryName(createOrderDetails))
+	assert.Equal(t, unknownQueryName, queryName("SELECT 1"))
+	assert.Equal(t, unknownQueryName, queryName("-- name: "))
+}
+
+func TestInstrumentedDB(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	db := newInstrumentedDB(mockDB)
+
+	t.Run("rows are passed through and closed", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta("SELECT id FROM book")).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+
+		rows, err := db.Query(context.Background(), "SELECT id FROM book")
+		assert.NoError(t, err)
+
+		count := 0
+		for rows.Next() {
+			count++
+		}
+		rows.Close()
+		rows.Close()
+		assert.Equal(t, 2, count)
+		assert.NoError(t, rows.Err())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta("SELECT id FROM book")).WillReturnError(errQuery)
+
+		rows, err := db.Query(context.Background(), "SELECT id FROM book")
+		assert.ErrorIs(t, err, errQuery)
+		assert.Nil(t, rows)
+	})
+
+	t.Run("row scan", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta("SELECT 1")).
+			WillReturnRows(pgxmock.NewRows([]string{"one"}).AddRow(1))
+
+		var one int
+		assert.NoError(t, db.QueryRow(context.Background(), "SELECT 1").Scan(&one))
+		assert.Equal(t, 1, one)
+	})
+
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestInstrumentedDBSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	userID := uuid.New()
+	orderID := uuid.New()
+
+	mockDB.ExpectQuery(regexp.QuoteMeta(checkOrderExists)).
+		WithArgs(orderID).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+	mockDB.ExpectQuery(regexp.QuoteMeta(findOrderByID)).
+		WithArgs(userID, orderID).
+		WillReturnError(pgx.ErrNoRows)
+	mockDB.ExpectExec(regexp.QuoteMeta(deleteCartItemsByCartID)).
+		WithArgs(orderID).
+		WillReturnError(errQuery)
+
+	_, err := q.CheckOrderExists(context.Background(), orderID)
+	assert.NoError(t, err)
+	_, err = q.FindOrderByID(context.Background(), FindOrderByIDParams{
+		UserID: userID,
+		ID:     orderID,
+	})
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+	err = q.DeleteCartItemsByCartID(context.Background(), orderID)
+	assert.ErrorIs(t, err, errQuery)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+	assert.Equal(t, "CheckOrderExists", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.operation.name", "CheckOrderExists"))
+	assert.Equal(t, "FindOrderByID", spans[1].Name())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	assert.Equal(t, "DeleteCartItemsByCartID", spans[2].Name())
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+}
@@ -38,6 +38,35 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AdjustBookStock mocks base method.
+func (m *MockRepository) AdjustBookStock(ctx context.Context, arg querier.AdjustBookStockParams) (querier.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustBookStock", ctx, arg)
+	ret0, _ := ret[0].(querier.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustBookStock indicates an expected call of AdjustBookStock.
+func (mr *MockRepositoryMockRecorder) AdjustBookStock(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustBookStock", reflect.TypeOf((*MockRepository)(nil).AdjustBookStock), ctx, arg)
+}
+
+// AnonymizeUserByID mocks base method.
+func (m *MockRepository) AnonymizeUserByID(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeUserByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnonymizeUserByID indicates an expected call of AnonymizeUserByID.
+func (mr *MockRepositoryMockRecorder) AnonymizeUserByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeUserByID", reflect.TypeOf((*MockRepository)(nil).AnonymizeUserByID), ctx, id)
+}
+
 // CheckBookExists mocks base method.
 func (m *MockRepository) CheckBookExists(ctx context.Context, id uuid.UUID) (bool, error) {
 	m.ctrl.T.Helper()
@@ -53,6 +82,21 @@ func (mr *MockRepositoryMockRecorder) CheckBookExists(ctx, id interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckBookExists", reflect.TypeOf((*MockRepository)(nil).CheckBookExists), ctx, id)
 }
 
+// CheckBookOrdered mocks base method.
+func (m *MockRepository) CheckBookOrdered(ctx context.Context, bookID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckBookOrdered", ctx, bookID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckBookOrdered indicates an expected call of CheckBookOrdered.
+func (mr *MockRepositoryMockRecorder) CheckBookOrdered(ctx, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckBookOrdered", reflect.TypeOf((*MockRepository)(nil).CheckBookOrdered), ctx, bookID)
+}
+
 // CheckEmailExists mocks base method.
 func (m *MockRepository) CheckEmailExists(ctx context.Context, email string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -98,6 +142,21 @@ func (mr *MockRepositoryMockRecorder) CreateBook(ctx, arg interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBook", reflect.TypeOf((*MockRepository)(nil).CreateBook), ctx, arg)
 }
 
+// CreateIdempotencyKey mocks base method.
+func (m *MockRepository) CreateIdempotencyKey(ctx context.Context, arg querier.CreateIdempotencyKeyParams) (querier.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdempotencyKey", ctx, arg)
+	ret0, _ := ret[0].(querier.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIdempotencyKey indicates an expected call of CreateIdempotencyKey.
+func (mr *MockRepositoryMockRecorder) CreateIdempotencyKey(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdempotencyKey", reflect.TypeOf((*MockRepository)(nil).CreateIdempotencyKey), ctx, arg)
+}
+
 // CreateOrder mocks base method.
 func (m *MockRepository) CreateOrder(ctx context.Context, arg querier.CreateOrderParams) (querier.Order, error) {
 	m.ctrl.T.Helper()
@@ -113,19 +172,62 @@ func (mr *MockRepositoryMockRecorder) CreateOrder(ctx, arg interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockRepository)(nil).CreateOrder), ctx, arg)
 }
 
-// CreateOrderDetail mocks base method.
-func (m *MockRepository) CreateOrderDetail(ctx context.Context, arg querier.CreateOrderDetailParams) (querier.OrderDetail, error) {
+// CreateOrderDetails mocks base method.
+func (m *MockRepository) CreateOrderDetails(ctx context.Context, arg querier.CreateOrderDetailsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrderDetails", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrderDetails indicates an expected call of CreateOrderDetails.
+func (mr *MockRepositoryMockRecorder) CreateOrderDetails(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrderDetails", reflect.TypeOf((*MockRepository)(nil).CreateOrderDetails), ctx, arg)
+}
+
+// CreateOrderStatusHistory mocks base method.
+func (m *MockRepository) CreateOrderStatusHistory(ctx context.Context, arg querier.CreateOrderStatusHistoryParams) (querier.OrderStatusHistory, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrderDetail", ctx, arg)
-	ret0, _ := ret[0].(querier.OrderDetail)
+	ret := m.ctrl.Call(m, "CreateOrderStatusHistory", ctx, arg)
+	ret0, _ := ret[0].(querier.OrderStatusHistory)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateOrderDetail indicates an expected call of CreateOrderDetail.
-func (mr *MockRepositoryMockRecorder) CreateOrderDetail(ctx, arg interface{}) *gomock.Call {
+// CreateOrderStatusHistory indicates an expected call of CreateOrderStatusHistory.
+func (mr *MockRepositoryMockRecorder) CreateOrderStatusHistory(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrderStatusHistory", reflect.TypeOf((*MockRepository)(nil).CreateOrderStatusHistory), ctx, arg)
+}
+
+// CreateStockMovement mocks base method.
+func (m *MockRepository) CreateStockMovement(ctx context.Context, arg querier.CreateStockMovementParams) (querier.StockMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStockMovement", ctx, arg)
+	ret0, _ := ret[0].(querier.StockMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStockMovement indicates an expected call of CreateStockMovement.
+func (mr *MockRepositoryMockRecorder) CreateStockMovement(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStockMovement", reflect.TypeOf((*MockRepository)(nil).CreateStockMovement), ctx, arg)
+}
+
+// CreateStockMovements mocks base method.
+func (m *MockRepository) CreateStockMovements(ctx context.Context, arg querier.CreateStockMovementsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStockMovements", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateStockMovements indicates an expected call of CreateStockMovements.
+func (mr *MockRepositoryMockRecorder) CreateStockMovements(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrderDetail", reflect.TypeOf((*MockRepository)(nil).CreateOrderDetail), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStockMovements", reflect.TypeOf((*MockRepository)(nil).CreateStockMovements), ctx, arg)
 }
 
 // CreateUser mocks base method.
@@ -143,6 +245,150 @@ func (mr *MockRepositoryMockRecorder) CreateUser(ctx, arg interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockRepository)(nil).CreateUser), ctx, arg)
 }
 
+// CreateUserRecoveryCodes mocks base method.
+func (m *MockRepository) CreateUserRecoveryCodes(ctx context.Context, arg querier.CreateUserRecoveryCodesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserRecoveryCodes", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUserRecoveryCodes indicates an expected call of CreateUserRecoveryCodes.
+func (mr *MockRepositoryMockRecorder) CreateUserRecoveryCodes(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserRecoveryCodes", reflect.TypeOf((*MockRepository)(nil).CreateUserRecoveryCodes), ctx, arg)
+}
+
+// CreateUserSession mocks base method.
+func (m *MockRepository) CreateUserSession(ctx context.Context, arg querier.CreateUserSessionParams) (querier.UserSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserSession", ctx, arg)
+	ret0, _ := ret[0].(querier.UserSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserSession indicates an expected call of CreateUserSession.
+func (mr *MockRepositoryMockRecorder) CreateUserSession(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserSession", reflect.TypeOf((*MockRepository)(nil).CreateUserSession), ctx, arg)
+}
+
+// CreateUserToken mocks base method.
+func (m *MockRepository) CreateUserToken(ctx context.Context, arg querier.CreateUserTokenParams) (querier.UserToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserToken", ctx, arg)
+	ret0, _ := ret[0].(querier.UserToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserToken indicates an expected call of CreateUserToken.
+func (mr *MockRepositoryMockRecorder) CreateUserToken(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserToken", reflect.TypeOf((*MockRepository)(nil).CreateUserToken), ctx, arg)
+}
+
+// DeleteBookByID mocks base method.
+func (m *MockRepository) DeleteBookByID(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBookByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBookByID indicates an expected call of DeleteBookByID.
+func (mr *MockRepositoryMockRecorder) DeleteBookByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBookByID", reflect.TypeOf((*MockRepository)(nil).DeleteBookByID), ctx, id)
+}
+
+// DeleteCartByUserID mocks base method.
+func (m *MockRepository) DeleteCartByUserID(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCartByUserID", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCartByUserID indicates an expected call of DeleteCartByUserID.
+func (mr *MockRepositoryMockRecorder) DeleteCartByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCartByUserID", reflect.TypeOf((*MockRepository)(nil).DeleteCartByUserID), ctx, userID)
+}
+
+// DeleteCartItem mocks base method.
+func (m *MockRepository) DeleteCartItem(ctx context.Context, arg querier.DeleteCartItemParams) (querier.CartItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCartItem", ctx, arg)
+	ret0, _ := ret[0].(querier.CartItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCartItem indicates an expected call of DeleteCartItem.
+func (mr *MockRepositoryMockRecorder) DeleteCartItem(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCartItem", reflect.TypeOf((*MockRepository)(nil).DeleteCartItem), ctx, arg)
+}
+
+// DeleteCartItemsByCartID mocks base method.
+func (m *MockRepository) DeleteCartItemsByCartID(ctx context.Context, cartID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCartItemsByCartID", ctx, cartID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCartItemsByCartID indicates an expected call of DeleteCartItemsByCartID.
+func (mr *MockRepositoryMockRecorder) DeleteCartItemsByCartID(ctx, cartID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCartItemsByCartID", reflect.TypeOf((*MockRepository)(nil).DeleteCartItemsByCartID), ctx, cartID)
+}
+
+// DeleteUserRecoveryCodesByUserID mocks base method.
+func (m *MockRepository) DeleteUserRecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserRecoveryCodesByUserID", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserRecoveryCodesByUserID indicates an expected call of DeleteUserRecoveryCodesByUserID.
+func (mr *MockRepositoryMockRecorder) DeleteUserRecoveryCodesByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserRecoveryCodesByUserID", reflect.TypeOf((*MockRepository)(nil).DeleteUserRecoveryCodesByUserID), ctx, userID)
+}
+
+// EnableUserTotpByID mocks base method.
+func (m *MockRepository) EnableUserTotpByID(ctx context.Context, arg querier.EnableUserTotpByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableUserTotpByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableUserTotpByID indicates an expected call of EnableUserTotpByID.
+func (mr *MockRepositoryMockRecorder) EnableUserTotpByID(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableUserTotpByID", reflect.TypeOf((*MockRepository)(nil).EnableUserTotpByID), ctx, arg)
+}
+
+// FindAllOrderByUserID mocks base method.
+func (m *MockRepository) FindAllOrderByUserID(ctx context.Context, userID uuid.UUID) ([]querier.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllOrderByUserID", ctx, userID)
+	ret0, _ := ret[0].([]querier.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllOrderByUserID indicates an expected call of FindAllOrderByUserID.
+func (mr *MockRepositoryMockRecorder) FindAllOrderByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllOrderByUserID", reflect.TypeOf((*MockRepository)(nil).FindAllOrderByUserID), ctx, userID)
+}
+
 // FindBook mocks base method.
 func (m *MockRepository) FindBook(ctx context.Context, arg querier.FindBookParams) ([]querier.Book, error) {
 	m.ctrl.T.Helper()
@@ -158,6 +404,36 @@ func (mr *MockRepositoryMockRecorder) FindBook(ctx, arg interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBook", reflect.TypeOf((*MockRepository)(nil).FindBook), ctx, arg)
 }
 
+// FindBookAfterCursor mocks base method.
+func (m *MockRepository) FindBookAfterCursor(ctx context.Context, arg querier.FindBookAfterCursorParams) ([]querier.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBookAfterCursor", ctx, arg)
+	ret0, _ := ret[0].([]querier.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBookAfterCursor indicates an expected call of FindBookAfterCursor.
+func (mr *MockRepositoryMockRecorder) FindBookAfterCursor(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBookAfterCursor", reflect.TypeOf((*MockRepository)(nil).FindBookAfterCursor), ctx, arg)
+}
+
+// FindBookBeforeCursor mocks base method.
+func (m *MockRepository) FindBookBeforeCursor(ctx context.Context, arg querier.FindBookBeforeCursorParams) ([]querier.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBookBeforeCursor", ctx, arg)
+	ret0, _ := ret[0].([]querier.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBookBeforeCursor indicates an expected call of FindBookBeforeCursor.
+func (mr *MockRepositoryMockRecorder) FindBookBeforeCursor(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBookBeforeCursor", reflect.TypeOf((*MockRepository)(nil).FindBookBeforeCursor), ctx, arg)
+}
+
 // FindBookByID mocks base method.
 func (m *MockRepository) FindBookByID(ctx context.Context, id uuid.UUID) (querier.Book, error) {
 	m.ctrl.T.Helper()
@@ -173,6 +449,81 @@ func (mr *MockRepositoryMockRecorder) FindBookByID(ctx, id interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBookByID", reflect.TypeOf((*MockRepository)(nil).FindBookByID), ctx, id)
 }
 
+// FindBooksByIDs mocks base method.
+func (m *MockRepository) FindBooksByIDs(ctx context.Context, ids []uuid.UUID) ([]querier.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBooksByIDs", ctx, ids)
+	ret0, _ := ret[0].([]querier.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBooksByIDs indicates an expected call of FindBooksByIDs.
+func (mr *MockRepositoryMockRecorder) FindBooksByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBooksByIDs", reflect.TypeOf((*MockRepository)(nil).FindBooksByIDs), ctx, ids)
+}
+
+// FindCartByUserID mocks base method.
+func (m *MockRepository) FindCartByUserID(ctx context.Context, userID uuid.UUID) (querier.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCartByUserID", ctx, userID)
+	ret0, _ := ret[0].(querier.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCartByUserID indicates an expected call of FindCartByUserID.
+func (mr *MockRepositoryMockRecorder) FindCartByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCartByUserID", reflect.TypeOf((*MockRepository)(nil).FindCartByUserID), ctx, userID)
+}
+
+// FindCartByUserIDForUpdate mocks base method.
+func (m *MockRepository) FindCartByUserIDForUpdate(ctx context.Context, userID uuid.UUID) (querier.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCartByUserIDForUpdate", ctx, userID)
+	ret0, _ := ret[0].(querier.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCartByUserIDForUpdate indicates an expected call of FindCartByUserIDForUpdate.
+func (mr *MockRepositoryMockRecorder) FindCartByUserIDForUpdate(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCartByUserIDForUpdate", reflect.TypeOf((*MockRepository)(nil).FindCartByUserIDForUpdate), ctx, userID)
+}
+
+// FindCartItemsByCartID mocks base method.
+func (m *MockRepository) FindCartItemsByCartID(ctx context.Context, cartID uuid.UUID) ([]querier.FindCartItemsByCartIDRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCartItemsByCartID", ctx, cartID)
+	ret0, _ := ret[0].([]querier.FindCartItemsByCartIDRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCartItemsByCartID indicates an expected call of FindCartItemsByCartID.
+func (mr *MockRepositoryMockRecorder) FindCartItemsByCartID(ctx, cartID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCartItemsByCartID", reflect.TypeOf((*MockRepository)(nil).FindCartItemsByCartID), ctx, cartID)
+}
+
+// FindIdempotencyKey mocks base method.
+func (m *MockRepository) FindIdempotencyKey(ctx context.Context, arg querier.FindIdempotencyKeyParams) (querier.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindIdempotencyKey", ctx, arg)
+	ret0, _ := ret[0].(querier.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindIdempotencyKey indicates an expected call of FindIdempotencyKey.
+func (mr *MockRepositoryMockRecorder) FindIdempotencyKey(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdempotencyKey", reflect.TypeOf((*MockRepository)(nil).FindIdempotencyKey), ctx, arg)
+}
+
 // FindOrderByID mocks base method.
 func (m *MockRepository) FindOrderByID(ctx context.Context, arg querier.FindOrderByIDParams) (querier.Order, error) {
 	m.ctrl.T.Helper()
@@ -188,6 +539,21 @@ func (mr *MockRepositoryMockRecorder) FindOrderByID(ctx, arg interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderByID", reflect.TypeOf((*MockRepository)(nil).FindOrderByID), ctx, arg)
 }
 
+// FindOrderByIDForUpdate mocks base method.
+func (m *MockRepository) FindOrderByIDForUpdate(ctx context.Context, id uuid.UUID) (querier.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrderByIDForUpdate", ctx, id)
+	ret0, _ := ret[0].(querier.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrderByIDForUpdate indicates an expected call of FindOrderByIDForUpdate.
+func (mr *MockRepositoryMockRecorder) FindOrderByIDForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderByIDForUpdate", reflect.TypeOf((*MockRepository)(nil).FindOrderByIDForUpdate), ctx, id)
+}
+
 // FindOrderByUserID mocks base method.
 func (m *MockRepository) FindOrderByUserID(ctx context.Context, arg querier.FindOrderByUserIDParams) ([]querier.Order, error) {
 	m.ctrl.T.Helper()
@@ -203,6 +569,36 @@ func (mr *MockRepositoryMockRecorder) FindOrderByUserID(ctx, arg interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderByUserID", reflect.TypeOf((*MockRepository)(nil).FindOrderByUserID), ctx, arg)
 }
 
+// FindOrderByUserIDAfterCursor mocks base method.
+func (m *MockRepository) FindOrderByUserIDAfterCursor(ctx context.Context, arg querier.FindOrderByUserIDAfterCursorParams) ([]querier.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrderByUserIDAfterCursor", ctx, arg)
+	ret0, _ := ret[0].([]querier.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrderByUserIDAfterCursor indicates an expected call of FindOrderByUserIDAfterCursor.
+func (mr *MockRepositoryMockRecorder) FindOrderByUserIDAfterCursor(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderByUserIDAfterCursor", reflect.TypeOf((*MockRepository)(nil).FindOrderByUserIDAfterCursor), ctx, arg)
+}
+
+// FindOrderByUserIDBeforeCursor mocks base method.
+func (m *MockRepository) FindOrderByUserIDBeforeCursor(ctx context.Context, arg querier.FindOrderByUserIDBeforeCursorParams) ([]querier.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrderByUserIDBeforeCursor", ctx, arg)
+	ret0, _ := ret[0].([]querier.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrderByUserIDBeforeCursor indicates an expected call of FindOrderByUserIDBeforeCursor.
+func (mr *MockRepositoryMockRecorder) FindOrderByUserIDBeforeCursor(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderByUserIDBeforeCursor", reflect.TypeOf((*MockRepository)(nil).FindOrderByUserIDBeforeCursor), ctx, arg)
+}
+
 // FindOrderDetailByOrderID mocks base method.
 func (m *MockRepository) FindOrderDetailByOrderID(ctx context.Context, arg querier.FindOrderDetailByOrderIDParams) ([]querier.FindOrderDetailByOrderIDRow, error) {
 	m.ctrl.T.Helper()
@@ -218,6 +614,36 @@ func (mr *MockRepositoryMockRecorder) FindOrderDetailByOrderID(ctx, arg interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderDetailByOrderID", reflect.TypeOf((*MockRepository)(nil).FindOrderDetailByOrderID), ctx, arg)
 }
 
+// FindOrderItemsByOrderID mocks base method.
+func (m *MockRepository) FindOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID) ([]querier.OrderDetail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrderItemsByOrderID", ctx, orderID)
+	ret0, _ := ret[0].([]querier.OrderDetail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrderItemsByOrderID indicates an expected call of FindOrderItemsByOrderID.
+func (mr *MockRepositoryMockRecorder) FindOrderItemsByOrderID(ctx, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderItemsByOrderID", reflect.TypeOf((*MockRepository)(nil).FindOrderItemsByOrderID), ctx, orderID)
+}
+
+// FindOrderItemsByUserID mocks base method.
+func (m *MockRepository) FindOrderItemsByUserID(ctx context.Context, userID uuid.UUID) ([]querier.FindOrderItemsByUserIDRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrderItemsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]querier.FindOrderItemsByUserIDRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrderItemsByUserID indicates an expected call of FindOrderItemsByUserID.
+func (mr *MockRepositoryMockRecorder) FindOrderItemsByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderItemsByUserID", reflect.TypeOf((*MockRepository)(nil).FindOrderItemsByUserID), ctx, userID)
+}
+
 // FindUserByEmail mocks base method.
 func (m *MockRepository) FindUserByEmail(ctx context.Context, email string) (querier.User, error) {
 	m.ctrl.T.Helper()
@@ -233,19 +659,79 @@ func (mr *MockRepositoryMockRecorder) FindUserByEmail(ctx, email interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserByEmail", reflect.TypeOf((*MockRepository)(nil).FindUserByEmail), ctx, email)
 }
 
+// FindUserByID mocks base method.
+func (m *MockRepository) FindUserByID(ctx context.Context, id uuid.UUID) (querier.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserByID", ctx, id)
+	ret0, _ := ret[0].(querier.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserByID indicates an expected call of FindUserByID.
+func (mr *MockRepositoryMockRecorder) FindUserByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserByID", reflect.TypeOf((*MockRepository)(nil).FindUserByID), ctx, id)
+}
+
+// FindUserRoleByID mocks base method.
+func (m *MockRepository) FindUserRoleByID(ctx context.Context, id uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserRoleByID", ctx, id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserRoleByID indicates an expected call of FindUserRoleByID.
+func (mr *MockRepositoryMockRecorder) FindUserRoleByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserRoleByID", reflect.TypeOf((*MockRepository)(nil).FindUserRoleByID), ctx, id)
+}
+
+// FindUserSessionByRefreshTokenHashForUpdate mocks base method.
+func (m *MockRepository) FindUserSessionByRefreshTokenHashForUpdate(ctx context.Context, refreshTokenHash string) (querier.UserSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserSessionByRefreshTokenHashForUpdate", ctx, refreshTokenHash)
+	ret0, _ := ret[0].(querier.UserSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserSessionByRefreshTokenHashForUpdate indicates an expected call of FindUserSessionByRefreshTokenHashForUpdate.
+func (mr *MockRepositoryMockRecorder) FindUserSessionByRefreshTokenHashForUpdate(ctx, refreshTokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserSessionByRefreshTokenHashForUpdate", reflect.TypeOf((*MockRepository)(nil).FindUserSessionByRefreshTokenHashForUpdate), ctx, refreshTokenHash)
+}
+
+// FindUserTokenByHashForUpdate mocks base method.
+func (m *MockRepository) FindUserTokenByHashForUpdate(ctx context.Context, arg querier.FindUserTokenByHashForUpdateParams) (querier.UserToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserTokenByHashForUpdate", ctx, arg)
+	ret0, _ := ret[0].(querier.UserToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserTokenByHashForUpdate indicates an expected call of FindUserTokenByHashForUpdate.
+func (mr *MockRepositoryMockRecorder) FindUserTokenByHashForUpdate(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserTokenByHashForUpdate", reflect.TypeOf((*MockRepository)(nil).FindUserTokenByHashForUpdate), ctx, arg)
+}
+
 // GetBookCount mocks base method.
-func (m *MockRepository) GetBookCount(ctx context.Context) (int64, error) {
+func (m *MockRepository) GetBookCount(ctx context.Context, arg querier.GetBookCountParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBookCount", ctx)
+	ret := m.ctrl.Call(m, "GetBookCount", ctx, arg)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBookCount indicates an expected call of GetBookCount.
-func (mr *MockRepositoryMockRecorder) GetBookCount(ctx interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetBookCount(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookCount", reflect.TypeOf((*MockRepository)(nil).GetBookCount), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookCount", reflect.TypeOf((*MockRepository)(nil).GetBookCount), ctx, arg)
 }
 
 // GetBookPurchasedByUserID mocks base method.
@@ -292,6 +778,150 @@ func (mr *MockRepositoryMockRecorder) GetOrderCountByUserId(ctx, userID interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderCountByUserId", reflect.TypeOf((*MockRepository)(nil).GetOrderCountByUserId), ctx, userID)
 }
 
+// InvalidateUserTokens mocks base method.
+func (m *MockRepository) InvalidateUserTokens(ctx context.Context, arg querier.InvalidateUserTokensParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateUserTokens", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateUserTokens indicates an expected call of InvalidateUserTokens.
+func (mr *MockRepositoryMockRecorder) InvalidateUserTokens(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateUserTokens", reflect.TypeOf((*MockRepository)(nil).InvalidateUserTokens), ctx, arg)
+}
+
+// IsUserEmailVerified mocks base method.
+func (m *MockRepository) IsUserEmailVerified(ctx context.Context, id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsUserEmailVerified", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsUserEmailVerified indicates an expected call of IsUserEmailVerified.
+func (mr *MockRepositoryMockRecorder) IsUserEmailVerified(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsUserEmailVerified", reflect.TypeOf((*MockRepository)(nil).IsUserEmailVerified), ctx, id)
+}
+
+// MarkUserTokenUsedByID mocks base method.
+func (m *MockRepository) MarkUserTokenUsedByID(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkUserTokenUsedByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkUserTokenUsedByID indicates an expected call of MarkUserTokenUsedByID.
+func (mr *MockRepositoryMockRecorder) MarkUserTokenUsedByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkUserTokenUsedByID", reflect.TypeOf((*MockRepository)(nil).MarkUserTokenUsedByID), ctx, id)
+}
+
+// ReserveBooksStock mocks base method.
+func (m *MockRepository) ReserveBooksStock(ctx context.Context, arg querier.ReserveBooksStockParams) ([]querier.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveBooksStock", ctx, arg)
+	ret0, _ := ret[0].([]querier.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveBooksStock indicates an expected call of ReserveBooksStock.
+func (mr *MockRepositoryMockRecorder) ReserveBooksStock(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveBooksStock", reflect.TypeOf((*MockRepository)(nil).ReserveBooksStock), ctx, arg)
+}
+
+// RevokeUserSessionByID mocks base method.
+func (m *MockRepository) RevokeUserSessionByID(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserSessionByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserSessionByID indicates an expected call of RevokeUserSessionByID.
+func (mr *MockRepositoryMockRecorder) RevokeUserSessionByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserSessionByID", reflect.TypeOf((*MockRepository)(nil).RevokeUserSessionByID), ctx, id)
+}
+
+// RevokeUserSessionByRefreshTokenHash mocks base method.
+func (m *MockRepository) RevokeUserSessionByRefreshTokenHash(ctx context.Context, arg querier.RevokeUserSessionByRefreshTokenHashParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserSessionByRefreshTokenHash", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserSessionByRefreshTokenHash indicates an expected call of RevokeUserSessionByRefreshTokenHash.
+func (mr *MockRepositoryMockRecorder) RevokeUserSessionByRefreshTokenHash(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserSessionByRefreshTokenHash", reflect.TypeOf((*MockRepository)(nil).RevokeUserSessionByRefreshTokenHash), ctx, arg)
+}
+
+// RevokeUserSessionsByUserID mocks base method.
+func (m *MockRepository) RevokeUserSessionsByUserID(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserSessionsByUserID", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserSessionsByUserID indicates an expected call of RevokeUserSessionsByUserID.
+func (mr *MockRepositoryMockRecorder) RevokeUserSessionsByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserSessionsByUserID", reflect.TypeOf((*MockRepository)(nil).RevokeUserSessionsByUserID), ctx, userID)
+}
+
+// SetUserTotpSecretByID mocks base method.
+func (m *MockRepository) SetUserTotpSecretByID(ctx context.Context, arg querier.SetUserTotpSecretByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserTotpSecretByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserTotpSecretByID indicates an expected call of SetUserTotpSecretByID.
+func (mr *MockRepositoryMockRecorder) SetUserTotpSecretByID(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserTotpSecretByID", reflect.TypeOf((*MockRepository)(nil).SetUserTotpSecretByID), ctx, arg)
+}
+
+// UpdateBookByID mocks base method.
+func (m *MockRepository) UpdateBookByID(ctx context.Context, arg querier.UpdateBookByIDParams) (querier.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBookByID", ctx, arg)
+	ret0, _ := ret[0].(querier.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBookByID indicates an expected call of UpdateBookByID.
+func (mr *MockRepositoryMockRecorder) UpdateBookByID(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBookByID", reflect.TypeOf((*MockRepository)(nil).UpdateBookByID), ctx, arg)
+}
+
+// UpdateCartItemQuantity mocks base method.
+func (m *MockRepository) UpdateCartItemQuantity(ctx context.Context, arg querier.UpdateCartItemQuantityParams) (querier.CartItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCartItemQuantity", ctx, arg)
+	ret0, _ := ret[0].(querier.CartItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCartItemQuantity indicates an expected call of UpdateCartItemQuantity.
+func (mr *MockRepositoryMockRecorder) UpdateCartItemQuantity(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCartItemQuantity", reflect.TypeOf((*MockRepository)(nil).UpdateCartItemQuantity), ctx, arg)
+}
+
 // UpdateOrderByID mocks base method.
 func (m *MockRepository) UpdateOrderByID(ctx context.Context, arg querier.UpdateOrderByIDParams) (querier.Order, error) {
 	m.ctrl.T.Helper()
@@ -307,6 +937,138 @@ func (mr *MockRepositoryMockRecorder) UpdateOrderByID(ctx, arg interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderByID", reflect.TypeOf((*MockRepository)(nil).UpdateOrderByID), ctx, arg)
 }
 
+// UpdateOrderStatusByID mocks base method.
+func (m *MockRepository) UpdateOrderStatusByID(ctx context.Context, arg querier.UpdateOrderStatusByIDParams) (querier.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatusByID", ctx, arg)
+	ret0, _ := ret[0].(querier.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrderStatusByID indicates an expected call of UpdateOrderStatusByID.
+func (mr *MockRepositoryMockRecorder) UpdateOrderStatusByID(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatusByID", reflect.TypeOf((*MockRepository)(nil).UpdateOrderStatusByID), ctx, arg)
+}
+
+// UpdateUserPasswordByID mocks base method.
+func (m *MockRepository) UpdateUserPasswordByID(ctx context.Context, arg querier.UpdateUserPasswordByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPasswordByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserPasswordByID indicates an expected call of UpdateUserPasswordByID.
+func (mr *MockRepositoryMockRecorder) UpdateUserPasswordByID(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPasswordByID", reflect.TypeOf((*MockRepository)(nil).UpdateUserPasswordByID), ctx, arg)
+}
+
+// UpdateUserProfileByID mocks base method.
+func (m *MockRepository) UpdateUserProfileByID(ctx context.Context, arg querier.UpdateUserProfileByIDParams) (querier.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserProfileByID", ctx, arg)
+	ret0, _ := ret[0].(querier.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserProfileByID indicates an expected call of UpdateUserProfileByID.
+func (mr *MockRepositoryMockRecorder) UpdateUserProfileByID(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserProfileByID", reflect.TypeOf((*MockRepository)(nil).UpdateUserProfileByID), ctx, arg)
+}
+
+// UpdateUserRoleByID mocks base method.
+func (m *MockRepository) UpdateUserRoleByID(ctx context.Context, arg querier.UpdateUserRoleByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRoleByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserRoleByID indicates an expected call of UpdateUserRoleByID.
+func (mr *MockRepositoryMockRecorder) UpdateUserRoleByID(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRoleByID", reflect.TypeOf((*MockRepository)(nil).UpdateUserRoleByID), ctx, arg)
+}
+
+// UpdateUserTotpLastStepByID mocks base method.
+func (m *MockRepository) UpdateUserTotpLastStepByID(ctx context.Context, arg querier.UpdateUserTotpLastStepByIDParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserTotpLastStepByID", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserTotpLastStepByID indicates an expected call of UpdateUserTotpLastStepByID.
+func (mr *MockRepositoryMockRecorder) UpdateUserTotpLastStepByID(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserTotpLastStepByID", reflect.TypeOf((*MockRepository)(nil).UpdateUserTotpLastStepByID), ctx, arg)
+}
+
+// UpsertCart mocks base method.
+func (m *MockRepository) UpsertCart(ctx context.Context, userID uuid.UUID) (querier.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCart", ctx, userID)
+	ret0, _ := ret[0].(querier.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertCart indicates an expected call of UpsertCart.
+func (mr *MockRepositoryMockRecorder) UpsertCart(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCart", reflect.TypeOf((*MockRepository)(nil).UpsertCart), ctx, userID)
+}
+
+// UpsertCartItem mocks base method.
+func (m *MockRepository) UpsertCartItem(ctx context.Context, arg querier.UpsertCartItemParams) (querier.CartItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCartItem", ctx, arg)
+	ret0, _ := ret[0].(querier.CartItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertCartItem indicates an expected call of UpsertCartItem.
+func (mr *MockRepositoryMockRecorder) UpsertCartItem(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCartItem", reflect.TypeOf((*MockRepository)(nil).UpsertCartItem), ctx, arg)
+}
+
+// UseUserRecoveryCode mocks base method.
+func (m *MockRepository) UseUserRecoveryCode(ctx context.Context, arg querier.UseUserRecoveryCodeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseUserRecoveryCode", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseUserRecoveryCode indicates an expected call of UseUserRecoveryCode.
+func (mr *MockRepositoryMockRecorder) UseUserRecoveryCode(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseUserRecoveryCode", reflect.TypeOf((*MockRepository)(nil).UseUserRecoveryCode), ctx, arg)
+}
+
+// VerifyUserEmailByID mocks base method.
+func (m *MockRepository) VerifyUserEmailByID(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyUserEmailByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyUserEmailByID indicates an expected call of VerifyUserEmailByID.
+func (mr *MockRepositoryMockRecorder) VerifyUserEmailByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyUserEmailByID", reflect.TypeOf((*MockRepository)(nil).VerifyUserEmailByID), ctx, id)
+}
+
 // WithTx mocks base method.
 func (m *MockRepository) WithTx(tx pgx.Tx) querier.Querier {
 	m.ctrl.T.Helper()
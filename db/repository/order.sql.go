@@ -7,9 +7,11 @@ package querier
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 const checkOrderExists = `-- name: CheckOrderExists :one
@@ -29,9 +31,9 @@ INSERT INTO "order"(user_id, date, total_price) VALUES
 `
 
 type CreateOrderParams struct {
-	UserID     uuid.UUID `json:"user_id"`
-	Date       time.Time `json:"date"`
-	TotalPrice float64   `json:"total_price"`
+	UserID     uuid.UUID       `json:"user_id"`
+	Date       time.Time       `json:"date"`
+	TotalPrice decimal.Decimal `json:"total_price"`
 }
 
 func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error) {
@@ -49,31 +51,101 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 	return i, err
 }
 
-const createOrderDetail = `-- name: CreateOrderDetail :one
-INSERT INTO "order_detail"(order_id, book_id, quantity) VALUES
-($1, $2, $3) RETURNING id, order_id, book_id, quantity, created_at, updated_at
+const createOrderDetails = `-- name: CreateOrderDetails :exec
+INSERT INTO "order_detail"(order_id, book_id, quantity, unit_price, line_total, title, author)
+SELECT $1::UUID, UNNEST($2::UUID[]), UNNEST($3::INT[]),
+    UNNEST($4::DECIMAL[]), UNNEST($5::DECIMAL[]),
+    UNNEST($6::VARCHAR[]), UNNEST($7::VARCHAR[])
 `
 
-type CreateOrderDetailParams struct {
-	OrderID  uuid.UUID `json:"order_id"`
-	BookID   uuid.UUID `json:"book_id"`
-	Quantity int32     `json:"quantity"`
+type CreateOrderDetailsParams struct {
+	OrderID    uuid.UUID         `json:"order_id"`
+	BookIds    []uuid.UUID       `json:"book_ids"`
+	Quantities []int32           `json:"quantities"`
+	UnitPrices []decimal.Decimal `json:"unit_prices"`
+	LineTotals []decimal.Decimal `json:"line_totals"`
+	Titles     []string          `json:"titles"`
+	Authors    []string          `json:"authors"`
+}
+
+func (q *Queries) CreateOrderDetails(ctx context.Context, arg CreateOrderDetailsParams) error {
+	_, err := q.db.Exec(ctx, createOrderDetails,
+		arg.OrderID,
+		arg.BookIds,
+		arg.Quantities,
+		arg.UnitPrices,
+		arg.LineTotals,
+		arg.Titles,
+		arg.Authors,
+	)
+	return err
 }
 
-func (q *Queries) CreateOrderDetail(ctx context.Context, arg CreateOrderDetailParams) (OrderDetail, error) {
-	row := q.db.QueryRow(ctx, createOrderDetail, arg.OrderID, arg.BookID, arg.Quantity)
-	var i OrderDetail
+const createOrderStatusHistory = `-- name: CreateOrderStatusHistory :one
+INSERT INTO "order_status_history"(order_id, from_status, to_status, changed_by) VALUES
+($1, $2, $3, $4) RETURNING id, order_id, from_status, to_status, changed_by, created_at
+`
+
+type CreateOrderStatusHistoryParams struct {
+	OrderID    uuid.UUID `json:"order_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	ChangedBy  uuid.UUID `json:"changed_by"`
+}
+
+func (q *Queries) CreateOrderStatusHistory(ctx context.Context, arg CreateOrderStatusHistoryParams) (OrderStatusHistory, error) {
+	row := q.db.QueryRow(ctx, createOrderStatusHistory,
+		arg.OrderID,
+		arg.FromStatus,
+		arg.ToStatus,
+		arg.ChangedBy,
+	)
+	var i OrderStatusHistory
 	err := row.Scan(
 		&i.ID,
 		&i.OrderID,
-		&i.BookID,
-		&i.Quantity,
+		&i.FromStatus,
+		&i.ToStatus,
+		&i.ChangedBy,
 		&i.CreatedAt,
-		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const findAllOrderByUserID = `-- name: FindAllOrderByUserID :many
+SELECT id, user_id, date, total_price, status, created_at, updated_at FROM "order"
+WHERE user_id=$1
+ORDER BY date DESC, id DESC
+`
+
+func (q *Queries) FindAllOrderByUserID(ctx context.Context, userID uuid.UUID) ([]Order, error) {
+	rows, err := q.db.Query(ctx, findAllOrderByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Order{}
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Date,
+			&i.TotalPrice,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const findOrderByID = `-- name: FindOrderByID :one
 SELECT id, user_id, date, total_price, status, created_at, updated_at FROM "order" AS o
 WHERE o.user_id=$1 AND o.id=$2
@@ -99,6 +171,25 @@ func (q *Queries) FindOrderByID(ctx context.Context, arg FindOrderByIDParams) (O
 	return i, err
 }
 
+const findOrderByIDForUpdate = `-- name: FindOrderByIDForUpdate :one
+SELECT id, user_id, date, total_price, status, created_at, updated_at FROM "order" WHERE id=$1 FOR UPDATE
+`
+
+func (q *Queries) FindOrderByIDForUpdate(ctx context.Context, id uuid.UUID) (Order, error) {
+	row := q.db.QueryRow(ctx, findOrderByIDForUpdate, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Date,
+		&i.TotalPrice,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const findOrderByUserID = `-- name: FindOrderByUserID :many
 SELECT id, user_id, date, total_price, status, created_at, updated_at FROM "order" AS o
 WHERE o.user_id=$1
@@ -140,11 +231,108 @@ func (q *Queries) FindOrderByUserID(ctx context.Context, arg FindOrderByUserIDPa
 	return items, nil
 }
 
+const findOrderByUserIDAfterCursor = `-- name: FindOrderByUserIDAfterCursor :many
+SELECT id, user_id, date, total_price, status, created_at, updated_at FROM "order" AS o
+WHERE o.user_id=$1
+AND ($2::TIMESTAMPTZ IS NULL
+    OR (o.date, o.id) < ($2::TIMESTAMPTZ, $3::UUID))
+ORDER BY o.date DESC, o.id DESC
+LIMIT $4
+`
+
+type FindOrderByUserIDAfterCursorParams struct {
+	UserID     uuid.UUID     `json:"user_id"`
+	CursorDate sql.NullTime  `json:"cursor_date"`
+	CursorID   uuid.NullUUID `json:"cursor_id"`
+	Limit      int32         `json:"limit"`
+}
+
+func (q *Queries) FindOrderByUserIDAfterCursor(ctx context.Context, arg FindOrderByUserIDAfterCursorParams) ([]Order, error) {
+	rows, err := q.db.Query(ctx, findOrderByUserIDAfterCursor,
+		arg.UserID,
+		arg.CursorDate,
+		arg.CursorID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Order{}
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Date,
+			&i.TotalPrice,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findOrderByUserIDBeforeCursor = `-- name: FindOrderByUserIDBeforeCursor :many
+SELECT id, user_id, date, total_price, status, created_at, updated_at FROM "order" AS o
+WHERE o.user_id=$1
+AND (o.date, o.id) > ($2::TIMESTAMPTZ, $3::UUID)
+ORDER BY o.date ASC, o.id ASC
+LIMIT $4
+`
+
+type FindOrderByUserIDBeforeCursorParams struct {
+	UserID     uuid.UUID `json:"user_id"`
+	CursorDate time.Time `json:"cursor_date"`
+	CursorID   uuid.UUID `json:"cursor_id"`
+	Limit      int32     `json:"limit"`
+}
+
+func (q *Queries) FindOrderByUserIDBeforeCursor(ctx context.Context, arg FindOrderByUserIDBeforeCursorParams) ([]Order, error) {
+	rows, err := q.db.Query(ctx, findOrderByUserIDBeforeCursor,
+		arg.UserID,
+		arg.CursorDate,
+		arg.CursorID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Order{}
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Date,
+			&i.TotalPrice,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const findOrderDetailByOrderID = `-- name: FindOrderDetailByOrderID :many
 SELECT 
-    o.id, o.date, od.book_id, b.title,
+    o.id, o.date, od.book_id, od.title,
     o.total_price, o.status, b.description, 
-    b.author, od.quantity, b.price
+    od.author, od.quantity, od.unit_price, od.line_total
 FROM "order" AS o
 JOIN "order_detail" od 
 ON o.id = od.order_id JOIN "book" AS b
@@ -158,16 +346,17 @@ type FindOrderDetailByOrderIDParams struct {
 }
 
 type FindOrderDetailByOrderIDRow struct {
-	ID          uuid.UUID `json:"id"`
-	Date        time.Time `json:"date"`
-	BookID      uuid.UUID `json:"book_id"`
-	Title       string    `json:"title"`
-	TotalPrice  float64   `json:"total_price"`
-	Status      string    `json:"status"`
-	Description string    `json:"description"`
-	Author      string    `json:"author"`
-	Quantity    int32     `json:"quantity"`
-	Price       float64   `json:"price"`
+	ID          uuid.UUID       `json:"id"`
+	Date        time.Time       `json:"date"`
+	BookID      uuid.UUID       `json:"book_id"`
+	Title       string          `json:"title"`
+	TotalPrice  decimal.Decimal `json:"total_price"`
+	Status      string          `json:"status"`
+	Description string          `json:"description"`
+	Author      string          `json:"author"`
+	Quantity    int32           `json:"quantity"`
+	UnitPrice   decimal.Decimal `json:"unit_price"`
+	LineTotal   decimal.Decimal `json:"line_total"`
 }
 
 func (q *Queries) FindOrderDetailByOrderID(ctx context.Context, arg FindOrderDetailByOrderIDParams) ([]FindOrderDetailByOrderIDRow, error) {
@@ -189,7 +378,96 @@ func (q *Queries) FindOrderDetailByOrderID(ctx context.Context, arg FindOrderDet
 			&i.Description,
 			&i.Author,
 			&i.Quantity,
-			&i.Price,
+			&i.UnitPrice,
+			&i.LineTotal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findOrderItemsByOrderID = `-- name: FindOrderItemsByOrderID :many
+SELECT id, order_id, book_id, quantity, created_at, updated_at, unit_price, line_total, title, author FROM "order_detail" WHERE order_id=$1
+`
+
+func (q *Queries) FindOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID) ([]OrderDetail, error) {
+	rows, err := q.db.Query(ctx, findOrderItemsByOrderID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OrderDetail{}
+	for rows.Next() {
+		var i OrderDetail
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.BookID,
+			&i.Quantity,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UnitPrice,
+			&i.LineTotal,
+			&i.Title,
+			&i.Author,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findOrderItemsByUserID = `-- name: FindOrderItemsByUserID :many
+SELECT
+    od.id, od.order_id, od.book_id, od.title, b.description,
+    od.author, od.quantity, od.unit_price, od.line_total
+FROM "order_detail" AS od
+JOIN "order" AS o ON o.id = od.order_id
+JOIN "book" AS b ON b.id = od.book_id
+WHERE o.user_id=$1
+ORDER BY od.created_at, od.id
+`
+
+type FindOrderItemsByUserIDRow struct {
+	ID          uuid.UUID       `json:"id"`
+	OrderID     uuid.UUID       `json:"order_id"`
+	BookID      uuid.UUID       `json:"book_id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Author      string          `json:"author"`
+	Quantity    int32           `json:"quantity"`
+	UnitPrice   decimal.Decimal `json:"unit_price"`
+	LineTotal   decimal.Decimal `json:"line_total"`
+}
+
+func (q *Queries) FindOrderItemsByUserID(ctx context.Context, userID uuid.UUID) ([]FindOrderItemsByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, findOrderItemsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FindOrderItemsByUserIDRow{}
+	for rows.Next() {
+		var i FindOrderItemsByUserIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.BookID,
+			&i.Title,
+			&i.Description,
+			&i.Author,
+			&i.Quantity,
+			&i.UnitPrice,
+			&i.LineTotal,
 		); err != nil {
 			return nil, err
 		}
@@ -220,8 +498,8 @@ WHERE id=$1 RETURNING id, user_id, date, total_price, status, created_at, update
 `
 
 type UpdateOrderByIDParams struct {
-	ID         uuid.UUID `json:"id"`
-	TotalPrice float64   `json:"total_price"`
+	ID         uuid.UUID       `json:"id"`
+	TotalPrice decimal.Decimal `json:"total_price"`
 }
 
 func (q *Queries) UpdateOrderByID(ctx context.Context, arg UpdateOrderByIDParams) (Order, error) {
@@ -238,3 +516,29 @@ func (q *Queries) UpdateOrderByID(ctx context.Context, arg UpdateOrderByIDParams
 	)
 	return i, err
 }
+
+const updateOrderStatusByID = `-- name: UpdateOrderStatusByID :one
+UPDATE "order"
+SET status=$2, updated_at=NOW()
+WHERE id=$1 RETURNING id, user_id, date, total_price, status, created_at, updated_at
+`
+
+type UpdateOrderStatusByIDParams struct {
+	ID     uuid.UUID `json:"id"`
+	Status string    `json:"status"`
+}
+
+func (q *Queries) UpdateOrderStatusByID(ctx context.Context, arg UpdateOrderStatusByIDParams) (Order, error) {
+	row := q.db.QueryRow(ctx, updateOrderStatusByID, arg.ID, arg.Status)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Date,
+		&i.TotalPrice,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
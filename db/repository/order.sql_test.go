@@ -2,12 +2,14 @@ package querier
 
 import (
 	"context"
+	"database/sql"
 	"regexp"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/pashagolub/pgxmock/v3"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -44,7 +46,7 @@ func TestCreateOrder(t *testing.T) {
 	defer mockDB.Close()
 	q := NewRepository(mockDB)
 	userID := uuid.New()
-	totalPrice := float64(20)
+	totalPrice := decimal.NewFromInt(20)
 	now := time.Now()
 	status := "pending"
 
@@ -100,71 +102,13 @@ func TestCreateOrder(t *testing.T) {
 	})
 }
 
-func TestCreateOrderDetail(t *testing.T) {
-	mockDB, _ := pgxmock.NewPool()
-	defer mockDB.Close()
-	q := NewRepository(mockDB)
-	orderID := uuid.New()
-	bookID := uuid.New()
-	quantity := int32(20)
-	now := time.Now()
-
-	req := CreateOrderDetailParams{
-		OrderID:  orderID,
-		BookID:   bookID,
-		Quantity: quantity,
-	}
-
-	expected := OrderDetail{
-		ID:        uuid.New(),
-		OrderID:   orderID,
-		BookID:    bookID,
-		Quantity:  quantity,
-		CreatedAt: now,
-		UpdatedAt: now,
-	}
-
-	t.Run("success query create order detail", func(t *testing.T) {
-		mockDB.ExpectQuery(regexp.QuoteMeta(createOrderDetail)).
-			WithArgs(req.OrderID, req.BookID, req.Quantity).
-			WillReturnRows(pgxmock.NewRows([]string{
-				"id",
-				"order_id",
-				"book_id",
-				"quantity",
-				"created_at",
-				"updated_at"}).AddRow(
-				expected.ID,
-				expected.OrderID,
-				expected.BookID,
-				expected.Quantity,
-				expected.CreatedAt,
-				expected.UpdatedAt,
-			))
-
-		res, err := q.CreateOrderDetail(context.Background(), req)
-		assert.NoError(t, err)
-		assert.Equal(t, expected, res)
-	})
-
-	t.Run("failed query create order", func(t *testing.T) {
-		mockDB.ExpectQuery(regexp.QuoteMeta(createOrder)).
-			WithArgs(req.OrderID, req.BookID, req.Quantity).
-			WillReturnError(errQuery)
-
-		res, err := q.CreateOrderDetail(context.Background(), req)
-		assert.Error(t, err)
-		assert.Empty(t, res)
-	})
-}
-
 func TestFindOrderByID(t *testing.T) {
 	mockDB, _ := pgxmock.NewPool()
 	defer mockDB.Close()
 	q := NewRepository(mockDB)
 	userID := uuid.New()
 	orderID := uuid.New()
-	totalPrice := float64(20)
+	totalPrice := decimal.NewFromInt(20)
 	now := time.Now()
 	status := "pending"
 
@@ -224,7 +168,7 @@ func TestFindOrderByUserID(t *testing.T) {
 	defer mockDB.Close()
 	q := NewRepository(mockDB)
 	userID := uuid.New()
-	totalPrice := float64(20)
+	totalPrice := decimal.NewFromInt(20)
 	now := time.Now()
 	status := "pending"
 
@@ -314,13 +258,13 @@ func TestFindOrderDetailByOrderID(t *testing.T) {
 	userID := uuid.New()
 	orderID := uuid.New()
 	bookID := uuid.New()
-	totalPrice := float64(20)
+	totalPrice := decimal.NewFromInt(20)
 	now := time.Now()
 	title := "Hello"
 	description := "World"
 	author := "Giri Putra Adhittana"
 	quantity := int32(5)
-	price := float64(12)
+	price := decimal.NewFromInt(12)
 	status := "pending"
 
 	req := FindOrderDetailByOrderIDParams{
@@ -339,7 +283,8 @@ func TestFindOrderDetailByOrderID(t *testing.T) {
 			Description: description,
 			Author:      author,
 			Quantity:    quantity,
-			Price:       price,
+			UnitPrice:   price,
+			LineTotal:   price.Mul(decimal.NewFromInt32(quantity)),
 		},
 	}
 
@@ -356,7 +301,8 @@ func TestFindOrderDetailByOrderID(t *testing.T) {
 				"description",
 				"author",
 				"quantity",
-				"price"}).AddRow(
+				"unit_price",
+				"line_total"}).AddRow(
 				expected[0].ID,
 				expected[0].Date,
 				expected[0].BookID,
@@ -366,7 +312,8 @@ func TestFindOrderDetailByOrderID(t *testing.T) {
 				expected[0].Description,
 				expected[0].Author,
 				expected[0].Quantity,
-				expected[0].Price,
+				expected[0].UnitPrice,
+				expected[0].LineTotal,
 			))
 
 		res, err := q.FindOrderDetailByOrderID(context.Background(), req)
@@ -397,7 +344,8 @@ func TestFindOrderDetailByOrderID(t *testing.T) {
 				"description",
 				"author",
 				"quantity",
-				"price"}).AddRow(
+				"unit_price",
+				"line_total"}).AddRow(
 				1,
 				expected[0].Date,
 				expected[0].BookID,
@@ -407,7 +355,8 @@ func TestFindOrderDetailByOrderID(t *testing.T) {
 				expected[0].Description,
 				expected[0].Author,
 				expected[0].Quantity,
-				expected[0].Price,
+				expected[0].UnitPrice,
+				expected[0].LineTotal,
 			))
 
 		res, err := q.FindOrderDetailByOrderID(context.Background(), req)
@@ -454,7 +403,7 @@ func TestUpdateOrderByID(t *testing.T) {
 	defer mockDB.Close()
 	q := NewRepository(mockDB)
 	orderID := uuid.New()
-	totalPrice := float64(10)
+	totalPrice := decimal.NewFromInt(10)
 	userID := uuid.New()
 	now := time.Now()
 	status := "pending"
@@ -510,3 +459,518 @@ func TestUpdateOrderByID(t *testing.T) {
 		assert.Empty(t, res)
 	})
 }
+
+func TestFindOrderByIDForUpdate(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	orderID := uuid.New()
+	userID := uuid.New()
+	totalPrice := decimal.NewFromInt(20)
+	now := time.Now()
+	status := "pending"
+
+	expected := Order{
+		ID:         orderID,
+		UserID:     userID,
+		Date:       now,
+		TotalPrice: totalPrice,
+		Status:     status,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	t.Run("success query find order by ID for update", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findOrderByIDForUpdate)).
+			WithArgs(orderID).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"user_id",
+				"date",
+				"total_price",
+				"status",
+				"created_at",
+				"updated_at"}).AddRow(
+				expected.ID,
+				expected.UserID,
+				expected.Date,
+				expected.TotalPrice,
+				expected.Status,
+				expected.CreatedAt,
+				expected.UpdatedAt,
+			))
+
+		res, err := q.FindOrderByIDForUpdate(context.Background(), orderID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query find order by ID for update", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findOrderByIDForUpdate)).
+			WithArgs(orderID).
+			WillReturnError(errQuery)
+
+		res, err := q.FindOrderByIDForUpdate(context.Background(), orderID)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestUpdateOrderStatusByID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	orderID := uuid.New()
+	userID := uuid.New()
+	totalPrice := decimal.NewFromInt(10)
+	now := time.Now()
+	status := "confirmed"
+
+	req := UpdateOrderStatusByIDParams{
+		ID:     orderID,
+		Status: status,
+	}
+
+	expected := Order{
+		ID:         orderID,
+		UserID:     userID,
+		Date:       now,
+		TotalPrice: totalPrice,
+		Status:     status,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	t.Run("success query update order status by ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(updateOrderStatusByID)).
+			WithArgs(req.ID, req.Status).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"user_id",
+				"date",
+				"total_price",
+				"status",
+				"created_at",
+				"updated_at",
+			}).AddRow(
+				expected.ID,
+				expected.UserID,
+				expected.Date,
+				expected.TotalPrice,
+				expected.Status,
+				expected.CreatedAt,
+				expected.UpdatedAt,
+			))
+
+		res, err := q.UpdateOrderStatusByID(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query update order status by ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(updateOrderStatusByID)).
+			WithArgs(req.ID, req.Status).
+			WillReturnError(errQuery)
+
+		res, err := q.UpdateOrderStatusByID(context.Background(), req)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestCreateOrderStatusHistory(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	req := CreateOrderStatusHistoryParams{
+		OrderID:    uuid.New(),
+		FromStatus: "pending",
+		ToStatus:   "confirmed",
+		ChangedBy:  uuid.New(),
+	}
+
+	expected := OrderStatusHistory{
+		ID:         uuid.New(),
+		OrderID:    req.OrderID,
+		FromStatus: req.FromStatus,
+		ToStatus:   req.ToStatus,
+		ChangedBy:  req.ChangedBy,
+		CreatedAt:  now,
+	}
+
+	t.Run("success query create order status history", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(createOrderStatusHistory)).
+			WithArgs(req.OrderID, req.FromStatus, req.ToStatus, req.ChangedBy).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"order_id",
+				"from_status",
+				"to_status",
+				"changed_by",
+				"created_at",
+			}).AddRow(
+				expected.ID,
+				expected.OrderID,
+				expected.FromStatus,
+				expected.ToStatus,
+				expected.ChangedBy,
+				expected.CreatedAt,
+			))
+
+		res, err := q.CreateOrderStatusHistory(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query create order status history", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(createOrderStatusHistory)).
+			WithArgs(req.OrderID, req.FromStatus, req.ToStatus, req.ChangedBy).
+			WillReturnError(errQuery)
+
+		res, err := q.CreateOrderStatusHistory(context.Background(), req)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestFindOrderItemsByOrderID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	orderID := uuid.New()
+	now := time.Now()
+
+	expected := []OrderDetail{
+		{
+			ID:        uuid.New(),
+			OrderID:   orderID,
+			BookID:    uuid.New(),
+			Quantity:  2,
+			CreatedAt: now,
+			UpdatedAt: now,
+			UnitPrice: decimal.NewFromInt(12),
+			LineTotal: decimal.NewFromInt(24),
+			Title:     "Hello",
+			Author:    "Giri Putra Adhittana",
+		},
+	}
+
+	t.Run("success query find order items by order ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findOrderItemsByOrderID)).
+			WithArgs(orderID).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"order_id",
+				"book_id",
+				"quantity",
+				"created_at",
+				"updated_at",
+				"unit_price",
+				"line_total",
+				"title",
+				"author",
+			}).AddRow(
+				expected[0].ID,
+				expected[0].OrderID,
+				expected[0].BookID,
+				expected[0].Quantity,
+				expected[0].CreatedAt,
+				expected[0].UpdatedAt,
+				expected[0].UnitPrice,
+				expected[0].LineTotal,
+				expected[0].Title,
+				expected[0].Author,
+			))
+
+		res, err := q.FindOrderItemsByOrderID(context.Background(), orderID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query find order items by order ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findOrderItemsByOrderID)).
+			WithArgs(orderID).
+			WillReturnError(errQuery)
+
+		res, err := q.FindOrderItemsByOrderID(context.Background(), orderID)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestFindAllOrderByUserID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	userID := uuid.New()
+	now := time.Now()
+
+	expected := []Order{
+		{
+			ID:         uuid.New(),
+			UserID:     userID,
+			Date:       now,
+			TotalPrice: decimal.NewFromInt(24),
+			Status:     "delivered",
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		},
+	}
+
+	t.Run("success query find all order by user ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findAllOrderByUserID)).
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"user_id",
+				"date",
+				"total_price",
+				"status",
+				"created_at",
+				"updated_at",
+			}).AddRow(
+				expected[0].ID,
+				expected[0].UserID,
+				expected[0].Date,
+				expected[0].TotalPrice,
+				expected[0].Status,
+				expected[0].CreatedAt,
+				expected[0].UpdatedAt,
+			))
+
+		res, err := q.FindAllOrderByUserID(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query find all order by user ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findAllOrderByUserID)).
+			WithArgs(userID).
+			WillReturnError(errQuery)
+
+		res, err := q.FindAllOrderByUserID(context.Background(), userID)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestFindOrderItemsByUserID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	userID := uuid.New()
+
+	expected := []FindOrderItemsByUserIDRow{
+		{
+			ID:          uuid.New(),
+			OrderID:     uuid.New(),
+			BookID:      uuid.New(),
+			Title:       "Hello",
+			Description: "Hello World",
+			Author:      "Giri Putra Adhittana",
+			Quantity:    2,
+			UnitPrice:   decimal.NewFromInt(12),
+			LineTotal:   decimal.NewFromInt(24),
+		},
+	}
+
+	t.Run("success query find order items by user ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findOrderItemsByUserID)).
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"order_id",
+				"book_id",
+				"title",
+				"description",
+				"author",
+				"quantity",
+				"unit_price",
+				"line_total",
+			}).AddRow(
+				expected[0].ID,
+				expected[0].OrderID,
+				expected[0].BookID,
+				expected[0].Title,
+				expected[0].Description,
+				expected[0].Author,
+				expected[0].Quantity,
+				expected[0].UnitPrice,
+				expected[0].LineTotal,
+			))
+
+		res, err := q.FindOrderItemsByUserID(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query find order items by user ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findOrderItemsByUserID)).
+			WithArgs(userID).
+			WillReturnError(errQuery)
+
+		res, err := q.FindOrderItemsByUserID(context.Background(), userID)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestCreateOrderDetails(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	req := CreateOrderDetailsParams{
+		OrderID:    uuid.New(),
+		BookIds:    []uuid.UUID{uuid.New(), uuid.New()},
+		Quantities: []int32{1, 2},
+		UnitPrices: []decimal.Decimal{decimal.NewFromInt(10), decimal.NewFromInt(12)},
+		LineTotals: []decimal.Decimal{decimal.NewFromInt(10), decimal.NewFromInt(24)},
+		Titles:     []string{"Hello", "World"},
+		Authors:    []string{"Giri Putra Adhittana", "Giri Putra Adhittana"},
+	}
+
+	t.Run("success query create order details", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(createOrderDetails)).
+			WithArgs(req.OrderID, req.BookIds, req.Quantities, req.UnitPrices, req.LineTotals, req.Titles, req.Authors).
+			WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+		err := q.CreateOrderDetails(context.Background(), req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query create order details", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(createOrderDetails)).
+			WithArgs(req.OrderID, req.BookIds, req.Quantities, req.UnitPrices, req.LineTotals, req.Titles, req.Authors).
+			WillReturnError(errQuery)
+
+		err := q.CreateOrderDetails(context.Background(), req)
+		assert.Error(t, err)
+	})
+}
+
+func TestFindOrderByUserIDAfterCursor(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	userID := uuid.New()
+	now := time.Now()
+
+	req := FindOrderByUserIDAfterCursorParams{
+		UserID:     userID,
+		CursorDate: sql.NullTime{},
+		CursorID:   uuid.NullUUID{},
+		Limit:      11,
+	}
+
+	expected := []Order{
+		{
+			ID:         uuid.New(),
+			UserID:     userID,
+			Date:       now,
+			TotalPrice: decimal.NewFromInt(20),
+			Status:     "pending",
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		},
+	}
+
+	t.Run("success query find order by user ID after cursor", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findOrderByUserIDAfterCursor)).
+			WithArgs(req.UserID, req.CursorDate, req.CursorID, req.Limit).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"user_id",
+				"date",
+				"total_price",
+				"status",
+				"created_at",
+				"updated_at"}).AddRow(
+				expected[0].ID,
+				expected[0].UserID,
+				expected[0].Date,
+				expected[0].TotalPrice,
+				expected[0].Status,
+				expected[0].CreatedAt,
+				expected[0].UpdatedAt,
+			))
+
+		res, err := q.FindOrderByUserIDAfterCursor(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query find order by user ID after cursor", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findOrderByUserIDAfterCursor)).
+			WithArgs(req.UserID, req.CursorDate, req.CursorID, req.Limit).
+			WillReturnError(errQuery)
+
+		res, err := q.FindOrderByUserIDAfterCursor(context.Background(), req)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestFindOrderByUserIDBeforeCursor(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	userID := uuid.New()
+	now := time.Now()
+
+	req := FindOrderByUserIDBeforeCursorParams{
+		UserID:     userID,
+		CursorDate: now,
+		CursorID:   uuid.New(),
+		Limit:      11,
+	}
+
+	expected := []Order{
+		{
+			ID:         uuid.New(),
+			UserID:     userID,
+			Date:       now,
+			TotalPrice: decimal.NewFromInt(20),
+			Status:     "pending",
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		},
+	}
+
+	t.Run("success query find order by user ID before cursor", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findOrderByUserIDBeforeCursor)).
+			WithArgs(req.UserID, req.CursorDate, req.CursorID, req.Limit).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"user_id",
+				"date",
+				"total_price",
+				"status",
+				"created_at",
+				"updated_at"}).AddRow(
+				expected[0].ID,
+				expected[0].UserID,
+				expected[0].Date,
+				expected[0].TotalPrice,
+				expected[0].Status,
+				expected[0].CreatedAt,
+				expected[0].UpdatedAt,
+			))
+
+		res, err := q.FindOrderByUserIDBeforeCursor(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query find order by user ID before cursor", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findOrderByUserIDBeforeCursor)).
+			WithArgs(req.UserID, req.CursorDate, req.CursorID, req.Limit).
+			WillReturnError(errQuery)
+
+		res, err := q.FindOrderByUserIDBeforeCursor(context.Background(), req)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
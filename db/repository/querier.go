@@ -11,23 +11,75 @@ import (
 )
 
 type Querier interface {
+	AdjustBookStock(ctx context.Context, arg AdjustBookStockParams) (Book, error)
+	AnonymizeUserByID(ctx context.Context, id uuid.UUID) error
 	CheckBookExists(ctx context.Context, id uuid.UUID) (bool, error)
+	CheckBookOrdered(ctx context.Context, bookID uuid.UUID) (bool, error)
 	CheckEmailExists(ctx context.Context, email string) (bool, error)
 	CheckOrderExists(ctx context.Context, id uuid.UUID) (bool, error)
 	CreateBook(ctx context.Context, arg CreateBookParams) (Book, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
 	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
-	CreateOrderDetail(ctx context.Context, arg CreateOrderDetailParams) (OrderDetail, error)
+	CreateOrderDetails(ctx context.Context, arg CreateOrderDetailsParams) error
+	CreateOrderStatusHistory(ctx context.Context, arg CreateOrderStatusHistoryParams) (OrderStatusHistory, error)
+	CreateStockMovement(ctx context.Context, arg CreateStockMovementParams) (StockMovement, error)
+	CreateStockMovements(ctx context.Context, arg CreateStockMovementsParams) error
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserRecoveryCodes(ctx context.Context, arg CreateUserRecoveryCodesParams) error
+	CreateUserSession(ctx context.Context, arg CreateUserSessionParams) (UserSession, error)
+	CreateUserToken(ctx context.Context, arg CreateUserTokenParams) (UserToken, error)
+	DeleteBookByID(ctx context.Context, id uuid.UUID) error
+	DeleteCartByUserID(ctx context.Context, userID uuid.UUID) error
+	DeleteCartItem(ctx context.Context, arg DeleteCartItemParams) (CartItem, error)
+	DeleteCartItemsByCartID(ctx context.Context, cartID uuid.UUID) error
+	DeleteUserRecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) error
+	EnableUserTotpByID(ctx context.Context, arg EnableUserTotpByIDParams) error
+	FindAllOrderByUserID(ctx context.Context, userID uuid.UUID) ([]Order, error)
 	FindBook(ctx context.Context, arg FindBookParams) ([]Book, error)
+	FindBookAfterCursor(ctx context.Context, arg FindBookAfterCursorParams) ([]Book, error)
+	FindBookBeforeCursor(ctx context.Context, arg FindBookBeforeCursorParams) ([]Book, error)
 	FindBookByID(ctx context.Context, id uuid.UUID) (Book, error)
+	FindBooksByIDs(ctx context.Context, ids []uuid.UUID) ([]Book, error)
+	FindCartByUserID(ctx context.Context, userID uuid.UUID) (Cart, error)
+	FindCartByUserIDForUpdate(ctx context.Context, userID uuid.UUID) (Cart, error)
+	FindCartItemsByCartID(ctx context.Context, cartID uuid.UUID) ([]FindCartItemsByCartIDRow, error)
+	FindIdempotencyKey(ctx context.Context, arg FindIdempotencyKeyParams) (IdempotencyKey, error)
 	FindOrderByID(ctx context.Context, arg FindOrderByIDParams) (Order, error)
+	FindOrderByIDForUpdate(ctx context.Context, id uuid.UUID) (Order, error)
 	FindOrderByUserID(ctx context.Context, arg FindOrderByUserIDParams) ([]Order, error)
+	FindOrderByUserIDAfterCursor(ctx context.Context, arg FindOrderByUserIDAfterCursorParams) ([]Order, error)
+	FindOrderByUserIDBeforeCursor(ctx context.Context, arg FindOrderByUserIDBeforeCursorParams) ([]Order, error)
 	FindOrderDetailByOrderID(ctx context.Context, arg FindOrderDetailByOrderIDParams) ([]FindOrderDetailByOrderIDRow, error)
+	FindOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID) ([]OrderDetail, error)
+	FindOrderItemsByUserID(ctx context.Context, userID uuid.UUID) ([]FindOrderItemsByUserIDRow, error)
 	FindUserByEmail(ctx context.Context, email string) (User, error)
-	GetBookCount(ctx context.Context) (int64, error)
+	FindUserByID(ctx context.Context, id uuid.UUID) (User, error)
+	FindUserRoleByID(ctx context.Context, id uuid.UUID) (string, error)
+	FindUserSessionByRefreshTokenHashForUpdate(ctx context.Context, refreshTokenHash string) (UserSession, error)
+	FindUserTokenByHashForUpdate(ctx context.Context, arg FindUserTokenByHashForUpdateParams) (UserToken, error)
+	GetBookCount(ctx context.Context, arg GetBookCountParams) (int64, error)
 	GetBookPurchasedByUserID(ctx context.Context, userID uuid.UUID) ([]GetBookPurchasedByUserIDRow, error)
 	GetOrderCountByUserId(ctx context.Context, userID uuid.UUID) (int64, error)
+	InvalidateUserTokens(ctx context.Context, arg InvalidateUserTokensParams) error
+	IsUserEmailVerified(ctx context.Context, id uuid.UUID) (bool, error)
+	MarkUserTokenUsedByID(ctx context.Context, id uuid.UUID) error
+	ReserveBooksStock(ctx context.Context, arg ReserveBooksStockParams) ([]Book, error)
+	RevokeUserSessionByID(ctx context.Context, id uuid.UUID) error
+	RevokeUserSessionByRefreshTokenHash(ctx context.Context, arg RevokeUserSessionByRefreshTokenHashParams) error
+	RevokeUserSessionsByUserID(ctx context.Context, userID uuid.UUID) error
+	SetUserTotpSecretByID(ctx context.Context, arg SetUserTotpSecretByIDParams) error
+	UpdateBookByID(ctx context.Context, arg UpdateBookByIDParams) (Book, error)
+	UpdateCartItemQuantity(ctx context.Context, arg UpdateCartItemQuantityParams) (CartItem, error)
 	UpdateOrderByID(ctx context.Context, arg UpdateOrderByIDParams) (Order, error)
+	UpdateOrderStatusByID(ctx context.Context, arg UpdateOrderStatusByIDParams) (Order, error)
+	UpdateUserPasswordByID(ctx context.Context, arg UpdateUserPasswordByIDParams) error
+	UpdateUserProfileByID(ctx context.Context, arg UpdateUserProfileByIDParams) (User, error)
+	UpdateUserRoleByID(ctx context.Context, arg UpdateUserRoleByIDParams) error
+	UpdateUserTotpLastStepByID(ctx context.Context, arg UpdateUserTotpLastStepByIDParams) (int64, error)
+	UpsertCart(ctx context.Context, userID uuid.UUID) (Cart, error)
+	UpsertCartItem(ctx context.Context, arg UpsertCartItemParams) (CartItem, error)
+	UseUserRecoveryCode(ctx context.Context, arg UseUserRecoveryCodeParams) (int64, error)
+	VerifyUserEmailByID(ctx context.Context, id uuid.UUID) error
 }
 
 var _ Querier = (*Queries)(nil)
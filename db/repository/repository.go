@@ -18,12 +18,12 @@ type RepositoryImpl struct {
 }
 
 func NewRepository(db utils.PGXPool) Repository {
-	return &RepositoryImpl{db: db, Queries: New(db)}
+	return &RepositoryImpl{db: db, Queries: New(newInstrumentedDB(db))}
 }
 
 func (r *RepositoryImpl) WithTx(tx pgx.Tx) Querier {
 	return &Queries{
-		db: tx,
+		db: newInstrumentedDB(tx),
 	}
 }
 
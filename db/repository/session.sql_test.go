@@ -0,0 +1,198 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+var userSessionColumns = []string{
+	"id",
+	"user_id",
+	"refresh_token_hash",
+	"expires_at",
+	"revoked_at",
+	"created_at",
+	"updated_at",
+}
+
+func TestCreateUserSession(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	expected := UserSession{
+		ID:               uuid.New(),
+		UserID:           uuid.New(),
+		RefreshTokenHash: "hash",
+		ExpiresAt:        now.Add(time.Hour),
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	params := CreateUserSessionParams{
+		UserID:           expected.UserID,
+		RefreshTokenHash: expected.RefreshTokenHash,
+		ExpiresAt:        expected.ExpiresAt,
+	}
+
+	t.Run("success query create user session", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(createUserSession)).
+			WithArgs(params.UserID, params.RefreshTokenHash, params.ExpiresAt).
+			WillReturnRows(pgxmock.NewRows(userSessionColumns).AddRow(
+				expected.ID,
+				expected.UserID,
+				expected.RefreshTokenHash,
+				expected.ExpiresAt,
+				expected.RevokedAt,
+				expected.CreatedAt,
+				expected.UpdatedAt,
+			))
+
+		res, err := q.CreateUserSession(context.Background(), params)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query create user session", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(createUserSession)).
+			WithArgs(params.UserID, params.RefreshTokenHash, params.ExpiresAt).
+			WillReturnError(errQuery)
+
+		res, err := q.CreateUserSession(context.Background(), params)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestFindUserSessionByRefreshTokenHashForUpdate(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	expected := UserSession{
+		ID:               uuid.New(),
+		UserID:           uuid.New(),
+		RefreshTokenHash: "hash",
+		ExpiresAt:        now.Add(time.Hour),
+		RevokedAt:        sql.NullTime{Time: now, Valid: true},
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	t.Run("success query find user session by refresh token hash", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findUserSessionByRefreshTokenHashForUpdate)).
+			WithArgs(expected.RefreshTokenHash).
+			WillReturnRows(pgxmock.NewRows(userSessionColumns).AddRow(
+				expected.ID,
+				expected.UserID,
+				expected.RefreshTokenHash,
+				expected.ExpiresAt,
+				expected.RevokedAt,
+				expected.CreatedAt,
+				expected.UpdatedAt,
+			))
+
+		res, err := q.FindUserSessionByRefreshTokenHashForUpdate(context.Background(), expected.RefreshTokenHash)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query find user session by refresh token hash", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findUserSessionByRefreshTokenHashForUpdate)).
+			WithArgs(expected.RefreshTokenHash).
+			WillReturnError(errQuery)
+
+		res, err := q.FindUserSessionByRefreshTokenHashForUpdate(context.Background(), expected.RefreshTokenHash)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestRevokeUserSessionByID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	sessionID := uuid.New()
+
+	t.Run("success query revoke user session by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(revokeUserSessionByID)).
+			WithArgs(sessionID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err := q.RevokeUserSessionByID(context.Background(), sessionID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query revoke user session by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(revokeUserSessionByID)).
+			WithArgs(sessionID).
+			WillReturnError(errQuery)
+
+		err := q.RevokeUserSessionByID(context.Background(), sessionID)
+		assert.Error(t, err)
+	})
+}
+
+func TestRevokeUserSessionByRefreshTokenHash(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	params := RevokeUserSessionByRefreshTokenHashParams{
+		UserID:           uuid.New(),
+		RefreshTokenHash: "hash",
+	}
+
+	t.Run("success query revoke user session by refresh token hash", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(revokeUserSessionByRefreshTokenHash)).
+			WithArgs(params.UserID, params.RefreshTokenHash).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err := q.RevokeUserSessionByRefreshTokenHash(context.Background(), params)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query revoke user session by refresh token hash", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(revokeUserSessionByRefreshTokenHash)).
+			WithArgs(params.UserID, params.RefreshTokenHash).
+			WillReturnError(errQuery)
+
+		err := q.RevokeUserSessionByRefreshTokenHash(context.Background(), params)
+		assert.Error(t, err)
+	})
+}
+
+func TestRevokeUserSessionsByUserID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	userID := uuid.New()
+
+	t.Run("success query revoke user sessions by user ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(revokeUserSessionsByUserID)).
+			WithArgs(userID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 3))
+
+		err := q.RevokeUserSessionsByUserID(context.Background(), userID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query revoke user sessions by user ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(revokeUserSessionsByUserID)).
+			WithArgs(userID).
+			WillReturnError(errQuery)
+
+		err := q.RevokeUserSessionsByUserID(context.Background(), userID)
+		assert.Error(t, err)
+	})
+}
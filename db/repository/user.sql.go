@@ -7,10 +7,26 @@ package querier
 
 import (
 	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
 )
 
+const anonymizeUserByID = `-- name: AnonymizeUserByID :exec
+UPDATE "user"
+SET name='Deleted user', email='deleted-' || id::TEXT || '@deleted.invalid', password='',
+    email_verified_at=NULL, totp_secret=NULL, totp_enabled_at=NULL,
+    deleted_at=NOW(), updated_at=NOW()
+WHERE id=$1 AND deleted_at IS NULL
+`
+
+func (q *Queries) AnonymizeUserByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, anonymizeUserByID, id)
+	return err
+}
+
 const checkEmailExists = `-- name: CheckEmailExists :one
-SELECT EXISTS(SELECT id FROM "user" WHERE email=$1)
+SELECT EXISTS(SELECT id FROM "user" WHERE email=LOWER(TRIM($1::TEXT)))
 `
 
 func (q *Queries) CheckEmailExists(ctx context.Context, email string) (bool, error) {
@@ -21,18 +37,24 @@ func (q *Queries) CheckEmailExists(ctx context.Context, email string) (bool, err
 }
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO "user"(name, email, password) VALUES
-($1, $2, $3) RETURNING id, name, email, password, created_at, updated_at
+INSERT INTO "user"(name, email, password, role) VALUES
+($1, LOWER(TRIM($2::TEXT)), $3, $4) RETURNING id, name, email, password, created_at, updated_at, role, email_verified_at, totp_secret, totp_enabled_at, totp_last_step, deleted_at
 `
 
 type CreateUserParams struct {
 	Name     string `json:"name"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	Role     string `json:"role"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRow(ctx, createUser, arg.Name, arg.Email, arg.Password)
+	row := q.db.QueryRow(ctx, createUser,
+		arg.Name,
+		arg.Email,
+		arg.Password,
+		arg.Role,
+	)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -41,12 +63,34 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const enableUserTotpByID = `-- name: EnableUserTotpByID :exec
+UPDATE "user"
+SET totp_enabled_at=NOW(), totp_last_step=$2, updated_at=NOW()
+WHERE id=$1
+`
+
+type EnableUserTotpByIDParams struct {
+	ID           uuid.UUID `json:"id"`
+	TotpLastStep int64     `json:"totp_last_step"`
+}
+
+func (q *Queries) EnableUserTotpByID(ctx context.Context, arg EnableUserTotpByIDParams) error {
+	_, err := q.db.Exec(ctx, enableUserTotpByID, arg.ID, arg.TotpLastStep)
+	return err
+}
+
 const findUserByEmail = `-- name: FindUserByEmail :one
-SELECT id, name, email, password, created_at, updated_at FROM "user" WHERE email=$1
+SELECT id, name, email, password, created_at, updated_at, role, email_verified_at, totp_secret, totp_enabled_at, totp_last_step, deleted_at FROM "user" WHERE email=LOWER(TRIM($1::TEXT))
 `
 
 func (q *Queries) FindUserByEmail(ctx context.Context, email string) (User, error) {
@@ -59,6 +103,171 @@ func (q *Queries) FindUserByEmail(ctx context.Context, email string) (User, erro
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const findUserByID = `-- name: FindUserByID :one
+SELECT id, name, email, password, created_at, updated_at, role, email_verified_at, totp_secret, totp_enabled_at, totp_last_step, deleted_at FROM "user" WHERE id=$1
+`
+
+func (q *Queries) FindUserByID(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, findUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const findUserRoleByID = `-- name: FindUserRoleByID :one
+SELECT role FROM "user" WHERE id=$1
+`
+
+func (q *Queries) FindUserRoleByID(ctx context.Context, id uuid.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, findUserRoleByID, id)
+	var role string
+	err := row.Scan(&role)
+	return role, err
+}
+
+const isUserEmailVerified = `-- name: IsUserEmailVerified :one
+SELECT (email_verified_at IS NOT NULL)::boolean AS is_verified FROM "user" WHERE id=$1
+`
+
+func (q *Queries) IsUserEmailVerified(ctx context.Context, id uuid.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, isUserEmailVerified, id)
+	var is_verified bool
+	err := row.Scan(&is_verified)
+	return is_verified, err
+}
+
+const setUserTotpSecretByID = `-- name: SetUserTotpSecretByID :exec
+UPDATE "user"
+SET totp_secret=$2, updated_at=NOW()
+WHERE id=$1 AND totp_enabled_at IS NULL
+`
+
+type SetUserTotpSecretByIDParams struct {
+	ID         uuid.UUID      `json:"id"`
+	TotpSecret sql.NullString `json:"totp_secret"`
+}
+
+func (q *Queries) SetUserTotpSecretByID(ctx context.Context, arg SetUserTotpSecretByIDParams) error {
+	_, err := q.db.Exec(ctx, setUserTotpSecretByID, arg.ID, arg.TotpSecret)
+	return err
+}
+
+const updateUserPasswordByID = `-- name: UpdateUserPasswordByID :exec
+UPDATE "user"
+SET password=$2, updated_at=NOW()
+WHERE id=$1
+`
+
+type UpdateUserPasswordByIDParams struct {
+	ID       uuid.UUID `json:"id"`
+	Password string    `json:"password"`
+}
+
+func (q *Queries) UpdateUserPasswordByID(ctx context.Context, arg UpdateUserPasswordByIDParams) error {
+	_, err := q.db.Exec(ctx, updateUserPasswordByID, arg.ID, arg.Password)
+	return err
+}
+
+const updateUserProfileByID = `-- name: UpdateUserProfileByID :one
+UPDATE "user"
+SET name=$1,
+    email=LOWER(TRIM($2::TEXT)),
+    email_verified_at=CASE WHEN email=LOWER(TRIM($2::TEXT)) THEN email_verified_at END,
+    updated_at=NOW()
+WHERE id=$3 RETURNING id, name, email, password, created_at, updated_at, role, email_verified_at, totp_secret, totp_enabled_at, totp_last_step, deleted_at
+`
+
+type UpdateUserProfileByIDParams struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateUserProfileByID(ctx context.Context, arg UpdateUserProfileByIDParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserProfileByID, arg.Name, arg.Email, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.DeletedAt,
 	)
 	return i, err
 }
+
+const updateUserRoleByID = `-- name: UpdateUserRoleByID :exec
+UPDATE "user"
+SET role=$2, updated_at=NOW()
+WHERE id=$1
+`
+
+type UpdateUserRoleByIDParams struct {
+	ID   uuid.UUID `json:"id"`
+	Role string    `json:"role"`
+}
+
+func (q *Queries) UpdateUserRoleByID(ctx context.Context, arg UpdateUserRoleByIDParams) error {
+	_, err := q.db.Exec(ctx, updateUserRoleByID, arg.ID, arg.Role)
+	return err
+}
+
+const updateUserTotpLastStepByID = `-- name: UpdateUserTotpLastStepByID :execrows
+UPDATE "user"
+SET totp_last_step=$2
+WHERE id=$1 AND totp_last_step < $2
+`
+
+type UpdateUserTotpLastStepByIDParams struct {
+	ID           uuid.UUID `json:"id"`
+	TotpLastStep int64     `json:"totp_last_step"`
+}
+
+func (q *Queries) UpdateUserTotpLastStepByID(ctx context.Context, arg UpdateUserTotpLastStepByIDParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateUserTotpLastStepByID, arg.ID, arg.TotpLastStep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const verifyUserEmailByID = `-- name: VerifyUserEmailByID :exec
+UPDATE "user"
+SET email_verified_at=NOW(), updated_at=NOW()
+WHERE id=$1 AND email_verified_at IS NULL
+`
+
+func (q *Queries) VerifyUserEmailByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, verifyUserEmailByID, id)
+	return err
+}
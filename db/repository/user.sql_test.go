@@ -2,6 +2,7 @@ package querier
 
 import (
 	"context"
+	"database/sql"
 	"regexp"
 	"testing"
 	"time"
@@ -46,12 +47,14 @@ func TestCreateUser(t *testing.T) {
 	name := "Giri Putra Adhittana"
 	email := "test@gmail.com"
 	pwd := "123"
+	role := "customer"
 	now := time.Now()
 
 	req := CreateUserParams{
 		Name:     name,
 		Email:    email,
 		Password: pwd,
+		Role:     role,
 	}
 
 	expected := User{
@@ -61,11 +64,12 @@ func TestCreateUser(t *testing.T) {
 		Password:  pwd,
 		CreatedAt: now,
 		UpdatedAt: now,
+		Role:      role,
 	}
 
 	t.Run("success query create user", func(t *testing.T) {
 		mockDB.ExpectQuery(regexp.QuoteMeta(createUser)).
-			WithArgs(req.Name, req.Email, req.Password).
+			WithArgs(req.Name, req.Email, req.Password, req.Role).
 			WillReturnRows(pgxmock.NewRows([]string{
 				"id",
 				"name",
@@ -73,6 +77,12 @@ func TestCreateUser(t *testing.T) {
 				"password",
 				"created_at",
 				"updated_at",
+				"role",
+				"email_verified_at",
+				"totp_secret",
+				"totp_enabled_at",
+				"totp_last_step",
+				"deleted_at",
 			}).AddRow(
 				expected.ID,
 				expected.Name,
@@ -80,6 +90,12 @@ func TestCreateUser(t *testing.T) {
 				expected.Password,
 				expected.CreatedAt,
 				expected.UpdatedAt,
+				expected.Role,
+				expected.EmailVerifiedAt,
+				expected.TotpSecret,
+				expected.TotpEnabledAt,
+				expected.TotpLastStep,
+				expected.DeletedAt,
 			))
 
 		res, err := q.CreateUser(context.Background(), req)
@@ -89,7 +105,7 @@ func TestCreateUser(t *testing.T) {
 
 	t.Run("failed query create user", func(t *testing.T) {
 		mockDB.ExpectQuery(regexp.QuoteMeta(createUser)).
-			WithArgs(req.Name, req.Email, req.Password).
+			WithArgs(req.Name, req.Email, req.Password, req.Role).
 			WillReturnError(errQuery)
 
 		res, err := q.CreateUser(context.Background(), req)
@@ -105,6 +121,7 @@ func TestFindUserByEmail(t *testing.T) {
 	name := "Giri Putra Adhittana"
 	email := "test@gmail.com"
 	pwd := "123"
+	role := "customer"
 	now := time.Now()
 
 	req := email
@@ -116,6 +133,7 @@ func TestFindUserByEmail(t *testing.T) {
 		Password:  pwd,
 		CreatedAt: now,
 		UpdatedAt: now,
+		Role:      role,
 	}
 
 	t.Run("success query find user by email", func(t *testing.T) {
@@ -128,6 +146,12 @@ func TestFindUserByEmail(t *testing.T) {
 				"password",
 				"created_at",
 				"updated_at",
+				"role",
+				"email_verified_at",
+				"totp_secret",
+				"totp_enabled_at",
+				"totp_last_step",
+				"deleted_at",
 			}).AddRow(
 				expected.ID,
 				expected.Name,
@@ -135,6 +159,12 @@ func TestFindUserByEmail(t *testing.T) {
 				expected.Password,
 				expected.CreatedAt,
 				expected.UpdatedAt,
+				expected.Role,
+				expected.EmailVerifiedAt,
+				expected.TotpSecret,
+				expected.TotpEnabledAt,
+				expected.TotpLastStep,
+				expected.DeletedAt,
 			))
 
 		res, err := q.FindUserByEmail(context.Background(), req)
@@ -152,3 +182,405 @@ func TestFindUserByEmail(t *testing.T) {
 		assert.Empty(t, res)
 	})
 }
+
+func TestFindUserRoleByID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	userID := uuid.New()
+	expected := "admin"
+
+	t.Run("success query find user role by ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findUserRoleByID)).
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows([]string{"role"}).AddRow(expected))
+
+		res, err := q.FindUserRoleByID(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query find user role by ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findUserRoleByID)).
+			WithArgs(userID).
+			WillReturnError(errQuery)
+
+		res, err := q.FindUserRoleByID(context.Background(), userID)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestUpdateUserRoleByID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	req := UpdateUserRoleByIDParams{
+		ID:   uuid.New(),
+		Role: "admin",
+	}
+
+	t.Run("success query update user role by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(updateUserRoleByID)).
+			WithArgs(req.ID, req.Role).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err := q.UpdateUserRoleByID(context.Background(), req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query update user role by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(updateUserRoleByID)).
+			WithArgs(req.ID, req.Role).
+			WillReturnError(errQuery)
+
+		err := q.UpdateUserRoleByID(context.Background(), req)
+		assert.Error(t, err)
+	})
+}
+
+func TestFindUserByID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	expected := User{
+		ID:              uuid.New(),
+		Name:            "Giri Putra Adhittana",
+		Email:           "test@gmail.com",
+		Password:        "123",
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		Role:            "customer",
+		EmailVerifiedAt: sql.NullTime{Time: now, Valid: true},
+		TotpSecret:      sql.NullString{String: "SECRET", Valid: true},
+		TotpEnabledAt:   sql.NullTime{Time: now, Valid: true},
+		TotpLastStep:    100,
+	}
+
+	t.Run("success query find user by ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findUserByID)).
+			WithArgs(expected.ID).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"name",
+				"email",
+				"password",
+				"created_at",
+				"updated_at",
+				"role",
+				"email_verified_at",
+				"totp_secret",
+				"totp_enabled_at",
+				"totp_last_step",
+				"deleted_at",
+			}).AddRow(
+				expected.ID,
+				expected.Name,
+				expected.Email,
+				expected.Password,
+				expected.CreatedAt,
+				expected.UpdatedAt,
+				expected.Role,
+				expected.EmailVerifiedAt,
+				expected.TotpSecret,
+				expected.TotpEnabledAt,
+				expected.TotpLastStep,
+				expected.DeletedAt,
+			))
+
+		res, err := q.FindUserByID(context.Background(), expected.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query find user by ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findUserByID)).
+			WithArgs(expected.ID).
+			WillReturnError(errQuery)
+
+		res, err := q.FindUserByID(context.Background(), expected.ID)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestUpdateUserPasswordByID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	req := UpdateUserPasswordByIDParams{
+		ID:       uuid.New(),
+		Password: "hashed",
+	}
+
+	t.Run("success query update user password by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(updateUserPasswordByID)).
+			WithArgs(req.ID, req.Password).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err := q.UpdateUserPasswordByID(context.Background(), req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query update user password by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(updateUserPasswordByID)).
+			WithArgs(req.ID, req.Password).
+			WillReturnError(errQuery)
+
+		err := q.UpdateUserPasswordByID(context.Background(), req)
+		assert.Error(t, err)
+	})
+}
+
+func TestUpdateUserProfileByID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	req := UpdateUserProfileByIDParams{
+		Name:  "Giri Putra Adhittana",
+		Email: "new@gmail.com",
+		ID:    uuid.New(),
+	}
+
+	expected := User{
+		ID:        req.ID,
+		Name:      req.Name,
+		Email:     req.Email,
+		Password:  "hashed",
+		CreatedAt: now,
+		UpdatedAt: now,
+		Role:      "customer",
+	}
+
+	t.Run("success query update user profile by ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(updateUserProfileByID)).
+			WithArgs(req.Name, req.Email, req.ID).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id",
+				"name",
+				"email",
+				"password",
+				"created_at",
+				"updated_at",
+				"role",
+				"email_verified_at",
+				"totp_secret",
+				"totp_enabled_at",
+				"totp_last_step",
+				"deleted_at",
+			}).AddRow(
+				expected.ID,
+				expected.Name,
+				expected.Email,
+				expected.Password,
+				expected.CreatedAt,
+				expected.UpdatedAt,
+				expected.Role,
+				expected.EmailVerifiedAt,
+				expected.TotpSecret,
+				expected.TotpEnabledAt,
+				expected.TotpLastStep,
+				expected.DeletedAt,
+			))
+
+		res, err := q.UpdateUserProfileByID(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query update user profile by ID", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(updateUserProfileByID)).
+			WithArgs(req.Name, req.Email, req.ID).
+			WillReturnError(errQuery)
+
+		res, err := q.UpdateUserProfileByID(context.Background(), req)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestVerifyUserEmailByID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	userID := uuid.New()
+
+	t.Run("success query verify user email by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(verifyUserEmailByID)).
+			WithArgs(userID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err := q.VerifyUserEmailByID(context.Background(), userID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query verify user email by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(verifyUserEmailByID)).
+			WithArgs(userID).
+			WillReturnError(errQuery)
+
+		err := q.VerifyUserEmailByID(context.Background(), userID)
+		assert.Error(t, err)
+	})
+}
+
+func TestIsUserEmailVerified(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	userID := uuid.New()
+
+	t.Run("success query is user email verified", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(isUserEmailVerified)).
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows([]string{"is_verified"}).AddRow(true))
+
+		isVerified, err := q.IsUserEmailVerified(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.True(t, isVerified)
+	})
+
+	t.Run("failed query is user email verified", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(isUserEmailVerified)).
+			WithArgs(userID).
+			WillReturnError(errQuery)
+
+		isVerified, err := q.IsUserEmailVerified(context.Background(), userID)
+		assert.Error(t, err)
+		assert.False(t, isVerified)
+	})
+}
+
+func TestSetUserTotpSecretByID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	req := SetUserTotpSecretByIDParams{
+		ID:         uuid.New(),
+		TotpSecret: sql.NullString{String: "SECRET", Valid: true},
+	}
+
+	t.Run("success query set user totp secret by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(setUserTotpSecretByID)).
+			WithArgs(req.ID, req.TotpSecret).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err := q.SetUserTotpSecretByID(context.Background(), req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query set user totp secret by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(setUserTotpSecretByID)).
+			WithArgs(req.ID, req.TotpSecret).
+			WillReturnError(errQuery)
+
+		err := q.SetUserTotpSecretByID(context.Background(), req)
+		assert.Error(t, err)
+	})
+}
+
+func TestEnableUserTotpByID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	req := EnableUserTotpByIDParams{
+		ID:           uuid.New(),
+		TotpLastStep: 100,
+	}
+
+	t.Run("success query enable user totp by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(enableUserTotpByID)).
+			WithArgs(req.ID, req.TotpLastStep).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err := q.EnableUserTotpByID(context.Background(), req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query enable user totp by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(enableUserTotpByID)).
+			WithArgs(req.ID, req.TotpLastStep).
+			WillReturnError(errQuery)
+
+		err := q.EnableUserTotpByID(context.Background(), req)
+		assert.Error(t, err)
+	})
+}
+
+func TestUpdateUserTotpLastStepByID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	req := UpdateUserTotpLastStepByIDParams{
+		ID:           uuid.New(),
+		TotpLastStep: 101,
+	}
+
+	t.Run("success query update user totp last step by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(updateUserTotpLastStepByID)).
+			WithArgs(req.ID, req.TotpLastStep).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		rows, err := q.UpdateUserTotpLastStepByID(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), rows)
+	})
+
+	t.Run("replayed step updates nothing", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(updateUserTotpLastStepByID)).
+			WithArgs(req.ID, req.TotpLastStep).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+		rows, err := q.UpdateUserTotpLastStepByID(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), rows)
+	})
+
+	t.Run("failed query update user totp last step by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(updateUserTotpLastStepByID)).
+			WithArgs(req.ID, req.TotpLastStep).
+			WillReturnError(errQuery)
+
+		rows, err := q.UpdateUserTotpLastStepByID(context.Background(), req)
+		assert.Error(t, err)
+		assert.Empty(t, rows)
+	})
+}
+
+func TestAnonymizeUserByID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	userID := uuid.New()
+
+	t.Run("success query anonymize user by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(anonymizeUserByID)).
+			WithArgs(userID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err := q.AnonymizeUserByID(context.Background(), userID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query anonymize user by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(anonymizeUserByID)).
+			WithArgs(userID).
+			WillReturnError(errQuery)
+
+		err := q.AnonymizeUserByID(context.Background(), userID)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,97 @@
+package querier
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateUserRecoveryCodes(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	params := CreateUserRecoveryCodesParams{
+		UserID:     uuid.New(),
+		CodeHashes: []string{"hash1", "hash2"},
+	}
+
+	t.Run("success query create user recovery codes", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(createUserRecoveryCodes)).
+			WithArgs(params.UserID, params.CodeHashes).
+			WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+		err := q.CreateUserRecoveryCodes(context.Background(), params)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query create user recovery codes", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(createUserRecoveryCodes)).
+			WithArgs(params.UserID, params.CodeHashes).
+			WillReturnError(errQuery)
+
+		err := q.CreateUserRecoveryCodes(context.Background(), params)
+		assert.Error(t, err)
+	})
+}
+
+func TestDeleteUserRecoveryCodesByUserID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	userID := uuid.New()
+
+	t.Run("success query delete user recovery codes by user ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(deleteUserRecoveryCodesByUserID)).
+			WithArgs(userID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 10))
+
+		err := q.DeleteUserRecoveryCodesByUserID(context.Background(), userID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query delete user recovery codes by user ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(deleteUserRecoveryCodesByUserID)).
+			WithArgs(userID).
+			WillReturnError(errQuery)
+
+		err := q.DeleteUserRecoveryCodesByUserID(context.Background(), userID)
+		assert.Error(t, err)
+	})
+}
+
+func TestUseUserRecoveryCode(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	params := UseUserRecoveryCodeParams{
+		UserID:   uuid.New(),
+		CodeHash: "hash",
+	}
+
+	t.Run("success query use user recovery code", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(useUserRecoveryCode)).
+			WithArgs(params.UserID, params.CodeHash).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		rows, err := q.UseUserRecoveryCode(context.Background(), params)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), rows)
+	})
+
+	t.Run("failed query use user recovery code", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(useUserRecoveryCode)).
+			WithArgs(params.UserID, params.CodeHash).
+			WillReturnError(errQuery)
+
+		rows, err := q.UseUserRecoveryCode(context.Background(), params)
+		assert.Error(t, err)
+		assert.Empty(t, rows)
+	})
+}
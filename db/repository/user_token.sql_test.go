@@ -0,0 +1,177 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+var userTokenColumns = []string{
+	"id",
+	"user_id",
+	"purpose",
+	"token_hash",
+	"expires_at",
+	"used_at",
+	"created_at",
+}
+
+func TestCreateUserToken(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	expected := UserToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Purpose:   "password_reset",
+		TokenHash: "hash",
+		ExpiresAt: now.Add(time.Hour),
+		CreatedAt: now,
+	}
+	params := CreateUserTokenParams{
+		UserID:    expected.UserID,
+		Purpose:   expected.Purpose,
+		TokenHash: expected.TokenHash,
+		ExpiresAt: expected.ExpiresAt,
+	}
+
+	t.Run("success query create user token", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(createUserToken)).
+			WithArgs(params.UserID, params.Purpose, params.TokenHash, params.ExpiresAt).
+			WillReturnRows(pgxmock.NewRows(userTokenColumns).AddRow(
+				expected.ID,
+				expected.UserID,
+				expected.Purpose,
+				expected.TokenHash,
+				expected.ExpiresAt,
+				expected.UsedAt,
+				expected.CreatedAt,
+			))
+
+		res, err := q.CreateUserToken(context.Background(), params)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query create user token", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(createUserToken)).
+			WithArgs(params.UserID, params.Purpose, params.TokenHash, params.ExpiresAt).
+			WillReturnError(errQuery)
+
+		res, err := q.CreateUserToken(context.Background(), params)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestFindUserTokenByHashForUpdate(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+	now := time.Now()
+
+	expected := UserToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Purpose:   "email_verification",
+		TokenHash: "hash",
+		ExpiresAt: now.Add(time.Hour),
+		UsedAt:    sql.NullTime{Time: now, Valid: true},
+		CreatedAt: now,
+	}
+	params := FindUserTokenByHashForUpdateParams{
+		TokenHash: expected.TokenHash,
+		Purpose:   expected.Purpose,
+	}
+
+	t.Run("success query find user token by hash", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findUserTokenByHashForUpdate)).
+			WithArgs(params.TokenHash, params.Purpose).
+			WillReturnRows(pgxmock.NewRows(userTokenColumns).AddRow(
+				expected.ID,
+				expected.UserID,
+				expected.Purpose,
+				expected.TokenHash,
+				expected.ExpiresAt,
+				expected.UsedAt,
+				expected.CreatedAt,
+			))
+
+		res, err := q.FindUserTokenByHashForUpdate(context.Background(), params)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("failed query find user token by hash", func(t *testing.T) {
+		mockDB.ExpectQuery(regexp.QuoteMeta(findUserTokenByHashForUpdate)).
+			WithArgs(params.TokenHash, params.Purpose).
+			WillReturnError(errQuery)
+
+		res, err := q.FindUserTokenByHashForUpdate(context.Background(), params)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestMarkUserTokenUsedByID(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	tokenID := uuid.New()
+
+	t.Run("success query mark user token used by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(markUserTokenUsedByID)).
+			WithArgs(tokenID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err := q.MarkUserTokenUsedByID(context.Background(), tokenID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query mark user token used by ID", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(markUserTokenUsedByID)).
+			WithArgs(tokenID).
+			WillReturnError(errQuery)
+
+		err := q.MarkUserTokenUsedByID(context.Background(), tokenID)
+		assert.Error(t, err)
+	})
+}
+
+func TestInvalidateUserTokens(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	defer mockDB.Close()
+	q := NewRepository(mockDB)
+
+	params := InvalidateUserTokensParams{
+		UserID:  uuid.New(),
+		Purpose: "password_reset",
+	}
+
+	t.Run("success query invalidate user tokens", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(invalidateUserTokens)).
+			WithArgs(params.UserID, params.Purpose).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+
+		err := q.InvalidateUserTokens(context.Background(), params)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed query invalidate user tokens", func(t *testing.T) {
+		mockDB.ExpectExec(regexp.QuoteMeta(invalidateUserTokens)).
+			WithArgs(params.UserID, params.Purpose).
+			WillReturnError(errQuery)
+
+		err := q.InvalidateUserTokens(context.Background(), params)
+		assert.Error(t, err)
+	})
+}
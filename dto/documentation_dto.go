@@ -16,12 +16,23 @@ type ErrorMsgResp struct {
 	Message string `json:"message"`
 }
 
+type FieldErrorMsgResp struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
 type FailedResp400 struct {
 	Success    bool           `json:"success" default:"false"`
 	StatusCode int            `json:"statusCode" default:"400"`
 	Errors     []ErrorMsgResp `json:"errors"`
 }
 
+type FailedValidationResp400 struct {
+	Success    bool                `json:"success" default:"false"`
+	StatusCode int                 `json:"statusCode" default:"400"`
+	Errors     []FieldErrorMsgResp `json:"errors"`
+}
+
 type FailedResp401 struct {
 	Success    bool           `json:"success" default:"false"`
 	StatusCode int            `json:"statusCode" default:"401"`
@@ -40,12 +51,24 @@ type FailedResp422 struct {
 	Errors     []ErrorMsgResp `json:"errors"`
 }
 
+type FailedResp429 struct {
+	Success    bool           `json:"success" default:"false"`
+	StatusCode int            `json:"statusCode" default:"429"`
+	Errors     []ErrorMsgResp `json:"errors"`
+}
+
 type FailedResp500 struct {
 	Success    bool           `json:"success" default:"false"`
 	StatusCode int            `json:"statusCode" default:"500"`
 	Errors     []ErrorMsgResp `json:"errors"`
 }
 
+type FailedReadinessResp503 struct {
+	Success    bool         `json:"success" default:"false"`
+	StatusCode int          `json:"statusCode" default:"503"`
+	Data       ReadinessRes `json:"data"`
+}
+
 type DocPaginationResp struct {
 	Total      int  `json:"total"`
 	IsLoadMore bool `json:"isLoadMore"`
@@ -1,5 +1,15 @@
 package dto
 
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
 type Next struct {
 	Page int `json:"page"`
 }
@@ -9,11 +19,53 @@ type Prev struct {
 }
 
 type PaginationResp[T any] struct {
-	Total      int  `json:"total"`
-	IsLoadMore bool `json:"isLoadMore"`
-	Data       []T  `json:"data"`
-	Next       Next `json:"next"`
-	Prev       Prev `json:"prev"`
+	Total      int    `json:"total"`
+	IsLoadMore bool   `json:"isLoadMore"`
+	Data       []T    `json:"data"`
+	Next       Next   `json:"next"`
+	Prev       Prev   `json:"prev"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+}
+
+// Cursor marks a position in a list ordered by (Time, ID) descending.
+// Backward cursors walk towards newer rows. The zero value is the first page.
+type Cursor struct {
+	Time     time.Time `json:"t"`
+	ID       uuid.UUID `json:"id"`
+	Backward bool      `json:"b,omitempty"`
+}
+
+func (c Cursor) IsZero() bool {
+	return c.ID == uuid.Nil
+}
+
+func EncodeCursor(cursor Cursor) string {
+	b, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCursor(value string) (Cursor, error) {
+	var cursor Cursor
+	if value == "" {
+		return cursor, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, err
+	}
+
+	err = json.Unmarshal(b, &cursor)
+	if err != nil {
+		return cursor, err
+	}
+
+	if cursor.IsZero() || cursor.Time.IsZero() {
+		return cursor, errors.New("cursor is missing its position")
+	}
+
+	return cursor, nil
 }
 
 func ToPaginationResp[T any](data []T, page int, limit int, total int) PaginationResp[T] {
@@ -47,3 +99,51 @@ func ToPaginationResp[T any](data []T, page int, limit int, total int) Paginatio
 		Data:       data,
 	}
 }
+
+// ToCursorPaginationResp builds a cursor page from rows fetched with one row
+// more than limit; the extra row only tells whether more data exists in the
+// direction of travel. Backward pages are fetched oldest first and are
+// reversed here. Pass a negative total when the count was skipped.
+func ToCursorPaginationResp[R any, T any](
+	rows []R,
+	limit int,
+	cursor Cursor,
+	total int,
+	position func(R) Cursor,
+	mapper func(R) T,
+) PaginationResp[T] {
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	if cursor.Backward {
+		rows = lo.Reverse(append([]R{}, rows...))
+	}
+
+	resp := PaginationResp[T]{
+		Total: total,
+		Data: lo.Map(rows, func(row R, _ int) T {
+			return mapper(row)
+		}),
+		Next: Next{Page: -1},
+		Prev: Prev{Page: -1},
+	}
+
+	if len(rows) == 0 {
+		return resp
+	}
+
+	if hasMore || cursor.Backward {
+		resp.NextCursor = EncodeCursor(position(rows[len(rows)-1]))
+	}
+
+	if (hasMore && cursor.Backward) || (!cursor.Backward && !cursor.IsZero()) {
+		prev := position(rows[0])
+		prev.Backward = true
+		resp.PrevCursor = EncodeCursor(prev)
+	}
+	resp.IsLoadMore = resp.NextCursor != ""
+
+	return resp
+}
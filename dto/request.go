@@ -1,6 +1,9 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
 
 type SignUpReq struct {
 	Name     string `json:"name" validate:"required"`
@@ -9,36 +12,154 @@ type SignUpReq struct {
 }
 
 type SignInReq struct {
+	IP       string `json:"-"`
 	Email    string `json:"email" validate:"required"`
 	Password string `json:"password" validate:"required"`
 }
 
+type SignInTwoFactorReq struct {
+	IP             string `json:"-"`
+	ChallengeToken string `json:"challengeToken" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+type VerifyTwoFactorReq struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type RefreshTokenReq struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type SignOutReq struct {
+	AccessToken  string `json:"-"`
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type SignOutAllReq struct {
+	AccessToken string `json:"-"`
+}
+
+type ForgotPasswordReq struct {
+	Email string `json:"email" validate:"required"`
+}
+
+type ResetPasswordReq struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type VerifyEmailReq struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type UpdateProfileReq struct {
+	Name            *string `json:"name" validate:"omitempty,min=1"`
+	Email           *string `json:"email" validate:"omitempty,min=1"`
+	CurrentPassword string  `json:"currentPassword"`
+}
+
+type ChangePasswordReq struct {
+	OldPassword string `json:"oldPassword" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required"`
+}
+
+type DeleteAccountReq struct {
+	AccessToken string `json:"-"`
+}
+
 type OrderDetailReq struct {
 	BookID   string `json:"bookId" validate:"required"`
 	Quantity int    `json:"quantity" validate:"required"`
 }
 
 type CreateOrderReq struct {
-	OrderDetail []OrderDetailReq `json:"orderDetail" validate:"required"`
+	IdempotencyKey string           `json:"-"`
+	OrderDetail    []OrderDetailReq `json:"orderDetail" validate:"required"`
 }
 
 type GetOrderReq struct {
-	Page  int32 `json:"page"`
-	Limit int32 `json:"limit"`
+	Page       int32  `json:"page"`
+	Limit      int32  `json:"limit"`
+	CursorMode bool   `json:"cursorMode"`
+	Cursor     Cursor `json:"cursor"`
+	WithTotal  bool   `json:"withTotal"`
 }
 
 type GetOrderDetailReq struct {
 	OrderID uuid.UUID `json:"orderId" validate:"required"`
 }
 
+type CancelOrderReq struct {
+	OrderID uuid.UUID `json:"orderId" validate:"required"`
+}
+
+type UpdateOrderStatusReq struct {
+	OrderID uuid.UUID `json:"orderId" validate:"required"`
+	Status  string    `json:"status" validate:"required"`
+}
+
 type CreateBookReq struct {
-	Title       string  `json:"title" validate:"required"`
-	Description string  `json:"description" validate:"required"`
-	Author      string  `json:"author" validate:"required"`
-	Price       float64 `json:"price" validate:"required"`
+	Title       string          `json:"title" validate:"required"`
+	Description string          `json:"description" validate:"required"`
+	Author      string          `json:"author" validate:"required"`
+	Price       decimal.Decimal `json:"price"`
+	Stock       int             `json:"stock"`
 }
 
 type GetBookReq struct {
-	Page  int32 `json:"page"`
-	Limit int32 `json:"limit"`
+	Page       int32               `json:"page"`
+	Limit      int32               `json:"limit"`
+	Search     string              `json:"search"`
+	Author     string              `json:"author"`
+	MinPrice   decimal.NullDecimal `json:"minPrice"`
+	MaxPrice   decimal.NullDecimal `json:"maxPrice"`
+	SortBy     string              `json:"sortBy"`
+	SortDir    string              `json:"sortDir"`
+	CursorMode bool                `json:"cursorMode"`
+	Cursor     Cursor              `json:"cursor"`
+	WithTotal  bool                `json:"withTotal"`
+}
+
+type GetBookByIDReq struct {
+	BookID uuid.UUID `json:"bookId" validate:"required"`
+}
+
+type UpdateBookReq struct {
+	Title       string          `json:"title" validate:"required"`
+	Description string          `json:"description" validate:"required"`
+	Author      string          `json:"author" validate:"required"`
+	Price       decimal.Decimal `json:"price"`
+}
+
+type PatchBookReq struct {
+	BookID      uuid.UUID        `json:"-"`
+	Title       *string          `json:"title" validate:"omitempty,min=1"`
+	Description *string          `json:"description" validate:"omitempty,min=1"`
+	Author      *string          `json:"author" validate:"omitempty,min=1"`
+	Price       *decimal.Decimal `json:"price"`
+}
+
+type DeleteBookReq struct {
+	BookID uuid.UUID `json:"bookId" validate:"required"`
+}
+
+type AdjustBookStockReq struct {
+	BookID   uuid.UUID `json:"-"`
+	Quantity int       `json:"quantity" validate:"required"`
+	Reason   string    `json:"reason" validate:"required,oneof=restock damaged correction returned"`
+}
+
+type AddCartItemReq struct {
+	BookID   string `json:"bookId" validate:"required,uuid"`
+	Quantity int    `json:"quantity" validate:"required,gt=0"`
+}
+
+type UpdateCartItemReq struct {
+	BookID   uuid.UUID `json:"-"`
+	Quantity int       `json:"quantity" validate:"required,gt=0"`
+}
+
+type DeleteCartItemReq struct {
+	BookID uuid.UUID `json:"bookId" validate:"required"`
 }
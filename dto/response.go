@@ -1,64 +1,139 @@
 package dto
 
+import "github.com/shopspring/decimal"
+
 type SignUpRes struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Token    string `json:"token"`
-	ExpToken int64  `json:"expToken"`
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Role            string `json:"role"`
+	Token           string `json:"token"`
+	ExpToken        int64  `json:"expToken"`
+	RefreshToken    string `json:"refreshToken"`
+	ExpRefreshToken int64  `json:"expRefreshToken"`
 }
 
+// SignInRes carries the session tokens, or only a challenge token when the
+// account has two-factor authentication and a code still has to be given.
 type SignInRes struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Token    string `json:"token"`
-	ExpToken int64  `json:"expToken"`
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	Role              string `json:"role"`
+	Token             string `json:"token"`
+	ExpToken          int64  `json:"expToken"`
+	RefreshToken      string `json:"refreshToken"`
+	ExpRefreshToken   int64  `json:"expRefreshToken"`
+	TwoFactorRequired bool   `json:"twoFactorRequired"`
+	ChallengeToken    string `json:"challengeToken,omitempty"`
+	ExpChallengeToken int64  `json:"expChallengeToken,omitempty"`
+}
+
+type EnrollTwoFactorRes struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauthUri"`
+}
+
+type VerifyTwoFactorRes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type ProfileRes struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	Email            string `json:"email"`
+	Role             string `json:"role"`
+	EmailVerified    bool   `json:"emailVerified"`
+	TwoFactorEnabled bool   `json:"twoFactorEnabled"`
+	CreatedAt        string `json:"createdAt"`
+	UpdatedAt        string `json:"updatedAt"`
+}
+
+// ExportDataRes is the archive of everything stored about a user.
+type ExportDataRes struct {
+	Profile    ProfileRes          `json:"profile"`
+	Orders     []GetOrderDetailRes `json:"orders"`
+	ExportedAt string              `json:"exportedAt"`
+}
+
+type RefreshTokenRes struct {
+	Token           string `json:"token"`
+	ExpToken        int64  `json:"expToken"`
+	RefreshToken    string `json:"refreshToken"`
+	ExpRefreshToken int64  `json:"expRefreshToken"`
 }
 
 type OrderDetail struct {
-	OrderDetailID string `json:"orderDetailId"`
-	BookID        string `json:"bookId"`
-	Title         string `json:"title"`
-	Description   string `json:"description"`
-	Author        string `json:"author"`
-	Quantity      int    `json:"quantity"`
+	OrderDetailID string          `json:"orderDetailId"`
+	BookID        string          `json:"bookId"`
+	Title         string          `json:"title"`
+	Description   string          `json:"description"`
+	Author        string          `json:"author"`
+	Quantity      int             `json:"quantity"`
+	UnitPrice     decimal.Decimal `json:"unitPrice"`
+	LineTotal     decimal.Decimal `json:"lineTotal"`
 }
 
 type CreateOrderRes struct {
-	OrderId    string  `json:"orderId"`
-	Date       string  `json:"date"`
-	TotalPrice float64 `json:"totalPrice"`
-	Status     string  `json:"status"`
+	OrderId    string          `json:"orderId"`
+	Date       string          `json:"date"`
+	TotalPrice decimal.Decimal `json:"totalPrice"`
+	Status     string          `json:"status"`
 }
 
 type GetOrderRes struct {
-	OrderId    string  `json:"orderId"`
-	Date       string  `json:"date"`
-	TotalPrice float64 `json:"totalPrice"`
-	Status     string  `json:"status"`
+	OrderId    string          `json:"orderId"`
+	Date       string          `json:"date"`
+	TotalPrice decimal.Decimal `json:"totalPrice"`
+	Status     string          `json:"status"`
 }
 
 type GetOrderDetailRes struct {
-	OrderId     string        `json:"orderId"`
-	Date        string        `json:"date"`
-	TotalPrice  float64       `json:"totalPrice"`
-	Status      string        `json:"status"`
-	OrderDetail []OrderDetail `json:"orderDetail"`
+	OrderId     string          `json:"orderId"`
+	Date        string          `json:"date"`
+	TotalPrice  decimal.Decimal `json:"totalPrice"`
+	Status      string          `json:"status"`
+	OrderDetail []OrderDetail   `json:"orderDetail"`
+}
+
+type UpdateOrderStatusRes struct {
+	OrderId    string          `json:"orderId"`
+	Date       string          `json:"date"`
+	TotalPrice decimal.Decimal `json:"totalPrice"`
+	Status     string          `json:"status"`
 }
 
 type CreateBookRes struct {
-	ID          string  `json:"id"`
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	Author      string  `json:"author"`
-	Price       float64 `json:"price"`
+	ID          string          `json:"id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Author      string          `json:"author"`
+	Price       decimal.Decimal `json:"price"`
+	Stock       int             `json:"stock"`
 }
 
 type GetBookRes struct {
-	ID          string  `json:"id"`
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	Author      string  `json:"author"`
-	Price       float64 `json:"price"`
+	ID          string          `json:"id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Author      string          `json:"author"`
+	Price       decimal.Decimal `json:"price"`
+	Stock       int             `json:"stock"`
+}
+
+type UpdateBookRes struct {
+	ID          string          `json:"id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Author      string          `json:"author"`
+	Price       decimal.Decimal `json:"price"`
+}
+
+type AdjustBookStockRes struct {
+	ID    string `json:"id"`
+	Stock int    `json:"stock"`
+}
+
+type DeleteBookRes struct {
+	ID string `json:"id"`
 }
 
 type GetBookPuchasedByUserRes struct {
@@ -66,3 +141,30 @@ type GetBookPuchasedByUserRes struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 }
+
+type CartItem struct {
+	BookID    string          `json:"bookId"`
+	Title     string          `json:"title"`
+	Author    string          `json:"author"`
+	Quantity  int             `json:"quantity"`
+	UnitPrice decimal.Decimal `json:"unitPrice"`
+	LineTotal decimal.Decimal `json:"lineTotal"`
+	Stock     int             `json:"stock"`
+	Available bool            `json:"available"`
+}
+
+type GetCartRes struct {
+	Items      []CartItem      `json:"items"`
+	TotalPrice decimal.Decimal `json:"totalPrice"`
+}
+
+type DependencyStatus struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latencyMs"`
+	Error     string `json:"error,omitempty"`
+}
+
+type ReadinessRes struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+}
@@ -2,12 +2,26 @@ package handler
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gadhittana-01/book-go/constant"
 	"github.com/gadhittana-01/book-go/dto"
+	"github.com/gadhittana-01/book-go/middleware"
 	"github.com/gadhittana-01/book-go/service"
 	"github.com/gadhittana01/go-modules/utils"
 	"github.com/go-chi/chi"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	InvalidPrice       = "Price must greater than zero"
+	InvalidStock       = "Stock cannot be negative"
+	InvalidPriceFilter = "Price filter must be a non-negative number"
+	InvalidPriceRange  = "Min price cannot be greater than max price"
+	InvalidSortBy      = "Sort field must be one of created_at, price or title"
+	InvalidSortDir     = "Sort direction must be asc or desc"
+	InvalidCursorSort  = "Cursor pagination only supports sorting by created_at desc"
 )
 
 type BookHandler interface {
@@ -15,17 +29,23 @@ type BookHandler interface {
 }
 
 type BookHandlerImpl struct {
-	bookSvc        service.BookSvc
-	authMiddleware utils.AuthMiddleware
+	bookSvc             service.BookSvc
+	authMiddleware      utils.AuthMiddleware
+	roleMiddleware      middleware.RoleMiddleware
+	rateLimitMiddleware middleware.RateLimitMiddleware
 }
 
 func NewBookHandler(
 	bookSvc service.BookSvc,
 	authMiddleware utils.AuthMiddleware,
+	roleMiddleware middleware.RoleMiddleware,
+	rateLimitMiddleware middleware.RateLimitMiddleware,
 ) BookHandler {
 	return &BookHandlerImpl{
-		bookSvc:        bookSvc,
-		authMiddleware: authMiddleware,
+		bookSvc:             bookSvc,
+		authMiddleware:      authMiddleware,
+		roleMiddleware:      roleMiddleware,
+		rateLimitMiddleware: rateLimitMiddleware,
 	}
 }
 
@@ -36,6 +56,14 @@ func (h *BookHandlerImpl) SetupBookRoutes(route *chi.Mux) {
 func (h *BookHandlerImpl) CreateBook(w http.ResponseWriter, r *http.Request) {
 	input := utils.ValidateBodyPayload(r.Body, &dto.CreateBookReq{})
 
+	if !input.Price.IsPositive() {
+		utils.PanicAppError(InvalidPrice, 400)
+	}
+
+	if input.Stock < 0 {
+		utils.PanicAppError(InvalidStock, 400)
+	}
+
 	resp := h.bookSvc.CreateBook(r.Context(), input)
 
 	utils.GenerateSuccessResp(w, resp, http.StatusCreated)
@@ -45,9 +73,42 @@ func (h *BookHandlerImpl) GetBook(w http.ResponseWriter, r *http.Request) {
 	page := utils.ValidateQueryParamInt(r, "page", 1)
 	limit := utils.ValidateQueryParamInt(r, "limit", constant.DefaultLimit)
 
+	query := r.URL.Query()
+	minPrice := parsePriceQueryParam(r, "minPrice")
+	maxPrice := parsePriceQueryParam(r, "maxPrice")
+	if minPrice.Valid && maxPrice.Valid && minPrice.Decimal.GreaterThan(maxPrice.Decimal) {
+		utils.PanicAppError(InvalidPriceRange, 400)
+	}
+
+	sortBy := lo.CoalesceOrEmpty(query.Get("sortBy"), constant.BookSortCreatedAt)
+	if !lo.Contains([]string{constant.BookSortCreatedAt, constant.BookSortPrice, constant.BookSortTitle}, sortBy) {
+		utils.PanicAppError(InvalidSortBy, 400)
+	}
+
+	sortDir := lo.CoalesceOrEmpty(query.Get("sortDir"), constant.SortDesc)
+	if !lo.Contains([]string{constant.SortAsc, constant.SortDesc}, sortDir) {
+		utils.PanicAppError(InvalidSortDir, 400)
+	}
+
+	// the cursor is a (created_at, id) position, so it only works with the
+	// default ordering
+	pagination := parseCursorQueryParams(r)
+	if pagination.enabled && (sortBy != constant.BookSortCreatedAt || sortDir != constant.SortDesc) {
+		utils.PanicAppError(InvalidCursorSort, 400)
+	}
+
 	resp := h.bookSvc.GetBook(r.Context(), dto.GetBookReq{
-		Page:  int32(page),
-		Limit: int32(limit),
+		Page:       int32(page),
+		Limit:      int32(limit),
+		Search:     strings.TrimSpace(query.Get("search")),
+		Author:     strings.TrimSpace(query.Get("author")),
+		MinPrice:   minPrice,
+		MaxPrice:   maxPrice,
+		SortBy:     sortBy,
+		SortDir:    sortDir,
+		CursorMode: pagination.enabled,
+		Cursor:     pagination.cursor,
+		WithTotal:  pagination.withTotal,
 	})
 
 	utils.GenerateSuccessResp(w, resp, http.StatusOK)
@@ -59,8 +120,103 @@ func (h *BookHandlerImpl) GetBookPuchasedByUser(w http.ResponseWriter, r *http.R
 	utils.GenerateSuccessResp(w, resp, http.StatusOK)
 }
 
+func (h *BookHandlerImpl) GetBookByID(w http.ResponseWriter, r *http.Request) {
+	bookID := utils.ValidateURLParamUUID(r, "bookId")
+
+	resp := h.bookSvc.GetBookByID(r.Context(), dto.GetBookByIDReq{
+		BookID: bookID,
+	})
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+func (h *BookHandlerImpl) UpdateBook(w http.ResponseWriter, r *http.Request) {
+	bookID := utils.ValidateURLParamUUID(r, "bookId")
+	input := utils.ValidateBodyPayload(r.Body, &dto.UpdateBookReq{})
+
+	if !input.Price.IsPositive() {
+		utils.PanicAppError(InvalidPrice, 400)
+	}
+
+	resp := h.bookSvc.UpdateBook(r.Context(), dto.PatchBookReq{
+		BookID:      bookID,
+		Title:       &input.Title,
+		Description: &input.Description,
+		Author:      &input.Author,
+		Price:       &input.Price,
+	})
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+func (h *BookHandlerImpl) PatchBook(w http.ResponseWriter, r *http.Request) {
+	bookID := utils.ValidateURLParamUUID(r, "bookId")
+	input := utils.ValidateBodyPayload(r.Body, &dto.PatchBookReq{})
+	input.BookID = bookID
+
+	if input.Price != nil && !input.Price.IsPositive() {
+		utils.PanicAppError(InvalidPrice, 400)
+	}
+
+	resp := h.bookSvc.UpdateBook(r.Context(), input)
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+func (h *BookHandlerImpl) DeleteBook(w http.ResponseWriter, r *http.Request) {
+	bookID := utils.ValidateURLParamUUID(r, "bookId")
+
+	resp := h.bookSvc.DeleteBook(r.Context(), dto.DeleteBookReq{
+		BookID: bookID,
+	})
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+func (h *BookHandlerImpl) AdjustBookStock(w http.ResponseWriter, r *http.Request) {
+	bookID := utils.ValidateURLParamUUID(r, "bookId")
+	input := utils.ValidateBodyPayload(r.Body, &dto.AdjustBookStockReq{})
+	input.BookID = bookID
+
+	resp := h.bookSvc.AdjustBookStock(r.Context(), input)
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+// catalogManager restricts catalog writes to admin and staff accounts.
+func (h *BookHandlerImpl) catalogManager(handler http.HandlerFunc) http.HandlerFunc {
+	return h.roleMiddleware.CheckHasRole(handler, constant.RoleAdmin, constant.RoleStaff)
+}
+
+// rateLimited counts a request against the per-user limit of the book routes.
+func (h *BookHandlerImpl) rateLimited(handler http.HandlerFunc) http.HandlerFunc {
+	return h.rateLimitMiddleware.Limit(constant.RateLimitBook, handler)
+}
+
+// parsePriceQueryParam reads an optional price filter, leaving it null when
+// the parameter is absent.
+func parsePriceQueryParam(r *http.Request, key string) decimal.NullDecimal {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return decimal.NullDecimal{}
+	}
+
+	price, err := decimal.NewFromString(value)
+	if err != nil || price.IsNegative() {
+		utils.PanicAppError(InvalidPriceFilter, 400)
+	}
+
+	return decimal.NewNullDecimal(price)
+}
+
 func setupBookV1Routes(route *chi.Mux, h *BookHandlerImpl) {
-	route.Post("/v1/book", h.authMiddleware.CheckIsAuthenticated(h.CreateBook))
-	route.Get("/v1/book", h.authMiddleware.CheckIsAuthenticated(h.GetBook))
+	route.Post("/v1/book", h.authMiddleware.CheckIsAuthenticated(h.rateLimited(h.catalogManager(h.CreateBook))))
+	route.Get("/v1/book", h.authMiddleware.CheckIsAuthenticated(h.rateLimited(h.GetBook)))
+	route.Get("/v1/book/{bookId}", h.authMiddleware.CheckIsAuthenticated(h.rateLimited(h.GetBookByID)))
+	route.Put("/v1/book/{bookId}", h.authMiddleware.CheckIsAuthenticated(h.rateLimited(h.catalogManager(h.UpdateBook))))
+	route.Patch("/v1/book/{bookId}", h.authMiddleware.CheckIsAuthenticated(h.rateLimited(h.catalogManager(h.PatchBook))))
+	route.Delete("/v1/book/{bookId}", h.authMiddleware.CheckIsAuthenticated(h.rateLimited(h.catalogManager(h.DeleteBook))))
 	route.Get("/v1/user/book", h.authMiddleware.CheckIsAuthenticated(h.GetBookPuchasedByUser))
+	route.Post("/v1/admin/book/{bookId}/stock", h.authMiddleware.CheckIsAuthenticated(
+		h.roleMiddleware.CheckHasRole(h.AdjustBookStock, constant.RoleAdmin)))
 }
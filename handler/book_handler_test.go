@@ -1,20 +1,27 @@
 package handler
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/gadhittana-01/book-go/constant"
 	"github.com/gadhittana-01/book-go/dto"
+	"github.com/gadhittana-01/book-go/middleware"
+	mockmdw "github.com/gadhittana-01/book-go/middleware/mock"
 	"github.com/gadhittana-01/book-go/service"
 	mocksvc "github.com/gadhittana-01/book-go/service/mock"
 	"github.com/gadhittana01/go-modules/utils"
 	mockutl "github.com/gadhittana01/go-modules/utils/mock"
+	"github.com/go-chi/chi"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -22,10 +29,14 @@ func TestNewBookHandler(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	bookMock := mocksvc.NewMockBookSvc(ctrl)
 	middlewareMock := mockutl.NewMockAuthMiddleware(ctrl)
+	roleMiddlewareMock := mockmdw.NewMockRoleMiddleware(ctrl)
+	rateLimitMiddlewareMock := mockmdw.NewMockRateLimitMiddleware(ctrl)
 
 	type args struct {
-		service        service.BookSvc
-		authMiddleware utils.AuthMiddleware
+		service             service.BookSvc
+		authMiddleware      utils.AuthMiddleware
+		roleMiddleware      middleware.RoleMiddleware
+		rateLimitMiddleware middleware.RateLimitMiddleware
 	}
 
 	tests := []struct {
@@ -35,18 +46,22 @@ func TestNewBookHandler(t *testing.T) {
 	}{
 		{
 			args: args{
-				service:        bookMock,
-				authMiddleware: middlewareMock,
+				service:             bookMock,
+				authMiddleware:      middlewareMock,
+				roleMiddleware:      roleMiddlewareMock,
+				rateLimitMiddleware: rateLimitMiddlewareMock,
 			},
 			want: &BookHandlerImpl{
-				bookSvc:        bookMock,
-				authMiddleware: middlewareMock,
+				bookSvc:             bookMock,
+				authMiddleware:      middlewareMock,
+				roleMiddleware:      roleMiddlewareMock,
+				rateLimitMiddleware: rateLimitMiddlewareMock,
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := NewBookHandler(tt.args.service, tt.args.authMiddleware); !reflect.DeepEqual(got, tt.want) {
+			if got := NewBookHandler(tt.args.service, tt.args.authMiddleware, tt.args.roleMiddleware, tt.args.rateLimitMiddleware); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewBookHandler() = %v, want %v", got, tt.want)
 			}
 		})
@@ -58,24 +73,41 @@ func TestCreateBook(t *testing.T) {
 	bookID := uuid.New()
 	title := "Hello"
 	description := "World"
-	price := float64(100)
+	price := decimal.NewFromInt(100)
 	author := "Giri Putra Adhittana"
 
 	sampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/book", strings.NewReader(fmt.Sprintf(`{
 		"title" : "%s",
 		"description" : "%s",
 		"author" : "%s",
-		"price" : %f
+		"price" : %s
 	}`, title, description, author, price)))
 	sampleResp := httptest.NewRecorder()
 
 	invalidSampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/book", strings.NewReader(fmt.Sprintf(`{
 		"description" : "%s",
 		"author" : "%s",
-		"price" : %f
+		"price" : %s
 	}`, description, author, price)))
 	invalidSampleResp := httptest.NewRecorder()
 
+	invalidPriceSampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/book", strings.NewReader(fmt.Sprintf(`{
+		"title" : "%s",
+		"description" : "%s",
+		"author" : "%s",
+		"price" : "-1"
+	}`, title, description, author)))
+	invalidPriceSampleResp := httptest.NewRecorder()
+
+	invalidStockSampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/book", strings.NewReader(fmt.Sprintf(`{
+		"title" : "%s",
+		"description" : "%s",
+		"author" : "%s",
+		"price" : %s,
+		"stock" : -1
+	}`, title, description, author, price)))
+	invalidStockSampleResp := httptest.NewRecorder()
+
 	type fields struct {
 		service service.BookSvc
 	}
@@ -147,6 +179,40 @@ func TestCreateBook(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid price",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   invalidPriceSampleResp,
+				req: invalidPriceSampleReq,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid stock",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   invalidStockSampleResp,
+				req: invalidStockSampleReq,
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -174,7 +240,7 @@ func TestGetBook(t *testing.T) {
 	bookID := uuid.New()
 	title := "Hello"
 	description := "World"
-	price := float64(100)
+	price := decimal.NewFromInt(100)
 	author := "Giri Putra Adhittana"
 	page := 1
 	limit := 10
@@ -186,6 +252,31 @@ func TestGetBook(t *testing.T) {
 	invalidSampleReq := httptest.NewRequest("GET", fmt.Sprintf("http://localhost:8000/v1/book?page=%d&limit=test", page), strings.NewReader(``))
 	invalidSampleResp := httptest.NewRecorder()
 
+	filterSampleReq := httptest.NewRequest("GET", fmt.Sprintf("http://localhost:8000/v1/book?page=%d&limit=%d&search=hello+world&author=Giri&minPrice=10&maxPrice=99.5&sortBy=price&sortDir=asc", page, limit), strings.NewReader(``))
+	filterSampleResp := httptest.NewRecorder()
+
+	invalidPriceSampleReq := httptest.NewRequest("GET", "http://localhost:8000/v1/book?minPrice=abc", strings.NewReader(``))
+	invalidPriceSampleResp := httptest.NewRecorder()
+
+	invalidPriceRangeSampleReq := httptest.NewRequest("GET", "http://localhost:8000/v1/book?minPrice=50&maxPrice=10", strings.NewReader(``))
+	invalidPriceRangeSampleResp := httptest.NewRecorder()
+
+	invalidSortBySampleReq := httptest.NewRequest("GET", "http://localhost:8000/v1/book?sortBy=stock", strings.NewReader(``))
+	invalidSortBySampleResp := httptest.NewRecorder()
+
+	invalidSortDirSampleReq := httptest.NewRequest("GET", "http://localhost:8000/v1/book?sortDir=up", strings.NewReader(``))
+	invalidSortDirSampleResp := httptest.NewRecorder()
+
+	cursor := dto.Cursor{Time: time.Now(), ID: bookID}
+	cursorSampleReq := httptest.NewRequest("GET", fmt.Sprintf("http://localhost:8000/v1/book?limit=%d&cursor=%s&withTotal=true", limit, dto.EncodeCursor(cursor)), strings.NewReader(``))
+	cursorSampleResp := httptest.NewRecorder()
+
+	invalidCursorSampleReq := httptest.NewRequest("GET", "http://localhost:8000/v1/book?cursor=not-a-cursor", strings.NewReader(``))
+	invalidCursorSampleResp := httptest.NewRecorder()
+
+	invalidCursorSortSampleReq := httptest.NewRequest("GET", "http://localhost:8000/v1/book?cursor=&sortBy=price", strings.NewReader(``))
+	invalidCursorSortSampleResp := httptest.NewRecorder()
+
 	type fields struct {
 		service service.BookSvc
 	}
@@ -207,8 +298,10 @@ func TestGetBook(t *testing.T) {
 				bookMock := mocksvc.NewMockBookSvc(ctrl)
 
 				bookMock.EXPECT().GetBook(gomock.Any(), dto.GetBookReq{
-					Page:  int32(page),
-					Limit: int32(limit),
+					Page:    int32(page),
+					Limit:   int32(limit),
+					SortBy:  constant.BookSortCreatedAt,
+					SortDir: constant.SortDesc,
 				}).Return(dto.PaginationResp[dto.GetBookRes]{
 					Total:      totalCount,
 					IsLoadMore: true,
@@ -239,8 +332,10 @@ func TestGetBook(t *testing.T) {
 				bookMock := mocksvc.NewMockBookSvc(ctrl)
 
 				bookMock.EXPECT().GetBook(gomock.Any(), dto.GetBookReq{
-					Page:  int32(page),
-					Limit: int32(limit),
+					Page:    int32(page),
+					Limit:   int32(limit),
+					SortBy:  constant.BookSortCreatedAt,
+					SortDir: constant.SortDesc,
 				}).Return(dto.PaginationResp[dto.GetBookRes]{
 					Total:      totalCount,
 					IsLoadMore: true,
@@ -265,6 +360,159 @@ func TestGetBook(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "success get book with filters",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().GetBook(gomock.Any(), dto.GetBookReq{
+					Page:     int32(page),
+					Limit:    int32(limit),
+					Search:   "hello world",
+					Author:   "Giri",
+					MinPrice: decimal.NewNullDecimal(decimal.NewFromInt(10)),
+					MaxPrice: decimal.NewNullDecimal(decimal.RequireFromString("99.5")),
+					SortBy:   constant.BookSortPrice,
+					SortDir:  constant.SortAsc,
+				}).Return(dto.PaginationResp[dto.GetBookRes]{}).Times(1)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   filterSampleResp,
+				req: filterSampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid price filter",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().GetBook(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   invalidPriceSampleResp,
+				req: invalidPriceSampleReq,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid price range",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().GetBook(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   invalidPriceRangeSampleResp,
+				req: invalidPriceRangeSampleReq,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid sort field",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().GetBook(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   invalidSortBySampleResp,
+				req: invalidSortBySampleReq,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid sort direction",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().GetBook(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   invalidSortDirSampleResp,
+				req: invalidSortDirSampleReq,
+			},
+			wantErr: true,
+		},
+		{
+			name: "success get book by cursor",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().GetBook(gomock.Any(), gomock.AssignableToTypeOf(dto.GetBookReq{})).DoAndReturn(
+					func(_ any, input dto.GetBookReq) dto.PaginationResp[dto.GetBookRes] {
+						assert.True(t, input.CursorMode)
+						assert.True(t, input.WithTotal)
+						assert.Equal(t, cursor.ID, input.Cursor.ID)
+						assert.True(t, cursor.Time.Equal(input.Cursor.Time))
+
+						return dto.PaginationResp[dto.GetBookRes]{}
+					}).Times(1)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   cursorSampleResp,
+				req: cursorSampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid cursor",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().GetBook(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   invalidCursorSampleResp,
+				req: invalidCursorSampleReq,
+			},
+			wantErr: true,
+		},
+		{
+			name: "cursor with unsupported sort",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().GetBook(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   invalidCursorSortSampleResp,
+				req: invalidCursorSortSampleReq,
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -286,3 +534,479 @@ func TestGetBook(t *testing.T) {
 		})
 	}
 }
+
+func withURLParam(req *http.Request, key string, value string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestGetBookByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bookID := uuid.New()
+	title := "Hello"
+	description := "World"
+	price := decimal.NewFromInt(100)
+	author := "Giri Putra Adhittana"
+
+	sampleReq := withURLParam(httptest.NewRequest("GET", fmt.Sprintf("http://localhost:8000/v1/book/%s", bookID), strings.NewReader(``)), "bookId", bookID.String())
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := withURLParam(httptest.NewRequest("GET", "http://localhost:8000/v1/book/123", strings.NewReader(``)), "bookId", "123")
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.BookSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success get book by ID",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().GetBookByID(gomock.Any(), dto.GetBookByIDReq{
+					BookID: bookID,
+				}).Return(dto.GetBookRes{
+					ID:          bookID.String(),
+					Title:       title,
+					Description: description,
+					Author:      author,
+					Price:       price,
+				}).Times(1)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid book ID",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().GetBookByID(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := BookHandlerImpl{
+				bookSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.GetBookByID(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.GetBookByID(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
+
+func TestUpdateBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bookID := uuid.New()
+	title := "Hello"
+	description := "World"
+	price := decimal.NewFromInt(100)
+	author := "Giri Putra Adhittana"
+
+	sampleReq := withURLParam(httptest.NewRequest("PUT", fmt.Sprintf("http://localhost:8000/v1/book/%s", bookID), strings.NewReader(fmt.Sprintf(`{
+		"title" : "%s",
+		"description" : "%s",
+		"author" : "%s",
+		"price" : %s
+	}`, title, description, author, price))), "bookId", bookID.String())
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := withURLParam(httptest.NewRequest("PUT", fmt.Sprintf("http://localhost:8000/v1/book/%s", bookID), strings.NewReader(fmt.Sprintf(`{
+		"title" : "%s"
+	}`, title))), "bookId", bookID.String())
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.BookSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success update book",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().UpdateBook(gomock.Any(), dto.PatchBookReq{
+					BookID:      bookID,
+					Title:       &title,
+					Description: &description,
+					Author:      &author,
+					Price:       &price,
+				}).Return(dto.UpdateBookRes{
+					ID:          bookID.String(),
+					Title:       title,
+					Description: description,
+					Author:      author,
+					Price:       price,
+				}).Times(1)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid request",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := BookHandlerImpl{
+				bookSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.UpdateBook(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.UpdateBook(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
+
+func TestPatchBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bookID := uuid.New()
+	title := "Hello"
+	description := "World"
+	price := decimal.NewFromInt(100)
+	author := "Giri Putra Adhittana"
+
+	sampleReq := withURLParam(httptest.NewRequest("PATCH", fmt.Sprintf("http://localhost:8000/v1/book/%s", bookID), strings.NewReader(fmt.Sprintf(`{
+		"price" : %s
+	}`, price))), "bookId", bookID.String())
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := withURLParam(httptest.NewRequest("PATCH", fmt.Sprintf("http://localhost:8000/v1/book/%s", bookID), strings.NewReader(`{
+		"price" : -1
+	}`)), "bookId", bookID.String())
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.BookSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success patch book",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().UpdateBook(gomock.Any(), dto.PatchBookReq{
+					BookID: bookID,
+					Price:  &price,
+				}).Return(dto.UpdateBookRes{
+					ID:          bookID.String(),
+					Title:       title,
+					Description: description,
+					Author:      author,
+					Price:       price,
+				}).Times(1)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid request",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := BookHandlerImpl{
+				bookSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.PatchBook(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.PatchBook(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
+
+func TestDeleteBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bookID := uuid.New()
+
+	sampleReq := withURLParam(httptest.NewRequest("DELETE", fmt.Sprintf("http://localhost:8000/v1/book/%s", bookID), strings.NewReader(``)), "bookId", bookID.String())
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := withURLParam(httptest.NewRequest("DELETE", "http://localhost:8000/v1/book/123", strings.NewReader(``)), "bookId", "123")
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.BookSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success delete book",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().DeleteBook(gomock.Any(), dto.DeleteBookReq{
+					BookID: bookID,
+				}).Return(dto.DeleteBookRes{
+					ID: bookID.String(),
+				}).Times(1)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid book ID",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().DeleteBook(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := BookHandlerImpl{
+				bookSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.DeleteBook(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.DeleteBook(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
+
+func TestAdjustBookStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bookID := uuid.New()
+	quantity := 5
+
+	sampleReq := withURLParam(httptest.NewRequest("POST", fmt.Sprintf("http://localhost:8000/v1/admin/book/%s/stock", bookID), strings.NewReader(fmt.Sprintf(`{
+		"quantity" : %d,
+		"reason" : "%s"
+	}`, quantity, constant.StockReasonRestock))), "bookId", bookID.String())
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := withURLParam(httptest.NewRequest("POST", fmt.Sprintf("http://localhost:8000/v1/admin/book/%s/stock", bookID), strings.NewReader(fmt.Sprintf(`{
+		"quantity" : %d,
+		"reason" : "%s"
+	}`, quantity, constant.StockReasonOrderPlaced))), "bookId", bookID.String())
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.BookSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success adjust book stock",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().AdjustBookStock(gomock.Any(), dto.AdjustBookStockReq{
+					BookID:   bookID,
+					Quantity: quantity,
+					Reason:   constant.StockReasonRestock,
+				}).Return(dto.AdjustBookStockRes{
+					ID:    bookID.String(),
+					Stock: quantity,
+				}).Times(1)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid reason",
+			fields: func() fields {
+				bookMock := mocksvc.NewMockBookSvc(ctrl)
+
+				bookMock.EXPECT().AdjustBookStock(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: bookMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := BookHandlerImpl{
+				bookSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.AdjustBookStock(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.AdjustBookStock(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gadhittana-01/book-go/constant"
+	"github.com/gadhittana-01/book-go/dto"
+	"github.com/gadhittana-01/book-go/middleware"
+	"github.com/gadhittana-01/book-go/service"
+	"github.com/gadhittana01/go-modules/utils"
+	"github.com/go-chi/chi"
+)
+
+type CartHandler interface {
+	SetupCartRoutes(route *chi.Mux)
+}
+
+type CartHandlerImpl struct {
+	cartSvc                     service.CartSvc
+	authMiddleware              utils.AuthMiddleware
+	emailVerificationMiddleware middleware.EmailVerificationMiddleware
+	rateLimitMiddleware         middleware.RateLimitMiddleware
+}
+
+func NewCartHandler(
+	cartSvc service.CartSvc,
+	authMiddleware utils.AuthMiddleware,
+	emailVerificationMiddleware middleware.EmailVerificationMiddleware,
+	rateLimitMiddleware middleware.RateLimitMiddleware,
+) CartHandler {
+	return &CartHandlerImpl{
+		cartSvc:                     cartSvc,
+		authMiddleware:              authMiddleware,
+		emailVerificationMiddleware: emailVerificationMiddleware,
+		rateLimitMiddleware:         rateLimitMiddleware,
+	}
+}
+
+func (h *CartHandlerImpl) SetupCartRoutes(route *chi.Mux) {
+	setupCartV1Routes(route, h)
+}
+
+func (h *CartHandlerImpl) GetCart(w http.ResponseWriter, r *http.Request) {
+	resp := h.cartSvc.GetCart(r.Context())
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+func (h *CartHandlerImpl) AddCartItem(w http.ResponseWriter, r *http.Request) {
+	input := utils.ValidateBodyPayload(r.Body, &dto.AddCartItemReq{})
+
+	resp := h.cartSvc.AddCartItem(r.Context(), input)
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+func (h *CartHandlerImpl) UpdateCartItem(w http.ResponseWriter, r *http.Request) {
+	bookID := utils.ValidateURLParamUUID(r, "bookId")
+	input := utils.ValidateBodyPayload(r.Body, &dto.UpdateCartItemReq{})
+	input.BookID = bookID
+
+	resp := h.cartSvc.UpdateCartItem(r.Context(), input)
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+func (h *CartHandlerImpl) DeleteCartItem(w http.ResponseWriter, r *http.Request) {
+	bookID := utils.ValidateURLParamUUID(r, "bookId")
+
+	resp := h.cartSvc.DeleteCartItem(r.Context(), dto.DeleteCartItemReq{
+		BookID: bookID,
+	})
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+func (h *CartHandlerImpl) ClearCart(w http.ResponseWriter, r *http.Request) {
+	resp := h.cartSvc.ClearCart(r.Context())
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+func (h *CartHandlerImpl) Checkout(w http.ResponseWriter, r *http.Request) {
+	resp := h.cartSvc.Checkout(r.Context())
+
+	utils.GenerateSuccessResp(w, resp, http.StatusCreated)
+}
+
+// rateLimited counts a checkout against the same per-user limit as placing an
+// order directly, since both create an order.
+func (h *CartHandlerImpl) rateLimited(handler http.HandlerFunc) http.HandlerFunc {
+	return h.rateLimitMiddleware.Limit(constant.RateLimitOrder, handler)
+}
+
+func setupCartV1Routes(route *chi.Mux, h *CartHandlerImpl) {
+	route.Get("/v1/cart", h.authMiddleware.CheckIsAuthenticated(h.GetCart))
+	route.Post("/v1/cart", h.authMiddleware.CheckIsAuthenticated(h.AddCartItem))
+	route.Delete("/v1/cart", h.authMiddleware.CheckIsAuthenticated(h.ClearCart))
+	route.Patch("/v1/cart/{bookId}", h.authMiddleware.CheckIsAuthenticated(h.UpdateCartItem))
+	route.Delete("/v1/cart/{bookId}", h.authMiddleware.CheckIsAuthenticated(h.DeleteCartItem))
+	route.Post("/v1/cart/checkout", h.authMiddleware.CheckIsAuthenticated(h.rateLimited(h.emailVerificationMiddleware.CheckIsVerified(h.Checkout))))
+}
@@ -0,0 +1,551 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gadhittana-01/book-go/constant"
+	"github.com/gadhittana-01/book-go/dto"
+	"github.com/gadhittana-01/book-go/middleware"
+	mockmdw "github.com/gadhittana-01/book-go/middleware/mock"
+	"github.com/gadhittana-01/book-go/service"
+	mocksvc "github.com/gadhittana-01/book-go/service/mock"
+	"github.com/gadhittana01/go-modules/utils"
+	mockutl "github.com/gadhittana01/go-modules/utils/mock"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCartHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cartMock := mocksvc.NewMockCartSvc(ctrl)
+	middlewareMock := mockutl.NewMockAuthMiddleware(ctrl)
+	emailVerificationMiddlewareMock := mockmdw.NewMockEmailVerificationMiddleware(ctrl)
+	rateLimitMiddlewareMock := mockmdw.NewMockRateLimitMiddleware(ctrl)
+
+	type args struct {
+		service                     service.CartSvc
+		authMiddleware              utils.AuthMiddleware
+		emailVerificationMiddleware middleware.EmailVerificationMiddleware
+		rateLimitMiddleware         middleware.RateLimitMiddleware
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want *CartHandlerImpl
+	}{
+		{
+			args: args{
+				service:                     cartMock,
+				authMiddleware:              middlewareMock,
+				emailVerificationMiddleware: emailVerificationMiddlewareMock,
+				rateLimitMiddleware:         rateLimitMiddlewareMock,
+			},
+			want: &CartHandlerImpl{
+				cartSvc:                     cartMock,
+				authMiddleware:              middlewareMock,
+				emailVerificationMiddleware: emailVerificationMiddlewareMock,
+				rateLimitMiddleware:         rateLimitMiddlewareMock,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewCartHandler(tt.args.service, tt.args.authMiddleware, tt.args.emailVerificationMiddleware, tt.args.rateLimitMiddleware); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewCartHandler() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func sampleCartRes(bookID uuid.UUID) dto.GetCartRes {
+	return dto.GetCartRes{
+		Items: []dto.CartItem{
+			{
+				BookID:    bookID.String(),
+				Title:     "Hello",
+				Author:    "Giri Putra Adhittana",
+				Quantity:  2,
+				UnitPrice: decimal.NewFromInt(10),
+				LineTotal: decimal.NewFromInt(20),
+				Stock:     5,
+				Available: true,
+			},
+		},
+		TotalPrice: decimal.NewFromInt(20),
+	}
+}
+
+func TestGetCart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bookID := uuid.New()
+
+	sampleReq := httptest.NewRequest("GET", "http://localhost:8000/v1/cart", nil)
+	sampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.CartSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success get cart",
+			fields: func() fields {
+				cartMock := mocksvc.NewMockCartSvc(ctrl)
+
+				cartMock.EXPECT().GetCart(gomock.Any()).Return(sampleCartRes(bookID)).Times(1)
+
+				return fields{
+					service: cartMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := CartHandlerImpl{
+				cartSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.GetCart(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.GetCart(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
+
+func TestAddCartItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bookID := uuid.New()
+
+	sampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/cart", strings.NewReader(fmt.Sprintf(`{
+		"bookId" : "%s",
+		"quantity" : 2
+	}`, bookID.String())))
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/cart", strings.NewReader(fmt.Sprintf(`{
+		"bookId" : "%s",
+		"quantity" : 0
+	}`, bookID.String())))
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.CartSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success add cart item",
+			fields: func() fields {
+				cartMock := mocksvc.NewMockCartSvc(ctrl)
+
+				cartMock.EXPECT().AddCartItem(gomock.Any(), dto.AddCartItemReq{
+					BookID:   bookID.String(),
+					Quantity: 2,
+				}).Return(sampleCartRes(bookID)).Times(1)
+
+				return fields{
+					service: cartMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid request",
+			fields: func() fields {
+				cartMock := mocksvc.NewMockCartSvc(ctrl)
+
+				cartMock.EXPECT().AddCartItem(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: cartMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := CartHandlerImpl{
+				cartSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.AddCartItem(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.AddCartItem(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
+
+func TestUpdateCartItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bookID := uuid.New()
+
+	sampleReq := withURLParam(httptest.NewRequest("PATCH", fmt.Sprintf("http://localhost:8000/v1/cart/%s", bookID), strings.NewReader(`{
+		"quantity" : 2
+	}`)), "bookId", bookID.String())
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := withURLParam(httptest.NewRequest("PATCH", "http://localhost:8000/v1/cart/123", strings.NewReader(`{
+		"quantity" : 2
+	}`)), "bookId", "123")
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.CartSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success update cart item",
+			fields: func() fields {
+				cartMock := mocksvc.NewMockCartSvc(ctrl)
+
+				cartMock.EXPECT().UpdateCartItem(gomock.Any(), dto.UpdateCartItemReq{
+					BookID:   bookID,
+					Quantity: 2,
+				}).Return(sampleCartRes(bookID)).Times(1)
+
+				return fields{
+					service: cartMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid book ID",
+			fields: func() fields {
+				cartMock := mocksvc.NewMockCartSvc(ctrl)
+
+				cartMock.EXPECT().UpdateCartItem(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: cartMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := CartHandlerImpl{
+				cartSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.UpdateCartItem(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.UpdateCartItem(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
+
+func TestDeleteCartItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bookID := uuid.New()
+
+	sampleReq := withURLParam(httptest.NewRequest("DELETE", fmt.Sprintf("http://localhost:8000/v1/cart/%s", bookID), strings.NewReader(``)), "bookId", bookID.String())
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := withURLParam(httptest.NewRequest("DELETE", "http://localhost:8000/v1/cart/123", strings.NewReader(``)), "bookId", "123")
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.CartSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success delete cart item",
+			fields: func() fields {
+				cartMock := mocksvc.NewMockCartSvc(ctrl)
+
+				cartMock.EXPECT().DeleteCartItem(gomock.Any(), dto.DeleteCartItemReq{
+					BookID: bookID,
+				}).Return(dto.GetCartRes{}).Times(1)
+
+				return fields{
+					service: cartMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid book ID",
+			fields: func() fields {
+				cartMock := mocksvc.NewMockCartSvc(ctrl)
+
+				cartMock.EXPECT().DeleteCartItem(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: cartMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := CartHandlerImpl{
+				cartSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.DeleteCartItem(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.DeleteCartItem(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
+
+func TestClearCart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	sampleReq := httptest.NewRequest("DELETE", "http://localhost:8000/v1/cart", nil)
+	sampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.CartSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success clear cart",
+			fields: func() fields {
+				cartMock := mocksvc.NewMockCartSvc(ctrl)
+
+				cartMock.EXPECT().ClearCart(gomock.Any()).Return(dto.GetCartRes{}).Times(1)
+
+				return fields{
+					service: cartMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := CartHandlerImpl{
+				cartSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.ClearCart(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.ClearCart(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
+
+func TestCheckout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	sampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/cart/checkout", nil)
+	sampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.CartSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success checkout",
+			fields: func() fields {
+				cartMock := mocksvc.NewMockCartSvc(ctrl)
+
+				cartMock.EXPECT().Checkout(gomock.Any()).Return(dto.CreateOrderRes{
+					OrderId:    uuid.New().String(),
+					TotalPrice: decimal.NewFromInt(20),
+					Status:     "pending",
+				}).Times(1)
+
+				return fields{
+					service: cartMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := CartHandlerImpl{
+				cartSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.Checkout(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.Checkout(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
+
+func TestSetupCartRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	passThrough := func(handler http.HandlerFunc) http.HandlerFunc { return handler }
+
+	t.Run("checkout counts against the order rate limit", func(t *testing.T) {
+		authMock := mockutl.NewMockAuthMiddleware(ctrl)
+		authMock.EXPECT().CheckIsAuthenticated(gomock.Any()).DoAndReturn(func(handler func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+			return handler
+		}).AnyTimes()
+		emailVerificationMock := mockmdw.NewMockEmailVerificationMiddleware(ctrl)
+		emailVerificationMock.EXPECT().CheckIsVerified(gomock.Any()).DoAndReturn(passThrough).Times(1)
+		rateLimitMock := mockmdw.NewMockRateLimitMiddleware(ctrl)
+		rateLimitMock.EXPECT().Limit(constant.RateLimitOrder, gomock.Any()).Return(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}).Times(1)
+		cartMock := mocksvc.NewMockCartSvc(ctrl)
+		cartMock.EXPECT().Checkout(gomock.Any()).Times(0)
+
+		route := chi.NewRouter()
+		NewCartHandler(cartMock, authMock, emailVerificationMock, rateLimitMock).SetupCartRoutes(route)
+
+		resp := httptest.NewRecorder()
+		route.ServeHTTP(resp, httptest.NewRequest("POST", "/v1/cart/checkout", nil))
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	})
+}
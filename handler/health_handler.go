@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gadhittana-01/book-go/constant"
+	"github.com/gadhittana-01/book-go/dto"
+	"github.com/gadhittana-01/book-go/service"
+	"github.com/gadhittana01/go-modules/utils"
+	"github.com/go-chi/chi"
+)
+
+type HealthHandler interface {
+	SetupHealthRoutes(route *chi.Mux)
+}
+
+type HealthHandlerImpl struct {
+	healthSvc service.HealthSvc
+}
+
+func NewHealthHandler(healthSvc service.HealthSvc) HealthHandler {
+	return &HealthHandlerImpl{
+		healthSvc: healthSvc,
+	}
+}
+
+func (h *HealthHandlerImpl) SetupHealthRoutes(route *chi.Mux) {
+	route.Get("/livez", h.Livez)
+	route.Get("/readyz", h.Readyz)
+}
+
+// @Id livez
+// @Summary      Liveness Probe
+// @Description  Report that the process is running. Dependencies are not checked, so an outage does not get the pod restarted.
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  dto.SuccessResp200
+// @Router       /livez [get]
+func (h *HealthHandlerImpl) Livez(w http.ResponseWriter, r *http.Request) {
+	utils.GenerateSuccessResp(w, constant.HealthStatusUp, http.StatusOK)
+}
+
+// @Id readyz
+// @Summary      Readiness Probe
+// @Description  Check Postgres, Redis and the migration version and report the status and latency of each. A Redis outage only reports the service as degraded, since the cache, rate limits and token revocation fall back without it.
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  dto.SuccessResp200{data=dto.ReadinessRes}
+// @Failure      503  {object}  dto.FailedReadinessResp503
+// @Router       /readyz [get]
+func (h *HealthHandlerImpl) Readyz(w http.ResponseWriter, r *http.Request) {
+	resp := h.healthSvc.CheckReadiness(r.Context())
+
+	if resp.Status == constant.HealthStatusDown {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(dto.FailedReadinessResp503{
+			Success:    false,
+			StatusCode: http.StatusServiceUnavailable,
+			Data:       resp,
+		})
+		return
+	}
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
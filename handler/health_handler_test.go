@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gadhittana-01/book-go/constant"
+	"github.com/gadhittana-01/book-go/dto"
+	mocksvc "github.com/gadhittana-01/book-go/service/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHealthHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	healthMock := mocksvc.NewMockHealthSvc(ctrl)
+
+	assert.Equal(t, &HealthHandlerImpl{
+		healthSvc: healthMock,
+	}, NewHealthHandler(healthMock))
+}
+
+func TestLivez(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	i := HealthHandlerImpl{
+		healthSvc: mocksvc.NewMockHealthSvc(ctrl),
+	}
+
+	resp := httptest.NewRecorder()
+	i.Livez(resp, httptest.NewRequest("GET", "http://localhost:8000/livez", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   string
+		wantCode int
+	}{
+		{
+			name:     "ready",
+			status:   constant.HealthStatusUp,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "degraded stays ready",
+			status:   constant.HealthStatusDegraded,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "not ready",
+			status:   constant.HealthStatusDown,
+			wantCode: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			healthMock := mocksvc.NewMockHealthSvc(ctrl)
+			readiness := dto.ReadinessRes{
+				Status: tt.status,
+				Dependencies: map[string]dto.DependencyStatus{
+					"redis": {
+						Status:    tt.status,
+						LatencyMs: 1,
+					},
+				},
+			}
+			healthMock.EXPECT().CheckReadiness(gomock.Any()).Return(readiness).Times(1)
+
+			i := HealthHandlerImpl{
+				healthSvc: healthMock,
+			}
+
+			resp := httptest.NewRecorder()
+			i.Readyz(resp, httptest.NewRequest("GET", "http://localhost:8000/readyz", nil))
+			assert.Equal(t, tt.wantCode, resp.Code)
+
+			if tt.wantCode == http.StatusServiceUnavailable {
+				body := dto.FailedReadinessResp503{}
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, readiness, body.Data)
+			}
+		})
+	}
+}
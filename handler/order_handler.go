@@ -5,13 +5,15 @@ import (
 
 	"github.com/gadhittana-01/book-go/constant"
 	"github.com/gadhittana-01/book-go/dto"
+	"github.com/gadhittana-01/book-go/middleware"
 	"github.com/gadhittana-01/book-go/service"
 	"github.com/gadhittana01/go-modules/utils"
 	"github.com/go-chi/chi"
 )
 
 const (
-	InvalidOrderDetail = "Order detail cannot be empty"
+	InvalidOrderDetail    = "Order detail cannot be empty"
+	InvalidIdempotencyKey = "Idempotency key cannot be longer than 255 characters"
 )
 
 type OrderHandler interface {
@@ -19,17 +21,29 @@ type OrderHandler interface {
 }
 
 type OrderHandlerImpl struct {
-	orderSvc       service.OrderSvc
-	authMiddleware utils.AuthMiddleware
+	orderSvc                    service.OrderSvc
+	orderStatusSvc              service.OrderStatusSvc
+	authMiddleware              utils.AuthMiddleware
+	roleMiddleware              middleware.RoleMiddleware
+	emailVerificationMiddleware middleware.EmailVerificationMiddleware
+	rateLimitMiddleware         middleware.RateLimitMiddleware
 }
 
 func NewOrderHandler(
 	orderSvc service.OrderSvc,
+	orderStatusSvc service.OrderStatusSvc,
 	authMiddleware utils.AuthMiddleware,
+	roleMiddleware middleware.RoleMiddleware,
+	emailVerificationMiddleware middleware.EmailVerificationMiddleware,
+	rateLimitMiddleware middleware.RateLimitMiddleware,
 ) OrderHandler {
 	return &OrderHandlerImpl{
-		orderSvc:       orderSvc,
-		authMiddleware: authMiddleware,
+		orderSvc:                    orderSvc,
+		orderStatusSvc:              orderStatusSvc,
+		authMiddleware:              authMiddleware,
+		roleMiddleware:              roleMiddleware,
+		emailVerificationMiddleware: emailVerificationMiddleware,
+		rateLimitMiddleware:         rateLimitMiddleware,
 	}
 }
 
@@ -44,6 +58,11 @@ func (h *OrderHandlerImpl) CreateOrder(w http.ResponseWriter, r *http.Request) {
 		utils.PanicAppError(InvalidOrderDetail, 400)
 	}
 
+	input.IdempotencyKey = r.Header.Get(constant.IdempotencyKeyHeader)
+	if len(input.IdempotencyKey) > 255 {
+		utils.PanicAppError(InvalidIdempotencyKey, 400)
+	}
+
 	resp := h.orderSvc.CreateOrder(r.Context(), input)
 
 	utils.GenerateSuccessResp(w, resp, http.StatusCreated)
@@ -53,9 +72,14 @@ func (h *OrderHandlerImpl) GetOrder(w http.ResponseWriter, r *http.Request) {
 	page := utils.ValidateQueryParamInt(r, "page", 1)
 	limit := utils.ValidateQueryParamInt(r, "limit", constant.DefaultLimit)
 
+	pagination := parseCursorQueryParams(r)
+
 	resp := h.orderSvc.GetOrder(r.Context(), dto.GetOrderReq{
-		Page:  int32(page),
-		Limit: int32(limit),
+		Page:       int32(page),
+		Limit:      int32(limit),
+		CursorMode: pagination.enabled,
+		Cursor:     pagination.cursor,
+		WithTotal:  pagination.withTotal,
 	})
 
 	utils.GenerateSuccessResp(w, resp, http.StatusOK)
@@ -71,8 +95,55 @@ func (h *OrderHandlerImpl) GetOrderDetail(w http.ResponseWriter, r *http.Request
 	utils.GenerateSuccessResp(w, resp, http.StatusOK)
 }
 
+func (h *OrderHandlerImpl) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	orderID := utils.ValidateURLParamUUID(r, "orderId")
+
+	resp := h.orderStatusSvc.CancelOrder(r.Context(), dto.CancelOrderReq{
+		OrderID: orderID,
+	})
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+func (h *OrderHandlerImpl) ConfirmOrder(w http.ResponseWriter, r *http.Request) {
+	h.updateOrderStatus(w, r, constant.OrderStatusConfirmed)
+}
+
+func (h *OrderHandlerImpl) ShipOrder(w http.ResponseWriter, r *http.Request) {
+	h.updateOrderStatus(w, r, constant.OrderStatusShipped)
+}
+
+func (h *OrderHandlerImpl) DeliverOrder(w http.ResponseWriter, r *http.Request) {
+	h.updateOrderStatus(w, r, constant.OrderStatusDelivered)
+}
+
+func (h *OrderHandlerImpl) updateOrderStatus(w http.ResponseWriter, r *http.Request, status string) {
+	orderID := utils.ValidateURLParamUUID(r, "orderId")
+
+	resp := h.orderStatusSvc.UpdateOrderStatus(r.Context(), dto.UpdateOrderStatusReq{
+		OrderID: orderID,
+		Status:  status,
+	})
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+// orderManager restricts order fulfilment endpoints to admin and staff accounts.
+func (h *OrderHandlerImpl) orderManager(handler http.HandlerFunc) http.HandlerFunc {
+	return h.roleMiddleware.CheckHasRole(handler, constant.RoleAdmin, constant.RoleStaff)
+}
+
+// rateLimited counts a request against the per-user limit of the order routes.
+func (h *OrderHandlerImpl) rateLimited(handler http.HandlerFunc) http.HandlerFunc {
+	return h.rateLimitMiddleware.Limit(constant.RateLimitOrder, handler)
+}
+
 func setupOrderV1Routes(route *chi.Mux, h *OrderHandlerImpl) {
-	route.Post("/v1/order", h.authMiddleware.CheckIsAuthenticated(h.CreateOrder))
-	route.Get("/v1/order", h.authMiddleware.CheckIsAuthenticated(h.GetOrder))
-	route.Get("/v1/order/{orderId}", h.authMiddleware.CheckIsAuthenticated(h.GetOrderDetail))
+	route.Post("/v1/order", h.authMiddleware.CheckIsAuthenticated(h.rateLimited(h.emailVerificationMiddleware.CheckIsVerified(h.CreateOrder))))
+	route.Get("/v1/order", h.authMiddleware.CheckIsAuthenticated(h.rateLimited(h.GetOrder)))
+	route.Get("/v1/order/{orderId}", h.authMiddleware.CheckIsAuthenticated(h.rateLimited(h.GetOrderDetail)))
+	route.Post("/v1/order/{orderId}/cancel", h.authMiddleware.CheckIsAuthenticated(h.rateLimited(h.CancelOrder)))
+	route.Post("/v1/admin/order/{orderId}/confirm", h.authMiddleware.CheckIsAuthenticated(h.orderManager(h.ConfirmOrder)))
+	route.Post("/v1/admin/order/{orderId}/ship", h.authMiddleware.CheckIsAuthenticated(h.orderManager(h.ShipOrder)))
+	route.Post("/v1/admin/order/{orderId}/deliver", h.authMiddleware.CheckIsAuthenticated(h.orderManager(h.DeliverOrder)))
 }
@@ -11,23 +11,34 @@ import (
 
 	"github.com/gadhittana-01/book-go/constant"
 	"github.com/gadhittana-01/book-go/dto"
+	"github.com/gadhittana-01/book-go/middleware"
+	mockmdw "github.com/gadhittana-01/book-go/middleware/mock"
 	"github.com/gadhittana-01/book-go/service"
 	mocksvc "github.com/gadhittana-01/book-go/service/mock"
 	"github.com/gadhittana01/go-modules/utils"
 	mockutl "github.com/gadhittana01/go-modules/utils/mock"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestNewOrderHandler(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	orderMock := mocksvc.NewMockOrderSvc(ctrl)
+	orderStatusMock := mocksvc.NewMockOrderStatusSvc(ctrl)
 	middlewareMock := mockutl.NewMockAuthMiddleware(ctrl)
+	roleMiddlewareMock := mockmdw.NewMockRoleMiddleware(ctrl)
+	emailVerificationMiddlewareMock := mockmdw.NewMockEmailVerificationMiddleware(ctrl)
+	rateLimitMiddlewareMock := mockmdw.NewMockRateLimitMiddleware(ctrl)
 
 	type args struct {
-		service        service.OrderSvc
-		authMiddleware utils.AuthMiddleware
+		service                     service.OrderSvc
+		statusService               service.OrderStatusSvc
+		authMiddleware              utils.AuthMiddleware
+		roleMiddleware              middleware.RoleMiddleware
+		emailVerificationMiddleware middleware.EmailVerificationMiddleware
+		rateLimitMiddleware         middleware.RateLimitMiddleware
 	}
 
 	tests := []struct {
@@ -37,18 +48,26 @@ func TestNewOrderHandler(t *testing.T) {
 	}{
 		{
 			args: args{
-				service:        orderMock,
-				authMiddleware: middlewareMock,
+				service:                     orderMock,
+				statusService:               orderStatusMock,
+				authMiddleware:              middlewareMock,
+				roleMiddleware:              roleMiddlewareMock,
+				emailVerificationMiddleware: emailVerificationMiddlewareMock,
+				rateLimitMiddleware:         rateLimitMiddlewareMock,
 			},
 			want: &OrderHandlerImpl{
-				orderSvc:       orderMock,
-				authMiddleware: middlewareMock,
+				orderSvc:                    orderMock,
+				orderStatusSvc:              orderStatusMock,
+				authMiddleware:              middlewareMock,
+				roleMiddleware:              roleMiddlewareMock,
+				emailVerificationMiddleware: emailVerificationMiddlewareMock,
+				rateLimitMiddleware:         rateLimitMiddlewareMock,
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := NewOrderHandler(tt.args.service, tt.args.authMiddleware); !reflect.DeepEqual(got, tt.want) {
+			if got := NewOrderHandler(tt.args.service, tt.args.statusService, tt.args.authMiddleware, tt.args.roleMiddleware, tt.args.emailVerificationMiddleware, tt.args.rateLimitMiddleware); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewOrderHandler() = %v, want %v", got, tt.want)
 			}
 		})
@@ -59,7 +78,7 @@ func TestCreateOrder(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	orderID := uuid.New()
 	now := time.Now()
-	totalPrice := float64(100)
+	totalPrice := decimal.NewFromInt(100)
 	status := "pending"
 	bookID := uuid.New()
 	quantity := 10
@@ -80,6 +99,23 @@ func TestCreateOrder(t *testing.T) {
 	}`))
 	invalidSampleResp := httptest.NewRecorder()
 
+	body := fmt.Sprintf(`{
+		"orderDetail" : [
+			{
+				"bookId" : "%s",
+				"quantity" : %d
+			}
+		]
+	}`, bookID.String(), quantity)
+	idempotencyKey := uuid.New().String()
+	idempotentSampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/order", strings.NewReader(body))
+	idempotentSampleReq.Header.Set(constant.IdempotencyKeyHeader, idempotencyKey)
+	idempotentSampleResp := httptest.NewRecorder()
+
+	longKeySampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/order", strings.NewReader(body))
+	longKeySampleReq.Header.Set(constant.IdempotencyKeyHeader, strings.Repeat("a", 256))
+	longKeySampleResp := httptest.NewRecorder()
+
 	type fields struct {
 		service service.OrderSvc
 	}
@@ -153,6 +189,53 @@ func TestCreateOrder(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "success create order with idempotency key",
+			fields: func() fields {
+				orderMock := mocksvc.NewMockOrderSvc(ctrl)
+
+				orderMock.EXPECT().CreateOrder(gomock.Any(), dto.CreateOrderReq{
+					OrderDetail: []dto.OrderDetailReq{
+						{
+							BookID:   bookID.String(),
+							Quantity: quantity,
+						},
+					},
+					IdempotencyKey: idempotencyKey,
+				}).Return(dto.CreateOrderRes{
+					OrderId:    orderID.String(),
+					Date:       now.Format(constant.TimeFormat),
+					TotalPrice: totalPrice,
+					Status:     status,
+				}).Times(1)
+
+				return fields{
+					service: orderMock,
+				}
+			},
+			args: args{
+				w:   idempotentSampleResp,
+				req: idempotentSampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid idempotency key",
+			fields: func() fields {
+				orderMock := mocksvc.NewMockOrderSvc(ctrl)
+
+				orderMock.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: orderMock,
+				}
+			},
+			args: args{
+				w:   longKeySampleResp,
+				req: longKeySampleReq,
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -179,7 +262,7 @@ func TestGetOrder(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	orderID := uuid.New()
 	now := time.Now()
-	totalPrice := float64(100)
+	totalPrice := decimal.NewFromInt(100)
 	status := "pending"
 	page := 1
 	limit := 10
@@ -191,6 +274,12 @@ func TestGetOrder(t *testing.T) {
 	invalidSampleReq := httptest.NewRequest("GET", fmt.Sprintf("http://localhost:8000/v1/order?page=%d&limit=test", page), strings.NewReader(``))
 	invalidSampleResp := httptest.NewRecorder()
 
+	firstPageSampleReq := httptest.NewRequest("GET", fmt.Sprintf("http://localhost:8000/v1/order?limit=%d&cursor=", limit), strings.NewReader(``))
+	firstPageSampleResp := httptest.NewRecorder()
+
+	invalidWithTotalSampleReq := httptest.NewRequest("GET", "http://localhost:8000/v1/order?cursor=&withTotal=maybe", strings.NewReader(``))
+	invalidWithTotalSampleResp := httptest.NewRecorder()
+
 	type fields struct {
 		service service.OrderSvc
 	}
@@ -268,6 +357,46 @@ func TestGetOrder(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "success get first order page by cursor",
+			fields: func() fields {
+				orderMock := mocksvc.NewMockOrderSvc(ctrl)
+
+				orderMock.EXPECT().GetOrder(gomock.Any(), dto.GetOrderReq{
+					Page:       1,
+					Limit:      int32(limit),
+					CursorMode: true,
+				}).Return(dto.PaginationResp[dto.GetOrderRes]{
+					Total: -1,
+				}).Times(1)
+
+				return fields{
+					service: orderMock,
+				}
+			},
+			args: args{
+				w:   firstPageSampleResp,
+				req: firstPageSampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid withTotal",
+			fields: func() fields {
+				orderMock := mocksvc.NewMockOrderSvc(ctrl)
+
+				orderMock.EXPECT().GetOrder(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: orderMock,
+				}
+			},
+			args: args{
+				w:   invalidWithTotalSampleResp,
+				req: invalidWithTotalSampleReq,
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -294,7 +423,7 @@ func TestGetOrderDetail(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	orderID := uuid.New()
 	now := time.Now()
-	totalPrice := float64(100)
+	totalPrice := decimal.NewFromInt(100)
 	status := "pending"
 	title := "Hello"
 	description := "World"
@@ -376,3 +505,237 @@ func TestGetOrderDetail(t *testing.T) {
 		})
 	}
 }
+
+func TestCancelOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orderID := uuid.New()
+	now := time.Now()
+	totalPrice := decimal.NewFromInt(100)
+
+	sampleReq := withURLParam(httptest.NewRequest("POST", fmt.Sprintf("http://localhost:8000/v1/order/%s/cancel", orderID), strings.NewReader(``)), "orderId", orderID.String())
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := withURLParam(httptest.NewRequest("POST", "http://localhost:8000/v1/order/123/cancel", strings.NewReader(``)), "orderId", "123")
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.OrderStatusSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success cancel order",
+			fields: func() fields {
+				orderStatusMock := mocksvc.NewMockOrderStatusSvc(ctrl)
+
+				orderStatusMock.EXPECT().CancelOrder(gomock.Any(), dto.CancelOrderReq{
+					OrderID: orderID,
+				}).Return(dto.UpdateOrderStatusRes{
+					OrderId:    orderID.String(),
+					Date:       now.Format(constant.TimeFormat),
+					TotalPrice: totalPrice,
+					Status:     constant.OrderStatusCancelled,
+				}).Times(1)
+
+				return fields{
+					service: orderStatusMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid order ID",
+			fields: func() fields {
+				orderStatusMock := mocksvc.NewMockOrderStatusSvc(ctrl)
+
+				orderStatusMock.EXPECT().CancelOrder(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: orderStatusMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := OrderHandlerImpl{
+				orderStatusSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.CancelOrder(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.CancelOrder(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
+
+func TestUpdateOrderStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orderID := uuid.New()
+	now := time.Now()
+	totalPrice := decimal.NewFromInt(100)
+
+	newReq := func(action string) *http.Request {
+		return withURLParam(httptest.NewRequest("POST", fmt.Sprintf("http://localhost:8000/v1/admin/order/%s/%s", orderID, action), strings.NewReader(``)), "orderId", orderID.String())
+	}
+
+	invalidSampleReq := withURLParam(httptest.NewRequest("POST", "http://localhost:8000/v1/admin/order/123/ship", strings.NewReader(``)), "orderId", "123")
+
+	type fields struct {
+		service service.OrderStatusSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		handler func(i OrderHandlerImpl) http.HandlerFunc
+		wantErr bool
+	}{
+		{
+			name: "success confirm order",
+			fields: func() fields {
+				orderStatusMock := mocksvc.NewMockOrderStatusSvc(ctrl)
+
+				orderStatusMock.EXPECT().UpdateOrderStatus(gomock.Any(), dto.UpdateOrderStatusReq{
+					OrderID: orderID,
+					Status:  constant.OrderStatusConfirmed,
+				}).Return(dto.UpdateOrderStatusRes{
+					OrderId:    orderID.String(),
+					Date:       now.Format(constant.TimeFormat),
+					TotalPrice: totalPrice,
+					Status:     constant.OrderStatusConfirmed,
+				}).Times(1)
+
+				return fields{
+					service: orderStatusMock,
+				}
+			},
+			args: args{
+				w:   httptest.NewRecorder(),
+				req: newReq("confirm"),
+			},
+			handler: func(i OrderHandlerImpl) http.HandlerFunc { return i.ConfirmOrder },
+			wantErr: false,
+		},
+		{
+			name: "success ship order",
+			fields: func() fields {
+				orderStatusMock := mocksvc.NewMockOrderStatusSvc(ctrl)
+
+				orderStatusMock.EXPECT().UpdateOrderStatus(gomock.Any(), dto.UpdateOrderStatusReq{
+					OrderID: orderID,
+					Status:  constant.OrderStatusShipped,
+				}).Return(dto.UpdateOrderStatusRes{
+					OrderId:    orderID.String(),
+					Date:       now.Format(constant.TimeFormat),
+					TotalPrice: totalPrice,
+					Status:     constant.OrderStatusShipped,
+				}).Times(1)
+
+				return fields{
+					service: orderStatusMock,
+				}
+			},
+			args: args{
+				w:   httptest.NewRecorder(),
+				req: newReq("ship"),
+			},
+			handler: func(i OrderHandlerImpl) http.HandlerFunc { return i.ShipOrder },
+			wantErr: false,
+		},
+		{
+			name: "success deliver order",
+			fields: func() fields {
+				orderStatusMock := mocksvc.NewMockOrderStatusSvc(ctrl)
+
+				orderStatusMock.EXPECT().UpdateOrderStatus(gomock.Any(), dto.UpdateOrderStatusReq{
+					OrderID: orderID,
+					Status:  constant.OrderStatusDelivered,
+				}).Return(dto.UpdateOrderStatusRes{
+					OrderId:    orderID.String(),
+					Date:       now.Format(constant.TimeFormat),
+					TotalPrice: totalPrice,
+					Status:     constant.OrderStatusDelivered,
+				}).Times(1)
+
+				return fields{
+					service: orderStatusMock,
+				}
+			},
+			args: args{
+				w:   httptest.NewRecorder(),
+				req: newReq("deliver"),
+			},
+			handler: func(i OrderHandlerImpl) http.HandlerFunc { return i.DeliverOrder },
+			wantErr: false,
+		},
+		{
+			name: "invalid order ID",
+			fields: func() fields {
+				orderStatusMock := mocksvc.NewMockOrderStatusSvc(ctrl)
+
+				orderStatusMock.EXPECT().UpdateOrderStatus(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: orderStatusMock,
+				}
+			},
+			args: args{
+				w:   httptest.NewRecorder(),
+				req: invalidSampleReq,
+			},
+			handler: func(i OrderHandlerImpl) http.HandlerFunc { return i.ShipOrder },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := OrderHandlerImpl{
+				orderStatusSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					tt.handler(i)(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					tt.handler(i)(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gadhittana-01/book-go/dto"
+	"github.com/gadhittana01/go-modules/utils"
+)
+
+const (
+	InvalidCursor    = "Cursor is invalid"
+	InvalidWithTotal = "withTotal must be true or false"
+)
+
+// cursorPagination holds the cursor mode query params. Passing the cursor
+// param, even empty for the first page, switches a list endpoint from
+// page/limit to cursor mode; in that mode the total count only runs when
+// withTotal=true.
+type cursorPagination struct {
+	enabled   bool
+	cursor    dto.Cursor
+	withTotal bool
+}
+
+func parseCursorQueryParams(r *http.Request) cursorPagination {
+	query := r.URL.Query()
+	if !query.Has("cursor") {
+		return cursorPagination{}
+	}
+
+	cursor, err := dto.DecodeCursor(query.Get("cursor"))
+	if err != nil {
+		utils.PanicAppError(InvalidCursor, 400)
+	}
+
+	withTotal := false
+	if value := query.Get("withTotal"); value != "" {
+		withTotal, err = strconv.ParseBool(value)
+		if err != nil {
+			utils.PanicAppError(InvalidWithTotal, 400)
+		}
+	}
+
+	return cursorPagination{
+		enabled:   true,
+		cursor:    cursor,
+		withTotal: withTotal,
+	}
+}
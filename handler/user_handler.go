@@ -3,7 +3,10 @@ package handler
 import (
 	"net/http"
 
+	"github.com/gadhittana-01/book-go/config"
+	"github.com/gadhittana-01/book-go/constant"
 	"github.com/gadhittana-01/book-go/dto"
+	"github.com/gadhittana-01/book-go/middleware"
 	"github.com/gadhittana-01/book-go/service"
 	"github.com/gadhittana01/go-modules/utils"
 	"github.com/go-chi/chi"
@@ -14,14 +17,23 @@ type UserHandler interface {
 }
 
 type UserHandlerImpl struct {
-	userSvc service.UserSvc
+	userSvc             service.UserSvc
+	authMiddleware      utils.AuthMiddleware
+	rateLimitMiddleware middleware.RateLimitMiddleware
+	appConfig           *config.AppConfig
 }
 
 func NewUserHandler(
 	userSvc service.UserSvc,
+	authMiddleware utils.AuthMiddleware,
+	rateLimitMiddleware middleware.RateLimitMiddleware,
+	appConfig *config.AppConfig,
 ) UserHandler {
 	return &UserHandlerImpl{
-		userSvc: userSvc,
+		userSvc:             userSvc,
+		authMiddleware:      authMiddleware,
+		rateLimitMiddleware: rateLimitMiddleware,
+		appConfig:           appConfig,
 	}
 }
 
@@ -32,20 +44,25 @@ func (h *UserHandlerImpl) SetupUserRoutes(route *chi.Mux) {
 // SignUp godoc
 // @Id signUp
 // @Summary      Sign Up
-// @Description  Sign Up
+// @Description  Sign Up. The email is stored lowercased and the password has to meet the password policy. Sign-ups are rate limited per client IP.
 // @Tags         auth
 // @Accept 		 json
 // @Param		 requestBody		body		dto.SignUpReq	true	"Sign Up Request"
 // @Produce      json
 // @Success      200  {object}  dto.SuccessResp200{data=dto.SignUpRes}
-// @Failure      400  {object}  dto.FailedResp400
+// @Failure      400  {object}  dto.FailedValidationResp400
 // @Failure      401  {object}  dto.FailedResp401
 // @Failure      404  {object}  dto.FailedResp404
+// @Failure      429  {object}  dto.FailedResp429
 // @Failure      500  {object}  dto.FailedResp500
 // @Router       /v1/sign-up [post]
 func (h *UserHandlerImpl) SignUp(w http.ResponseWriter, r *http.Request) {
 	input := utils.ValidateBodyPayload(r.Body, &dto.SignUpReq{})
 
+	if validateSignUpReq(input, h.appConfig).writeTo(w) {
+		return
+	}
+
 	resp := h.userSvc.SignUp(r.Context(), input)
 
 	utils.GenerateSuccessResp(w, resp, http.StatusOK)
@@ -54,7 +71,7 @@ func (h *UserHandlerImpl) SignUp(w http.ResponseWriter, r *http.Request) {
 // SignIn godoc
 // @Id signIn
 // @Summary      Sign In
-// @Description  Sign In
+// @Description  Sign In. Repeated failures lock the email and the client IP out for an increasing time. For accounts with two-factor authentication only a challenge token is returned, to be exchanged at /v1/sign-in/2fa.
 // @Tags         auth
 // @Accept 		 json
 // @Param		 requestBody		body		dto.SignInReq	true	"Sign In Request"
@@ -63,22 +80,366 @@ func (h *UserHandlerImpl) SignUp(w http.ResponseWriter, r *http.Request) {
 // @Failure      400  {object}  dto.FailedResp400
 // @Failure      401  {object}  dto.FailedResp401
 // @Failure      404  {object}  dto.FailedResp404
+// @Failure      429  {object}  dto.FailedResp429
 // @Failure      500  {object}  dto.FailedResp500
 // @Router       /v1/sign-in [post]
 func (h *UserHandlerImpl) SignIn(w http.ResponseWriter, r *http.Request) {
 	input := utils.ValidateBodyPayload(r.Body, &dto.SignInReq{})
+	input.IP = middleware.ClientIP(r, h.appConfig.TrustedProxies)
 
 	resp := h.userSvc.SignIn(r.Context(), input)
 
 	utils.GenerateSuccessResp(w, resp, http.StatusOK)
 }
 
+// SignInTwoFactor godoc
+// @Id signInTwoFactor
+// @Summary      Sign In Two-Factor
+// @Description  Exchange the challenge token from sign in and an authenticator or recovery code for an access and refresh token pair. Too many wrong codes invalidate the challenge.
+// @Tags         auth
+// @Accept 		 json
+// @Param		 requestBody		body		dto.SignInTwoFactorReq	true	"Sign In Two-Factor Request"
+// @Produce      json
+// @Success      200  {object}  dto.SuccessResp200{data=dto.SignInRes}
+// @Failure      400  {object}  dto.FailedResp400
+// @Failure      401  {object}  dto.FailedResp401
+// @Failure      429  {object}  dto.FailedResp429
+// @Failure      500  {object}  dto.FailedResp500
+// @Router       /v1/sign-in/2fa [post]
+func (h *UserHandlerImpl) SignInTwoFactor(w http.ResponseWriter, r *http.Request) {
+	input := utils.ValidateBodyPayload(r.Body, &dto.SignInTwoFactorReq{})
+	input.IP = middleware.ClientIP(r, h.appConfig.TrustedProxies)
+
+	resp := h.userSvc.SignInTwoFactor(r.Context(), input)
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+// RefreshToken godoc
+// @Id refreshToken
+// @Summary      Refresh Token
+// @Description  Exchange a refresh token for a new access and refresh token pair. The refresh token can only be used once.
+// @Tags         auth
+// @Accept 		 json
+// @Param		 requestBody		body		dto.RefreshTokenReq	true	"Refresh Token Request"
+// @Produce      json
+// @Success      200  {object}  dto.SuccessResp200{data=dto.RefreshTokenRes}
+// @Failure      400  {object}  dto.FailedResp400
+// @Failure      401  {object}  dto.FailedResp401
+// @Failure      500  {object}  dto.FailedResp500
+// @Router       /v1/token/refresh [post]
+func (h *UserHandlerImpl) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	input := utils.ValidateBodyPayload(r.Body, &dto.RefreshTokenReq{})
+
+	resp := h.userSvc.RefreshToken(r.Context(), input)
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+// SignOut godoc
+// @Id signOut
+// @Summary      Sign Out
+// @Description  End the session of the given refresh token and revoke the current access token
+// @Tags         auth
+// @Accept 		 json
+// @Param		 requestBody		body		dto.SignOutReq	true	"Sign Out Request"
+// @Produce      json
+// @Success      200  {object}  dto.SuccessResp200
+// @Failure      400  {object}  dto.FailedResp400
+// @Failure      401  {object}  dto.FailedResp401
+// @Failure      500  {object}  dto.FailedResp500
+// @Security authorization
+// @Router       /v1/sign-out [post]
+func (h *UserHandlerImpl) SignOut(w http.ResponseWriter, r *http.Request) {
+	input := utils.ValidateBodyPayload(r.Body, &dto.SignOutReq{})
+	input.AccessToken = middleware.GetBearerToken(r)
+
+	h.userSvc.SignOut(r.Context(), input)
+
+	utils.GenerateSuccessResp[any](w, nil, http.StatusOK)
+}
+
+// SignOutAll godoc
+// @Id signOutAll
+// @Summary      Sign Out All
+// @Description  End every session of the caller and revoke all of their access tokens
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  dto.SuccessResp200
+// @Failure      400  {object}  dto.FailedResp400
+// @Failure      401  {object}  dto.FailedResp401
+// @Failure      500  {object}  dto.FailedResp500
+// @Security authorization
+// @Router       /v1/sign-out-all [post]
+func (h *UserHandlerImpl) SignOutAll(w http.ResponseWriter, r *http.Request) {
+	h.userSvc.SignOutAll(r.Context(), dto.SignOutAllReq{
+		AccessToken: middleware.GetBearerToken(r),
+	})
+
+	utils.GenerateSuccessResp[any](w, nil, http.StatusOK)
+}
+
+// ForgotPassword godoc
+// @Id forgotPassword
+// @Summary      Forgot Password
+// @Description  Mail a single-use password reset link. The response is the same whether or not the email has an account.
+// @Tags         auth
+// @Accept 		 json
+// @Param		 requestBody		body		dto.ForgotPasswordReq	true	"Forgot Password Request"
+// @Produce      json
+// @Success      200  {object}  dto.SuccessResp200
+// @Failure      400  {object}  dto.FailedResp400
+// @Failure      500  {object}  dto.FailedResp500
+// @Router       /v1/password/forgot [post]
+func (h *UserHandlerImpl) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	input := utils.ValidateBodyPayload(r.Body, &dto.ForgotPasswordReq{})
+
+	h.userSvc.ForgotPassword(r.Context(), input)
+
+	utils.GenerateSuccessResp[any](w, nil, http.StatusOK)
+}
+
+// ResetPassword godoc
+// @Id resetPassword
+// @Summary      Reset Password
+// @Description  Set a new password with a password reset token and end every session of the account
+// @Tags         auth
+// @Accept 		 json
+// @Param		 requestBody		body		dto.ResetPasswordReq	true	"Reset Password Request"
+// @Produce      json
+// @Success      200  {object}  dto.SuccessResp200
+// @Failure      400  {object}  dto.FailedValidationResp400
+// @Failure      500  {object}  dto.FailedResp500
+// @Router       /v1/password/reset [post]
+func (h *UserHandlerImpl) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	input := utils.ValidateBodyPayload(r.Body, &dto.ResetPasswordReq{})
+
+	if validateResetPasswordReq(input, h.appConfig).writeTo(w) {
+		return
+	}
+
+	h.userSvc.ResetPassword(r.Context(), input)
+
+	utils.GenerateSuccessResp[any](w, nil, http.StatusOK)
+}
+
+// VerifyEmail godoc
+// @Id verifyEmail
+// @Summary      Verify Email
+// @Description  Confirm the email address of an account with an email verification token
+// @Tags         auth
+// @Accept 		 json
+// @Param		 requestBody		body		dto.VerifyEmailReq	true	"Verify Email Request"
+// @Produce      json
+// @Success      200  {object}  dto.SuccessResp200
+// @Failure      400  {object}  dto.FailedResp400
+// @Failure      500  {object}  dto.FailedResp500
+// @Router       /v1/email/verify [post]
+func (h *UserHandlerImpl) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	input := utils.ValidateBodyPayload(r.Body, &dto.VerifyEmailReq{})
+
+	h.userSvc.VerifyEmail(r.Context(), input)
+
+	utils.GenerateSuccessResp[any](w, nil, http.StatusOK)
+}
+
+// ResendVerificationEmail godoc
+// @Id resendVerificationEmail
+// @Summary      Resend Verification Email
+// @Description  Mail the caller a new email verification link
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  dto.SuccessResp200
+// @Failure      400  {object}  dto.FailedResp400
+// @Failure      401  {object}  dto.FailedResp401
+// @Failure      404  {object}  dto.FailedResp404
+// @Failure      500  {object}  dto.FailedResp500
+// @Security authorization
+// @Router       /v1/email/verify/resend [post]
+func (h *UserHandlerImpl) ResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	h.userSvc.ResendVerificationEmail(r.Context())
+
+	utils.GenerateSuccessResp[any](w, nil, http.StatusOK)
+}
+
+// EnrollTwoFactor godoc
+// @Id enrollTwoFactor
+// @Summary      Enroll Two-Factor
+// @Description  Generate a TOTP secret and its otpauth URI for an authenticator app. Two-factor authentication is enabled once a code is verified.
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  dto.SuccessResp200{data=dto.EnrollTwoFactorRes}
+// @Failure      400  {object}  dto.FailedResp400
+// @Failure      401  {object}  dto.FailedResp401
+// @Failure      404  {object}  dto.FailedResp404
+// @Failure      500  {object}  dto.FailedResp500
+// @Security authorization
+// @Router       /v1/2fa/enroll [post]
+func (h *UserHandlerImpl) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	resp := h.userSvc.EnrollTwoFactor(r.Context())
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+// VerifyTwoFactor godoc
+// @Id verifyTwoFactor
+// @Summary      Verify Two-Factor
+// @Description  Enable two-factor authentication with a code from the enrolled secret. The returned recovery codes are only shown once.
+// @Tags         auth
+// @Accept 		 json
+// @Param		 requestBody		body		dto.VerifyTwoFactorReq	true	"Verify Two-Factor Request"
+// @Produce      json
+// @Success      200  {object}  dto.SuccessResp200{data=dto.VerifyTwoFactorRes}
+// @Failure      400  {object}  dto.FailedResp400
+// @Failure      401  {object}  dto.FailedResp401
+// @Failure      404  {object}  dto.FailedResp404
+// @Failure      500  {object}  dto.FailedResp500
+// @Security authorization
+// @Router       /v1/2fa/verify [post]
+func (h *UserHandlerImpl) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	input := utils.ValidateBodyPayload(r.Body, &dto.VerifyTwoFactorReq{})
+
+	resp := h.userSvc.VerifyTwoFactor(r.Context(), input)
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+// GetProfile godoc
+// @Id getProfile
+// @Summary      Get Profile
+// @Description  Get the profile of the caller
+// @Tags         profile
+// @Produce      json
+// @Success      200  {object}  dto.SuccessResp200{data=dto.ProfileRes}
+// @Failure      400  {object}  dto.FailedResp400
+// @Failure      401  {object}  dto.FailedResp401
+// @Failure      404  {object}  dto.FailedResp404
+// @Failure      500  {object}  dto.FailedResp500
+// @Security authorization
+// @Router       /v1/me [get]
+func (h *UserHandlerImpl) GetProfile(w http.ResponseWriter, r *http.Request) {
+	resp := h.userSvc.GetProfile(r.Context())
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+// UpdateProfile godoc
+// @Id updateProfile
+// @Summary      Update Profile
+// @Description  Change the name and/or email of the caller. Omitted fields are kept. Changing the email takes the current password and notifies the old address; the new email is unverified until the mailed verification link is opened.
+// @Tags         profile
+// @Accept 		 json
+// @Param		 requestBody		body		dto.UpdateProfileReq	true	"Update Profile Request"
+// @Produce      json
+// @Success      200  {object}  dto.SuccessResp200{data=dto.ProfileRes}
+// @Failure      400  {object}  dto.FailedValidationResp400
+// @Failure      401  {object}  dto.FailedResp401
+// @Failure      404  {object}  dto.FailedResp404
+// @Failure      429  {object}  dto.FailedResp429
+// @Failure      500  {object}  dto.FailedResp500
+// @Security authorization
+// @Router       /v1/me [patch]
+func (h *UserHandlerImpl) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	input := utils.ValidateBodyPayload(r.Body, &dto.UpdateProfileReq{})
+
+	if validateUpdateProfileReq(input).writeTo(w) {
+		return
+	}
+
+	resp := h.userSvc.UpdateProfile(r.Context(), input)
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+// ChangePassword godoc
+// @Id changePassword
+// @Summary      Change Password
+// @Description  Set a new password, given the current one, and end every session of the account including the current one
+// @Tags         profile
+// @Accept 		 json
+// @Param		 requestBody		body		dto.ChangePasswordReq	true	"Change Password Request"
+// @Produce      json
+// @Success      200  {object}  dto.SuccessResp200
+// @Failure      400  {object}  dto.FailedValidationResp400
+// @Failure      401  {object}  dto.FailedResp401
+// @Failure      404  {object}  dto.FailedResp404
+// @Failure      429  {object}  dto.FailedResp429
+// @Failure      500  {object}  dto.FailedResp500
+// @Security authorization
+// @Router       /v1/me/password [post]
+func (h *UserHandlerImpl) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	input := utils.ValidateBodyPayload(r.Body, &dto.ChangePasswordReq{})
+
+	if validateChangePasswordReq(input, h.appConfig).writeTo(w) {
+		return
+	}
+
+	h.userSvc.ChangePassword(r.Context(), input)
+
+	utils.GenerateSuccessResp[any](w, nil, http.StatusOK)
+}
+
+// ExportData godoc
+// @Id exportData
+// @Summary      Export Data
+// @Description  Download an archive of everything stored about the caller: the profile and every order with its details
+// @Tags         profile
+// @Produce      json
+// @Success      200  {object}  dto.SuccessResp200{data=dto.ExportDataRes}
+// @Failure      400  {object}  dto.FailedResp400
+// @Failure      401  {object}  dto.FailedResp401
+// @Failure      404  {object}  dto.FailedResp404
+// @Failure      500  {object}  dto.FailedResp500
+// @Security authorization
+// @Router       /v1/me/export [get]
+func (h *UserHandlerImpl) ExportData(w http.ResponseWriter, r *http.Request) {
+	resp := h.userSvc.ExportData(r.Context())
+
+	utils.GenerateSuccessResp(w, resp, http.StatusOK)
+}
+
+// DeleteAccount godoc
+// @Id deleteAccount
+// @Summary      Delete Account
+// @Description  Anonymize the name, email and password of the caller and end every session. Orders are kept for accounting.
+// @Tags         profile
+// @Produce      json
+// @Success      200  {object}  dto.SuccessResp200
+// @Failure      400  {object}  dto.FailedResp400
+// @Failure      401  {object}  dto.FailedResp401
+// @Failure      404  {object}  dto.FailedResp404
+// @Failure      500  {object}  dto.FailedResp500
+// @Security authorization
+// @Router       /v1/me [delete]
+func (h *UserHandlerImpl) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	h.userSvc.DeleteAccount(r.Context(), dto.DeleteAccountReq{
+		AccessToken: middleware.GetBearerToken(r),
+	})
+
+	utils.GenerateSuccessResp[any](w, nil, http.StatusOK)
+}
+
 func (h *UserHandlerImpl) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	utils.GenerateSuccessResp(w, "UP", http.StatusOK)
 }
 
 func setupUserV1Routes(route *chi.Mux, h *UserHandlerImpl) {
 	route.Get("/v1/health-check", h.HealthCheck)
-	route.Post("/v1/sign-up", h.SignUp)
+	route.Post("/v1/sign-up", h.rateLimitMiddleware.Limit(constant.RateLimitSignUp, h.SignUp))
 	route.Post("/v1/sign-in", h.SignIn)
+	route.Post("/v1/sign-in/2fa", h.SignInTwoFactor)
+	route.Post("/v1/token/refresh", h.RefreshToken)
+	route.Post("/v1/sign-out", h.authMiddleware.CheckIsAuthenticated(h.SignOut))
+	route.Post("/v1/sign-out-all", h.authMiddleware.CheckIsAuthenticated(h.SignOutAll))
+	route.Post("/v1/password/forgot", h.ForgotPassword)
+	route.Post("/v1/password/reset", h.ResetPassword)
+	route.Post("/v1/email/verify", h.VerifyEmail)
+	route.Post("/v1/email/verify/resend", h.authMiddleware.CheckIsAuthenticated(h.ResendVerificationEmail))
+	route.Post("/v1/2fa/enroll", h.authMiddleware.CheckIsAuthenticated(h.EnrollTwoFactor))
+	route.Post("/v1/2fa/verify", h.authMiddleware.CheckIsAuthenticated(h.VerifyTwoFactor))
+	route.Get("/v1/me", h.authMiddleware.CheckIsAuthenticated(h.GetProfile))
+	route.Patch("/v1/me", h.authMiddleware.CheckIsAuthenticated(h.UpdateProfile))
+	route.Delete("/v1/me", h.authMiddleware.CheckIsAuthenticated(h.DeleteAccount))
+	route.Post("/v1/me/password", h.authMiddleware.CheckIsAuthenticated(h.ChangePassword))
+	route.Get("/v1/me/export", h.authMiddleware.CheckIsAuthenticated(h.ExportData))
 }
@@ -8,9 +8,14 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gadhittana-01/book-go/config"
 	"github.com/gadhittana-01/book-go/dto"
+	"github.com/gadhittana-01/book-go/middleware"
+	mockmdw "github.com/gadhittana-01/book-go/middleware/mock"
 	"github.com/gadhittana-01/book-go/service"
 	mocksvc "github.com/gadhittana-01/book-go/service/mock"
+	"github.com/gadhittana01/go-modules/utils"
+	mockutl "github.com/gadhittana01/go-modules/utils/mock"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -19,9 +24,15 @@ import (
 func TestNewUserHandler(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	userMock := mocksvc.NewMockUserSvc(ctrl)
+	authMiddlewareMock := mockutl.NewMockAuthMiddleware(ctrl)
+	rateLimitMiddlewareMock := mockmdw.NewMockRateLimitMiddleware(ctrl)
+	appConfig := &config.AppConfig{}
 
 	type args struct {
-		service service.UserSvc
+		service             service.UserSvc
+		authMiddleware      utils.AuthMiddleware
+		rateLimitMiddleware middleware.RateLimitMiddleware
+		appConfig           *config.AppConfig
 	}
 
 	tests := []struct {
@@ -31,27 +42,43 @@ func TestNewUserHandler(t *testing.T) {
 	}{
 		{
 			args: args{
-				service: userMock,
+				service:             userMock,
+				authMiddleware:      authMiddlewareMock,
+				rateLimitMiddleware: rateLimitMiddlewareMock,
+				appConfig:           appConfig,
 			},
 			want: &UserHandlerImpl{
-				userSvc: userMock,
+				userSvc:             userMock,
+				authMiddleware:      authMiddlewareMock,
+				rateLimitMiddleware: rateLimitMiddlewareMock,
+				appConfig:           appConfig,
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := NewUserHandler(tt.args.service); !reflect.DeepEqual(got, tt.want) {
+			if got := NewUserHandler(tt.args.service, tt.args.authMiddleware, tt.args.rateLimitMiddleware, tt.args.appConfig); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewUserHandler() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func loadTestAppConfig(t *testing.T) *config.AppConfig {
+	appConfig := &config.AppConfig{}
+	if err := config.LoadAppConfig("../config", "test", appConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	return appConfig
+}
+
 func TestSignUp(t *testing.T) {
 	ctrl := gomock.NewController(t)
+	appConfig := loadTestAppConfig(t)
 	name := "Giri Putra Adhittana"
 	email := "test@gmail.com"
-	password := "123"
+	password := "Secret123"
 	userID := uuid.New()
 	token := "123"
 	expToken := int64(1000)
@@ -141,7 +168,8 @@ func TestSignUp(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			field := tt.fields()
 			i := UserHandlerImpl{
-				userSvc: field.service,
+				userSvc:   field.service,
+				appConfig: appConfig,
 			}
 
 			if tt.wantErr {
@@ -156,6 +184,38 @@ func TestSignUp(t *testing.T) {
 
 		})
 	}
+
+	t.Run("invalid email and weak password", func(t *testing.T) {
+		userMock := mocksvc.NewMockUserSvc(ctrl)
+		userMock.EXPECT().SignUp(gomock.Any(), gomock.Any()).Times(0)
+
+		req := httptest.NewRequest("POST", "http://localhost:8000/v1/sign-up", strings.NewReader(`{
+			"name" : "Giri",
+			"email" : "not-an-email",
+			"password" : "secret"
+		}`))
+		resp := httptest.NewRecorder()
+
+		i := UserHandlerImpl{
+			userSvc:   userMock,
+			appConfig: appConfig,
+		}
+
+		assert.NotPanics(t, func() {
+			i.SignUp(resp, req)
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.JSONEq(t, `{
+			"success": false,
+			"statusCode": 400,
+			"errors": [
+				{"field": "email", "message": "Email is not a valid email address"},
+				{"field": "password", "message": "Password must be at least 8 characters"},
+				{"field": "password", "message": "Password must contain an uppercase letter"},
+				{"field": "password", "message": "Password must contain a digit"}
+			]
+		}`, resp.Body.String())
+	})
 }
 
 func TestSignIn(t *testing.T) {
@@ -199,6 +259,7 @@ func TestSignIn(t *testing.T) {
 				userMock := mocksvc.NewMockUserSvc(ctrl)
 
 				userMock.EXPECT().SignIn(gomock.Any(), dto.SignInReq{
+					IP:       "192.0.2.1",
 					Email:    email,
 					Password: password,
 				}).Return(dto.SignInRes{
@@ -224,6 +285,7 @@ func TestSignIn(t *testing.T) {
 				userMock := mocksvc.NewMockUserSvc(ctrl)
 
 				userMock.EXPECT().SignIn(gomock.Any(), dto.SignInReq{
+					IP:       "192.0.2.1",
 					Email:    email,
 					Password: password,
 				}).Return(dto.SignInRes{
@@ -248,7 +310,8 @@ func TestSignIn(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			field := tt.fields()
 			i := UserHandlerImpl{
-				userSvc: field.service,
+				userSvc:   field.service,
+				appConfig: &config.AppConfig{},
 			}
 
 			if tt.wantErr {
@@ -264,3 +327,982 @@ func TestSignIn(t *testing.T) {
 		})
 	}
 }
+
+func TestSignInTwoFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	sampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/sign-in/2fa", strings.NewReader(`{
+		"challengeToken" : "challenge-token",
+		"code" : "123456"
+	}`))
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/sign-in/2fa", strings.NewReader(`{}`))
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.UserSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success sign in two-factor",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().SignInTwoFactor(gomock.Any(), dto.SignInTwoFactorReq{
+					IP:             "192.0.2.1",
+					ChallengeToken: "challenge-token",
+					Code:           "123456",
+				}).Return(dto.SignInRes{
+					ID:    uuid.NewString(),
+					Token: "token",
+				}).Times(1)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid request body",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().SignInTwoFactor(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := UserHandlerImpl{
+				userSvc:   field.service,
+				appConfig: &config.AppConfig{},
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.SignInTwoFactor(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.SignInTwoFactor(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	refreshToken := "refresh-token"
+
+	sampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/token/refresh", strings.NewReader(fmt.Sprintf(`{
+		"refreshToken" : "%s"
+	}`, refreshToken)))
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/token/refresh", strings.NewReader(`{}`))
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.UserSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success refresh token",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().RefreshToken(gomock.Any(), dto.RefreshTokenReq{
+					RefreshToken: refreshToken,
+				}).Return(dto.RefreshTokenRes{
+					Token:           "123",
+					ExpToken:        1000,
+					RefreshToken:    "new-refresh-token",
+					ExpRefreshToken: 2000,
+				}).Times(1)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing refresh token",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().RefreshToken(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := UserHandlerImpl{
+				userSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.RefreshToken(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.RefreshToken(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
+
+func TestSignOut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	accessToken := "123"
+	refreshToken := "refresh-token"
+
+	sampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/sign-out", strings.NewReader(fmt.Sprintf(`{
+		"refreshToken" : "%s"
+	}`, refreshToken)))
+	sampleReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/sign-out", strings.NewReader(`{}`))
+	invalidSampleReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.UserSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success sign out",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().SignOut(gomock.Any(), dto.SignOutReq{
+					AccessToken:  accessToken,
+					RefreshToken: refreshToken,
+				}).Times(1)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing refresh token",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().SignOut(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := UserHandlerImpl{
+				userSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.SignOut(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.SignOut(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
+
+func TestSignOutAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	accessToken := "123"
+
+	sampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/sign-out-all", nil)
+	sampleReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	sampleResp := httptest.NewRecorder()
+
+	userMock := mocksvc.NewMockUserSvc(ctrl)
+	userMock.EXPECT().SignOutAll(gomock.Any(), dto.SignOutAllReq{
+		AccessToken: accessToken,
+	}).Times(1)
+
+	i := UserHandlerImpl{
+		userSvc: userMock,
+	}
+
+	assert.NotPanics(t, func() {
+		i.SignOutAll(sampleResp, sampleReq)
+	})
+}
+
+func TestForgotPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	sampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/password/forgot", strings.NewReader(`{
+		"email" : "test@gmail.com"
+	}`))
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/password/forgot", strings.NewReader(`{}`))
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.UserSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success forgot password",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().ForgotPassword(gomock.Any(), dto.ForgotPasswordReq{
+					Email: "test@gmail.com",
+				}).Times(1)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid request body",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().ForgotPassword(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := UserHandlerImpl{
+				userSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.ForgotPassword(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.ForgotPassword(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	appConfig := loadTestAppConfig(t)
+
+	sampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/password/reset", strings.NewReader(`{
+		"token" : "reset-token",
+		"password" : "NewPassword1"
+	}`))
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/password/reset", strings.NewReader(`{}`))
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.UserSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success reset password",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().ResetPassword(gomock.Any(), dto.ResetPasswordReq{
+					Token:    "reset-token",
+					Password: "NewPassword1",
+				}).Times(1)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid request body",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().ResetPassword(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := UserHandlerImpl{
+				userSvc:   field.service,
+				appConfig: appConfig,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.ResetPassword(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.ResetPassword(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+
+	t.Run("weak password", func(t *testing.T) {
+		userMock := mocksvc.NewMockUserSvc(ctrl)
+		userMock.EXPECT().ResetPassword(gomock.Any(), gomock.Any()).Times(0)
+
+		req := httptest.NewRequest("POST", "http://localhost:8000/v1/password/reset", strings.NewReader(`{
+			"token" : "reset-token",
+			"password" : "newpassword1"
+		}`))
+		resp := httptest.NewRecorder()
+
+		i := UserHandlerImpl{
+			userSvc:   userMock,
+			appConfig: appConfig,
+		}
+
+		assert.NotPanics(t, func() {
+			i.ResetPassword(resp, req)
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), PasswordNeedUppercase)
+	})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	sampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/email/verify", strings.NewReader(`{
+		"token" : "verification-token"
+	}`))
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/email/verify", strings.NewReader(`{}`))
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.UserSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success verify email",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().VerifyEmail(gomock.Any(), dto.VerifyEmailReq{
+					Token: "verification-token",
+				}).Times(1)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid request body",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().VerifyEmail(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := UserHandlerImpl{
+				userSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.VerifyEmail(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.VerifyEmail(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
+
+func TestResendVerificationEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	sampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/email/verify/resend", nil)
+	sampleResp := httptest.NewRecorder()
+
+	userMock := mocksvc.NewMockUserSvc(ctrl)
+	userMock.EXPECT().ResendVerificationEmail(gomock.Any()).Times(1)
+
+	i := UserHandlerImpl{
+		userSvc: userMock,
+	}
+
+	assert.NotPanics(t, func() {
+		i.ResendVerificationEmail(sampleResp, sampleReq)
+	})
+}
+
+func TestEnrollTwoFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	sampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/2fa/enroll", nil)
+	sampleResp := httptest.NewRecorder()
+
+	userMock := mocksvc.NewMockUserSvc(ctrl)
+	userMock.EXPECT().EnrollTwoFactor(gomock.Any()).Return(dto.EnrollTwoFactorRes{
+		Secret:     "SECRET",
+		OtpauthURI: "otpauth://totp/Book%20Store:test@gmail.com?secret=SECRET",
+	}).Times(1)
+
+	i := UserHandlerImpl{
+		userSvc: userMock,
+	}
+
+	assert.NotPanics(t, func() {
+		i.EnrollTwoFactor(sampleResp, sampleReq)
+	})
+	assert.Equal(t, http.StatusOK, sampleResp.Code)
+}
+
+func TestVerifyTwoFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	sampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/2fa/verify", strings.NewReader(`{
+		"code" : "123456"
+	}`))
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/2fa/verify", strings.NewReader(`{}`))
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.UserSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success verify two-factor",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().VerifyTwoFactor(gomock.Any(), dto.VerifyTwoFactorReq{
+					Code: "123456",
+				}).Return(dto.VerifyTwoFactorRes{
+					RecoveryCodes: []string{"abcde-fghij"},
+				}).Times(1)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid request body",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().VerifyTwoFactor(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := UserHandlerImpl{
+				userSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.VerifyTwoFactor(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.VerifyTwoFactor(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+}
+
+func TestGetProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	sampleReq := httptest.NewRequest("GET", "http://localhost:8000/v1/me", nil)
+	sampleResp := httptest.NewRecorder()
+
+	userMock := mocksvc.NewMockUserSvc(ctrl)
+	userMock.EXPECT().GetProfile(gomock.Any()).Return(dto.ProfileRes{
+		ID:    uuid.NewString(),
+		Name:  "Giri Putra Adhittana",
+		Email: "test@gmail.com",
+		Role:  "customer",
+	}).Times(1)
+
+	i := UserHandlerImpl{
+		userSvc: userMock,
+	}
+
+	assert.NotPanics(t, func() {
+		i.GetProfile(sampleResp, sampleReq)
+	})
+	assert.Equal(t, http.StatusOK, sampleResp.Code)
+}
+
+func TestUpdateProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	name := "Giri"
+
+	sampleReq := httptest.NewRequest("PATCH", "http://localhost:8000/v1/me", strings.NewReader(`{
+		"name" : "Giri"
+	}`))
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := httptest.NewRequest("PATCH", "http://localhost:8000/v1/me", strings.NewReader(`{
+		"name" : ""
+	}`))
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.UserSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success update profile",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().UpdateProfile(gomock.Any(), dto.UpdateProfileReq{
+					Name: &name,
+				}).Return(dto.ProfileRes{
+					Name: name,
+				}).Times(1)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid request body",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := UserHandlerImpl{
+				userSvc: field.service,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.UpdateProfile(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.UpdateProfile(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+
+	t.Run("invalid email", func(t *testing.T) {
+		userMock := mocksvc.NewMockUserSvc(ctrl)
+		userMock.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).Times(0)
+
+		req := httptest.NewRequest("PATCH", "http://localhost:8000/v1/me", strings.NewReader(`{
+			"name" : "  ",
+			"email" : "test@"
+		}`))
+		resp := httptest.NewRecorder()
+
+		i := UserHandlerImpl{
+			userSvc: userMock,
+		}
+
+		assert.NotPanics(t, func() {
+			i.UpdateProfile(resp, req)
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), NameRequired)
+		assert.Contains(t, resp.Body.String(), InvalidEmail)
+	})
+}
+
+func TestChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	appConfig := loadTestAppConfig(t)
+
+	sampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/me/password", strings.NewReader(`{
+		"oldPassword" : "Password1",
+		"newPassword" : "NewPassword1"
+	}`))
+	sampleResp := httptest.NewRecorder()
+
+	invalidSampleReq := httptest.NewRequest("POST", "http://localhost:8000/v1/me/password", strings.NewReader(`{}`))
+	invalidSampleResp := httptest.NewRecorder()
+
+	type fields struct {
+		service service.UserSvc
+	}
+
+	type args struct {
+		w   http.ResponseWriter
+		req *http.Request
+	}
+
+	tests := []struct {
+		name    string
+		fields  func() fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success change password",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().ChangePassword(gomock.Any(), dto.ChangePasswordReq{
+					OldPassword: "Password1",
+					NewPassword: "NewPassword1",
+				}).Times(1)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   sampleResp,
+				req: sampleReq,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid request body",
+			fields: func() fields {
+				userMock := mocksvc.NewMockUserSvc(ctrl)
+
+				userMock.EXPECT().ChangePassword(gomock.Any(), gomock.Any()).Times(0)
+
+				return fields{
+					service: userMock,
+				}
+			},
+			args: args{
+				w:   invalidSampleResp,
+				req: invalidSampleReq,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := tt.fields()
+			i := UserHandlerImpl{
+				userSvc:   field.service,
+				appConfig: appConfig,
+			}
+
+			if tt.wantErr {
+				assert.Panics(t, func() {
+					i.ChangePassword(tt.args.w, tt.args.req)
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					i.ChangePassword(tt.args.w, tt.args.req)
+				})
+			}
+		})
+	}
+
+	t.Run("weak new password", func(t *testing.T) {
+		userMock := mocksvc.NewMockUserSvc(ctrl)
+		userMock.EXPECT().ChangePassword(gomock.Any(), gomock.Any()).Times(0)
+
+		req := httptest.NewRequest("POST", "http://localhost:8000/v1/me/password", strings.NewReader(`{
+			"oldPassword" : "Password1",
+			"newPassword" : "newpassword1"
+		}`))
+		resp := httptest.NewRecorder()
+
+		i := UserHandlerImpl{
+			userSvc:   userMock,
+			appConfig: appConfig,
+		}
+
+		assert.NotPanics(t, func() {
+			i.ChangePassword(resp, req)
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), PasswordNeedUppercase)
+	})
+}
+
+func TestExportData(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	sampleReq := httptest.NewRequest("GET", "http://localhost:8000/v1/me/export", nil)
+	sampleResp := httptest.NewRecorder()
+
+	userMock := mocksvc.NewMockUserSvc(ctrl)
+	userMock.EXPECT().ExportData(gomock.Any()).Return(dto.ExportDataRes{
+		Profile: dto.ProfileRes{
+			ID:    uuid.NewString(),
+			Email: "test@gmail.com",
+		},
+		Orders: []dto.GetOrderDetailRes{},
+	}).Times(1)
+
+	i := UserHandlerImpl{
+		userSvc: userMock,
+	}
+
+	assert.NotPanics(t, func() {
+		i.ExportData(sampleResp, sampleReq)
+	})
+	assert.Equal(t, http.StatusOK, sampleResp.Code)
+}
+
+func TestDeleteAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	sampleReq := httptest.NewRequest("DELETE", "http://localhost:8000/v1/me", nil)
+	sampleReq.Header.Set("Authorization", "Bearer current-token")
+	sampleResp := httptest.NewRecorder()
+
+	userMock := mocksvc.NewMockUserSvc(ctrl)
+	userMock.EXPECT().DeleteAccount(gomock.Any(), dto.DeleteAccountReq{
+		AccessToken: "current-token",
+	}).Times(1)
+
+	i := UserHandlerImpl{
+		userSvc: userMock,
+	}
+
+	assert.NotPanics(t, func() {
+		i.DeleteAccount(sampleResp, sampleReq)
+	})
+	assert.Equal(t, http.StatusOK, sampleResp.Code)
+}
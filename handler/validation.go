@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"unicode"
+
+	"github.com/gadhittana-01/book-go/config"
+	"github.com/gadhittana-01/book-go/dto"
+)
+
+const (
+	NameRequired          = "Name cannot be blank"
+	InvalidEmail          = "Email is not a valid email address"
+	PasswordTooShort      = "Password must be at least %d characters"
+	PasswordTooLong       = "Password must be at most %d bytes"
+	PasswordNeedUppercase = "Password must contain an uppercase letter"
+	PasswordNeedLowercase = "Password must contain a lowercase letter"
+	PasswordNeedDigit     = "Password must contain a digit"
+	PasswordNeedSymbol    = "Password must contain a symbol"
+)
+
+const (
+	// maxEmailLength is the longest address SMTP can deliver to.
+	maxEmailLength = 254
+	// maxPasswordBytes is where bcrypt stops reading the password.
+	maxPasswordBytes = 72
+)
+
+// fieldErrors collects the validation failures of a request body, one entry
+// per failed rule, so a client can show every problem at once.
+type fieldErrors []dto.FieldErrorMsgResp
+
+func (e *fieldErrors) add(field string, message string) {
+	*e = append(*e, dto.FieldErrorMsgResp{
+		Field:   field,
+		Message: message,
+	})
+}
+
+// writeTo responds with the collected errors and reports whether there were
+// any, in which case the handler must stop.
+func (e fieldErrors) writeTo(w http.ResponseWriter) bool {
+	if len(e) == 0 {
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(dto.FailedValidationResp400{
+		Success:    false,
+		StatusCode: http.StatusBadRequest,
+		Errors:     e,
+	})
+
+	return true
+}
+
+// validateEmail only checks the syntax. Case and surrounding spaces are
+// normalized by the user queries, so they are not errors.
+func validateEmail(errs *fieldErrors, field string, email string) {
+	email = strings.TrimSpace(email)
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || len(email) > maxEmailLength {
+		errs.add(field, InvalidEmail)
+	}
+}
+
+// validatePassword applies the password policy of appConfig.
+func validatePassword(errs *fieldErrors, field string, password string, appConfig *config.AppConfig) {
+	if len([]rune(password)) < appConfig.PasswordMinLength {
+		errs.add(field, fmt.Sprintf(PasswordTooShort, appConfig.PasswordMinLength))
+	}
+
+	if len(password) > maxPasswordBytes {
+		errs.add(field, fmt.Sprintf(PasswordTooLong, maxPasswordBytes))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	if appConfig.PasswordRequireUppercase && !hasUpper {
+		errs.add(field, PasswordNeedUppercase)
+	}
+
+	if appConfig.PasswordRequireLowercase && !hasLower {
+		errs.add(field, PasswordNeedLowercase)
+	}
+
+	if appConfig.PasswordRequireDigit && !hasDigit {
+		errs.add(field, PasswordNeedDigit)
+	}
+
+	if appConfig.PasswordRequireSymbol && !hasSymbol {
+		errs.add(field, PasswordNeedSymbol)
+	}
+}
+
+func validateSignUpReq(input dto.SignUpReq, appConfig *config.AppConfig) fieldErrors {
+	var errs fieldErrors
+
+	if strings.TrimSpace(input.Name) == "" {
+		errs.add("name", NameRequired)
+	}
+
+	validateEmail(&errs, "email", input.Email)
+	validatePassword(&errs, "password", input.Password, appConfig)
+
+	return errs
+}
+
+func validateResetPasswordReq(input dto.ResetPasswordReq, appConfig *config.AppConfig) fieldErrors {
+	var errs fieldErrors
+
+	validatePassword(&errs, "password", input.Password, appConfig)
+
+	return errs
+}
+
+func validateUpdateProfileReq(input dto.UpdateProfileReq) fieldErrors {
+	var errs fieldErrors
+
+	if input.Name != nil && strings.TrimSpace(*input.Name) == "" {
+		errs.add("name", NameRequired)
+	}
+
+	if input.Email != nil {
+		validateEmail(&errs, "email", *input.Email)
+	}
+
+	return errs
+}
+
+func validateChangePasswordReq(input dto.ChangePasswordReq, appConfig *config.AppConfig) fieldErrors {
+	var errs fieldErrors
+
+	validatePassword(&errs, "newPassword", input.NewPassword, appConfig)
+
+	return errs
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gadhittana-01/book-go/config"
+	"github.com/gadhittana-01/book-go/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		isValid bool
+	}{
+		{name: "valid email", email: "test@gmail.com", isValid: true},
+		{name: "mixed case and spaces", email: "  Test@Gmail.com ", isValid: true},
+		{name: "missing domain", email: "test@", isValid: false},
+		{name: "missing at sign", email: "test.gmail.com", isValid: false},
+		{name: "display name", email: "Test <test@gmail.com>", isValid: false},
+		{name: "empty", email: "", isValid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs fieldErrors
+			validateEmail(&errs, "email", tt.email)
+
+			if tt.isValid {
+				assert.Empty(t, errs)
+			} else {
+				assert.Equal(t, fieldErrors{{Field: "email", Message: InvalidEmail}}, errs)
+			}
+		})
+	}
+}
+
+func TestValidatePassword(t *testing.T) {
+	appConfig := &config.AppConfig{
+		PasswordMinLength:        8,
+		PasswordRequireUppercase: true,
+		PasswordRequireLowercase: true,
+		PasswordRequireDigit:     true,
+		PasswordRequireSymbol:    true,
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		want     []string
+	}{
+		{name: "meets policy", password: "Secret123!"},
+		{name: "too short", password: "Se1!", want: []string{"Password must be at least 8 characters"}},
+		{name: "missing classes", password: "secretsecret", want: []string{
+			PasswordNeedUppercase,
+			PasswordNeedDigit,
+			PasswordNeedSymbol,
+		}},
+		{name: "too long for bcrypt", password: "Secret123!" + strings.Repeat("a", 70), want: []string{
+			"Password must be at most 72 bytes",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs fieldErrors
+			validatePassword(&errs, "password", tt.password, appConfig)
+
+			var got []string
+			for _, err := range errs {
+				assert.Equal(t, "password", err.Field)
+				got = append(got, err.Message)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateSignUpReq(t *testing.T) {
+	errs := validateSignUpReq(dto.SignUpReq{
+		Name:     "  ",
+		Email:    "test@gmail.com",
+		Password: "Secret123",
+	}, &config.AppConfig{PasswordMinLength: 8})
+
+	assert.Equal(t, fieldErrors{{Field: "name", Message: NameRequired}}, errs)
+}
@@ -5,8 +5,12 @@ package main
 
 import (
 	"github.com/gadhittana-01/book-go/app"
+	"github.com/gadhittana-01/book-go/cache"
+	appConfig "github.com/gadhittana-01/book-go/config"
 	querier "github.com/gadhittana-01/book-go/db/repository"
 	"github.com/gadhittana-01/book-go/handler"
+	"github.com/gadhittana-01/book-go/mailer"
+	"github.com/gadhittana-01/book-go/middleware"
 	"github.com/gadhittana-01/book-go/service"
 	"github.com/gadhittana01/go-modules/utils"
 	"github.com/go-chi/chi"
@@ -19,11 +23,16 @@ var userHandlerSet = wire.NewSet(
 	utils.NewToken,
 	handler.NewUserHandler,
 	service.NewUserSvc,
+	service.NewTokenRevocationSvc,
+	service.NewLoginAttemptSvc,
+	service.NewTwoFactorChallengeSvc,
+	mailer.NewMailer,
 )
 
 var orderHandlerSet = wire.NewSet(
 	handler.NewOrderHandler,
 	service.NewOrderSvc,
+	service.NewOrderStatusSvc,
 )
 
 var bookHandlerSet = wire.NewSet(
@@ -31,27 +40,48 @@ var bookHandlerSet = wire.NewSet(
 	service.NewBookSvc,
 )
 
+var cartHandlerSet = wire.NewSet(
+	handler.NewCartHandler,
+	service.NewCartSvc,
+)
+
+var healthHandlerSet = wire.NewSet(
+	handler.NewHealthHandler,
+	service.NewHealthSvc,
+)
+
 var authMiddlewareSet = wire.NewSet(
-	utils.NewAuthMiddleware,
+	middleware.NewAuthMiddleware,
+	middleware.NewRoleMiddleware,
+	middleware.NewEmailVerificationMiddleware,
+)
+
+var rateLimitMiddlewareSet = wire.NewSet(
+	middleware.NewRateLimitMiddleware,
+	service.NewRateLimitSvc,
 )
 
 var cacheSet = wire.NewSet(
 	wire.Bind(new(utils.RedisClient), new(*redis.Client)),
 	utils.NewRedisClient,
-	utils.NewCacheSvc,
+	cache.NewResilientCacheSvc,
 )
 
 func InitializeApp(
 	route *chi.Mux,
 	DB utils.PGXPool,
 	config *utils.BaseConfig,
+	appCfg *appConfig.AppConfig,
 ) (app.App, error) {
 	wire.Build(
 		userHandlerSet,
 		orderHandlerSet,
 		bookHandlerSet,
+		cartHandlerSet,
+		healthHandlerSet,
 		cacheSet,
 		authMiddlewareSet,
+		rateLimitMiddlewareSet,
 		app.NewApp,
 	)
 
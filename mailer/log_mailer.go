@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/gadhittana-01/book-go/config"
+	"github.com/gadhittana01/go-modules/utils"
+)
+
+// LogMailer is meant for local development and tests. It appends every
+// message to MAIL_LOG_PATH, or writes it to the application log when no
+// path is configured.
+type LogMailer struct {
+	path string
+	from string
+	mu   sync.Mutex
+}
+
+func NewLogMailer(appConfig *config.AppConfig) Mailer {
+	return &LogMailer{
+		path: appConfig.MailLogPath,
+		from: appConfig.MailFrom,
+	}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	content := string(buildMessage(m.from, msg))
+
+	if m.path == "" {
+		utils.LogInfo(fmt.Sprintf("mail sent:\n%s", content))
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "%s\r\n\r\n", content)
+	return err
+}
@@ -0,0 +1,32 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/gadhittana-01/book-go/config"
+)
+
+const (
+	DriverSMTP = "smtp"
+	DriverLog  = "log"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewMailer returns the mailer selected by MAIL_DRIVER. Anything other than
+// smtp falls back to the log mailer, so local setups never send real mail.
+func NewMailer(appConfig *config.AppConfig) Mailer {
+	if appConfig.MailDriver == DriverSMTP {
+		return NewSMTPMailer(appConfig)
+	}
+
+	return NewLogMailer(appConfig)
+}
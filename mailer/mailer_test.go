@@ -0,0 +1,97 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gadhittana-01/book-go/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMailer(t *testing.T) {
+	t.Run("smtp driver", func(t *testing.T) {
+		m := NewMailer(&config.AppConfig{
+			MailDriver: DriverSMTP,
+			MailFrom:   "no-reply@book.com",
+			SMTPHost:   "localhost",
+			SMTPPort:   587,
+		})
+
+		assert.IsType(t, &SMTPMailer{}, m)
+		assert.Equal(t, "localhost:587", m.(*SMTPMailer).addr)
+		assert.Nil(t, m.(*SMTPMailer).auth)
+	})
+
+	t.Run("smtp driver with credentials", func(t *testing.T) {
+		m := NewMailer(&config.AppConfig{
+			MailDriver:   DriverSMTP,
+			SMTPHost:     "localhost",
+			SMTPPort:     587,
+			SMTPUsername: "user",
+			SMTPPassword: "secret",
+		})
+
+		assert.NotNil(t, m.(*SMTPMailer).auth)
+	})
+
+	t.Run("log driver is the default", func(t *testing.T) {
+		assert.IsType(t, &LogMailer{}, NewMailer(&config.AppConfig{}))
+	})
+}
+
+func TestBuildMessage(t *testing.T) {
+	msg := buildMessage("no-reply@book.com", Message{
+		To:      "test@gmail.com",
+		Subject: "Hello",
+		Body:    "line 1\nline 2",
+	})
+
+	assert.Equal(t, "From: no-reply@book.com\r\n"+
+		"To: test@gmail.com\r\n"+
+		"Subject: Hello\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=\"utf-8\"\r\n"+
+		"\r\n"+
+		"line 1\r\nline 2", string(msg))
+}
+
+func TestLogMailerSend(t *testing.T) {
+	ctx := context.Background()
+	msg := Message{
+		To:      "test@gmail.com",
+		Subject: "Hello",
+		Body:    "token: 123",
+	}
+
+	t.Run("write to file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "mail.log")
+		m := NewLogMailer(&config.AppConfig{
+			MailFrom:    "no-reply@book.com",
+			MailLogPath: path,
+		})
+
+		assert.NoError(t, m.Send(ctx, msg))
+		assert.NoError(t, m.Send(ctx, msg))
+
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "To: test@gmail.com")
+		assert.Contains(t, string(content), "token: 123")
+	})
+
+	t.Run("write to log", func(t *testing.T) {
+		m := NewLogMailer(&config.AppConfig{})
+
+		assert.NoError(t, m.Send(ctx, msg))
+	})
+
+	t.Run("unwritable path", func(t *testing.T) {
+		m := NewLogMailer(&config.AppConfig{
+			MailLogPath: filepath.Join(t.TempDir(), "missing", "mail.log"),
+		})
+
+		assert.Error(t, m.Send(ctx, msg))
+	})
+}
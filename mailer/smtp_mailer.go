@@ -0,0 +1,50 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/gadhittana-01/book-go/config"
+)
+
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPMailer(appConfig *config.AppConfig) Mailer {
+	var auth smtp.Auth
+	if appConfig.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", appConfig.SMTPUsername, appConfig.SMTPPassword, appConfig.SMTPHost)
+	}
+
+	return &SMTPMailer{
+		addr: net.JoinHostPort(appConfig.SMTPHost, strconv.Itoa(appConfig.SMTPPort)),
+		from: appConfig.MailFrom,
+		auth: auth,
+	}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, buildMessage(m.from, msg))
+}
+
+// buildMessage renders msg as a plain text RFC 5322 message.
+func buildMessage(from string, msg Message) []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	return []byte(b.String())
+}
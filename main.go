@@ -1,6 +1,14 @@
 package main
 
 import (
+	"context"
+	"fmt"
+
+	appConfig "github.com/gadhittana-01/book-go/config"
+	querier "github.com/gadhittana-01/book-go/db/repository"
+	"github.com/gadhittana-01/book-go/metrics"
+	"github.com/gadhittana-01/book-go/service"
+	"github.com/gadhittana-01/book-go/tracing"
 	"github.com/gadhittana01/go-modules/utils"
 	"github.com/go-chi/chi"
 )
@@ -27,17 +35,43 @@ import (
 func main() {
 	r := chi.NewRouter()
 	config := utils.CheckAndSetConfig("./config", "app")
+	appCfg := appConfig.CheckAndSetAppConfig("./config", "app")
+
+	shutdownTracing, err := tracing.Setup(context.Background(), appCfg)
+	if err != nil {
+		panic(err)
+	}
+	defer flushTraces(shutdownTracing, appCfg)
+
 	DBpool := utils.ConnectDBPool(config.DBConnString)
+	metrics.RegisterDBPool(DBpool)
 	DB := utils.ConnectDB(config.DBConnString)
 
 	if err := utils.RunMigrationPool(DB, config); err != nil {
 		panic(err)
 	}
 
-	app, err := InitializeApp(r, DBpool, config)
+	if err := service.BootstrapAdmin(context.Background(), querier.NewRepository(DBpool), appCfg); err != nil {
+		panic(err)
+	}
+
+	app, err := InitializeApp(r, DBpool, config, appCfg)
 	if err != nil {
 		panic(err)
 	}
 
-	app.Start()
+	if err := app.Start(); err != nil {
+		panic(err)
+	}
+}
+
+// flushTraces sends the spans still buffered, so the last requests before a
+// shutdown are not lost.
+func flushTraces(shutdownTracing func(context.Context) error, appCfg *appConfig.AppConfig) {
+	ctx, cancel := context.WithTimeout(context.Background(), appCfg.ServerShutdownTimeout)
+	defer cancel()
+
+	if err := shutdownTracing(ctx); err != nil {
+		utils.LogInfo(fmt.Sprintf("failed to flush traces: %v", err))
+	}
 }
@@ -0,0 +1,140 @@
+// Package metrics defines the Prometheus metrics of the service. They are
+// registered on the default registry, which also carries the Go runtime and
+// process metrics, and served at /metrics on METRICS_PORT, apart from the API.
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/shopspring/decimal"
+)
+
+const namespace = "book"
+
+// result label values
+const (
+	resultOK    = "ok"
+	resultError = "error"
+	resultHit   = "hit"
+	resultMiss  = "miss"
+)
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of HTTP requests by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests by method, route pattern and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Latency of database queries by sqlc query name and result.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"query", "result"})
+
+	cacheRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Number of cache lookups by cache prefix and result.",
+	}, []string{"cache", "result"})
+
+	cacheCircuitOpen = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cache_circuit_open",
+		Help:      "1 while Redis is skipped after repeated errors and reads fall back to the database, 0 otherwise.",
+	})
+
+	ordersCreatedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_created_total",
+		Help:      "Number of orders placed, by whether they came from the order API or a cart checkout.",
+	}, []string{"source"})
+
+	orderValue = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "order_value",
+		Help:      "Total price of placed orders.",
+		Buckets:   []float64{10, 25, 50, 100, 250, 500, 1000, 2500, 5000},
+	}, []string{"source"})
+
+	signUpsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sign_ups_total",
+		Help:      "Number of accounts created through sign-up.",
+	})
+
+	failedLoginsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "failed_logins_total",
+		Help:      "Number of rejected sign-in attempts by reason.",
+	}, []string{"reason"})
+)
+
+// Handler serves the metrics of the default registry.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+func ObserveHTTPRequest(method string, route string, status int, duration time.Duration) {
+	statusCode := strconv.Itoa(status)
+
+	httpRequestsTotal.WithLabelValues(method, route, statusCode).Inc()
+	httpRequestDuration.WithLabelValues(method, route, statusCode).Observe(duration.Seconds())
+}
+
+// ObserveDBQuery records a query by name. A missing row is an expected
+// answer, not a failure.
+func ObserveDBQuery(query string, err error, duration time.Duration) {
+	result := resultOK
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		result = resultError
+	}
+
+	dbQueryDuration.WithLabelValues(query, result).Observe(duration.Seconds())
+}
+
+func RecordCacheLookup(cache string, hit bool) {
+	result := resultMiss
+	if hit {
+		result = resultHit
+	}
+
+	cacheRequestsTotal.WithLabelValues(cache, result).Inc()
+}
+
+func SetCacheCircuitOpen(open bool) {
+	if open {
+		cacheCircuitOpen.Set(1)
+		return
+	}
+
+	cacheCircuitOpen.Set(0)
+}
+
+func RecordOrderCreated(source string, totalPrice decimal.Decimal) {
+	ordersCreatedTotal.WithLabelValues(source).Inc()
+	orderValue.WithLabelValues(source).Observe(totalPrice.InexactFloat64())
+}
+
+func RecordSignUp() {
+	signUpsTotal.Inc()
+}
+
+func RecordFailedLogin(reason string) {
+	failedLoginsTotal.WithLabelValues(reason).Inc()
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	clientModel "github.com/prometheus/client_model/go"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func histogramCount(t *testing.T, vec *prometheus.HistogramVec, labels ...string) uint64 {
+	metric := &clientModel.Metric{}
+	if err := vec.WithLabelValues(labels...).(prometheus.Metric).Write(metric); err != nil {
+		t.Fatal(err)
+	}
+
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestObserveHTTPRequest(t *testing.T) {
+	ObserveHTTPRequest("GET", "/v1/book/{bookId}", 404, time.Millisecond)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(httpRequestsTotal.WithLabelValues("GET", "/v1/book/{bookId}", "404")))
+	assert.Equal(t, uint64(1), histogramCount(t, httpRequestDuration, "GET", "/v1/book/{bookId}", "404"))
+}
+
+func TestObserveDBQuery(t *testing.T) {
+	t.Run("no rows is not an error", func(t *testing.T) {
+		ObserveDBQuery("FindUserByEmail", pgx.ErrNoRows, time.Millisecond)
+
+		assert.Equal(t, uint64(1), histogramCount(t, dbQueryDuration, "FindUserByEmail", resultOK))
+		assert.Equal(t, uint64(0), histogramCount(t, dbQueryDuration, "FindUserByEmail", resultError))
+	})
+
+	t.Run("failed query", func(t *testing.T) {
+		ObserveDBQuery("CreateUser", errors.New("connection reset"), time.Millisecond)
+
+		assert.Equal(t, uint64(1), histogramCount(t, dbQueryDuration, "CreateUser", resultError))
+	})
+}
+
+func TestRecordCacheLookup(t *testing.T) {
+	RecordCacheLookup("book", false)
+	RecordCacheLookup("book", true)
+	RecordCacheLookup("book", true)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(cacheRequestsTotal.WithLabelValues("book", resultMiss)))
+	assert.Equal(t, float64(2), testutil.ToFloat64(cacheRequestsTotal.WithLabelValues("book", resultHit)))
+}
+
+func TestRecordOrderCreated(t *testing.T) {
+	RecordOrderCreated("cart", decimal.NewFromFloat(12.5))
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(ordersCreatedTotal.WithLabelValues("cart")))
+
+	metric := &clientModel.Metric{}
+	assert.NoError(t, orderValue.WithLabelValues("cart").(prometheus.Metric).Write(metric))
+	assert.Equal(t, 12.5, metric.GetHistogram().GetSampleSum())
+}
+
+func TestRecordSignUpAndFailedLogin(t *testing.T) {
+	before := testutil.ToFloat64(signUpsTotal)
+	RecordSignUp()
+	assert.Equal(t, before+1, testutil.ToFloat64(signUpsTotal))
+
+	RecordFailedLogin("locked_out")
+	assert.Equal(t, float64(1), testutil.ToFloat64(failedLoginsTotal.WithLabelValues("locked_out")))
+}
+
+func TestDBPoolCollector(t *testing.T) {
+	// the pool connects lazily, so no database is needed to read its stats
+	pool, err := pgxpool.New(context.Background(), "postgres://localhost:5432/book_db?pool_max_conns=7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	collector := newDBPoolCollector(pool)
+	assert.Equal(t, 10, testutil.CollectAndCount(collector))
+
+	problems, err := testutil.CollectAndLint(collector)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+
+	registry := prometheus.NewPedanticRegistry()
+	assert.NoError(t, registry.Register(collector))
+
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == "book_db_pool_max_conns" {
+			assert.Equal(t, float64(7), family.GetMetric()[0].GetGauge().GetValue())
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	chiMiddleware "github.com/go-chi/chi/middleware"
+)
+
+// AccessLog writes one JSON line per request. It has to run inside RequestID,
+// which provides the request ID and collects the user ID.
+func AccessLog(logger *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := chiMiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			// a panic is logged once utils.Recovery has written the response
+			next.ServeHTTP(ww, r)
+
+			route := unmatchedRoute
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			var requestID, userID string
+			if info := getRequestInfo(r.Context()); info != nil {
+				requestID = info.requestID
+				userID = info.userID
+			}
+
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+
+			logger.LogAttrs(r.Context(), level, "request",
+				slog.String("requestId", requestID),
+				slog.String("method", r.Method),
+				slog.String("route", route),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", status),
+				slog.Float64("latencyMs", float64(time.Since(start).Microseconds())/1000),
+				slog.String("userId", userID),
+				slog.Int("bytes", ww.BytesWritten()),
+				slog.String("remoteAddr", r.RemoteAddr),
+			)
+		})
+	}
+}
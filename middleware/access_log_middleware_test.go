@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gadhittana-01/book-go/constant"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessLog(t *testing.T) {
+	var logs bytes.Buffer
+	r := chi.NewRouter()
+	r.Use(RequestID)
+	r.Use(AccessLog(slog.New(slog.NewJSONHandler(&logs, nil))))
+	r.Get("/v1/order/{orderId}", func(w http.ResponseWriter, r *http.Request) {
+		getRequestInfo(r.Context()).userID = "user-1"
+		w.Write([]byte("hello"))
+	})
+
+	readLine := func(t *testing.T) map[string]any {
+		line := map[string]any{}
+		assert.NoError(t, json.NewDecoder(&logs).Decode(&line))
+		return line
+	}
+
+	t.Run("matched route", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/v1/order/42", nil)
+		req.Header.Set(constant.RequestIDHeader, "abc-123")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+
+		line := readLine(t)
+		assert.Equal(t, "INFO", line["level"])
+		assert.Equal(t, "request", line["msg"])
+		assert.Equal(t, "abc-123", line["requestId"])
+		assert.Equal(t, "GET", line["method"])
+		assert.Equal(t, "/v1/order/{orderId}", line["route"])
+		assert.Equal(t, "/v1/order/42", line["path"])
+		assert.Equal(t, float64(200), line["status"])
+		assert.Equal(t, "user-1", line["userId"])
+		assert.Equal(t, float64(5), line["bytes"])
+		assert.Contains(t, line, "latencyMs")
+	})
+
+	t.Run("unmatched route", func(t *testing.T) {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/v1/missing", nil))
+
+		line := readLine(t)
+		assert.Equal(t, unmatchedRoute, line["route"])
+		assert.Equal(t, float64(404), line["status"])
+		assert.Empty(t, line["userId"])
+	})
+}
@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gadhittana-01/book-go/constant"
+	mocksvc "github.com/gadhittana-01/book-go/service/mock"
+	utilsConstant "github.com/gadhittana01/go-modules/constant"
+	"github.com/gadhittana01/go-modules/utils"
+	mockutl "github.com/gadhittana01/go-modules/utils/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckIsAuthenticated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	mockToken := mockutl.NewMockTokenClient(ctrl)
+	mockTokenRevocation := mocksvc.NewMockTokenRevocationSvc(ctrl)
+	authMiddleware := NewAuthMiddleware(config, mockToken, mockTokenRevocation)
+
+	userID := uuid.New()
+	token := "dmytoken"
+	newReq := func() *http.Request {
+		req := httptest.NewRequest("POST", "http://localhost:8000/v1/sign-out", nil)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		return req
+	}
+
+	t.Run("token not revoked", func(t *testing.T) {
+		isCalled := false
+		mockTokenRevocation.EXPECT().IsAccessTokenRevoked(gomock.Any(), token).Return(false, nil).Times(1)
+		mockToken.EXPECT().ValidateToken(token).Return(utils.GenerateTokenReq{
+			UserID: userID.String(),
+		}, nil).Times(1)
+
+		handler := authMiddleware.CheckIsAuthenticated(func(w http.ResponseWriter, r *http.Request) {
+			isCalled = true
+			assert.Equal(t, userID.String(), utils.GetRequestCtx(r.Context(), utilsConstant.UserSession).UserID)
+		})
+
+		info := &requestInfo{}
+		req := newReq()
+		assert.NotPanics(t, func() {
+			handler(httptest.NewRecorder(), req.WithContext(context.WithValue(req.Context(), constant.RequestInfo, info)))
+		})
+		assert.True(t, isCalled)
+		assert.Equal(t, userID.String(), info.userID)
+	})
+
+	t.Run("token revoked", func(t *testing.T) {
+		mockToken.EXPECT().ValidateToken(token).Return(utils.GenerateTokenReq{
+			UserID: userID.String(),
+		}, nil).Times(1)
+		mockTokenRevocation.EXPECT().IsAccessTokenRevoked(gomock.Any(), token).Return(true, nil).Times(1)
+
+		handler := authMiddleware.CheckIsAuthenticated(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler must not be called")
+		})
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 401,
+			Message:    fmt.Sprintf("%s|%s", TokenRevoked, TokenRevoked),
+		}, func() {
+			handler(httptest.NewRecorder(), newReq())
+		})
+	})
+
+	t.Run("failed check token revocation accepts the valid token on reads", func(t *testing.T) {
+		isCalled := false
+		mockToken.EXPECT().ValidateToken(token).Return(utils.GenerateTokenReq{
+			UserID: userID.String(),
+		}, nil).Times(1)
+		mockTokenRevocation.EXPECT().IsAccessTokenRevoked(gomock.Any(), token).Return(false, errInvalidReq).Times(1)
+
+		handler := authMiddleware.CheckIsAuthenticated(func(w http.ResponseWriter, r *http.Request) {
+			isCalled = true
+		})
+
+		req := httptest.NewRequest("GET", "http://localhost:8000/v1/book", nil)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		assert.NotPanics(t, func() {
+			handler(httptest.NewRecorder(), req)
+		})
+		assert.True(t, isCalled)
+	})
+
+	t.Run("failed check token revocation rejects writes", func(t *testing.T) {
+		mockToken.EXPECT().ValidateToken(token).Return(utils.GenerateTokenReq{
+			UserID: userID.String(),
+		}, nil).Times(1)
+		mockTokenRevocation.EXPECT().IsAccessTokenRevoked(gomock.Any(), token).Return(false, errInvalidReq).Times(1)
+
+		handler := authMiddleware.CheckIsAuthenticated(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler must not be called")
+		})
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 500,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToCheckTokenRevocation),
+		}, func() {
+			handler(httptest.NewRecorder(), newReq())
+		})
+	})
+}
+
+func TestCheckIsAuthenticatedInvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	mockToken := mockutl.NewMockTokenClient(ctrl)
+	mockTokenRevocation := mocksvc.NewMockTokenRevocationSvc(ctrl)
+	authMiddleware := NewAuthMiddleware(config, mockToken, mockTokenRevocation)
+
+	mockToken.EXPECT().ValidateToken("madeup").Return(utils.GenerateTokenReq{}, errInvalidReq).Times(1)
+	mockTokenRevocation.EXPECT().IsAccessTokenRevoked(gomock.Any(), gomock.Any()).Times(0)
+
+	handler := authMiddleware.CheckIsAuthenticated(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler must not be called")
+	})
+
+	req := httptest.NewRequest("GET", "http://localhost:8000/v1/cart", nil)
+	req.Header.Set("Authorization", "Bearer madeup")
+	assert.Panics(t, func() {
+		handler(httptest.NewRecorder(), req)
+	})
+}
+
+func TestGetBearerToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			name:   "bearer token",
+			header: "Bearer dmytoken",
+			want:   "dmytoken",
+		},
+		{
+			name:   "missing header",
+			header: "",
+			want:   "",
+		},
+		{
+			name:   "other scheme",
+			header: "Basic dXNlcjpwYXNz",
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://localhost:8000/v1/cart", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			assert.Equal(t, tt.want, GetBearerToken(req))
+		})
+	}
+}
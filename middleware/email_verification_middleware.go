@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+
+	querier "github.com/gadhittana-01/book-go/db/repository"
+	utilsConstant "github.com/gadhittana01/go-modules/constant"
+	"github.com/gadhittana01/go-modules/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	FailedToCheckEmailVerified = "Failed to check email verification"
+	EmailNotVerified           = "Please verify your email before continuing"
+)
+
+type EmailVerificationMiddleware interface {
+	CheckIsVerified(handler http.HandlerFunc) http.HandlerFunc
+}
+
+// EmailVerificationMiddlewareImpl lets a request through only when the
+// caller has verified their email address. Like RoleMiddleware it must run
+// behind AuthMiddleware.CheckIsAuthenticated.
+type EmailVerificationMiddlewareImpl struct {
+	repo querier.Repository
+}
+
+func NewEmailVerificationMiddleware(repo querier.Repository) EmailVerificationMiddleware {
+	return &EmailVerificationMiddlewareImpl{
+		repo: repo,
+	}
+}
+
+func (m *EmailVerificationMiddlewareImpl) CheckIsVerified(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authPayload := utils.GetRequestCtx(r.Context(), utilsConstant.UserSession)
+
+		userID, err := uuid.Parse(authPayload.UserID)
+		utils.PanicIfAppError(err, FailedToParseStringToUUID, 401)
+
+		isVerified, err := m.repo.IsUserEmailVerified(r.Context(), userID)
+		if err == pgx.ErrNoRows {
+			utils.PanicAppError(EmailNotVerified, 403)
+		}
+		utils.PanicIfAppError(err, FailedToCheckEmailVerified, 400)
+
+		if !isVerified {
+			utils.PanicAppError(EmailNotVerified, 403)
+		}
+
+		handler(w, r)
+	}
+}
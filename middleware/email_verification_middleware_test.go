@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockrepo "github.com/gadhittana-01/book-go/db/repository/mock"
+	"github.com/gadhittana01/go-modules/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckIsVerified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mockrepo.NewMockRepository(ctrl)
+	emailVerificationMiddleware := NewEmailVerificationMiddleware(mockRepo)
+
+	userID := uuid.New()
+	newReq := func() *http.Request {
+		return httptest.NewRequest("POST", "http://localhost:8000/v1/order", nil).
+			WithContext(utils.SetRequestContext(userID.String()))
+	}
+
+	t.Run("verified email", func(t *testing.T) {
+		isCalled := false
+		mockRepo.EXPECT().IsUserEmailVerified(gomock.Any(), userID).Return(true, nil).Times(1)
+
+		handler := emailVerificationMiddleware.CheckIsVerified(func(w http.ResponseWriter, r *http.Request) {
+			isCalled = true
+		})
+
+		assert.NotPanics(t, func() {
+			handler(httptest.NewRecorder(), newReq())
+		})
+		assert.True(t, isCalled)
+	})
+
+	t.Run("unverified email", func(t *testing.T) {
+		mockRepo.EXPECT().IsUserEmailVerified(gomock.Any(), userID).Return(false, nil).Times(1)
+
+		handler := emailVerificationMiddleware.CheckIsVerified(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler must not be called")
+		})
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 403,
+			Message:    fmt.Sprintf("%s|%s", EmailNotVerified, EmailNotVerified),
+		}, func() {
+			handler(httptest.NewRecorder(), newReq())
+		})
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockRepo.EXPECT().IsUserEmailVerified(gomock.Any(), userID).Return(false, pgx.ErrNoRows).Times(1)
+
+		handler := emailVerificationMiddleware.CheckIsVerified(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler must not be called")
+		})
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 403,
+			Message:    fmt.Sprintf("%s|%s", EmailNotVerified, EmailNotVerified),
+		}, func() {
+			handler(httptest.NewRecorder(), newReq())
+		})
+	})
+
+	t.Run("failed check email verified", func(t *testing.T) {
+		mockRepo.EXPECT().IsUserEmailVerified(gomock.Any(), userID).Return(false, errInvalidReq).Times(1)
+
+		handler := emailVerificationMiddleware.CheckIsVerified(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler must not be called")
+		})
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToCheckEmailVerified),
+		}, func() {
+			handler(httptest.NewRecorder(), newReq())
+		})
+	})
+
+	t.Run("invalid user session", func(t *testing.T) {
+		handler := emailVerificationMiddleware.CheckIsVerified(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler must not be called")
+		})
+
+		assert.Panics(t, func() {
+			handler(httptest.NewRecorder(), httptest.NewRequest("POST", "http://localhost:8000/v1/order", nil).
+				WithContext(utils.SetRequestContext("123")))
+		})
+	})
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gadhittana-01/book-go/metrics"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func scrapeMetrics(t *testing.T) string {
+	resp := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(resp, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(Metrics)
+	r.Get("/v1/book/{bookId}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	r.Get("/v1/book", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	for _, path := range []string{"/v1/book/1", "/v1/book/2", "/v1/book", "/v1/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	body := scrapeMetrics(t)
+	assert.Contains(t, body, `book_http_requests_total{method="GET",route="/v1/book/{bookId}",status="201"} 2`)
+	assert.Contains(t, body, `book_http_requests_total{method="GET",route="/v1/book",status="200"} 1`)
+	assert.Contains(t, body, `book_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `book_http_request_duration_seconds_count{method="GET",route="/v1/book/{bookId}",status="201"} 2`)
+}
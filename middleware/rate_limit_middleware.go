@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gadhittana-01/book-go/config"
+	"github.com/gadhittana-01/book-go/constant"
+	"github.com/gadhittana-01/book-go/service"
+	utilsConstant "github.com/gadhittana01/go-modules/constant"
+	"github.com/gadhittana01/go-modules/utils"
+)
+
+const (
+	TooManyRequests        = "Too many requests, please try again later"
+	FailedToCheckRateLimit = "Failed to check rate limit"
+)
+
+type RateLimitMiddleware interface {
+	Limit(group string, handler http.HandlerFunc) http.HandlerFunc
+}
+
+// RateLimitMiddlewareImpl limits how often a client can call the routes of a
+// group. Behind AuthMiddleware.CheckIsAuthenticated the client is the user of
+// the token, elsewhere it is the client IP. The limits of each group come
+// from the RATE_LIMIT_* settings, and a limit of 0 disables the group. When
+// Redis cannot be reached the request is let through unlimited, so an outage
+// does not take down reads that could be served from the database.
+type RateLimitMiddlewareImpl struct {
+	rateLimitSvc service.RateLimitSvc
+	appConfig    *config.AppConfig
+}
+
+func NewRateLimitMiddleware(
+	rateLimitSvc service.RateLimitSvc,
+	appConfig *config.AppConfig,
+) RateLimitMiddleware {
+	return &RateLimitMiddlewareImpl{
+		rateLimitSvc: rateLimitSvc,
+		appConfig:    appConfig,
+	}
+}
+
+func (m *RateLimitMiddlewareImpl) Limit(group string, handler http.HandlerFunc) http.HandlerFunc {
+	limit, window := m.policy(group)
+	if limit <= 0 {
+		return handler
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		rateLimit, err := m.rateLimitSvc.Allow(r.Context(), m.rateLimitSubject(group, r), limit, window)
+		if err != nil {
+			utils.LogInfo(fmt.Sprintf("%s: %v", FailedToCheckRateLimit, err))
+			handler(w, r)
+			return
+		}
+
+		reset := strconv.Itoa(ceilSeconds(rateLimit.Reset))
+		w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit, ceilSeconds(window)))
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(rateLimit.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(rateLimit.Remaining))
+		w.Header().Set("RateLimit-Reset", reset)
+
+		if !rateLimit.Allowed {
+			w.Header().Set("Retry-After", reset)
+			utils.PanicAppError(TooManyRequests, 429)
+		}
+
+		handler(w, r)
+	}
+}
+
+func (m *RateLimitMiddlewareImpl) policy(group string) (int, time.Duration) {
+	switch group {
+	case constant.RateLimitSignUp:
+		return m.appConfig.RateLimitSignUpRequests, m.appConfig.RateLimitSignUpWindow
+	case constant.RateLimitBook:
+		return m.appConfig.RateLimitBookRequests, m.appConfig.RateLimitBookWindow
+	case constant.RateLimitOrder:
+		return m.appConfig.RateLimitOrderRequests, m.appConfig.RateLimitOrderWindow
+	}
+
+	panic(fmt.Sprintf("unknown rate limit group %q", group))
+}
+
+// rateLimitSubject keys a group by the user of the auth payload, or by the
+// client IP when the route is public.
+func (m *RateLimitMiddlewareImpl) rateLimitSubject(group string, r *http.Request) string {
+	if r.Context().Value(utilsConstant.UserSession) != nil {
+		if userID := utils.GetRequestCtx(r.Context(), utilsConstant.UserSession).UserID; userID != "" {
+			return fmt.Sprintf("%s:user:%s", group, userID)
+		}
+	}
+
+	return fmt.Sprintf("%s:ip:%s", group, ClientIP(r, m.appConfig.TrustedProxies))
+}
+
+// ceilSeconds rounds up, so a client that waits the advertised number of
+// seconds is not rejected again.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// ClientIP returns the address of the client. X-Forwarded-For is only read
+// when the direct peer is one of the trusted proxies, since any client could
+// set it to dodge the per-IP limits. The hops are walked from the right and
+// the first one that is not a trusted proxy is the client.
+func ClientIP(r *http.Request, trustedProxies []string) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	if !isTrustedProxy(host, trustedProxies) {
+		return host
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+
+		host = hop
+		if !isTrustedProxy(hop, trustedProxies) {
+			break
+		}
+	}
+
+	return host
+}
+
+func isTrustedProxy(addr string, trustedProxies []string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+
+	for _, cidr := range trustedProxies {
+		if _, network, err := net.ParseCIDR(cidr); err == nil && network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
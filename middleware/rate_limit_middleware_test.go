@@ -0,0 +1,229 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gadhittana-01/book-go/config"
+	"github.com/gadhittana-01/book-go/constant"
+	"github.com/gadhittana-01/book-go/service"
+	mocksvc "github.com/gadhittana-01/book-go/service/mock"
+	"github.com/gadhittana01/go-modules/utils"
+	mockutl "github.com/gadhittana01/go-modules/utils/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRateLimit := mocksvc.NewMockRateLimitSvc(ctrl)
+	rateLimitMiddleware := NewRateLimitMiddleware(mockRateLimit, &config.AppConfig{
+		RateLimitSignUpRequests: 5,
+		RateLimitSignUpWindow:   time.Hour,
+		RateLimitOrderRequests:  30,
+		RateLimitOrderWindow:    time.Minute,
+		TrustedProxies:          []string{"10.0.0.0/8"},
+	})
+
+	isCalled := false
+	next := func(w http.ResponseWriter, r *http.Request) {
+		isCalled = true
+	}
+
+	newReq := func() *http.Request {
+		req := httptest.NewRequest("POST", "http://localhost:8000/v1/sign-up", nil)
+		req.RemoteAddr = "203.0.113.7:52100"
+		return req
+	}
+
+	t.Run("allowed request by client IP", func(t *testing.T) {
+		isCalled = false
+		mockRateLimit.EXPECT().Allow(gomock.Any(), "sign_up:ip:203.0.113.7", 5, time.Hour).Return(service.RateLimit{
+			Allowed:   true,
+			Limit:     5,
+			Remaining: 4,
+			Reset:     time.Hour,
+		}, nil).Times(1)
+
+		resp := httptest.NewRecorder()
+		rateLimitMiddleware.Limit(constant.RateLimitSignUp, next)(resp, newReq())
+
+		assert.True(t, isCalled)
+		assert.Equal(t, "5;w=3600", resp.Header().Get("RateLimit-Policy"))
+		assert.Equal(t, "5", resp.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "4", resp.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "3600", resp.Header().Get("RateLimit-Reset"))
+		assert.Empty(t, resp.Header().Get("Retry-After"))
+	})
+
+	t.Run("request behind a trusted proxy by forwarded client IP", func(t *testing.T) {
+		isCalled = false
+		mockRateLimit.EXPECT().Allow(gomock.Any(), "sign_up:ip:198.51.100.1", 5, time.Hour).Return(service.RateLimit{
+			Allowed:   true,
+			Limit:     5,
+			Remaining: 4,
+			Reset:     time.Hour,
+		}, nil).Times(1)
+
+		req := newReq()
+		req.RemoteAddr = "10.0.0.2:52100"
+		req.Header.Set("X-Forwarded-For", "192.0.2.99, 198.51.100.1")
+		rateLimitMiddleware.Limit(constant.RateLimitSignUp, next)(httptest.NewRecorder(), req)
+
+		assert.True(t, isCalled)
+	})
+
+	t.Run("rejected request", func(t *testing.T) {
+		isCalled = false
+		mockRateLimit.EXPECT().Allow(gomock.Any(), "sign_up:ip:203.0.113.7", 5, time.Hour).Return(service.RateLimit{
+			Limit: 5,
+			Reset: 1500 * time.Millisecond,
+		}, nil).Times(1)
+
+		resp := httptest.NewRecorder()
+		assert.Panics(t, func() {
+			rateLimitMiddleware.Limit(constant.RateLimitSignUp, next)(resp, newReq())
+		})
+
+		// the headers are set before the panic, so the 429 written by
+		// utils.Recovery carries them
+		assert.False(t, isCalled)
+		assert.Equal(t, "0", resp.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "2", resp.Header().Get("RateLimit-Reset"))
+		assert.Equal(t, "2", resp.Header().Get("Retry-After"))
+	})
+
+	t.Run("authenticated request by user", func(t *testing.T) {
+		isCalled = false
+		baseConfig := &utils.BaseConfig{}
+		utils.LoadBaseConfig("../config", "test", baseConfig)
+		mockToken := mockutl.NewMockTokenClient(ctrl)
+		userID := uuid.New()
+
+		mockToken.EXPECT().ValidateToken("dmytoken").Return(utils.GenerateTokenReq{
+			UserID: userID.String(),
+		}, nil).Times(1)
+		mockRateLimit.EXPECT().Allow(gomock.Any(), fmt.Sprintf("order:user:%s", userID), 30, time.Minute).Return(service.RateLimit{
+			Allowed:   true,
+			Limit:     30,
+			Remaining: 29,
+			Reset:     time.Minute,
+		}, nil).Times(1)
+
+		req := newReq()
+		req.Header.Set("Authorization", "Bearer dmytoken")
+		handler := utils.NewAuthMiddleware(baseConfig, mockToken).CheckIsAuthenticated(
+			rateLimitMiddleware.Limit(constant.RateLimitOrder, next))
+
+		assert.NotPanics(t, func() {
+			handler(httptest.NewRecorder(), req)
+		})
+		assert.True(t, isCalled)
+	})
+
+	t.Run("failed to check rate limit lets the request through", func(t *testing.T) {
+		isCalled = false
+		mockRateLimit.EXPECT().Allow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(service.RateLimit{}, errors.New("redis down")).Times(1)
+
+		resp := httptest.NewRecorder()
+		assert.NotPanics(t, func() {
+			rateLimitMiddleware.Limit(constant.RateLimitSignUp, next)(resp, newReq())
+		})
+		assert.True(t, isCalled)
+		assert.Empty(t, resp.Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("disabled group", func(t *testing.T) {
+		isCalled = false
+
+		rateLimitMiddleware.Limit(constant.RateLimitBook, next)(httptest.NewRecorder(), newReq())
+		assert.True(t, isCalled)
+	})
+
+	t.Run("unknown group", func(t *testing.T) {
+		assert.Panics(t, func() {
+			rateLimitMiddleware.Limit("unknown", next)
+		})
+	})
+}
+
+func TestClientIP(t *testing.T) {
+	trustedProxies := []string{"10.0.0.0/8", "2001:db8:ffff::/48"}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		want         string
+	}{
+		{
+			name:         "untrusted peer ignores forwarded for",
+			remoteAddr:   "203.0.113.7:52100",
+			forwardedFor: []string{"198.51.100.1"},
+			want:         "203.0.113.7",
+		},
+		{
+			name:       "ipv6 peer",
+			remoteAddr: "[2001:db8::1]:52100",
+			want:       "2001:db8::1",
+		},
+		{
+			name:       "peer without port",
+			remoteAddr: "unix-socket",
+			want:       "unix-socket",
+		},
+		{
+			name:       "trusted peer without forwarded for",
+			remoteAddr: "10.0.0.2:52100",
+			want:       "10.0.0.2",
+		},
+		{
+			name:         "trusted peer reads the forwarded client",
+			remoteAddr:   "10.0.0.2:52100",
+			forwardedFor: []string{"198.51.100.1"},
+			want:         "198.51.100.1",
+		},
+		{
+			name:         "spoofed hops left of the client are ignored",
+			remoteAddr:   "10.0.0.2:52100",
+			forwardedFor: []string{"192.0.2.99, 198.51.100.1, 10.0.0.3"},
+			want:         "198.51.100.1",
+		},
+		{
+			name:         "hops across several headers",
+			remoteAddr:   "[2001:db8:ffff::1]:52100",
+			forwardedFor: []string{"192.0.2.99", "198.51.100.1"},
+			want:         "198.51.100.1",
+		},
+		{
+			name:         "only trusted hops",
+			remoteAddr:   "10.0.0.2:52100",
+			forwardedFor: []string{"10.0.0.4, 10.0.0.3"},
+			want:         "10.0.0.4",
+		},
+		{
+			name:         "malformed hop stops at the last valid address",
+			remoteAddr:   "10.0.0.2:52100",
+			forwardedFor: []string{"198.51.100.1, unknown, 10.0.0.3"},
+			want:         "10.0.0.3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "http://localhost:8000/v1/sign-in", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, forwardedFor := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", forwardedFor)
+			}
+
+			assert.Equal(t, tt.want, ClientIP(req, trustedProxies))
+		})
+	}
+}
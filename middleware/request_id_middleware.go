@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+
+	"github.com/gadhittana-01/book-go/constant"
+	"github.com/google/uuid"
+)
+
+// a request ID from the caller is only kept when it is safe to log
+var requestIDRegexp = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// requestInfo collects what the access log needs but only inner handlers
+// know. It is shared by pointer, because a handler sees a copy of the request
+// whose context the outer middlewares never get back.
+type requestInfo struct {
+	requestID string
+	userID    string
+}
+
+// RequestID keeps the X-Request-ID of the caller, or generates one, stores it
+// in the context and echoes it in the response header and in JSON error
+// bodies, so a client can quote it when reporting a failure.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(constant.RequestIDHeader)
+		if !requestIDRegexp.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		w.Header().Set(constant.RequestIDHeader, requestID)
+		ctx := context.WithValue(r.Context(), constant.RequestInfo, &requestInfo{requestID: requestID})
+
+		ew := &errorBodyWriter{ResponseWriter: w, requestID: requestID}
+		defer ew.finish()
+
+		next.ServeHTTP(ew, r.WithContext(ctx))
+	})
+}
+
+// GetRequestID returns the ID of the request ctx belongs to, or an empty
+// string outside of a request.
+func GetRequestID(ctx context.Context) string {
+	if info := getRequestInfo(ctx); info != nil {
+		return info.requestID
+	}
+
+	return ""
+}
+
+func getRequestInfo(ctx context.Context) *requestInfo {
+	info, _ := ctx.Value(constant.RequestInfo).(*requestInfo)
+	return info
+}
+
+// errorBodyWriter holds back error responses to add the request ID to their
+// JSON body. Errors are written by utils.Recovery and other shared code, so
+// this is the one place that sees all of them. Other responses pass through.
+type errorBodyWriter struct {
+	http.ResponseWriter
+	requestID string
+	status    int
+	body      bytes.Buffer
+}
+
+func (w *errorBodyWriter) WriteHeader(status int) {
+	if w.status != 0 {
+		return
+	}
+
+	w.status = status
+	if status < http.StatusBadRequest {
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (w *errorBodyWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.status < http.StatusBadRequest {
+		return w.ResponseWriter.Write(b)
+	}
+
+	return w.body.Write(b)
+}
+
+func (w *errorBodyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *errorBodyWriter) finish() {
+	if w.status < http.StatusBadRequest {
+		return
+	}
+
+	body := w.body.Bytes()
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err == nil && fields != nil {
+		fields["requestId"], _ = json.Marshal(w.requestID)
+		body, _ = json.Marshal(fields)
+		body = append(body, '\n')
+		w.Header().Del("Content-Length")
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(body)
+}
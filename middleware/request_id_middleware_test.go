@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gadhittana-01/book-go/constant"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	var ctxRequestID string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctxRequestID = GetRequestID(r.Context())
+
+		switch r.URL.Path {
+		case "/error":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"success":false,"statusCode":404,"errors":[{"message":"Book not found"}]}`))
+		case "/plain-error":
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		default:
+			w.Write([]byte(`{"success":true}`))
+		}
+	}))
+
+	t.Run("keeps the request ID of the caller", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(constant.RequestIDHeader, "abc-123")
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		assert.Equal(t, "abc-123", resp.Header().Get(constant.RequestIDHeader))
+		assert.Equal(t, "abc-123", ctxRequestID)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, `{"success":true}`, resp.Body.String())
+	})
+
+	t.Run("generates a request ID", func(t *testing.T) {
+		for _, header := range []string{"", "has spaces", string(make([]byte, 129))} {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set(constant.RequestIDHeader, header)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			_, err := uuid.Parse(resp.Header().Get(constant.RequestIDHeader))
+			assert.NoError(t, err)
+			assert.Equal(t, resp.Header().Get(constant.RequestIDHeader), ctxRequestID)
+		}
+	})
+
+	t.Run("adds the request ID to JSON error bodies", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/error", nil)
+		req.Header.Set(constant.RequestIDHeader, "abc-123")
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+
+		body := map[string]any{}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "abc-123", body["requestId"])
+		assert.Equal(t, float64(404), body["statusCode"])
+		assert.Len(t, body["errors"], 1)
+	})
+
+	t.Run("leaves other error bodies alone", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest("GET", "/plain-error", nil))
+
+		assert.Equal(t, http.StatusBadGateway, resp.Code)
+		assert.Equal(t, "bad gateway\n", resp.Body.String())
+	})
+}
+
+func TestGetRequestID(t *testing.T) {
+	assert.Empty(t, GetRequestID(httptest.NewRequest("GET", "/", nil).Context()))
+}
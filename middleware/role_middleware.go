@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+
+	querier "github.com/gadhittana-01/book-go/db/repository"
+	utilsConstant "github.com/gadhittana01/go-modules/constant"
+	"github.com/gadhittana01/go-modules/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/lo"
+)
+
+const (
+	FailedToParseStringToUUID = "Failed to parse string to UUID"
+	FailedToFindUserRole      = "Failed to find user role"
+	Forbidden                 = "You don't have permission to access this resource"
+)
+
+type RoleMiddleware interface {
+	CheckHasRole(handler http.HandlerFunc, roles ...string) http.HandlerFunc
+}
+
+// RoleMiddlewareImpl resolves the caller's role from the database on every
+// request, so it must run behind AuthMiddleware.CheckIsAuthenticated. Reading
+// the role server-side means a demoted account loses access immediately
+// instead of when its token expires.
+type RoleMiddlewareImpl struct {
+	repo querier.Repository
+}
+
+func NewRoleMiddleware(repo querier.Repository) RoleMiddleware {
+	return &RoleMiddlewareImpl{
+		repo: repo,
+	}
+}
+
+func (m *RoleMiddlewareImpl) CheckHasRole(handler http.HandlerFunc, roles ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authPayload := utils.GetRequestCtx(r.Context(), utilsConstant.UserSession)
+
+		userID, err := uuid.Parse(authPayload.UserID)
+		utils.PanicIfAppError(err, FailedToParseStringToUUID, 401)
+
+		role, err := m.repo.FindUserRoleByID(r.Context(), userID)
+		if err == pgx.ErrNoRows {
+			utils.PanicAppError(Forbidden, 403)
+		}
+		utils.PanicIfAppError(err, FailedToFindUserRole, 400)
+
+		if !lo.Contains(roles, role) {
+			utils.PanicAppError(Forbidden, 403)
+		}
+
+		handler(w, r)
+	}
+}
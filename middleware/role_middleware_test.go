@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gadhittana-01/book-go/constant"
+	mockrepo "github.com/gadhittana-01/book-go/db/repository/mock"
+	"github.com/gadhittana01/go-modules/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+var errInvalidReq = errors.New("invalid request")
+
+func TestCheckHasRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mockrepo.NewMockRepository(ctrl)
+	roleMiddleware := NewRoleMiddleware(mockRepo)
+
+	userID := uuid.New()
+	newReq := func() *http.Request {
+		return httptest.NewRequest("POST", "http://localhost:8000/v1/book", nil).
+			WithContext(utils.SetRequestContext(userID.String()))
+	}
+
+	t.Run("allowed role", func(t *testing.T) {
+		isCalled := false
+		mockRepo.EXPECT().FindUserRoleByID(gomock.Any(), userID).Return(constant.RoleStaff, nil).Times(1)
+
+		handler := roleMiddleware.CheckHasRole(func(w http.ResponseWriter, r *http.Request) {
+			isCalled = true
+		}, constant.RoleAdmin, constant.RoleStaff)
+
+		assert.NotPanics(t, func() {
+			handler(httptest.NewRecorder(), newReq())
+		})
+		assert.True(t, isCalled)
+	})
+
+	t.Run("forbidden role", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserRoleByID(gomock.Any(), userID).Return(constant.RoleCustomer, nil).Times(1)
+
+		handler := roleMiddleware.CheckHasRole(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler must not be called")
+		}, constant.RoleAdmin, constant.RoleStaff)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 403,
+			Message:    fmt.Sprintf("%s|%s", Forbidden, Forbidden),
+		}, func() {
+			handler(httptest.NewRecorder(), newReq())
+		})
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserRoleByID(gomock.Any(), userID).Return("", pgx.ErrNoRows).Times(1)
+
+		handler := roleMiddleware.CheckHasRole(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler must not be called")
+		}, constant.RoleAdmin)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 403,
+			Message:    fmt.Sprintf("%s|%s", Forbidden, Forbidden),
+		}, func() {
+			handler(httptest.NewRecorder(), newReq())
+		})
+	})
+
+	t.Run("failed find user role", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserRoleByID(gomock.Any(), userID).Return("", errInvalidReq).Times(1)
+
+		handler := roleMiddleware.CheckHasRole(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler must not be called")
+		}, constant.RoleAdmin)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToFindUserRole),
+		}, func() {
+			handler(httptest.NewRecorder(), newReq())
+		})
+	})
+
+	t.Run("invalid user session", func(t *testing.T) {
+		handler := roleMiddleware.CheckHasRole(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler must not be called")
+		}, constant.RoleAdmin)
+
+		assert.Panics(t, func() {
+			handler(httptest.NewRecorder(), httptest.NewRequest("POST", "http://localhost:8000/v1/book", nil).
+				WithContext(utils.SetRequestContext("123")))
+		})
+	})
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/gadhittana-01/book-go/constant"
 	querier "github.com/gadhittana-01/book-go/db/repository"
@@ -20,6 +21,14 @@ const (
 	FailedToCreateBook               = "Failed to create book"
 	FailedToGetBook                  = "Failed to get book"
 	FailedToGetBookPurchasedByUserID = "Failed to get book purchased by user id"
+	FailedToUpdateBook               = "Failed to update book"
+	FailedToDeleteBook               = "Failed to delete book"
+	FailedToCheckBookOrdered         = "Failed to check book ordered"
+	BookNotFound                     = "Book not found"
+	BookAlreadyOrdered               = "Book has already been ordered and cannot be deleted"
+	FailedToAdjustBookStock          = "Failed to adjust book stock"
+	FailedToCreateStockMovement      = "Failed to create stock movement"
+	InsufficientBookStock            = "Book stock cannot go below zero"
 )
 
 type (
@@ -30,6 +39,10 @@ type BookSvc interface {
 	CreateBook(ctx context.Context, input dto.CreateBookReq) dto.CreateBookRes
 	GetBook(ctx context.Context, input dto.GetBookReq) PaginationBookResp
 	GetBookPuchasedByUser(ctx context.Context) []dto.GetBookPuchasedByUserRes
+	GetBookByID(ctx context.Context, input dto.GetBookByIDReq) dto.GetBookRes
+	UpdateBook(ctx context.Context, input dto.PatchBookReq) dto.UpdateBookRes
+	DeleteBook(ctx context.Context, input dto.DeleteBookReq) dto.DeleteBookRes
+	AdjustBookStock(ctx context.Context, input dto.AdjustBookStockReq) dto.AdjustBookStockRes
 }
 
 type BookSvcImpl struct {
@@ -54,7 +67,10 @@ func NewBookSvc(
 func (s *BookSvcImpl) CreateBook(ctx context.Context, input dto.CreateBookReq) dto.CreateBookRes {
 	var resp dto.CreateBookRes
 	var book querier.Book
-	var err error
+	authPayload := utils.GetRequestCtx(ctx, utilsConstant.UserSession)
+
+	userID, err := uuid.Parse(authPayload.UserID)
+	utils.PanicIfAppError(err, FailedToParseStringToUUID, 400)
 
 	err = utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
 		repoTx := s.repo.WithTx(tx)
@@ -64,11 +80,26 @@ func (s *BookSvcImpl) CreateBook(ctx context.Context, input dto.CreateBookReq) d
 			Description: input.Description,
 			Author:      input.Author,
 			Price:       input.Price,
+			Stock:       int32(input.Stock),
 		})
 		if err != nil {
 			return utils.CustomErrorWithTrace(err, FailedToCreateBook, 422)
 		}
 
+		// the initial stock is recorded like any restock, so the movements
+		// of a book always add up to its stock
+		if input.Stock > 0 {
+			_, err = repoTx.CreateStockMovement(ctx, querier.CreateStockMovementParams{
+				BookID:    book.ID,
+				Quantity:  int32(input.Stock),
+				Reason:    constant.StockReasonRestock,
+				CreatedBy: userID,
+			})
+			if err != nil {
+				return utils.CustomErrorWithTrace(err, FailedToCreateStockMovement, 422)
+			}
+		}
+
 		return nil
 	})
 	utils.PanicIfError(err)
@@ -80,14 +111,19 @@ func (s *BookSvcImpl) CreateBook(ctx context.Context, input dto.CreateBookReq) d
 		Description: book.Description,
 		Author:      book.Author,
 		Price:       book.Price,
+		Stock:       int(book.Stock),
 	}
 
 	return resp
 }
 
 func (s *BookSvcImpl) GetBook(ctx context.Context, input dto.GetBookReq) dto.PaginationResp[dto.GetBookRes] {
-	resp, err := utils.GetOrSetData(s.cacheSvc, utils.BuildCacheKey(constant.BookCacheKey,
+	resp, err := getOrSetData(ctx, s.cacheSvc, constant.BookCacheKey, utils.BuildCacheKey(constant.BookCacheKey,
 		"", "GetBook", input), func() (dto.PaginationResp[dto.GetBookRes], error) {
+		if input.CursorMode {
+			return s.findBookByCursor(ctx, input)
+		}
+
 		ewg := errgroup.Group{}
 		var err1 error
 		var err2 error
@@ -96,14 +132,25 @@ func (s *BookSvcImpl) GetBook(ctx context.Context, input dto.GetBookReq) dto.Pag
 
 		ewg.Go(func() error {
 			books, err1 = s.repo.FindBook(ctx, querier.FindBookParams{
-				Limit:  input.Limit,
-				Offset: (input.Page - 1) * input.Limit,
+				Search:   input.Search,
+				Author:   input.Author,
+				MinPrice: input.MinPrice,
+				MaxPrice: input.MaxPrice,
+				SortBy:   input.SortBy,
+				SortDir:  input.SortDir,
+				Limit:    input.Limit,
+				Offset:   (input.Page - 1) * input.Limit,
 			})
 			return err1
 		})
 
 		ewg.Go(func() error {
-			count, err2 = s.repo.GetBookCount(ctx)
+			count, err2 = s.repo.GetBookCount(ctx, querier.GetBookCountParams{
+				Search:   input.Search,
+				Author:   input.Author,
+				MinPrice: input.MinPrice,
+				MaxPrice: input.MaxPrice,
+			})
 			return err2
 		})
 
@@ -113,13 +160,7 @@ func (s *BookSvcImpl) GetBook(ctx context.Context, input dto.GetBookReq) dto.Pag
 		}
 
 		return dto.ToPaginationResp(lo.Map(books, func(item querier.Book, index int) dto.GetBookRes {
-			return dto.GetBookRes{
-				ID:          item.ID.String(),
-				Title:       item.Title,
-				Description: item.Description,
-				Author:      item.Author,
-				Price:       item.Price,
-			}
+			return toGetBookRes(item)
 		}), int(input.Page), int(input.Limit), int(count)), nil
 	})
 	utils.PanicIfError(err)
@@ -127,6 +168,81 @@ func (s *BookSvcImpl) GetBook(ctx context.Context, input dto.GetBookReq) dto.Pag
 	return resp
 }
 
+// findBookByCursor pages through books by (created_at, id) without OFFSET.
+// The count only runs when the client asks for it.
+func (s *BookSvcImpl) findBookByCursor(ctx context.Context, input dto.GetBookReq) (dto.PaginationResp[dto.GetBookRes], error) {
+	ewg := errgroup.Group{}
+	var err1 error
+	var err2 error
+	var books []querier.Book
+	count := int64(-1)
+
+	ewg.Go(func() error {
+		if input.Cursor.Backward {
+			books, err1 = s.repo.FindBookBeforeCursor(ctx, querier.FindBookBeforeCursorParams{
+				Search:          input.Search,
+				Author:          input.Author,
+				MinPrice:        input.MinPrice,
+				MaxPrice:        input.MaxPrice,
+				CursorCreatedAt: input.Cursor.Time,
+				CursorID:        input.Cursor.ID,
+				Limit:           input.Limit + 1,
+			})
+			return err1
+		}
+
+		books, err1 = s.repo.FindBookAfterCursor(ctx, querier.FindBookAfterCursorParams{
+			Search:   input.Search,
+			Author:   input.Author,
+			MinPrice: input.MinPrice,
+			MaxPrice: input.MaxPrice,
+			CursorCreatedAt: sql.NullTime{
+				Time:  input.Cursor.Time,
+				Valid: !input.Cursor.IsZero(),
+			},
+			CursorID: uuid.NullUUID{
+				UUID:  input.Cursor.ID,
+				Valid: !input.Cursor.IsZero(),
+			},
+			Limit: input.Limit + 1,
+		})
+		return err1
+	})
+
+	if input.WithTotal {
+		ewg.Go(func() error {
+			count, err2 = s.repo.GetBookCount(ctx, querier.GetBookCountParams{
+				Search:   input.Search,
+				Author:   input.Author,
+				MinPrice: input.MinPrice,
+				MaxPrice: input.MaxPrice,
+			})
+			return err2
+		})
+	}
+
+	if err := ewg.Wait(); err != nil {
+		return dto.PaginationResp[dto.GetBookRes]{}, utils.CustomErrorWithTrace(err,
+			FailedToGetBook, 400)
+	}
+
+	return dto.ToCursorPaginationResp(books, int(input.Limit), input.Cursor, int(count),
+		func(item querier.Book) dto.Cursor {
+			return dto.Cursor{Time: item.CreatedAt, ID: item.ID}
+		}, toGetBookRes), nil
+}
+
+func toGetBookRes(item querier.Book) dto.GetBookRes {
+	return dto.GetBookRes{
+		ID:          item.ID.String(),
+		Title:       item.Title,
+		Description: item.Description,
+		Author:      item.Author,
+		Price:       item.Price,
+		Stock:       int(item.Stock),
+	}
+}
+
 func (s *BookSvcImpl) GetBookPuchasedByUser(ctx context.Context) []dto.GetBookPuchasedByUserRes {
 	authPayload := utils.GetRequestCtx(ctx, utilsConstant.UserSession)
 
@@ -144,3 +260,161 @@ func (s *BookSvcImpl) GetBookPuchasedByUser(ctx context.Context) []dto.GetBookPu
 		}
 	})
 }
+
+func (s *BookSvcImpl) GetBookByID(ctx context.Context, input dto.GetBookByIDReq) dto.GetBookRes {
+	resp, err := getOrSetData(ctx, s.cacheSvc, constant.BookCacheKey, utils.BuildCacheKey(constant.BookCacheKey,
+		"", "GetBookByID", input), func() (dto.GetBookRes, error) {
+		book, err := s.repo.FindBookByID(ctx, input.BookID)
+		if err == pgx.ErrNoRows {
+			return dto.GetBookRes{}, utils.CustomError(BookNotFound, 404)
+		}
+
+		if err != nil {
+			return dto.GetBookRes{}, utils.CustomErrorWithTrace(err, FailedToFindBookByID, 400)
+		}
+
+		return dto.GetBookRes{
+			ID:          book.ID.String(),
+			Title:       book.Title,
+			Description: book.Description,
+			Author:      book.Author,
+			Price:       book.Price,
+			Stock:       int(book.Stock),
+		}, nil
+	})
+	utils.PanicIfError(err)
+
+	return resp
+}
+
+func (s *BookSvcImpl) UpdateBook(ctx context.Context, input dto.PatchBookReq) dto.UpdateBookRes {
+	var book querier.Book
+	var err error
+
+	err = utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		repoTx := s.repo.WithTx(tx)
+
+		book, err = repoTx.FindBookByID(ctx, input.BookID)
+		if err == pgx.ErrNoRows {
+			return utils.CustomError(BookNotFound, 404)
+		}
+
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToFindBookByID, 400)
+		}
+
+		book, err = repoTx.UpdateBookByID(ctx, querier.UpdateBookByIDParams{
+			ID:          book.ID,
+			Title:       lo.FromPtrOr(input.Title, book.Title),
+			Description: lo.FromPtrOr(input.Description, book.Description),
+			Author:      lo.FromPtrOr(input.Author, book.Author),
+			Price:       lo.FromPtrOr(input.Price, book.Price),
+		})
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToUpdateBook, 422)
+		}
+
+		return nil
+	})
+	utils.PanicIfError(err)
+	s.cacheSvc.ClearCaches([]string{constant.BookCacheKey}, "")
+
+	return dto.UpdateBookRes{
+		ID:          book.ID.String(),
+		Title:       book.Title,
+		Description: book.Description,
+		Author:      book.Author,
+		Price:       book.Price,
+	}
+}
+
+func (s *BookSvcImpl) DeleteBook(ctx context.Context, input dto.DeleteBookReq) dto.DeleteBookRes {
+	err := utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		repoTx := s.repo.WithTx(tx)
+
+		isExists, err := repoTx.CheckBookExists(ctx, input.BookID)
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToCheckBookExists, 400)
+		}
+
+		if !isExists {
+			return utils.CustomError(BookNotFound, 404)
+		}
+
+		// order_detail cascades on book deletion, so removing an ordered
+		// book would silently rewrite customers' order history.
+		isOrdered, err := repoTx.CheckBookOrdered(ctx, input.BookID)
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToCheckBookOrdered, 400)
+		}
+
+		if isOrdered {
+			return utils.CustomError(BookAlreadyOrdered, 409)
+		}
+
+		err = repoTx.DeleteBookByID(ctx, input.BookID)
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToDeleteBook, 422)
+		}
+
+		return nil
+	})
+	utils.PanicIfError(err)
+	s.cacheSvc.ClearCaches([]string{constant.BookCacheKey}, "")
+
+	return dto.DeleteBookRes{
+		ID: input.BookID.String(),
+	}
+}
+
+func (s *BookSvcImpl) AdjustBookStock(ctx context.Context, input dto.AdjustBookStockReq) dto.AdjustBookStockRes {
+	var book querier.Book
+	authPayload := utils.GetRequestCtx(ctx, utilsConstant.UserSession)
+
+	userID, err := uuid.Parse(authPayload.UserID)
+	utils.PanicIfAppError(err, FailedToParseStringToUUID, 400)
+
+	err = utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		repoTx := s.repo.WithTx(tx)
+
+		isExists, err := repoTx.CheckBookExists(ctx, input.BookID)
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToCheckBookExists, 400)
+		}
+
+		if !isExists {
+			return utils.CustomError(BookNotFound, 404)
+		}
+
+		book, err = repoTx.AdjustBookStock(ctx, querier.AdjustBookStockParams{
+			ID:       input.BookID,
+			Quantity: int32(input.Quantity),
+		})
+		if err == pgx.ErrNoRows {
+			return utils.CustomError(InsufficientBookStock, 409)
+		}
+
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToAdjustBookStock, 422)
+		}
+
+		_, err = repoTx.CreateStockMovement(ctx, querier.CreateStockMovementParams{
+			BookID:    input.BookID,
+			Quantity:  int32(input.Quantity),
+			Reason:    input.Reason,
+			CreatedBy: userID,
+		})
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToCreateStockMovement, 422)
+		}
+
+		return nil
+	})
+	utils.PanicIfError(err)
+	s.cacheSvc.ClearCaches([]string{constant.BookCacheKey}, "")
+
+	return dto.AdjustBookStockRes{
+		ID:    book.ID.String(),
+		Stock: int(book.Stock),
+	}
+}
@@ -12,6 +12,9 @@ import (
 	"github.com/gadhittana01/go-modules/utils"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -37,7 +40,7 @@ func TestCreateBook(t *testing.T) {
 	title := "Hello"
 	description := "World"
 	author := "Giri Putra Adhittana"
-	price := float64(10)
+	price := decimal.NewFromInt(10)
 	now := time.Now()
 	req := dto.CreateBookReq{
 		Title:       title,
@@ -76,6 +79,40 @@ func TestCreateBook(t *testing.T) {
 		}, resp)
 	})
 
+	t.Run("success create Book with initial stock", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+		stockReq := req
+		stockReq.Stock = 25
+
+		mockRepo.EXPECT().CreateBook(gomock.Any(), querier.CreateBookParams{
+			Title:       title,
+			Description: description,
+			Author:      author,
+			Price:       price,
+			Stock:       25,
+		}).Return(querier.Book{
+			ID:          bookID,
+			Title:       title,
+			Description: description,
+			Author:      author,
+			Price:       price,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			Stock:       25,
+		}, nil).Times(1)
+
+		mockRepo.EXPECT().CreateStockMovement(gomock.Any(), querier.CreateStockMovementParams{
+			BookID:    bookID,
+			Quantity:  25,
+			Reason:    constant.StockReasonRestock,
+			CreatedBy: userID,
+		}).Return(querier.StockMovement{}, nil).Times(1)
+
+		resp := bookSvcMock.CreateBook(ctx, stockReq)
+
+		assert.Equal(t, 25, resp.Stock)
+	})
+
 	t.Run("failed to create Book", func(t *testing.T) {
 		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
 
@@ -117,7 +154,7 @@ func TestGetBook(t *testing.T) {
 	title := "Hello"
 	description := "World"
 	author := "Giri Putra Adhittana"
-	price := float64(10)
+	price := decimal.NewFromInt(10)
 	now := time.Now()
 	page := int32(1)
 	limit := int32(10)
@@ -143,7 +180,7 @@ func TestGetBook(t *testing.T) {
 			},
 		}, nil).Times(1)
 
-		mockRepo.EXPECT().GetBookCount(gomock.Any()).Return(int64(totalCount), nil).Times(1)
+		mockRepo.EXPECT().GetBookCount(gomock.Any(), querier.GetBookCountParams{}).Return(int64(totalCount), nil).Times(1)
 
 		resp := bookSvcMock.GetBook(ctx, req)
 
@@ -194,6 +231,114 @@ func TestGetBook(t *testing.T) {
 		}, resp)
 	})
 
+	t.Run("success get book with filters skips unfiltered cache", func(t *testing.T) {
+		filteredReq := dto.GetBookReq{
+			Page:     page,
+			Limit:    limit,
+			Search:   "hello world",
+			Author:   "Giri",
+			MinPrice: decimal.NewNullDecimal(decimal.NewFromInt(5)),
+			MaxPrice: decimal.NewNullDecimal(decimal.NewFromInt(15)),
+			SortBy:   constant.BookSortPrice,
+			SortDir:  constant.SortAsc,
+		}
+
+		mockRepo.EXPECT().FindBook(gomock.Any(), querier.FindBookParams{
+			Search:   filteredReq.Search,
+			Author:   filteredReq.Author,
+			MinPrice: filteredReq.MinPrice,
+			MaxPrice: filteredReq.MaxPrice,
+			SortBy:   filteredReq.SortBy,
+			SortDir:  filteredReq.SortDir,
+			Limit:    limit,
+			Offset:   (page - 1) * limit,
+		}).Return([]querier.Book{}, nil).Times(1)
+
+		mockRepo.EXPECT().GetBookCount(gomock.Any(), querier.GetBookCountParams{
+			Search:   filteredReq.Search,
+			Author:   filteredReq.Author,
+			MinPrice: filteredReq.MinPrice,
+			MaxPrice: filteredReq.MaxPrice,
+		}).Return(int64(0), nil).Times(1)
+
+		resp := bookSvcMock.GetBook(ctx, filteredReq)
+
+		assert.Equal(t, 0, resp.Total)
+		assert.Empty(t, resp.Data)
+	})
+
+	t.Run("success get book by cursor", func(t *testing.T) {
+		cursorReq := dto.GetBookReq{
+			Limit:      2,
+			CursorMode: true,
+		}
+		books := []querier.Book{
+			{ID: uuid.New(), Title: "C", CreatedAt: now},
+			{ID: uuid.New(), Title: "B", CreatedAt: now.Add(-time.Minute)},
+			{ID: uuid.New(), Title: "A", CreatedAt: now.Add(-2 * time.Minute)},
+		}
+
+		mockRepo.EXPECT().FindBookAfterCursor(gomock.Any(), querier.FindBookAfterCursorParams{
+			Limit: 3,
+		}).Return(books, nil).Times(1)
+		mockRepo.EXPECT().GetBookCount(gomock.Any(), gomock.Any()).Times(0)
+
+		resp := bookSvcMock.GetBook(ctx, cursorReq)
+
+		assert.Equal(t, -1, resp.Total)
+		assert.True(t, resp.IsLoadMore)
+		assert.Equal(t, []string{"C", "B"}, lo.Map(resp.Data, func(item dto.GetBookRes, _ int) string {
+			return item.Title
+		}))
+		assert.Equal(t, dto.EncodeCursor(dto.Cursor{Time: books[1].CreatedAt, ID: books[1].ID}), resp.NextCursor)
+		assert.Empty(t, resp.PrevCursor)
+	})
+
+	t.Run("success get previous book page by cursor with total", func(t *testing.T) {
+		cursor := dto.Cursor{Time: now.Add(-3 * time.Minute), ID: uuid.New(), Backward: true}
+		cursorReq := dto.GetBookReq{
+			Limit:      2,
+			CursorMode: true,
+			Cursor:     cursor,
+			WithTotal:  true,
+		}
+		books := []querier.Book{
+			{ID: uuid.New(), Title: "A", CreatedAt: now.Add(-2 * time.Minute)},
+			{ID: uuid.New(), Title: "B", CreatedAt: now.Add(-time.Minute)},
+		}
+
+		mockRepo.EXPECT().FindBookBeforeCursor(gomock.Any(), querier.FindBookBeforeCursorParams{
+			CursorCreatedAt: cursor.Time,
+			CursorID:        cursor.ID,
+			Limit:           3,
+		}).Return(books, nil).Times(1)
+		mockRepo.EXPECT().GetBookCount(gomock.Any(), querier.GetBookCountParams{}).Return(int64(totalCount), nil).Times(1)
+
+		resp := bookSvcMock.GetBook(ctx, cursorReq)
+
+		assert.Equal(t, totalCount, resp.Total)
+		assert.Equal(t, []string{"B", "A"}, lo.Map(resp.Data, func(item dto.GetBookRes, _ int) string {
+			return item.Title
+		}))
+		assert.Equal(t, dto.EncodeCursor(dto.Cursor{Time: books[0].CreatedAt, ID: books[0].ID}), resp.NextCursor)
+		assert.Empty(t, resp.PrevCursor)
+	})
+
+	t.Run("failed get book by cursor", func(t *testing.T) {
+		mockRepo.EXPECT().FindBookAfterCursor(gomock.Any(), gomock.Any()).Return(nil, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToGetBook),
+		}, func() {
+			resp := bookSvcMock.GetBook(ctx, dto.GetBookReq{
+				Limit:      5,
+				CursorMode: true,
+			})
+			assert.Empty(t, resp)
+		})
+	})
+
 	t.Run("failed get book count", func(t *testing.T) {
 		mockCache.DelByPrefix(ctx, constant.BookCacheKey)
 
@@ -212,7 +357,7 @@ func TestGetBook(t *testing.T) {
 			},
 		}, nil).Times(1)
 
-		mockRepo.EXPECT().GetBookCount(gomock.Any()).Return(int64(totalCount), errInvalidReq).Times(1)
+		mockRepo.EXPECT().GetBookCount(gomock.Any(), querier.GetBookCountParams{}).Return(int64(totalCount), errInvalidReq).Times(1)
 
 		assert.PanicsWithValue(t, utils.AppError{
 			StatusCode: 400,
@@ -241,7 +386,7 @@ func TestGetBook(t *testing.T) {
 			},
 		}, errInvalidReq).Times(1)
 
-		mockRepo.EXPECT().GetBookCount(gomock.Any()).Return(int64(totalCount), nil).Times(1)
+		mockRepo.EXPECT().GetBookCount(gomock.Any(), querier.GetBookCountParams{}).Return(int64(totalCount), nil).Times(1)
 
 		assert.PanicsWithValue(t, utils.AppError{
 			StatusCode: 400,
@@ -252,3 +397,348 @@ func TestGetBook(t *testing.T) {
 		})
 	})
 }
+
+func TestGetBookByID(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	bookSvcMock, mockRepo, mockCache := initBookSvc(t, ctrl, config)
+
+	bookID := uuid.New()
+	title := "Hello"
+	description := "World"
+	author := "Giri Putra Adhittana"
+	price := decimal.NewFromInt(10)
+	now := time.Now()
+	req := dto.GetBookByIDReq{
+		BookID: bookID,
+	}
+
+	t.Run("success get book by ID", func(t *testing.T) {
+		mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(querier.Book{
+			ID:          bookID,
+			Title:       title,
+			Description: description,
+			Author:      author,
+			Price:       price,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}, nil).Times(1)
+
+		resp := bookSvcMock.GetBookByID(ctx, req)
+
+		assert.Equal(t, dto.GetBookRes{
+			ID:          bookID.String(),
+			Title:       title,
+			Description: description,
+			Author:      author,
+			Price:       price,
+		}, resp)
+	})
+
+	t.Run("success get book by ID from cache", func(t *testing.T) {
+		resp := bookSvcMock.GetBookByID(ctx, req)
+
+		assert.Equal(t, bookID.String(), resp.ID)
+	})
+
+	t.Run("book not found", func(t *testing.T) {
+		mockCache.DelByPrefix(ctx, constant.BookCacheKey)
+
+		mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(querier.Book{}, pgx.ErrNoRows).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("%s|%s", BookNotFound, BookNotFound),
+		}, func() {
+			resp := bookSvcMock.GetBookByID(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("failed find book by ID", func(t *testing.T) {
+		mockCache.DelByPrefix(ctx, constant.BookCacheKey)
+
+		mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(querier.Book{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToFindBookByID),
+		}, func() {
+			resp := bookSvcMock.GetBookByID(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+}
+
+func TestUpdateBook(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	bookSvcMock, mockRepo, _ := initBookSvc(t, ctrl, config)
+
+	bookID := uuid.New()
+	title := "Hello"
+	description := "World"
+	author := "Giri Putra Adhittana"
+	price := decimal.NewFromInt(10)
+	newPrice := decimal.NewFromInt(15)
+	now := time.Now()
+	book := querier.Book{
+		ID:          bookID,
+		Title:       title,
+		Description: description,
+		Author:      author,
+		Price:       price,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	req := dto.PatchBookReq{
+		BookID: bookID,
+		Price:  lo.ToPtr(newPrice),
+	}
+
+	t.Run("success patch book", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(book, nil).Times(1)
+
+		mockRepo.EXPECT().UpdateBookByID(gomock.Any(), querier.UpdateBookByIDParams{
+			ID:          bookID,
+			Title:       title,
+			Description: description,
+			Author:      author,
+			Price:       newPrice,
+		}).Return(querier.Book{
+			ID:          bookID,
+			Title:       title,
+			Description: description,
+			Author:      author,
+			Price:       newPrice,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}, nil).Times(1)
+
+		resp := bookSvcMock.UpdateBook(ctx, req)
+
+		assert.Equal(t, dto.UpdateBookRes{
+			ID:          bookID.String(),
+			Title:       title,
+			Description: description,
+			Author:      author,
+			Price:       newPrice,
+		}, resp)
+	})
+
+	t.Run("book not found", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(querier.Book{}, pgx.ErrNoRows).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("%s|%s", BookNotFound, BookNotFound),
+		}, func() {
+			resp := bookSvcMock.UpdateBook(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("failed find book by ID", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(querier.Book{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToFindBookByID),
+		}, func() {
+			resp := bookSvcMock.UpdateBook(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("failed update book", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(book, nil).Times(1)
+
+		mockRepo.EXPECT().UpdateBookByID(gomock.Any(), gomock.Any()).Return(querier.Book{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToUpdateBook),
+		}, func() {
+			resp := bookSvcMock.UpdateBook(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+}
+
+func TestDeleteBook(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	bookSvcMock, mockRepo, _ := initBookSvc(t, ctrl, config)
+
+	bookID := uuid.New()
+	req := dto.DeleteBookReq{
+		BookID: bookID,
+	}
+
+	t.Run("success delete book", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(true, nil).Times(1)
+		mockRepo.EXPECT().CheckBookOrdered(gomock.Any(), bookID).Return(false, nil).Times(1)
+		mockRepo.EXPECT().DeleteBookByID(gomock.Any(), bookID).Return(nil).Times(1)
+
+		resp := bookSvcMock.DeleteBook(ctx, req)
+
+		assert.Equal(t, dto.DeleteBookRes{
+			ID: bookID.String(),
+		}, resp)
+	})
+
+	t.Run("book not found", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(false, nil).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("%s|%s", BookNotFound, BookNotFound),
+		}, func() {
+			resp := bookSvcMock.DeleteBook(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("book already ordered", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(true, nil).Times(1)
+		mockRepo.EXPECT().CheckBookOrdered(gomock.Any(), bookID).Return(true, nil).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 409,
+			Message:    fmt.Sprintf("%s|%s", BookAlreadyOrdered, BookAlreadyOrdered),
+		}, func() {
+			resp := bookSvcMock.DeleteBook(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("failed delete book", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(true, nil).Times(1)
+		mockRepo.EXPECT().CheckBookOrdered(gomock.Any(), bookID).Return(false, nil).Times(1)
+		mockRepo.EXPECT().DeleteBookByID(gomock.Any(), bookID).Return(errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToDeleteBook),
+		}, func() {
+			resp := bookSvcMock.DeleteBook(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+}
+
+func TestAdjustBookStock(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	bookSvcMock, mockRepo, _ := initBookSvc(t, ctrl, config)
+
+	bookID := uuid.New()
+	req := dto.AdjustBookStockReq{
+		BookID:   bookID,
+		Quantity: 5,
+		Reason:   constant.StockReasonRestock,
+	}
+
+	t.Run("success adjust book stock", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(true, nil).Times(1)
+		mockRepo.EXPECT().AdjustBookStock(gomock.Any(), querier.AdjustBookStockParams{
+			ID:       bookID,
+			Quantity: 5,
+		}).Return(querier.Book{
+			ID:    bookID,
+			Stock: 15,
+		}, nil).Times(1)
+		mockRepo.EXPECT().CreateStockMovement(gomock.Any(), querier.CreateStockMovementParams{
+			BookID:    bookID,
+			Quantity:  5,
+			Reason:    constant.StockReasonRestock,
+			CreatedBy: userID,
+		}).Return(querier.StockMovement{}, nil).Times(1)
+
+		resp := bookSvcMock.AdjustBookStock(ctx, req)
+
+		assert.Equal(t, dto.AdjustBookStockRes{
+			ID:    bookID.String(),
+			Stock: 15,
+		}, resp)
+	})
+
+	t.Run("book not found", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(false, nil).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("%s|%s", BookNotFound, BookNotFound),
+		}, func() {
+			resp := bookSvcMock.AdjustBookStock(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("insufficient stock", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(true, nil).Times(1)
+		mockRepo.EXPECT().AdjustBookStock(gomock.Any(), gomock.Any()).Return(querier.Book{}, pgx.ErrNoRows).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 409,
+			Message:    fmt.Sprintf("%s|%s", InsufficientBookStock, InsufficientBookStock),
+		}, func() {
+			resp := bookSvcMock.AdjustBookStock(ctx, dto.AdjustBookStockReq{
+				BookID:   bookID,
+				Quantity: -100,
+				Reason:   constant.StockReasonDamaged,
+			})
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("failed create stock movement", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(true, nil).Times(1)
+		mockRepo.EXPECT().AdjustBookStock(gomock.Any(), gomock.Any()).Return(querier.Book{ID: bookID, Stock: 15}, nil).Times(1)
+		mockRepo.EXPECT().CreateStockMovement(gomock.Any(), gomock.Any()).Return(querier.StockMovement{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToCreateStockMovement),
+		}, func() {
+			resp := bookSvcMock.AdjustBookStock(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"github.com/gadhittana-01/book-go/metrics"
+	"github.com/gadhittana-01/book-go/tracing"
+	"github.com/gadhittana01/go-modules/utils"
+)
+
+// getOrSetData is utils.GetOrSetData that traces the cache get and set and
+// counts the lookup as a hit or miss of the cache prefix. fn only runs on a
+// miss.
+func getOrSetData[T any](
+	ctx context.Context,
+	cacheSvc utils.CacheSvc,
+	prefix string,
+	key string,
+	fn func() (T, error),
+) (T, error) {
+	hit := true
+	resp, err := utils.GetOrSetData(tracing.WithCacheSvc(ctx, cacheSvc), key, func() (T, error) {
+		hit = false
+		return fn()
+	})
+	metrics.RecordCacheLookup(prefix, hit)
+
+	return resp, err
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gadhittana-01/book-go/constant"
+	querier "github.com/gadhittana-01/book-go/db/repository"
+	"github.com/gadhittana-01/book-go/dto"
+	"github.com/gadhittana-01/book-go/metrics"
+	utilsConstant "github.com/gadhittana01/go-modules/constant"
+	"github.com/gadhittana01/go-modules/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	FailedToFindCart       = "Failed to find cart"
+	FailedToCreateCart     = "Failed to create cart"
+	FailedToFindCartItems  = "Failed to find cart items"
+	FailedToAddCartItem    = "Failed to add cart item"
+	FailedToUpdateCartItem = "Failed to update cart item"
+	FailedToDeleteCartItem = "Failed to delete cart item"
+	FailedToClearCart      = "Failed to clear cart"
+	CartItemNotFound       = "Cart item not found"
+	CartEmpty              = "Cart is empty"
+)
+
+type CartSvc interface {
+	GetCart(ctx context.Context) dto.GetCartRes
+	AddCartItem(ctx context.Context, input dto.AddCartItemReq) dto.GetCartRes
+	UpdateCartItem(ctx context.Context, input dto.UpdateCartItemReq) dto.GetCartRes
+	DeleteCartItem(ctx context.Context, input dto.DeleteCartItemReq) dto.GetCartRes
+	ClearCart(ctx context.Context) dto.GetCartRes
+	Checkout(ctx context.Context) dto.CreateOrderRes
+}
+
+type CartSvcImpl struct {
+	repo     querier.Repository
+	config   *utils.BaseConfig
+	cacheSvc utils.CacheSvc
+}
+
+func NewCartSvc(
+	repo querier.Repository,
+	config *utils.BaseConfig,
+	cacheSvc utils.CacheSvc,
+) CartSvc {
+	return &CartSvcImpl{
+		repo:     repo,
+		config:   config,
+		cacheSvc: cacheSvc,
+	}
+}
+
+func (s *CartSvcImpl) GetCart(ctx context.Context) dto.GetCartRes {
+	userID := getCartUserID(ctx)
+
+	cart, err := s.repo.FindCartByUserID(ctx, userID)
+	if err == pgx.ErrNoRows {
+		return emptyCart()
+	}
+	utils.PanicIfAppError(err, FailedToFindCart, 400)
+
+	resp, err := buildCart(ctx, s.repo, cart.ID)
+	utils.PanicIfError(err)
+
+	return resp
+}
+
+func (s *CartSvcImpl) AddCartItem(ctx context.Context, input dto.AddCartItemReq) dto.GetCartRes {
+	var resp dto.GetCartRes
+	userID := getCartUserID(ctx)
+
+	bookID, err := uuid.Parse(input.BookID)
+	utils.PanicIfAppError(err, FailedToParseStringToUUID, 400)
+
+	err = utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		repoTx := s.repo.WithTx(tx)
+
+		book, err := findCartBook(ctx, repoTx, bookID)
+		if err != nil {
+			return err
+		}
+
+		cart, err := repoTx.UpsertCart(ctx, userID)
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToCreateCart, 422)
+		}
+
+		item, err := repoTx.UpsertCartItem(ctx, querier.UpsertCartItemParams{
+			CartID:   cart.ID,
+			BookID:   bookID,
+			Quantity: int32(input.Quantity),
+		})
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToAddCartItem, 422)
+		}
+
+		// adding to an existing line sums the quantities, so the stock check
+		// runs against the merged quantity
+		if item.Quantity > book.Stock {
+			return utils.CustomError(fmt.Sprintf(OutOfStock, bookID.String()), 409)
+		}
+
+		resp, err = buildCart(ctx, repoTx, cart.ID)
+		return err
+	})
+	utils.PanicIfError(err)
+
+	return resp
+}
+
+func (s *CartSvcImpl) UpdateCartItem(ctx context.Context, input dto.UpdateCartItemReq) dto.GetCartRes {
+	var resp dto.GetCartRes
+	userID := getCartUserID(ctx)
+
+	err := utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		repoTx := s.repo.WithTx(tx)
+
+		cart, err := repoTx.FindCartByUserID(ctx, userID)
+		if err == pgx.ErrNoRows {
+			return utils.CustomError(CartItemNotFound, 404)
+		}
+
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToFindCart, 400)
+		}
+
+		book, err := findCartBook(ctx, repoTx, input.BookID)
+		if err != nil {
+			return err
+		}
+
+		if int32(input.Quantity) > book.Stock {
+			return utils.CustomError(fmt.Sprintf(OutOfStock, input.BookID.String()), 409)
+		}
+
+		_, err = repoTx.UpdateCartItemQuantity(ctx, querier.UpdateCartItemQuantityParams{
+			CartID:   cart.ID,
+			BookID:   input.BookID,
+			Quantity: int32(input.Quantity),
+		})
+		if err == pgx.ErrNoRows {
+			return utils.CustomError(CartItemNotFound, 404)
+		}
+
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToUpdateCartItem, 422)
+		}
+
+		resp, err = buildCart(ctx, repoTx, cart.ID)
+		return err
+	})
+	utils.PanicIfError(err)
+
+	return resp
+}
+
+func (s *CartSvcImpl) DeleteCartItem(ctx context.Context, input dto.DeleteCartItemReq) dto.GetCartRes {
+	userID := getCartUserID(ctx)
+
+	cart, err := s.repo.FindCartByUserID(ctx, userID)
+	if err == pgx.ErrNoRows {
+		utils.PanicAppError(CartItemNotFound, 404)
+	}
+	utils.PanicIfAppError(err, FailedToFindCart, 400)
+
+	_, err = s.repo.DeleteCartItem(ctx, querier.DeleteCartItemParams{
+		CartID: cart.ID,
+		BookID: input.BookID,
+	})
+	if err == pgx.ErrNoRows {
+		utils.PanicAppError(CartItemNotFound, 404)
+	}
+	utils.PanicIfAppError(err, FailedToDeleteCartItem, 422)
+
+	resp, err := buildCart(ctx, s.repo, cart.ID)
+	utils.PanicIfError(err)
+
+	return resp
+}
+
+func (s *CartSvcImpl) ClearCart(ctx context.Context) dto.GetCartRes {
+	userID := getCartUserID(ctx)
+
+	cart, err := s.repo.FindCartByUserID(ctx, userID)
+	if err == pgx.ErrNoRows {
+		return emptyCart()
+	}
+	utils.PanicIfAppError(err, FailedToFindCart, 400)
+
+	err = s.repo.DeleteCartItemsByCartID(ctx, cart.ID)
+	utils.PanicIfAppError(err, FailedToClearCart, 422)
+
+	return emptyCart()
+}
+
+func (s *CartSvcImpl) Checkout(ctx context.Context) dto.CreateOrderRes {
+	var order querier.Order
+	now := time.Now()
+	userID := getCartUserID(ctx)
+
+	err := utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		repoTx := s.repo.WithTx(tx)
+
+		// locking the cart keeps a double submitted checkout from placing
+		// the same items twice
+		cart, err := repoTx.FindCartByUserIDForUpdate(ctx, userID)
+		if err == pgx.ErrNoRows {
+			return utils.CustomError(CartEmpty, 400)
+		}
+
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToFindCart, 400)
+		}
+
+		cartItems, err := repoTx.FindCartItemsByCartID(ctx, cart.ID)
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToFindCartItems, 400)
+		}
+
+		if len(cartItems) == 0 {
+			return utils.CustomError(CartEmpty, 400)
+		}
+
+		items := lo.Map(cartItems, func(item querier.FindCartItemsByCartIDRow, _ int) orderItem {
+			return orderItem{
+				bookID:   item.BookID,
+				quantity: item.Quantity,
+			}
+		})
+
+		order, err = placeOrder(ctx, repoTx, userID, items, now)
+		if err != nil {
+			return err
+		}
+
+		err = repoTx.DeleteCartItemsByCartID(ctx, cart.ID)
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToClearCart, 422)
+		}
+
+		return nil
+	})
+	utils.PanicIfError(err)
+	s.cacheSvc.ClearCaches([]string{constant.OrderCacheKey}, userID.String())
+	s.cacheSvc.ClearCaches([]string{constant.BookCacheKey}, "")
+	metrics.RecordOrderCreated(constant.OrderSourceCart, order.TotalPrice)
+
+	return dto.CreateOrderRes{
+		OrderId:    order.ID.String(),
+		Date:       order.Date.Format(constant.TimeFormat),
+		TotalPrice: order.TotalPrice,
+		Status:     order.Status,
+	}
+}
+
+func getCartUserID(ctx context.Context) uuid.UUID {
+	authPayload := utils.GetRequestCtx(ctx, utilsConstant.UserSession)
+
+	userID, err := uuid.Parse(authPayload.UserID)
+	utils.PanicIfAppError(err, FailedToParseStringToUUID, 400)
+
+	return userID
+}
+
+func findCartBook(ctx context.Context, repoTx querier.Querier, bookID uuid.UUID) (querier.Book, error) {
+	book, err := repoTx.FindBookByID(ctx, bookID)
+	if err == pgx.ErrNoRows {
+		return querier.Book{}, utils.CustomError(BookNotFound, 404)
+	}
+
+	if err != nil {
+		return querier.Book{}, utils.CustomErrorWithTrace(err, FailedToFindBookByID, 400)
+	}
+
+	return book, nil
+}
+
+// buildCart reads the cart lines with the books' current price and stock,
+// so the totals always reflect the live catalog rather than the time an
+// item was added.
+func buildCart(ctx context.Context, repo querier.Querier, cartID uuid.UUID) (dto.GetCartRes, error) {
+	cartItems, err := repo.FindCartItemsByCartID(ctx, cartID)
+	if err != nil {
+		return dto.GetCartRes{}, utils.CustomErrorWithTrace(err, FailedToFindCartItems, 400)
+	}
+
+	resp := emptyCart()
+	for _, item := range cartItems {
+		lineTotal := item.Price.Mul(decimal.NewFromInt32(item.Quantity))
+
+		resp.Items = append(resp.Items, dto.CartItem{
+			BookID:    item.BookID.String(),
+			Title:     item.Title,
+			Author:    item.Author,
+			Quantity:  int(item.Quantity),
+			UnitPrice: item.Price,
+			LineTotal: lineTotal,
+			Stock:     int(item.Stock),
+			Available: item.Stock >= item.Quantity,
+		})
+		resp.TotalPrice = resp.TotalPrice.Add(lineTotal)
+	}
+
+	return resp, nil
+}
+
+func emptyCart() dto.GetCartRes {
+	return dto.GetCartRes{
+		Items:      []dto.CartItem{},
+		TotalPrice: decimal.Zero,
+	}
+}
@@ -0,0 +1,569 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gadhittana-01/book-go/constant"
+	querier "github.com/gadhittana-01/book-go/db/repository"
+	mockrepo "github.com/gadhittana-01/book-go/db/repository/mock"
+	"github.com/gadhittana-01/book-go/dto"
+	"github.com/gadhittana01/go-modules/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func initCartSvc(
+	t *testing.T,
+	ctrl *gomock.Controller,
+	config *utils.BaseConfig,
+) (CartSvc, *mockrepo.MockRepository, utils.CacheSvc) {
+	mockRepo := mockrepo.NewMockRepository(ctrl)
+	cacheSvc := utils.InitCacheSvc(t, config)
+	return NewCartSvc(mockRepo, config, cacheSvc), mockRepo, cacheSvc
+}
+
+type cartFixture struct {
+	now       time.Time
+	cart      querier.Cart
+	book      querier.Book
+	cartItems []querier.FindCartItemsByCartIDRow
+	cartRes   dto.GetCartRes
+}
+
+func newCartFixture() cartFixture {
+	now := time.Now()
+	price := decimal.NewFromInt(10)
+	book := querier.Book{
+		ID:          uuid.New(),
+		Title:       "Hello",
+		Description: "World",
+		Author:      "Giri Putra Adhittana",
+		Price:       price,
+		Stock:       5,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	cart := querier.Cart{
+		ID:        uuid.New(),
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	return cartFixture{
+		now:  now,
+		cart: cart,
+		book: book,
+		cartItems: []querier.FindCartItemsByCartIDRow{
+			{
+				BookID:   book.ID,
+				Quantity: 2,
+				Title:    book.Title,
+				Author:   book.Author,
+				Price:    price,
+				Stock:    book.Stock,
+			},
+		},
+		cartRes: dto.GetCartRes{
+			Items: []dto.CartItem{
+				{
+					BookID:    book.ID.String(),
+					Title:     book.Title,
+					Author:    book.Author,
+					Quantity:  2,
+					UnitPrice: price,
+					LineTotal: decimal.NewFromInt(20),
+					Stock:     int(book.Stock),
+					Available: true,
+				},
+			},
+			TotalPrice: decimal.NewFromInt(20),
+		},
+	}
+}
+
+func TestGetCart(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	cartSvcMock, mockRepo, _ := initCartSvc(t, ctrl, config)
+	fixture := newCartFixture()
+
+	t.Run("success get cart", func(t *testing.T) {
+		mockRepo.EXPECT().FindCartByUserID(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().FindCartItemsByCartID(gomock.Any(), fixture.cart.ID).Return(fixture.cartItems, nil).Times(1)
+
+		resp := cartSvcMock.GetCart(ctx)
+
+		assert.Equal(t, fixture.cartRes, resp)
+	})
+
+	t.Run("success get cart with live stock", func(t *testing.T) {
+		soldOut := fixture.cartItems[0]
+		soldOut.Stock = 1
+		mockRepo.EXPECT().FindCartByUserID(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().FindCartItemsByCartID(gomock.Any(), fixture.cart.ID).Return(
+			[]querier.FindCartItemsByCartIDRow{soldOut}, nil).Times(1)
+
+		resp := cartSvcMock.GetCart(ctx)
+
+		assert.False(t, resp.Items[0].Available)
+		assert.Equal(t, 1, resp.Items[0].Stock)
+	})
+
+	t.Run("success get empty cart", func(t *testing.T) {
+		mockRepo.EXPECT().FindCartByUserID(gomock.Any(), userID).Return(querier.Cart{}, pgx.ErrNoRows).Times(1)
+
+		resp := cartSvcMock.GetCart(ctx)
+
+		assert.Equal(t, emptyCart(), resp)
+	})
+
+	t.Run("failed to find cart items", func(t *testing.T) {
+		mockRepo.EXPECT().FindCartByUserID(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().FindCartItemsByCartID(gomock.Any(), fixture.cart.ID).Return(nil, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToFindCartItems),
+		}, func() {
+			resp := cartSvcMock.GetCart(ctx)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("failed to find cart", func(t *testing.T) {
+		mockRepo.EXPECT().FindCartByUserID(gomock.Any(), userID).Return(querier.Cart{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToFindCart),
+		}, func() {
+			resp := cartSvcMock.GetCart(ctx)
+			assert.Empty(t, resp)
+		})
+	})
+}
+
+func TestAddCartItem(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	cartSvcMock, mockRepo, _ := initCartSvc(t, ctrl, config)
+	fixture := newCartFixture()
+
+	req := dto.AddCartItemReq{
+		BookID:   fixture.book.ID.String(),
+		Quantity: 2,
+	}
+	upsertParams := querier.UpsertCartItemParams{
+		CartID:   fixture.cart.ID,
+		BookID:   fixture.book.ID,
+		Quantity: 2,
+	}
+
+	t.Run("success add cart item", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		mockRepo.EXPECT().FindBookByID(gomock.Any(), fixture.book.ID).Return(fixture.book, nil).Times(1)
+		mockRepo.EXPECT().UpsertCart(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().UpsertCartItem(gomock.Any(), upsertParams).Return(querier.CartItem{
+			CartID:   fixture.cart.ID,
+			BookID:   fixture.book.ID,
+			Quantity: 2,
+		}, nil).Times(1)
+		mockRepo.EXPECT().FindCartItemsByCartID(gomock.Any(), fixture.cart.ID).Return(fixture.cartItems, nil).Times(1)
+
+		resp := cartSvcMock.AddCartItem(ctx, req)
+
+		assert.Equal(t, fixture.cartRes, resp)
+	})
+
+	t.Run("merged quantity exceeds stock", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindBookByID(gomock.Any(), fixture.book.ID).Return(fixture.book, nil).Times(1)
+		mockRepo.EXPECT().UpsertCart(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().UpsertCartItem(gomock.Any(), upsertParams).Return(querier.CartItem{
+			CartID:   fixture.cart.ID,
+			BookID:   fixture.book.ID,
+			Quantity: 6,
+		}, nil).Times(1)
+
+		message := fmt.Sprintf(OutOfStock, fixture.book.ID.String())
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 409,
+			Message:    fmt.Sprintf("%s|%s", message, message),
+		}, func() {
+			resp := cartSvcMock.AddCartItem(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("failed to add cart item", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindBookByID(gomock.Any(), fixture.book.ID).Return(fixture.book, nil).Times(1)
+		mockRepo.EXPECT().UpsertCart(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().UpsertCartItem(gomock.Any(), upsertParams).Return(querier.CartItem{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToAddCartItem),
+		}, func() {
+			resp := cartSvcMock.AddCartItem(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("failed to create cart", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindBookByID(gomock.Any(), fixture.book.ID).Return(fixture.book, nil).Times(1)
+		mockRepo.EXPECT().UpsertCart(gomock.Any(), userID).Return(querier.Cart{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToCreateCart),
+		}, func() {
+			resp := cartSvcMock.AddCartItem(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("book not found", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindBookByID(gomock.Any(), fixture.book.ID).Return(querier.Book{}, pgx.ErrNoRows).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("%s|%s", BookNotFound, BookNotFound),
+		}, func() {
+			resp := cartSvcMock.AddCartItem(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("failed to parse bookID", func(t *testing.T) {
+		assert.Panics(t, func() {
+			resp := cartSvcMock.AddCartItem(ctx, dto.AddCartItemReq{
+				BookID:   "123",
+				Quantity: 2,
+			})
+			assert.Empty(t, resp)
+		})
+	})
+}
+
+func TestUpdateCartItem(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	cartSvcMock, mockRepo, _ := initCartSvc(t, ctrl, config)
+	fixture := newCartFixture()
+
+	req := dto.UpdateCartItemReq{
+		BookID:   fixture.book.ID,
+		Quantity: 2,
+	}
+	updateParams := querier.UpdateCartItemQuantityParams{
+		CartID:   fixture.cart.ID,
+		BookID:   fixture.book.ID,
+		Quantity: 2,
+	}
+
+	t.Run("success update cart item", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		mockRepo.EXPECT().FindCartByUserID(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().FindBookByID(gomock.Any(), fixture.book.ID).Return(fixture.book, nil).Times(1)
+		mockRepo.EXPECT().UpdateCartItemQuantity(gomock.Any(), updateParams).Return(querier.CartItem{}, nil).Times(1)
+		mockRepo.EXPECT().FindCartItemsByCartID(gomock.Any(), fixture.cart.ID).Return(fixture.cartItems, nil).Times(1)
+
+		resp := cartSvcMock.UpdateCartItem(ctx, req)
+
+		assert.Equal(t, fixture.cartRes, resp)
+	})
+
+	t.Run("cart item not found", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindCartByUserID(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().FindBookByID(gomock.Any(), fixture.book.ID).Return(fixture.book, nil).Times(1)
+		mockRepo.EXPECT().UpdateCartItemQuantity(gomock.Any(), updateParams).Return(querier.CartItem{}, pgx.ErrNoRows).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("%s|%s", CartItemNotFound, CartItemNotFound),
+		}, func() {
+			resp := cartSvcMock.UpdateCartItem(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("quantity exceeds stock", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindCartByUserID(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().FindBookByID(gomock.Any(), fixture.book.ID).Return(fixture.book, nil).Times(1)
+
+		message := fmt.Sprintf(OutOfStock, fixture.book.ID.String())
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 409,
+			Message:    fmt.Sprintf("%s|%s", message, message),
+		}, func() {
+			resp := cartSvcMock.UpdateCartItem(ctx, dto.UpdateCartItemReq{
+				BookID:   fixture.book.ID,
+				Quantity: 6,
+			})
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("cart not found", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindCartByUserID(gomock.Any(), userID).Return(querier.Cart{}, pgx.ErrNoRows).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("%s|%s", CartItemNotFound, CartItemNotFound),
+		}, func() {
+			resp := cartSvcMock.UpdateCartItem(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+}
+
+func TestDeleteCartItem(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	cartSvcMock, mockRepo, _ := initCartSvc(t, ctrl, config)
+	fixture := newCartFixture()
+
+	req := dto.DeleteCartItemReq{
+		BookID: fixture.book.ID,
+	}
+	deleteParams := querier.DeleteCartItemParams{
+		CartID: fixture.cart.ID,
+		BookID: fixture.book.ID,
+	}
+
+	t.Run("success delete cart item", func(t *testing.T) {
+		mockRepo.EXPECT().FindCartByUserID(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().DeleteCartItem(gomock.Any(), deleteParams).Return(querier.CartItem{}, nil).Times(1)
+		mockRepo.EXPECT().FindCartItemsByCartID(gomock.Any(), fixture.cart.ID).Return(
+			[]querier.FindCartItemsByCartIDRow{}, nil).Times(1)
+
+		resp := cartSvcMock.DeleteCartItem(ctx, req)
+
+		assert.Equal(t, emptyCart(), resp)
+	})
+
+	t.Run("cart item not found", func(t *testing.T) {
+		mockRepo.EXPECT().FindCartByUserID(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().DeleteCartItem(gomock.Any(), deleteParams).Return(querier.CartItem{}, pgx.ErrNoRows).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("%s|%s", CartItemNotFound, CartItemNotFound),
+		}, func() {
+			resp := cartSvcMock.DeleteCartItem(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("failed to delete cart item", func(t *testing.T) {
+		mockRepo.EXPECT().FindCartByUserID(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().DeleteCartItem(gomock.Any(), deleteParams).Return(querier.CartItem{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToDeleteCartItem),
+		}, func() {
+			resp := cartSvcMock.DeleteCartItem(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+}
+
+func TestClearCart(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	cartSvcMock, mockRepo, _ := initCartSvc(t, ctrl, config)
+	fixture := newCartFixture()
+
+	t.Run("success clear cart", func(t *testing.T) {
+		mockRepo.EXPECT().FindCartByUserID(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().DeleteCartItemsByCartID(gomock.Any(), fixture.cart.ID).Return(nil).Times(1)
+
+		resp := cartSvcMock.ClearCart(ctx)
+
+		assert.Equal(t, emptyCart(), resp)
+	})
+
+	t.Run("success clear missing cart", func(t *testing.T) {
+		mockRepo.EXPECT().FindCartByUserID(gomock.Any(), userID).Return(querier.Cart{}, pgx.ErrNoRows).Times(1)
+
+		resp := cartSvcMock.ClearCart(ctx)
+
+		assert.Equal(t, emptyCart(), resp)
+	})
+
+	t.Run("failed to clear cart", func(t *testing.T) {
+		mockRepo.EXPECT().FindCartByUserID(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().DeleteCartItemsByCartID(gomock.Any(), fixture.cart.ID).Return(errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToClearCart),
+		}, func() {
+			resp := cartSvcMock.ClearCart(ctx)
+			assert.Empty(t, resp)
+		})
+	})
+}
+
+func TestCheckout(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	cartSvcMock, mockRepo, _ := initCartSvc(t, ctrl, config)
+	fixture := newCartFixture()
+
+	orderID := uuid.New()
+	totalPrice := decimal.NewFromInt(20)
+	order := querier.Order{
+		ID:        orderID,
+		UserID:    userID,
+		Date:      fixture.now,
+		Status:    constant.OrderStatusPending,
+		CreatedAt: fixture.now,
+		UpdatedAt: fixture.now,
+	}
+
+	t.Run("success checkout", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		mockRepo.EXPECT().FindCartByUserIDForUpdate(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().FindCartItemsByCartID(gomock.Any(), fixture.cart.ID).Return(fixture.cartItems, nil).Times(1)
+		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(order, nil).Times(1)
+		mockRepo.EXPECT().ReserveBooksStock(gomock.Any(), querier.ReserveBooksStockParams{
+			Ids:        []uuid.UUID{fixture.book.ID},
+			Quantities: []int32{2},
+		}).Return([]querier.Book{fixture.book}, nil).Times(1)
+		mockRepo.EXPECT().CreateStockMovements(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockRepo.EXPECT().CreateOrderDetails(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockRepo.EXPECT().UpdateOrderByID(gomock.Any(), querier.UpdateOrderByIDParams{
+			ID:         orderID,
+			TotalPrice: totalPrice,
+		}).Return(querier.Order{
+			ID:         orderID,
+			UserID:     userID,
+			Date:       fixture.now,
+			TotalPrice: totalPrice,
+			Status:     constant.OrderStatusPending,
+		}, nil).Times(1)
+		mockRepo.EXPECT().DeleteCartItemsByCartID(gomock.Any(), fixture.cart.ID).Return(nil).Times(1)
+
+		resp := cartSvcMock.Checkout(ctx)
+
+		assert.Equal(t, dto.CreateOrderRes{
+			OrderId:    orderID.String(),
+			Date:       fixture.now.Format(constant.TimeFormat),
+			TotalPrice: totalPrice,
+			Status:     constant.OrderStatusPending,
+		}, resp)
+	})
+
+	t.Run("out of stock keeps cart", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindCartByUserIDForUpdate(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().FindCartItemsByCartID(gomock.Any(), fixture.cart.ID).Return(fixture.cartItems, nil).Times(1)
+		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(order, nil).Times(1)
+		mockRepo.EXPECT().ReserveBooksStock(gomock.Any(), gomock.Any()).Return([]querier.Book{}, nil).Times(1)
+		mockRepo.EXPECT().FindBooksByIDs(gomock.Any(), gomock.Any()).Return([]querier.Book{fixture.book}, nil).Times(1)
+		mockRepo.EXPECT().DeleteCartItemsByCartID(gomock.Any(), gomock.Any()).Times(0)
+
+		message := fmt.Sprintf(OutOfStock, fixture.book.ID.String())
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 409,
+			Message:    fmt.Sprintf("%s|%s", message, message),
+		}, func() {
+			resp := cartSvcMock.Checkout(ctx)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("failed to clear cart", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindCartByUserIDForUpdate(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().FindCartItemsByCartID(gomock.Any(), fixture.cart.ID).Return(fixture.cartItems, nil).Times(1)
+		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(order, nil).Times(1)
+		mockRepo.EXPECT().ReserveBooksStock(gomock.Any(), gomock.Any()).Return([]querier.Book{fixture.book}, nil).Times(1)
+		mockRepo.EXPECT().CreateStockMovements(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockRepo.EXPECT().CreateOrderDetails(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockRepo.EXPECT().UpdateOrderByID(gomock.Any(), gomock.Any()).Return(order, nil).Times(1)
+		mockRepo.EXPECT().DeleteCartItemsByCartID(gomock.Any(), fixture.cart.ID).Return(errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToClearCart),
+		}, func() {
+			resp := cartSvcMock.Checkout(ctx)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("empty cart", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindCartByUserIDForUpdate(gomock.Any(), userID).Return(fixture.cart, nil).Times(1)
+		mockRepo.EXPECT().FindCartItemsByCartID(gomock.Any(), fixture.cart.ID).Return(
+			[]querier.FindCartItemsByCartIDRow{}, nil).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("%s|%s", CartEmpty, CartEmpty),
+		}, func() {
+			resp := cartSvcMock.Checkout(ctx)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("cart not exists", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindCartByUserIDForUpdate(gomock.Any(), userID).Return(querier.Cart{}, pgx.ErrNoRows).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("%s|%s", CartEmpty, CartEmpty),
+		}, func() {
+			resp := cartSvcMock.Checkout(ctx)
+			assert.Empty(t, resp)
+		})
+	})
+}
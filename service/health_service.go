@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	appConfig "github.com/gadhittana-01/book-go/config"
+	"github.com/gadhittana-01/book-go/constant"
+	"github.com/gadhittana-01/book-go/dto"
+	"github.com/gadhittana01/go-modules/utils"
+)
+
+// health check dependency names
+const (
+	postgresDependency   = "postgres"
+	redisDependency      = "redis"
+	migrationsDependency = "migrations"
+)
+
+const (
+	pingQuery             = "SELECT 1"
+	migrationVersionQuery = "SELECT version, dirty FROM schema_migrations LIMIT 1"
+)
+
+var migrationFileRegexp = regexp.MustCompile(`^(\d+)_.*\.up\.sql$`)
+
+// degradableDependencies are reported but do not make the pod unready. Redis
+// only backs the cache, rate limits and token revocation, which all fall back
+// when it is down, so taking every pod out of rotation would turn a Redis
+// outage into a full one.
+var degradableDependencies = map[string]bool{
+	redisDependency: true,
+}
+
+type HealthSvc interface {
+	CheckReadiness(ctx context.Context) dto.ReadinessRes
+}
+
+// HealthSvcImpl checks the dependencies of the service. Every check gets
+// HEALTH_CHECK_TIMEOUT, so a hung dependency only makes the probe report it as
+// down instead of blocking it.
+type HealthSvcImpl struct {
+	db          utils.PGXPool
+	redisClient utils.RedisClient
+	config      *utils.BaseConfig
+	appConfig   *appConfig.AppConfig
+}
+
+func NewHealthSvc(
+	db utils.PGXPool,
+	redisClient utils.RedisClient,
+	config *utils.BaseConfig,
+	appConfig *appConfig.AppConfig,
+) HealthSvc {
+	return &HealthSvcImpl{
+		db:          db,
+		redisClient: redisClient,
+		config:      config,
+		appConfig:   appConfig,
+	}
+}
+
+func (s *HealthSvcImpl) CheckReadiness(ctx context.Context) dto.ReadinessRes {
+	checks := map[string]func(ctx context.Context) error{
+		postgresDependency:   s.checkPostgres,
+		redisDependency:      s.checkRedis,
+		migrationsDependency: s.checkMigrations,
+	}
+
+	resp := dto.ReadinessRes{
+		Status:       constant.HealthStatusUp,
+		Dependencies: make(map[string]dto.DependencyStatus, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := s.runCheck(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			resp.Dependencies[name] = status
+			switch {
+			case status.Status == constant.HealthStatusUp:
+			case !degradableDependencies[name]:
+				resp.Status = constant.HealthStatusDown
+			case resp.Status == constant.HealthStatusUp:
+				resp.Status = constant.HealthStatusDegraded
+			}
+		}()
+	}
+	wg.Wait()
+
+	return resp
+}
+
+// runCheck does not wait for the check past the timeout, in case the check
+// itself ignores the context.
+func (s *HealthSvcImpl) runCheck(ctx context.Context, check func(ctx context.Context) error) dto.DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, s.appConfig.HealthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	result := make(chan error, 1)
+	go func() {
+		result <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-result:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	status := dto.DependencyStatus{
+		Status:    constant.HealthStatusUp,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		status.Status = constant.HealthStatusDown
+		status.Error = err.Error()
+	}
+
+	return status
+}
+
+func (s *HealthSvcImpl) checkPostgres(ctx context.Context) error {
+	_, err := s.db.Exec(ctx, pingQuery)
+	return err
+}
+
+func (s *HealthSvcImpl) checkRedis(ctx context.Context) error {
+	return s.redisClient.Ping(ctx).Err()
+}
+
+// checkMigrations compares the version recorded by golang-migrate with the
+// latest migration shipped next to the binary, so a pod does not serve traffic
+// against a schema it was not built for.
+func (s *HealthSvcImpl) checkMigrations(ctx context.Context) error {
+	expectedVersion, err := latestMigrationVersion(strings.TrimPrefix(s.config.MigrationURL, "file://"))
+	if err != nil {
+		return err
+	}
+
+	var version int64
+	var dirty bool
+	err = s.db.QueryRow(ctx, migrationVersionQuery).Scan(&version, &dirty)
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		return fmt.Errorf("migration %d is dirty", version)
+	}
+
+	if version != expectedVersion {
+		return fmt.Errorf("migration version is %d, expected %d", version, expectedVersion)
+	}
+
+	return nil
+}
+
+func latestMigrationVersion(dir string) (int64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var latest int64
+	for _, entry := range entries {
+		match := migrationFileRegexp.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+
+		latest = max(latest, version)
+	}
+
+	if latest == 0 {
+		return 0, fmt.Errorf("no migrations found in %s", dir)
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	appConfig "github.com/gadhittana-01/book-go/config"
+	"github.com/gadhittana-01/book-go/constant"
+	"github.com/gadhittana01/go-modules/utils"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func initHealthSvc(t *testing.T) (HealthSvc, pgxmock.PgxPoolIface, *miniredis.Miniredis) {
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(mockDB.Close)
+	mockDB.MatchExpectationsInOrder(false)
+
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(redisServer.Close)
+
+	migrationDir := t.TempDir()
+	for _, name := range []string{"000001_init.up.sql", "000001_init.down.sql", "000002_add_book.up.sql", "000002_add_book.down.sql"} {
+		if err := os.WriteFile(filepath.Join(migrationDir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return NewHealthSvc(mockDB, redis.NewClient(&redis.Options{
+		Addr: redisServer.Addr(),
+	}), &utils.BaseConfig{
+		MigrationURL: "file://" + migrationDir,
+	}, &appConfig.AppConfig{
+		HealthCheckTimeout: 100 * time.Millisecond,
+	}), mockDB, redisServer
+}
+
+func expectMigrationVersion(mockDB pgxmock.PgxPoolIface, version int64, dirty bool) {
+	mockDB.ExpectQuery(regexp.QuoteMeta(migrationVersionQuery)).
+		WillReturnRows(pgxmock.NewRows([]string{"version", "dirty"}).AddRow(version, dirty))
+}
+
+func TestCheckReadiness(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("all dependencies up", func(t *testing.T) {
+		healthSvc, mockDB, _ := initHealthSvc(t)
+		mockDB.ExpectExec(regexp.QuoteMeta(pingQuery)).WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		expectMigrationVersion(mockDB, 2, false)
+
+		resp := healthSvc.CheckReadiness(ctx)
+		assert.Equal(t, constant.HealthStatusUp, resp.Status)
+		assert.Len(t, resp.Dependencies, 3)
+		for _, status := range resp.Dependencies {
+			assert.Equal(t, constant.HealthStatusUp, status.Status)
+			assert.Empty(t, status.Error)
+		}
+	})
+
+	t.Run("postgres down", func(t *testing.T) {
+		healthSvc, mockDB, _ := initHealthSvc(t)
+		mockDB.ExpectExec(regexp.QuoteMeta(pingQuery)).WillReturnError(errors.New("connection refused"))
+		expectMigrationVersion(mockDB, 2, false)
+
+		resp := healthSvc.CheckReadiness(ctx)
+		assert.Equal(t, constant.HealthStatusDown, resp.Status)
+		assert.Equal(t, constant.HealthStatusDown, resp.Dependencies[postgresDependency].Status)
+		assert.Equal(t, "connection refused", resp.Dependencies[postgresDependency].Error)
+		assert.Equal(t, constant.HealthStatusUp, resp.Dependencies[redisDependency].Status)
+	})
+
+	t.Run("postgres hangs", func(t *testing.T) {
+		healthSvc, mockDB, _ := initHealthSvc(t)
+		mockDB.ExpectExec(regexp.QuoteMeta(pingQuery)).
+			WillReturnResult(pgxmock.NewResult("SELECT", 1)).
+			WillDelayFor(time.Second)
+		expectMigrationVersion(mockDB, 2, false)
+
+		start := time.Now()
+		resp := healthSvc.CheckReadiness(ctx)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, constant.HealthStatusDown, resp.Status)
+		assert.Equal(t, context.DeadlineExceeded.Error(), resp.Dependencies[postgresDependency].Error)
+	})
+
+	t.Run("redis down", func(t *testing.T) {
+		healthSvc, mockDB, redisServer := initHealthSvc(t)
+		mockDB.ExpectExec(regexp.QuoteMeta(pingQuery)).WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		expectMigrationVersion(mockDB, 2, false)
+		redisServer.Close()
+
+		resp := healthSvc.CheckReadiness(ctx)
+		assert.Equal(t, constant.HealthStatusDegraded, resp.Status)
+		assert.Equal(t, constant.HealthStatusDown, resp.Dependencies[redisDependency].Status)
+		assert.Equal(t, constant.HealthStatusUp, resp.Dependencies[postgresDependency].Status)
+	})
+
+	t.Run("postgres and redis down", func(t *testing.T) {
+		healthSvc, mockDB, redisServer := initHealthSvc(t)
+		mockDB.ExpectExec(regexp.QuoteMeta(pingQuery)).WillReturnError(errors.New("connection refused"))
+		expectMigrationVersion(mockDB, 2, false)
+		redisServer.Close()
+
+		resp := healthSvc.CheckReadiness(ctx)
+		assert.Equal(t, constant.HealthStatusDown, resp.Status)
+	})
+
+	t.Run("migrations behind", func(t *testing.T) {
+		healthSvc, mockDB, _ := initHealthSvc(t)
+		mockDB.ExpectExec(regexp.QuoteMeta(pingQuery)).WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		expectMigrationVersion(mockDB, 1, false)
+
+		resp := healthSvc.CheckReadiness(ctx)
+		assert.Equal(t, constant.HealthStatusDown, resp.Status)
+		assert.Equal(t, "migration version is 1, expected 2", resp.Dependencies[migrationsDependency].Error)
+	})
+
+	t.Run("migrations dirty", func(t *testing.T) {
+		healthSvc, mockDB, _ := initHealthSvc(t)
+		mockDB.ExpectExec(regexp.QuoteMeta(pingQuery)).WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		expectMigrationVersion(mockDB, 2, true)
+
+		resp := healthSvc.CheckReadiness(ctx)
+		assert.Equal(t, constant.HealthStatusDown, resp.Status)
+		assert.Equal(t, "migration 2 is dirty", resp.Dependencies[migrationsDependency].Error)
+	})
+}
+
+func TestLatestMigrationVersion(t *testing.T) {
+	t.Run("repository migrations", func(t *testing.T) {
+		version, err := latestMigrationVersion("../db/migration")
+		assert.NoError(t, err)
+		assert.Positive(t, version)
+	})
+
+	t.Run("no migrations", func(t *testing.T) {
+		_, err := latestMigrationVersion(t.TempDir())
+		assert.Error(t, err)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		_, err := latestMigrationVersion(filepath.Join(t.TempDir(), "missing"))
+		assert.Error(t, err)
+	})
+}
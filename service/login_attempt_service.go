@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gadhittana-01/book-go/config"
+	"github.com/gadhittana-01/book-go/constant"
+	"github.com/gadhittana01/go-modules/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+type LoginAttemptSvc interface {
+	IsLockedOut(ctx context.Context, email string, ip string) (bool, error)
+	RecordFailure(ctx context.Context, email string, ip string) error
+	Reset(ctx context.Context, email string) error
+}
+
+// LoginAttemptSvcImpl counts failed sign-ins per email and per client IP in
+// Redis. Once a counter reaches its limit every further failure locks the
+// email or IP out for LOGIN_LOCKOUT_DURATION, doubled per extra failure and
+// capped at LOGIN_MAX_LOCKOUT_DURATION. A limit of 0 disables that counter.
+type LoginAttemptSvcImpl struct {
+	client    utils.RedisClient
+	appConfig *config.AppConfig
+}
+
+func NewLoginAttemptSvc(
+	client utils.RedisClient,
+	appConfig *config.AppConfig,
+) LoginAttemptSvc {
+	return &LoginAttemptSvcImpl{
+		client:    client,
+		appConfig: appConfig,
+	}
+}
+
+// loginAttemptSubject is one thing failed sign-ins are counted against.
+type loginAttemptSubject struct {
+	key         string
+	maxAttempts int
+}
+
+func (s *LoginAttemptSvcImpl) subjects(email string, ip string) []loginAttemptSubject {
+	var subjects []loginAttemptSubject
+
+	if s.appConfig.LoginMaxAttemptsPerEmail > 0 {
+		subjects = append(subjects, loginAttemptSubject{
+			key:         emailSubjectKey(email),
+			maxAttempts: s.appConfig.LoginMaxAttemptsPerEmail,
+		})
+	}
+
+	if s.appConfig.LoginMaxAttemptsPerIP > 0 && ip != "" {
+		subjects = append(subjects, loginAttemptSubject{
+			key:         "ip:" + ip,
+			maxAttempts: s.appConfig.LoginMaxAttemptsPerIP,
+		})
+	}
+
+	return subjects
+}
+
+func (s *LoginAttemptSvcImpl) IsLockedOut(ctx context.Context, email string, ip string) (bool, error) {
+	subjects := s.subjects(email, ip)
+	if len(subjects) == 0 {
+		return false, nil
+	}
+
+	keys := make([]string, 0, len(subjects))
+	for _, subject := range subjects {
+		keys = append(keys, loginLockoutKey(subject.key))
+	}
+
+	count, err := s.client.Exists(ctx, keys...).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (s *LoginAttemptSvcImpl) RecordFailure(ctx context.Context, email string, ip string) error {
+	for _, subject := range s.subjects(email, ip) {
+		key := loginAttemptKey(subject.key)
+
+		var attempts *redis.IntCmd
+		_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			attempts = pipe.Incr(ctx, key)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		lockout := s.lockoutDuration(attempts.Val(), subject.maxAttempts)
+
+		_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			// the counter has to outlive the lockout, or the next failure
+			// after it would start the backoff over
+			pipe.Expire(ctx, key, s.appConfig.LoginAttemptWindow+lockout)
+			if lockout > 0 {
+				pipe.Set(ctx, loginLockoutKey(subject.key), 1, lockout)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Reset clears the email counter after a successful sign-in. The IP counter
+// is left alone, so one valid account cannot be used to keep guessing others.
+func (s *LoginAttemptSvcImpl) Reset(ctx context.Context, email string) error {
+	if s.appConfig.LoginMaxAttemptsPerEmail <= 0 {
+		return nil
+	}
+
+	key := emailSubjectKey(email)
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, loginAttemptKey(key), loginLockoutKey(key))
+		return nil
+	})
+
+	return err
+}
+
+func (s *LoginAttemptSvcImpl) lockoutDuration(attempts int64, maxAttempts int) time.Duration {
+	if attempts < int64(maxAttempts) {
+		return 0
+	}
+
+	lockout := s.appConfig.LoginLockoutDuration
+	for i := int64(maxAttempts); i < attempts && lockout < s.appConfig.LoginMaxLockoutDuration; i++ {
+		lockout *= 2
+	}
+
+	return min(lockout, s.appConfig.LoginMaxLockoutDuration)
+}
+
+// emailSubjectKey hashes the email so Redis does not hold a list of
+// addresses. It is normalized the same way as the user queries, so case
+// variants of an address share a counter.
+func emailSubjectKey(email string) string {
+	return "email:" + hashToken(normalizeEmail(email))
+}
+
+func loginAttemptKey(subjectKey string) string {
+	return fmt.Sprintf("%s:%s", constant.LoginAttemptKey, subjectKey)
+}
+
+func loginLockoutKey(subjectKey string) string {
+	return fmt.Sprintf("%s:%s", constant.LoginLockoutKey, subjectKey)
+}
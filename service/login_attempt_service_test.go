@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	appConfig "github.com/gadhittana-01/book-go/config"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func initLoginAttemptSvc(t *testing.T) (LoginAttemptSvc, *miniredis.Miniredis) {
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(redisServer.Close)
+
+	return NewLoginAttemptSvc(redis.NewClient(&redis.Options{
+		Addr: redisServer.Addr(),
+	}), &appConfig.AppConfig{
+		LoginMaxAttemptsPerEmail: 3,
+		LoginMaxAttemptsPerIP:    5,
+		LoginAttemptWindow:       15 * time.Minute,
+		LoginLockoutDuration:     time.Minute,
+		LoginMaxLockoutDuration:  5 * time.Minute,
+	}), redisServer
+}
+
+func recordFailures(t *testing.T, loginAttemptSvc LoginAttemptSvc, email string, ip string, count int) {
+	for i := 0; i < count; i++ {
+		assert.NoError(t, loginAttemptSvc.RecordFailure(context.Background(), email, ip))
+	}
+}
+
+func TestLoginAttemptEmailLockout(t *testing.T) {
+	ctx := context.Background()
+	loginAttemptSvc, redisServer := initLoginAttemptSvc(t)
+	email := "test@gmail.com"
+	lockoutKey := loginLockoutKey(emailSubjectKey(email))
+
+	t.Run("not locked out below the limit", func(t *testing.T) {
+		recordFailures(t, loginAttemptSvc, email, "", 2)
+
+		isLockedOut, err := loginAttemptSvc.IsLockedOut(ctx, email, "")
+		assert.NoError(t, err)
+		assert.False(t, isLockedOut)
+	})
+
+	t.Run("locked out at the limit, case insensitive", func(t *testing.T) {
+		recordFailures(t, loginAttemptSvc, " Test@Gmail.com", "", 1)
+
+		isLockedOut, err := loginAttemptSvc.IsLockedOut(ctx, email, "")
+		assert.NoError(t, err)
+		assert.True(t, isLockedOut)
+		assert.Equal(t, time.Minute, redisServer.TTL(lockoutKey))
+	})
+
+	t.Run("lockout doubles and is capped", func(t *testing.T) {
+		recordFailures(t, loginAttemptSvc, email, "", 1)
+		assert.Equal(t, 2*time.Minute, redisServer.TTL(lockoutKey))
+
+		recordFailures(t, loginAttemptSvc, email, "", 1)
+		assert.Equal(t, 4*time.Minute, redisServer.TTL(lockoutKey))
+
+		recordFailures(t, loginAttemptSvc, email, "", 1)
+		assert.Equal(t, 5*time.Minute, redisServer.TTL(lockoutKey))
+	})
+
+	t.Run("lockout expires", func(t *testing.T) {
+		redisServer.FastForward(5 * time.Minute)
+
+		isLockedOut, err := loginAttemptSvc.IsLockedOut(ctx, email, "")
+		assert.NoError(t, err)
+		assert.False(t, isLockedOut)
+	})
+
+	t.Run("reset clears the counter", func(t *testing.T) {
+		assert.NoError(t, loginAttemptSvc.Reset(ctx, email))
+		recordFailures(t, loginAttemptSvc, email, "", 2)
+
+		isLockedOut, err := loginAttemptSvc.IsLockedOut(ctx, email, "")
+		assert.NoError(t, err)
+		assert.False(t, isLockedOut)
+	})
+}
+
+func TestLoginAttemptIPLockout(t *testing.T) {
+	ctx := context.Background()
+	loginAttemptSvc, _ := initLoginAttemptSvc(t)
+	ip := "203.0.113.7"
+
+	t.Run("failures across emails lock the IP out", func(t *testing.T) {
+		for _, email := range []string{"a@gmail.com", "b@gmail.com", "c@gmail.com", "d@gmail.com", "e@gmail.com"} {
+			recordFailures(t, loginAttemptSvc, email, ip, 1)
+		}
+
+		isLockedOut, err := loginAttemptSvc.IsLockedOut(ctx, "f@gmail.com", ip)
+		assert.NoError(t, err)
+		assert.True(t, isLockedOut)
+	})
+
+	t.Run("other IPs are not affected", func(t *testing.T) {
+		isLockedOut, err := loginAttemptSvc.IsLockedOut(ctx, "f@gmail.com", "198.51.100.1")
+		assert.NoError(t, err)
+		assert.False(t, isLockedOut)
+	})
+
+	t.Run("successful sign-in does not clear the IP counter", func(t *testing.T) {
+		assert.NoError(t, loginAttemptSvc.Reset(ctx, "a@gmail.com"))
+
+		isLockedOut, err := loginAttemptSvc.IsLockedOut(ctx, "a@gmail.com", ip)
+		assert.NoError(t, err)
+		assert.True(t, isLockedOut)
+	})
+}
@@ -36,6 +36,20 @@ func (m *MockBookSvc) EXPECT() *MockBookSvcMockRecorder {
 	return m.recorder
 }
 
+// AdjustBookStock mocks base method.
+func (m *MockBookSvc) AdjustBookStock(ctx context.Context, input dto.AdjustBookStockReq) dto.AdjustBookStockRes {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustBookStock", ctx, input)
+	ret0, _ := ret[0].(dto.AdjustBookStockRes)
+	return ret0
+}
+
+// AdjustBookStock indicates an expected call of AdjustBookStock.
+func (mr *MockBookSvcMockRecorder) AdjustBookStock(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustBookStock", reflect.TypeOf((*MockBookSvc)(nil).AdjustBookStock), ctx, input)
+}
+
 // CreateBook mocks base method.
 func (m *MockBookSvc) CreateBook(ctx context.Context, input dto.CreateBookReq) dto.CreateBookRes {
 	m.ctrl.T.Helper()
@@ -50,6 +64,20 @@ func (mr *MockBookSvcMockRecorder) CreateBook(ctx, input interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBook", reflect.TypeOf((*MockBookSvc)(nil).CreateBook), ctx, input)
 }
 
+// DeleteBook mocks base method.
+func (m *MockBookSvc) DeleteBook(ctx context.Context, input dto.DeleteBookReq) dto.DeleteBookRes {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBook", ctx, input)
+	ret0, _ := ret[0].(dto.DeleteBookRes)
+	return ret0
+}
+
+// DeleteBook indicates an expected call of DeleteBook.
+func (mr *MockBookSvcMockRecorder) DeleteBook(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockBookSvc)(nil).DeleteBook), ctx, input)
+}
+
 // GetBook mocks base method.
 func (m *MockBookSvc) GetBook(ctx context.Context, input dto.GetBookReq) service.PaginationBookResp {
 	m.ctrl.T.Helper()
@@ -64,6 +92,20 @@ func (mr *MockBookSvcMockRecorder) GetBook(ctx, input interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBook", reflect.TypeOf((*MockBookSvc)(nil).GetBook), ctx, input)
 }
 
+// GetBookByID mocks base method.
+func (m *MockBookSvc) GetBookByID(ctx context.Context, input dto.GetBookByIDReq) dto.GetBookRes {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookByID", ctx, input)
+	ret0, _ := ret[0].(dto.GetBookRes)
+	return ret0
+}
+
+// GetBookByID indicates an expected call of GetBookByID.
+func (mr *MockBookSvcMockRecorder) GetBookByID(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByID", reflect.TypeOf((*MockBookSvc)(nil).GetBookByID), ctx, input)
+}
+
 // GetBookPuchasedByUser mocks base method.
 func (m *MockBookSvc) GetBookPuchasedByUser(ctx context.Context) []dto.GetBookPuchasedByUserRes {
 	m.ctrl.T.Helper()
@@ -77,3 +119,17 @@ func (mr *MockBookSvcMockRecorder) GetBookPuchasedByUser(ctx interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookPuchasedByUser", reflect.TypeOf((*MockBookSvc)(nil).GetBookPuchasedByUser), ctx)
 }
+
+// UpdateBook mocks base method.
+func (m *MockBookSvc) UpdateBook(ctx context.Context, input dto.PatchBookReq) dto.UpdateBookRes {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBook", ctx, input)
+	ret0, _ := ret[0].(dto.UpdateBookRes)
+	return ret0
+}
+
+// UpdateBook indicates an expected call of UpdateBook.
+func (mr *MockBookSvcMockRecorder) UpdateBook(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBook", reflect.TypeOf((*MockBookSvc)(nil).UpdateBook), ctx, input)
+}
@@ -35,6 +35,122 @@ func (m *MockUserSvc) EXPECT() *MockUserSvcMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockUserSvc) ChangePassword(ctx context.Context, input dto.ChangePasswordReq) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ChangePassword", ctx, input)
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUserSvcMockRecorder) ChangePassword(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUserSvc)(nil).ChangePassword), ctx, input)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockUserSvc) DeleteAccount(ctx context.Context, input dto.DeleteAccountReq) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteAccount", ctx, input)
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockUserSvcMockRecorder) DeleteAccount(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockUserSvc)(nil).DeleteAccount), ctx, input)
+}
+
+// EnrollTwoFactor mocks base method.
+func (m *MockUserSvc) EnrollTwoFactor(ctx context.Context) dto.EnrollTwoFactorRes {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTwoFactor", ctx)
+	ret0, _ := ret[0].(dto.EnrollTwoFactorRes)
+	return ret0
+}
+
+// EnrollTwoFactor indicates an expected call of EnrollTwoFactor.
+func (mr *MockUserSvcMockRecorder) EnrollTwoFactor(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTwoFactor", reflect.TypeOf((*MockUserSvc)(nil).EnrollTwoFactor), ctx)
+}
+
+// ExportData mocks base method.
+func (m *MockUserSvc) ExportData(ctx context.Context) dto.ExportDataRes {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportData", ctx)
+	ret0, _ := ret[0].(dto.ExportDataRes)
+	return ret0
+}
+
+// ExportData indicates an expected call of ExportData.
+func (mr *MockUserSvcMockRecorder) ExportData(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportData", reflect.TypeOf((*MockUserSvc)(nil).ExportData), ctx)
+}
+
+// ForgotPassword mocks base method.
+func (m *MockUserSvc) ForgotPassword(ctx context.Context, input dto.ForgotPasswordReq) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ForgotPassword", ctx, input)
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockUserSvcMockRecorder) ForgotPassword(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockUserSvc)(nil).ForgotPassword), ctx, input)
+}
+
+// GetProfile mocks base method.
+func (m *MockUserSvc) GetProfile(ctx context.Context) dto.ProfileRes {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", ctx)
+	ret0, _ := ret[0].(dto.ProfileRes)
+	return ret0
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockUserSvcMockRecorder) GetProfile(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockUserSvc)(nil).GetProfile), ctx)
+}
+
+// RefreshToken mocks base method.
+func (m *MockUserSvc) RefreshToken(ctx context.Context, input dto.RefreshTokenReq) dto.RefreshTokenRes {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", ctx, input)
+	ret0, _ := ret[0].(dto.RefreshTokenRes)
+	return ret0
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockUserSvcMockRecorder) RefreshToken(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockUserSvc)(nil).RefreshToken), ctx, input)
+}
+
+// ResendVerificationEmail mocks base method.
+func (m *MockUserSvc) ResendVerificationEmail(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ResendVerificationEmail", ctx)
+}
+
+// ResendVerificationEmail indicates an expected call of ResendVerificationEmail.
+func (mr *MockUserSvcMockRecorder) ResendVerificationEmail(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerificationEmail", reflect.TypeOf((*MockUserSvc)(nil).ResendVerificationEmail), ctx)
+}
+
+// ResetPassword mocks base method.
+func (m *MockUserSvc) ResetPassword(ctx context.Context, input dto.ResetPasswordReq) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ResetPassword", ctx, input)
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserSvcMockRecorder) ResetPassword(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserSvc)(nil).ResetPassword), ctx, input)
+}
+
 // SignIn mocks base method.
 func (m *MockUserSvc) SignIn(ctx context.Context, input dto.SignInReq) dto.SignInRes {
 	m.ctrl.T.Helper()
@@ -49,6 +165,44 @@ func (mr *MockUserSvcMockRecorder) SignIn(ctx, input interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignIn", reflect.TypeOf((*MockUserSvc)(nil).SignIn), ctx, input)
 }
 
+// SignInTwoFactor mocks base method.
+func (m *MockUserSvc) SignInTwoFactor(ctx context.Context, input dto.SignInTwoFactorReq) dto.SignInRes {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignInTwoFactor", ctx, input)
+	ret0, _ := ret[0].(dto.SignInRes)
+	return ret0
+}
+
+// SignInTwoFactor indicates an expected call of SignInTwoFactor.
+func (mr *MockUserSvcMockRecorder) SignInTwoFactor(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignInTwoFactor", reflect.TypeOf((*MockUserSvc)(nil).SignInTwoFactor), ctx, input)
+}
+
+// SignOut mocks base method.
+func (m *MockUserSvc) SignOut(ctx context.Context, input dto.SignOutReq) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SignOut", ctx, input)
+}
+
+// SignOut indicates an expected call of SignOut.
+func (mr *MockUserSvcMockRecorder) SignOut(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignOut", reflect.TypeOf((*MockUserSvc)(nil).SignOut), ctx, input)
+}
+
+// SignOutAll mocks base method.
+func (m *MockUserSvc) SignOutAll(ctx context.Context, input dto.SignOutAllReq) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SignOutAll", ctx, input)
+}
+
+// SignOutAll indicates an expected call of SignOutAll.
+func (mr *MockUserSvcMockRecorder) SignOutAll(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignOutAll", reflect.TypeOf((*MockUserSvc)(nil).SignOutAll), ctx, input)
+}
+
 // SignUp mocks base method.
 func (m *MockUserSvc) SignUp(ctx context.Context, input dto.SignUpReq) dto.SignUpRes {
 	m.ctrl.T.Helper()
@@ -62,3 +216,43 @@ func (mr *MockUserSvcMockRecorder) SignUp(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignUp", reflect.TypeOf((*MockUserSvc)(nil).SignUp), ctx, input)
 }
+
+// UpdateProfile mocks base method.
+func (m *MockUserSvc) UpdateProfile(ctx context.Context, input dto.UpdateProfileReq) dto.ProfileRes {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, input)
+	ret0, _ := ret[0].(dto.ProfileRes)
+	return ret0
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockUserSvcMockRecorder) UpdateProfile(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockUserSvc)(nil).UpdateProfile), ctx, input)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockUserSvc) VerifyEmail(ctx context.Context, input dto.VerifyEmailReq) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "VerifyEmail", ctx, input)
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockUserSvcMockRecorder) VerifyEmail(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockUserSvc)(nil).VerifyEmail), ctx, input)
+}
+
+// VerifyTwoFactor mocks base method.
+func (m *MockUserSvc) VerifyTwoFactor(ctx context.Context, input dto.VerifyTwoFactorReq) dto.VerifyTwoFactorRes {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTwoFactor", ctx, input)
+	ret0, _ := ret[0].(dto.VerifyTwoFactorRes)
+	return ret0
+}
+
+// VerifyTwoFactor indicates an expected call of VerifyTwoFactor.
+func (mr *MockUserSvcMockRecorder) VerifyTwoFactor(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTwoFactor", reflect.TypeOf((*MockUserSvc)(nil).VerifyTwoFactor), ctx, input)
+}
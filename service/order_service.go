@@ -2,16 +2,25 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/gadhittana-01/book-go/constant"
 	querier "github.com/gadhittana-01/book-go/db/repository"
 	"github.com/gadhittana-01/book-go/dto"
+	"github.com/gadhittana-01/book-go/metrics"
 	utilsConstant "github.com/gadhittana01/go-modules/constant"
 	"github.com/gadhittana01/go-modules/utils"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -27,8 +36,15 @@ const (
 	OrderNotExists            = "Order doesn't exists"
 	InvalidBookID             = "BookID must UUID and cannot be empty"
 	InvalidQuantity           = "Quantity must greater than zero"
+	OutOfStock                = "Not enough stock for book %s"
+	FailedToFindIdempotency   = "Failed to find idempotency key"
+	FailedToSaveIdempotency   = "Failed to save idempotency key"
+	IdempotencyKeyReused      = "Idempotency key was already used with a different request"
 )
 
+// uniqueViolationCode is the Postgres SQLSTATE for unique_violation.
+const uniqueViolationCode = "23505"
+
 type (
 	PaginationOrderResp = dto.PaginationResp[dto.GetOrderRes]
 )
@@ -60,86 +76,282 @@ func NewOrderSvc(
 func (s *OrderSvcImpl) CreateOrder(ctx context.Context, input dto.CreateOrderReq) dto.CreateOrderRes {
 	var resp dto.CreateOrderRes
 	var order querier.Order
-	var totalPrice float64
 	now := time.Now()
 	authPayload := utils.GetRequestCtx(ctx, utilsConstant.UserSession)
 
 	userID, err := uuid.Parse(authPayload.UserID)
 	utils.PanicIfAppError(err, FailedToParseStringToUUID, 400)
 
+	var requestHash string
+	if input.IdempotencyKey != "" {
+		requestHash = hashOrderRequest(input)
+		if resp, ok := s.findIdempotentOrder(ctx, userID, input.IdempotencyKey, requestHash); ok {
+			return resp
+		}
+	}
+
 	err = utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
 		repoTx := s.repo.WithTx(tx)
 
-		order, err = repoTx.CreateOrder(ctx, querier.CreateOrderParams{
-			UserID: userID,
-			Date:   now,
-		})
+		items, err := mergeOrderItems(input.OrderDetail)
+		if err != nil {
+			return err
+		}
+
+		order, err = placeOrder(ctx, repoTx, userID, items, now)
 		if err != nil {
-			return utils.CustomErrorWithTrace(err, FailedToCreateOrder, 422)
+			return err
+		}
+
+		resp = dto.CreateOrderRes{
+			OrderId:    order.ID.String(),
+			Date:       order.Date.Format(constant.TimeFormat),
+			TotalPrice: order.TotalPrice,
+			Status:     order.Status,
 		}
 
-		for _, item := range input.OrderDetail {
-			if item.BookID == "" {
-				return utils.CustomError(InvalidBookID, 400)
-			}
+		if input.IdempotencyKey != "" {
+			return saveIdempotentOrder(ctx, repoTx, userID, input.IdempotencyKey, requestHash, resp)
+		}
 
-			if item.Quantity <= 0 {
-				return utils.CustomError(InvalidQuantity, 400)
-			}
+		return nil
+	})
+	// a concurrent retry committed the same key first, so replay its order
+	if isUniqueViolation(err) {
+		if resp, ok := s.findIdempotentOrder(ctx, userID, input.IdempotencyKey, requestHash); ok {
+			return resp
+		}
+	}
+	utils.PanicIfError(err)
+	s.cacheSvc.ClearCaches([]string{constant.OrderCacheKey}, authPayload.UserID)
+	s.cacheSvc.ClearCaches([]string{constant.BookCacheKey}, "")
+	metrics.RecordOrderCreated(constant.OrderSourceAPI, order.TotalPrice)
 
-			bookID, err := uuid.Parse(item.BookID)
-			if err != nil {
-				return utils.CustomErrorWithTrace(err, FailedToParseStringToUUID, 400)
-			}
+	return resp
+}
 
-			isExists, err := repoTx.CheckBookExists(ctx, bookID)
-			if err != nil {
-				return utils.CustomErrorWithTrace(err, FailedToCheckBookExists, 400)
-			}
+// findIdempotentOrder returns the order stored for a previously used
+// idempotency key. Reusing a key for a different payload is rejected.
+func (s *OrderSvcImpl) findIdempotentOrder(
+	ctx context.Context,
+	userID uuid.UUID,
+	key string,
+	requestHash string,
+) (dto.CreateOrderRes, bool) {
+	var resp dto.CreateOrderRes
 
-			if !isExists {
-				return utils.CustomError(BookNotExists, 400)
-			}
+	idempotencyKey, err := s.repo.FindIdempotencyKey(ctx, querier.FindIdempotencyKeyParams{
+		UserID: userID,
+		Key:    key,
+	})
+	if err == pgx.ErrNoRows {
+		return resp, false
+	}
+	utils.PanicIfAppError(err, FailedToFindIdempotency, 400)
 
-			_, err = repoTx.CreateOrderDetail(ctx, querier.CreateOrderDetailParams{
-				OrderID:  order.ID,
-				BookID:   bookID,
-				Quantity: int32(item.Quantity),
-			})
-			if err != nil {
-				return utils.CustomErrorWithTrace(err, FailedToCreateOrderDetail, 422)
-			}
+	if idempotencyKey.RequestHash != requestHash {
+		utils.PanicAppError(IdempotencyKeyReused, 422)
+	}
+
+	err = json.Unmarshal(idempotencyKey.Response, &resp)
+	utils.PanicIfAppError(err, FailedToFindIdempotency, 400)
+
+	return resp, true
+}
+
+func saveIdempotentOrder(
+	ctx context.Context,
+	repoTx querier.Querier,
+	userID uuid.UUID,
+	key string,
+	requestHash string,
+	resp dto.CreateOrderRes,
+) error {
+	response, err := json.Marshal(resp)
+	if err != nil {
+		return utils.CustomErrorWithTrace(err, FailedToSaveIdempotency, 422)
+	}
+
+	_, err = repoTx.CreateIdempotencyKey(ctx, querier.CreateIdempotencyKeyParams{
+		UserID:      userID,
+		Key:         key,
+		RequestHash: requestHash,
+		Response:    response,
+	})
+	if isUniqueViolation(err) {
+		return err
+	}
+
+	if err != nil {
+		return utils.CustomErrorWithTrace(err, FailedToSaveIdempotency, 422)
+	}
+
+	return nil
+}
+
+// hashOrderRequest fingerprints the order lines so a replayed idempotency
+// key can be checked against the payload it was first used with.
+func hashOrderRequest(input dto.CreateOrderReq) string {
+	payload, _ := json.Marshal(input.OrderDetail)
+	sum := sha256.Sum256(payload)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
+
+// placeOrder creates an order for merged items inside the caller's
+// transaction. It is shared by CreateOrder and cart checkout.
+func placeOrder(
+	ctx context.Context,
+	repoTx querier.Querier,
+	userID uuid.UUID,
+	items []orderItem,
+	date time.Time,
+) (querier.Order, error) {
+	bookIDs := lo.Map(items, func(item orderItem, _ int) uuid.UUID { return item.bookID })
+	quantities := lo.Map(items, func(item orderItem, _ int) int32 { return item.quantity })
+
+	order, err := repoTx.CreateOrder(ctx, querier.CreateOrderParams{
+		UserID: userID,
+		Date:   date,
+	})
+	if err != nil {
+		return querier.Order{}, utils.CustomErrorWithTrace(err, FailedToCreateOrder, 422)
+	}
+
+	// the conditional update only matches books that still have enough
+	// stock, so concurrent orders can never drive it below zero
+	reserved, err := repoTx.ReserveBooksStock(ctx, querier.ReserveBooksStockParams{
+		Ids:        bookIDs,
+		Quantities: quantities,
+	})
+	if err != nil {
+		return querier.Order{}, utils.CustomErrorWithTrace(err, FailedToAdjustBookStock, 422)
+	}
+
+	reservedByID := lo.KeyBy(reserved, func(book querier.Book) uuid.UUID { return book.ID })
+	if len(reservedByID) != len(items) {
+		return querier.Order{}, unreservedBookError(ctx, repoTx, items, reservedByID)
+	}
+
+	err = repoTx.CreateStockMovements(ctx, querier.CreateStockMovementsParams{
+		BookIds: bookIDs,
+		Quantities: lo.Map(quantities, func(quantity int32, _ int) int32 {
+			return -quantity
+		}),
+		Reason:    constant.StockReasonOrderPlaced,
+		CreatedBy: userID,
+	})
+	if err != nil {
+		return querier.Order{}, utils.CustomErrorWithTrace(err, FailedToCreateStockMovement, 422)
+	}
+
+	// price, title and author are copied onto the lines so later catalog
+	// edits never change what this order shows
+	details := querier.CreateOrderDetailsParams{
+		OrderID:    order.ID,
+		BookIds:    bookIDs,
+		Quantities: quantities,
+	}
+	totalPrice := decimal.Zero
+	for _, item := range items {
+		book := reservedByID[item.bookID]
+		lineTotal := book.Price.Mul(decimal.NewFromInt32(item.quantity))
+
+		details.UnitPrices = append(details.UnitPrices, book.Price)
+		details.LineTotals = append(details.LineTotals, lineTotal)
+		details.Titles = append(details.Titles, book.Title)
+		details.Authors = append(details.Authors, book.Author)
+		totalPrice = totalPrice.Add(lineTotal)
+	}
+
+	err = repoTx.CreateOrderDetails(ctx, details)
+	if err != nil {
+		return querier.Order{}, utils.CustomErrorWithTrace(err, FailedToCreateOrderDetail, 422)
+	}
+
+	order, err = repoTx.UpdateOrderByID(ctx, querier.UpdateOrderByIDParams{
+		ID:         order.ID,
+		TotalPrice: totalPrice,
+	})
+	if err != nil {
+		return querier.Order{}, utils.CustomErrorWithTrace(err, FailedToUpdateOrder, 422)
+	}
+
+	return order, nil
+}
+
+// unreservedBookError looks the books up only once a reservation came up
+// short, to tell a book that doesn't exist apart from one out of stock.
+func unreservedBookError(
+	ctx context.Context,
+	repoTx querier.Querier,
+	items []orderItem,
+	reservedByID map[uuid.UUID]querier.Book,
+) error {
+	bookIDs := lo.Map(items, func(item orderItem, _ int) uuid.UUID { return item.bookID })
+
+	books, err := repoTx.FindBooksByIDs(ctx, bookIDs)
+	if err != nil {
+		return utils.CustomErrorWithTrace(err, FailedToFindBookByID, 400)
+	}
 
-			book, err := repoTx.FindBookByID(ctx, bookID)
-			if err != nil {
-				return utils.CustomErrorWithTrace(err, FailedToFindBookByID, 400)
-			}
+	if len(books) != len(items) {
+		return utils.CustomError(BookNotExists, 400)
+	}
 
-			itemPrice := book.Price * float64(item.Quantity)
-			totalPrice += itemPrice
+	for _, item := range items {
+		if _, ok := reservedByID[item.bookID]; !ok {
+			return utils.CustomError(fmt.Sprintf(OutOfStock, item.bookID.String()), 409)
 		}
+	}
 
-		order, err = repoTx.UpdateOrderByID(ctx, querier.UpdateOrderByIDParams{
-			ID:         order.ID,
-			TotalPrice: totalPrice,
-		})
+	return nil
+}
+
+type orderItem struct {
+	bookID   uuid.UUID
+	quantity int32
+}
+
+// mergeOrderItems validates the requested lines and folds repeated book IDs
+// into a single line, keeping the order in which each book first appeared.
+func mergeOrderItems(details []dto.OrderDetailReq) ([]orderItem, error) {
+	var items []orderItem
+	indexByID := make(map[uuid.UUID]int)
+
+	for _, detail := range details {
+		if detail.BookID == "" {
+			return nil, utils.CustomError(InvalidBookID, 400)
+		}
+
+		if detail.Quantity <= 0 {
+			return nil, utils.CustomError(InvalidQuantity, 400)
+		}
+
+		bookID, err := uuid.Parse(detail.BookID)
 		if err != nil {
-			return utils.CustomErrorWithTrace(err, FailedToUpdateOrder, 422)
+			return nil, utils.CustomErrorWithTrace(err, FailedToParseStringToUUID, 400)
 		}
 
-		return nil
-	})
-	utils.PanicIfError(err)
-	s.cacheSvc.ClearCaches([]string{constant.OrderCacheKey}, authPayload.UserID)
+		if i, ok := indexByID[bookID]; ok {
+			items[i].quantity += int32(detail.Quantity)
+			continue
+		}
 
-	resp = dto.CreateOrderRes{
-		OrderId:    order.ID.String(),
-		Date:       order.Date.Format(constant.TimeFormat),
-		TotalPrice: order.TotalPrice,
-		Status:     order.Status,
+		indexByID[bookID] = len(items)
+		items = append(items, orderItem{
+			bookID:   bookID,
+			quantity: int32(detail.Quantity),
+		})
 	}
 
-	return resp
+	return items, nil
 }
 
 func (s *OrderSvcImpl) GetOrder(ctx context.Context, input dto.GetOrderReq) dto.PaginationResp[dto.GetOrderRes] {
@@ -148,8 +360,12 @@ func (s *OrderSvcImpl) GetOrder(ctx context.Context, input dto.GetOrderReq) dto.
 	userID, err := uuid.Parse(authPayload.UserID)
 	utils.PanicIfAppError(err, FailedToParseStringToUUID, 400)
 
-	resp, err := utils.GetOrSetData(s.cacheSvc, utils.BuildCacheKey(constant.OrderCacheKey,
+	resp, err := getOrSetData(ctx, s.cacheSvc, constant.OrderCacheKey, utils.BuildCacheKey(constant.OrderCacheKey,
 		authPayload.UserID, "GetOrder", input), func() (dto.PaginationResp[dto.GetOrderRes], error) {
+		if input.CursorMode {
+			return s.findOrderByCursor(ctx, userID, input)
+		}
+
 		ewg := errgroup.Group{}
 		var err1 error
 		var err2 error
@@ -176,12 +392,7 @@ func (s *OrderSvcImpl) GetOrder(ctx context.Context, input dto.GetOrderReq) dto.
 		}
 
 		return dto.ToPaginationResp(lo.Map(orders, func(item querier.Order, index int) dto.GetOrderRes {
-			return dto.GetOrderRes{
-				OrderId:    item.ID.String(),
-				Date:       item.Date.Format(constant.TimeFormat),
-				TotalPrice: item.TotalPrice,
-				Status:     item.Status,
-			}
+			return toGetOrderRes(item)
 		}), int(input.Page), int(input.Limit), int(count)), nil
 	})
 	utils.PanicIfError(err)
@@ -189,13 +400,79 @@ func (s *OrderSvcImpl) GetOrder(ctx context.Context, input dto.GetOrderReq) dto.
 	return resp
 }
 
+// findOrderByCursor pages through the user's orders by (date, id) without
+// OFFSET. The count only runs when the client asks for it.
+func (s *OrderSvcImpl) findOrderByCursor(
+	ctx context.Context,
+	userID uuid.UUID,
+	input dto.GetOrderReq,
+) (dto.PaginationResp[dto.GetOrderRes], error) {
+	ewg := errgroup.Group{}
+	var err1 error
+	var err2 error
+	var orders []querier.Order
+	count := int64(-1)
+
+	ewg.Go(func() error {
+		if input.Cursor.Backward {
+			orders, err1 = s.repo.FindOrderByUserIDBeforeCursor(ctx, querier.FindOrderByUserIDBeforeCursorParams{
+				UserID:     userID,
+				CursorDate: input.Cursor.Time,
+				CursorID:   input.Cursor.ID,
+				Limit:      input.Limit + 1,
+			})
+			return err1
+		}
+
+		orders, err1 = s.repo.FindOrderByUserIDAfterCursor(ctx, querier.FindOrderByUserIDAfterCursorParams{
+			UserID: userID,
+			CursorDate: sql.NullTime{
+				Time:  input.Cursor.Time,
+				Valid: !input.Cursor.IsZero(),
+			},
+			CursorID: uuid.NullUUID{
+				UUID:  input.Cursor.ID,
+				Valid: !input.Cursor.IsZero(),
+			},
+			Limit: input.Limit + 1,
+		})
+		return err1
+	})
+
+	if input.WithTotal {
+		ewg.Go(func() error {
+			count, err2 = s.repo.GetOrderCountByUserId(ctx, userID)
+			return err2
+		})
+	}
+
+	if err := ewg.Wait(); err != nil {
+		return dto.PaginationResp[dto.GetOrderRes]{}, utils.CustomErrorWithTrace(err,
+			FailedToGetOrder, 400)
+	}
+
+	return dto.ToCursorPaginationResp(orders, int(input.Limit), input.Cursor, int(count),
+		func(item querier.Order) dto.Cursor {
+			return dto.Cursor{Time: item.Date, ID: item.ID}
+		}, toGetOrderRes), nil
+}
+
+func toGetOrderRes(item querier.Order) dto.GetOrderRes {
+	return dto.GetOrderRes{
+		OrderId:    item.ID.String(),
+		Date:       item.Date.Format(constant.TimeFormat),
+		TotalPrice: item.TotalPrice,
+		Status:     item.Status,
+	}
+}
+
 func (s *OrderSvcImpl) GetOrderDetail(ctx context.Context, input dto.GetOrderDetailReq) dto.GetOrderDetailRes {
 	authPayload := utils.GetRequestCtx(ctx, utilsConstant.UserSession)
 
 	userID, err := uuid.Parse(authPayload.UserID)
 	utils.PanicIfAppError(err, FailedToParseStringToUUID, 400)
 
-	resp, err := utils.GetOrSetData(s.cacheSvc, utils.BuildCacheKey(constant.OrderCacheKey,
+	resp, err := getOrSetData(ctx, s.cacheSvc, constant.OrderCacheKey, utils.BuildCacheKey(constant.OrderCacheKey,
 		authPayload.UserID, "GetOrderDetail", input), func() (dto.GetOrderDetailRes, error) {
 
 		isExists, err := s.repo.CheckOrderExists(ctx, input.OrderID)
@@ -236,6 +513,8 @@ func (s *OrderSvcImpl) GetOrderDetail(ctx context.Context, input dto.GetOrderDet
 					Description:   item.Description,
 					Author:        item.Author,
 					Quantity:      int(item.Quantity),
+					UnitPrice:     item.UnitPrice,
+					LineTotal:     item.LineTotal,
 				}
 			}),
 		}, nil
@@ -1,10 +1,13 @@
 package service
 
 import (
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis"
 	"github.com/gadhittana-01/book-go/constant"
 	querier "github.com/gadhittana-01/book-go/db/repository"
 	mockrepo "github.com/gadhittana-01/book-go/db/repository/mock"
@@ -12,6 +15,10 @@ import (
 	"github.com/gadhittana01/go-modules/utils"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -43,6 +50,18 @@ func TestCreateOrder(t *testing.T) {
 			},
 		},
 	}
+	duplicateBookReq := dto.CreateOrderReq{
+		OrderDetail: []dto.OrderDetailReq{
+			{
+				BookID:   bookID.String(),
+				Quantity: 4,
+			},
+			{
+				BookID:   bookID.String(),
+				Quantity: 6,
+			},
+		},
+	}
 	invalidBookIDReq := dto.CreateOrderReq{
 		OrderDetail: []dto.OrderDetailReq{
 			{
@@ -68,14 +87,52 @@ func TestCreateOrder(t *testing.T) {
 		},
 	}
 	now := time.Now()
-	totalPrice := float64(100)
+	totalPrice := decimal.NewFromInt(100)
 	orderID := uuid.New()
 	status := "pending"
-	orderDetailID := uuid.New()
 	title := "Hello"
 	description := "World"
 	author := "Giri Putra Adhittana"
-	price := float64(10)
+	price := decimal.NewFromInt(10)
+	stock := int32(5)
+
+	order := querier.Order{
+		ID:        orderID,
+		UserID:    userID,
+		Date:      now,
+		Status:    status,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	book := querier.Book{
+		ID:          bookID,
+		Title:       title,
+		Description: description,
+		Author:      author,
+		Price:       price,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Stock:       stock,
+	}
+	reserveParams := querier.ReserveBooksStockParams{
+		Ids:        []uuid.UUID{bookID},
+		Quantities: []int32{int32(quantity)},
+	}
+	stockMovementsParams := querier.CreateStockMovementsParams{
+		BookIds:    []uuid.UUID{bookID},
+		Quantities: []int32{-int32(quantity)},
+		Reason:     constant.StockReasonOrderPlaced,
+		CreatedBy:  userID,
+	}
+	orderDetailsParams := querier.CreateOrderDetailsParams{
+		OrderID:    orderID,
+		BookIds:    []uuid.UUID{bookID},
+		Quantities: []int32{int32(quantity)},
+		UnitPrices: []decimal.Decimal{price},
+		LineTotals: []decimal.Decimal{totalPrice},
+		Titles:     []string{title},
+		Authors:    []string{author},
+	}
 
 	t.Run("success create order", func(t *testing.T) {
 		mockrepo.SetupMockTxPool(ctrl, mockRepo)
@@ -83,41 +140,11 @@ func TestCreateOrder(t *testing.T) {
 		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateOrderParams{})).DoAndReturn(func(_ any, params querier.CreateOrderParams) (querier.Order, error) {
 			assert.Equal(t, userID, params.UserID)
 
-			return querier.Order{
-				ID:        orderID,
-				UserID:    userID,
-				Date:      now,
-				Status:    status,
-				CreatedAt: now,
-				UpdatedAt: now,
-			}, nil
+			return order, nil
 		}).Times(1)
-
-		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(true, nil).Times(1)
-
-		mockRepo.EXPECT().CreateOrderDetail(gomock.Any(), querier.CreateOrderDetailParams{
-			OrderID:  orderID,
-			BookID:   bookID,
-			Quantity: int32(quantity),
-		}).Return(querier.OrderDetail{
-			ID:        orderDetailID,
-			OrderID:   orderID,
-			BookID:    bookID,
-			Quantity:  int32(quantity),
-			CreatedAt: now,
-			UpdatedAt: now,
-		}, nil).Times(1)
-
-		mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(querier.Book{
-			ID:          bookID,
-			Title:       title,
-			Description: description,
-			Author:      author,
-			Price:       price,
-			CreatedAt:   now,
-			UpdatedAt:   now,
-		}, nil).Times(1)
-
+		mockRepo.EXPECT().ReserveBooksStock(gomock.Any(), reserveParams).Return([]querier.Book{book}, nil).Times(1)
+		mockRepo.EXPECT().CreateStockMovements(gomock.Any(), stockMovementsParams).Return(nil).Times(1)
+		mockRepo.EXPECT().CreateOrderDetails(gomock.Any(), orderDetailsParams).Return(nil).Times(1)
 		mockRepo.EXPECT().UpdateOrderByID(gomock.Any(), querier.UpdateOrderByIDParams{
 			ID:         orderID,
 			TotalPrice: totalPrice,
@@ -142,59 +169,81 @@ func TestCreateOrder(t *testing.T) {
 		}, resp)
 	})
 
-	t.Run("failed update order by ID", func(t *testing.T) {
-		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+	t.Run("success merge duplicate book IDs", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
 
-		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateOrderParams{})).DoAndReturn(func(_ any, params querier.CreateOrderParams) (querier.Order, error) {
-			assert.Equal(t, userID, params.UserID)
+		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(order, nil).Times(1)
+		mockRepo.EXPECT().ReserveBooksStock(gomock.Any(), reserveParams).Return([]querier.Book{book}, nil).Times(1)
+		mockRepo.EXPECT().CreateStockMovements(gomock.Any(), stockMovementsParams).Return(nil).Times(1)
+		mockRepo.EXPECT().CreateOrderDetails(gomock.Any(), orderDetailsParams).Return(nil).Times(1)
+		mockRepo.EXPECT().UpdateOrderByID(gomock.Any(), querier.UpdateOrderByIDParams{
+			ID:         orderID,
+			TotalPrice: totalPrice,
+		}).Return(querier.Order{
+			ID:         orderID,
+			UserID:     userID,
+			Date:       now,
+			TotalPrice: totalPrice,
+			Status:     status,
+		}, nil).Times(1)
 
-			return querier.Order{
-				ID:        orderID,
-				UserID:    userID,
-				Date:      now,
-				Status:    status,
-				CreatedAt: now,
-				UpdatedAt: now,
-			}, nil
-		}).Times(1)
+		resp := orderSvcMock.CreateOrder(ctx, duplicateBookReq)
 
-		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(true, nil).Times(1)
-
-		mockRepo.EXPECT().CreateOrderDetail(gomock.Any(), querier.CreateOrderDetailParams{
-			OrderID:  orderID,
-			BookID:   bookID,
-			Quantity: int32(quantity),
-		}).Return(querier.OrderDetail{
-			ID:        orderDetailID,
-			OrderID:   orderID,
-			BookID:    bookID,
-			Quantity:  int32(quantity),
-			CreatedAt: now,
-			UpdatedAt: now,
-		}, nil).Times(1)
+		assert.Equal(t, totalPrice, resp.TotalPrice)
+	})
 
-		mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(querier.Book{
-			ID:          bookID,
-			Title:       title,
-			Description: description,
-			Author:      author,
-			Price:       price,
-			CreatedAt:   now,
-			UpdatedAt:   now,
-		}, nil).Times(1)
+	t.Run("success create order with exact fractional total", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
 
+		fractionalPrice := decimal.RequireFromString("0.1")
+		fractionalTotal := decimal.RequireFromString("0.3")
+		fractionalBook := book
+		fractionalBook.Price = fractionalPrice
+		fractionalReq := dto.CreateOrderReq{
+			OrderDetail: []dto.OrderDetailReq{
+				{
+					BookID:   bookID.String(),
+					Quantity: 3,
+				},
+			},
+		}
+
+		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(order, nil).Times(1)
+		mockRepo.EXPECT().ReserveBooksStock(gomock.Any(), gomock.Any()).Return([]querier.Book{fractionalBook}, nil).Times(1)
+		mockRepo.EXPECT().CreateStockMovements(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockRepo.EXPECT().CreateOrderDetails(gomock.Any(), querier.CreateOrderDetailsParams{
+			OrderID:    orderID,
+			BookIds:    []uuid.UUID{bookID},
+			Quantities: []int32{3},
+			UnitPrices: []decimal.Decimal{fractionalPrice},
+			LineTotals: []decimal.Decimal{fractionalTotal},
+			Titles:     []string{title},
+			Authors:    []string{author},
+		}).Return(nil).Times(1)
 		mockRepo.EXPECT().UpdateOrderByID(gomock.Any(), querier.UpdateOrderByIDParams{
 			ID:         orderID,
-			TotalPrice: totalPrice,
+			TotalPrice: fractionalTotal,
 		}).Return(querier.Order{
 			ID:         orderID,
 			UserID:     userID,
 			Date:       now,
-			TotalPrice: totalPrice,
+			TotalPrice: fractionalTotal,
 			Status:     status,
-			CreatedAt:  now,
-			UpdatedAt:  now,
-		}, errInvalidReq).Times(1)
+		}, nil).Times(1)
+
+		resp := orderSvcMock.CreateOrder(ctx, fractionalReq)
+
+		assert.Equal(t, "0.3", resp.TotalPrice.String())
+	})
+
+	t.Run("failed update order by ID", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(order, nil).Times(1)
+		mockRepo.EXPECT().ReserveBooksStock(gomock.Any(), reserveParams).Return([]querier.Book{book}, nil).Times(1)
+		mockRepo.EXPECT().CreateStockMovements(gomock.Any(), stockMovementsParams).Return(nil).Times(1)
+		mockRepo.EXPECT().CreateOrderDetails(gomock.Any(), orderDetailsParams).Return(nil).Times(1)
+		mockRepo.EXPECT().UpdateOrderByID(gomock.Any(), gomock.Any()).Return(querier.Order{}, errInvalidReq).Times(1)
 
 		assert.PanicsWithValue(t, utils.AppError{
 			StatusCode: 422,
@@ -205,126 +254,65 @@ func TestCreateOrder(t *testing.T) {
 		})
 	})
 
-	t.Run("failed to find book by ID", func(t *testing.T) {
+	t.Run("failed to create order details", func(t *testing.T) {
 		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
 
-		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateOrderParams{})).DoAndReturn(func(_ any, params querier.CreateOrderParams) (querier.Order, error) {
-			assert.Equal(t, userID, params.UserID)
+		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(order, nil).Times(1)
+		mockRepo.EXPECT().ReserveBooksStock(gomock.Any(), reserveParams).Return([]querier.Book{book}, nil).Times(1)
+		mockRepo.EXPECT().CreateStockMovements(gomock.Any(), stockMovementsParams).Return(nil).Times(1)
+		mockRepo.EXPECT().CreateOrderDetails(gomock.Any(), orderDetailsParams).Return(errInvalidReq).Times(1)
 
-			return querier.Order{
-				ID:        orderID,
-				UserID:    userID,
-				Date:      now,
-				Status:    status,
-				CreatedAt: now,
-				UpdatedAt: now,
-			}, nil
-		}).Times(1)
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToCreateOrderDetail),
+		}, func() {
+			resp := orderSvcMock.CreateOrder(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
 
-		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(true, nil).Times(1)
-
-		mockRepo.EXPECT().CreateOrderDetail(gomock.Any(), querier.CreateOrderDetailParams{
-			OrderID:  orderID,
-			BookID:   bookID,
-			Quantity: int32(quantity),
-		}).Return(querier.OrderDetail{
-			ID:        orderDetailID,
-			OrderID:   orderID,
-			BookID:    bookID,
-			Quantity:  int32(quantity),
-			CreatedAt: now,
-			UpdatedAt: now,
-		}, nil).Times(1)
+	t.Run("failed to create stock movements", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
 
-		mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(querier.Book{
-			ID:          bookID,
-			Title:       title,
-			Description: description,
-			Author:      author,
-			Price:       price,
-			CreatedAt:   now,
-			UpdatedAt:   now,
-		}, errInvalidReq).Times(1)
-
-		mockRepo.EXPECT().UpdateOrderByID(gomock.Any(), querier.UpdateOrderByIDParams{
-			ID:         orderID,
-			TotalPrice: totalPrice,
-		}).Return(querier.Order{
-			ID:         orderID,
-			UserID:     userID,
-			Date:       now,
-			TotalPrice: totalPrice,
-			Status:     status,
-			CreatedAt:  now,
-			UpdatedAt:  now,
-		}, errInvalidReq).Times(0)
+		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(order, nil).Times(1)
+		mockRepo.EXPECT().ReserveBooksStock(gomock.Any(), reserveParams).Return([]querier.Book{book}, nil).Times(1)
+		mockRepo.EXPECT().CreateStockMovements(gomock.Any(), stockMovementsParams).Return(errInvalidReq).Times(1)
 
 		assert.PanicsWithValue(t, utils.AppError{
-			StatusCode: 400,
-			Message:    fmt.Sprintf("invalid request|%s", FailedToFindBookByID),
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToCreateStockMovement),
 		}, func() {
 			resp := orderSvcMock.CreateOrder(ctx, req)
 			assert.Empty(t, resp)
 		})
 	})
 
-	t.Run("failed to create order detail", func(t *testing.T) {
+	t.Run("out of stock", func(t *testing.T) {
 		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
 
-		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateOrderParams{})).DoAndReturn(func(_ any, params querier.CreateOrderParams) (querier.Order, error) {
-			assert.Equal(t, userID, params.UserID)
-
-			return querier.Order{
-				ID:        orderID,
-				UserID:    userID,
-				Date:      now,
-				Status:    status,
-				CreatedAt: now,
-				UpdatedAt: now,
-			}, nil
-		}).Times(1)
+		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(order, nil).Times(1)
+		mockRepo.EXPECT().ReserveBooksStock(gomock.Any(), reserveParams).Return([]querier.Book{}, nil).Times(1)
+		mockRepo.EXPECT().FindBooksByIDs(gomock.Any(), []uuid.UUID{bookID}).Return([]querier.Book{book}, nil).Times(1)
 
-		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(true, nil).Times(1)
-
-		mockRepo.EXPECT().CreateOrderDetail(gomock.Any(), querier.CreateOrderDetailParams{
-			OrderID:  orderID,
-			BookID:   bookID,
-			Quantity: int32(quantity),
-		}).Return(querier.OrderDetail{
-			ID:        orderDetailID,
-			OrderID:   orderID,
-			BookID:    bookID,
-			Quantity:  int32(quantity),
-			CreatedAt: now,
-			UpdatedAt: now,
-		}, errInvalidReq).Times(1)
+		message := fmt.Sprintf(OutOfStock, bookID.String())
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 409,
+			Message:    fmt.Sprintf("%s|%s", message, message),
+		}, func() {
+			resp := orderSvcMock.CreateOrder(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
 
-		mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(querier.Book{
-			ID:          bookID,
-			Title:       title,
-			Description: description,
-			Author:      author,
-			Price:       price,
-			CreatedAt:   now,
-			UpdatedAt:   now,
-		}, errInvalidReq).Times(0)
+	t.Run("failed to reserve book stock", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
 
-		mockRepo.EXPECT().UpdateOrderByID(gomock.Any(), querier.UpdateOrderByIDParams{
-			ID:         orderID,
-			TotalPrice: totalPrice,
-		}).Return(querier.Order{
-			ID:         orderID,
-			UserID:     userID,
-			Date:       now,
-			TotalPrice: totalPrice,
-			Status:     status,
-			CreatedAt:  now,
-			UpdatedAt:  now,
-		}, errInvalidReq).Times(0)
+		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(order, nil).Times(1)
+		mockRepo.EXPECT().ReserveBooksStock(gomock.Any(), reserveParams).Return(nil, errInvalidReq).Times(1)
 
 		assert.PanicsWithValue(t, utils.AppError{
 			StatusCode: 422,
-			Message:    fmt.Sprintf("invalid request|%s", FailedToCreateOrderDetail),
+			Message:    fmt.Sprintf("invalid request|%s", FailedToAdjustBookStock),
 		}, func() {
 			resp := orderSvcMock.CreateOrder(ctx, req)
 			assert.Empty(t, resp)
@@ -334,56 +322,9 @@ func TestCreateOrder(t *testing.T) {
 	t.Run("book not exists", func(t *testing.T) {
 		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
 
-		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateOrderParams{})).DoAndReturn(func(_ any, params querier.CreateOrderParams) (querier.Order, error) {
-			assert.Equal(t, userID, params.UserID)
-
-			return querier.Order{
-				ID:        orderID,
-				UserID:    userID,
-				Date:      now,
-				Status:    status,
-				CreatedAt: now,
-				UpdatedAt: now,
-			}, nil
-		}).Times(1)
-
-		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(false, nil).Times(1)
-
-		mockRepo.EXPECT().CreateOrderDetail(gomock.Any(), querier.CreateOrderDetailParams{
-			OrderID:  orderID,
-			BookID:   bookID,
-			Quantity: int32(quantity),
-		}).Return(querier.OrderDetail{
-			ID:        orderDetailID,
-			OrderID:   orderID,
-			BookID:    bookID,
-			Quantity:  int32(quantity),
-			CreatedAt: now,
-			UpdatedAt: now,
-		}, errInvalidReq).Times(0)
-
-		mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(querier.Book{
-			ID:          bookID,
-			Title:       title,
-			Description: description,
-			Author:      author,
-			Price:       price,
-			CreatedAt:   now,
-			UpdatedAt:   now,
-		}, errInvalidReq).Times(0)
-
-		mockRepo.EXPECT().UpdateOrderByID(gomock.Any(), querier.UpdateOrderByIDParams{
-			ID:         orderID,
-			TotalPrice: totalPrice,
-		}).Return(querier.Order{
-			ID:         orderID,
-			UserID:     userID,
-			Date:       now,
-			TotalPrice: totalPrice,
-			Status:     status,
-			CreatedAt:  now,
-			UpdatedAt:  now,
-		}, errInvalidReq).Times(0)
+		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(order, nil).Times(1)
+		mockRepo.EXPECT().ReserveBooksStock(gomock.Any(), reserveParams).Return([]querier.Book{}, nil).Times(1)
+		mockRepo.EXPECT().FindBooksByIDs(gomock.Any(), []uuid.UUID{bookID}).Return([]querier.Book{}, nil).Times(1)
 
 		assert.PanicsWithValue(t, utils.AppError{
 			StatusCode: 400,
@@ -394,63 +335,16 @@ func TestCreateOrder(t *testing.T) {
 		})
 	})
 
-	t.Run("failed to check book exists", func(t *testing.T) {
+	t.Run("failed to find books by IDs", func(t *testing.T) {
 		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
 
-		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateOrderParams{})).DoAndReturn(func(_ any, params querier.CreateOrderParams) (querier.Order, error) {
-			assert.Equal(t, userID, params.UserID)
-
-			return querier.Order{
-				ID:        orderID,
-				UserID:    userID,
-				Date:      now,
-				Status:    status,
-				CreatedAt: now,
-				UpdatedAt: now,
-			}, nil
-		}).Times(1)
-
-		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(false, errInvalidReq).Times(1)
-
-		mockRepo.EXPECT().CreateOrderDetail(gomock.Any(), querier.CreateOrderDetailParams{
-			OrderID:  orderID,
-			BookID:   bookID,
-			Quantity: int32(quantity),
-		}).Return(querier.OrderDetail{
-			ID:        orderDetailID,
-			OrderID:   orderID,
-			BookID:    bookID,
-			Quantity:  int32(quantity),
-			CreatedAt: now,
-			UpdatedAt: now,
-		}, errInvalidReq).Times(0)
-
-		mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(querier.Book{
-			ID:          bookID,
-			Title:       title,
-			Description: description,
-			Author:      author,
-			Price:       price,
-			CreatedAt:   now,
-			UpdatedAt:   now,
-		}, errInvalidReq).Times(0)
-
-		mockRepo.EXPECT().UpdateOrderByID(gomock.Any(), querier.UpdateOrderByIDParams{
-			ID:         orderID,
-			TotalPrice: totalPrice,
-		}).Return(querier.Order{
-			ID:         orderID,
-			UserID:     userID,
-			Date:       now,
-			TotalPrice: totalPrice,
-			Status:     status,
-			CreatedAt:  now,
-			UpdatedAt:  now,
-		}, errInvalidReq).Times(0)
+		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(order, nil).Times(1)
+		mockRepo.EXPECT().ReserveBooksStock(gomock.Any(), reserveParams).Return([]querier.Book{}, nil).Times(1)
+		mockRepo.EXPECT().FindBooksByIDs(gomock.Any(), []uuid.UUID{bookID}).Return(nil, errInvalidReq).Times(1)
 
 		assert.PanicsWithValue(t, utils.AppError{
 			StatusCode: 400,
-			Message:    fmt.Sprintf("invalid request|%s", FailedToCheckBookExists),
+			Message:    fmt.Sprintf("invalid request|%s", FailedToFindBookByID),
 		}, func() {
 			resp := orderSvcMock.CreateOrder(ctx, req)
 			assert.Empty(t, resp)
@@ -460,56 +354,7 @@ func TestCreateOrder(t *testing.T) {
 	t.Run("failed to parse bookID", func(t *testing.T) {
 		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
 
-		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateOrderParams{})).DoAndReturn(func(_ any, params querier.CreateOrderParams) (querier.Order, error) {
-			assert.Equal(t, userID, params.UserID)
-
-			return querier.Order{
-				ID:        orderID,
-				UserID:    userID,
-				Date:      now,
-				Status:    status,
-				CreatedAt: now,
-				UpdatedAt: now,
-			}, nil
-		}).Times(1)
-
-		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(false, errInvalidReq).Times(0)
-
-		mockRepo.EXPECT().CreateOrderDetail(gomock.Any(), querier.CreateOrderDetailParams{
-			OrderID:  orderID,
-			BookID:   bookID,
-			Quantity: int32(quantity),
-		}).Return(querier.OrderDetail{
-			ID:        orderDetailID,
-			OrderID:   orderID,
-			BookID:    bookID,
-			Quantity:  int32(quantity),
-			CreatedAt: now,
-			UpdatedAt: now,
-		}, errInvalidReq).Times(0)
-
-		mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(querier.Book{
-			ID:          bookID,
-			Title:       title,
-			Description: description,
-			Author:      author,
-			Price:       price,
-			CreatedAt:   now,
-			UpdatedAt:   now,
-		}, errInvalidReq).Times(0)
-
-		mockRepo.EXPECT().UpdateOrderByID(gomock.Any(), querier.UpdateOrderByIDParams{
-			ID:         orderID,
-			TotalPrice: totalPrice,
-		}).Return(querier.Order{
-			ID:         orderID,
-			UserID:     userID,
-			Date:       now,
-			TotalPrice: totalPrice,
-			Status:     status,
-			CreatedAt:  now,
-			UpdatedAt:  now,
-		}, errInvalidReq).Times(0)
+		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Times(0)
 
 		assert.PanicsWithValue(t, utils.AppError{
 			StatusCode: 400,
@@ -523,56 +368,7 @@ func TestCreateOrder(t *testing.T) {
 	t.Run("invalid quantity", func(t *testing.T) {
 		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
 
-		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateOrderParams{})).DoAndReturn(func(_ any, params querier.CreateOrderParams) (querier.Order, error) {
-			assert.Equal(t, userID, params.UserID)
-
-			return querier.Order{
-				ID:        orderID,
-				UserID:    userID,
-				Date:      now,
-				Status:    status,
-				CreatedAt: now,
-				UpdatedAt: now,
-			}, nil
-		}).Times(1)
-
-		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(false, errInvalidReq).Times(0)
-
-		mockRepo.EXPECT().CreateOrderDetail(gomock.Any(), querier.CreateOrderDetailParams{
-			OrderID:  orderID,
-			BookID:   bookID,
-			Quantity: int32(quantity),
-		}).Return(querier.OrderDetail{
-			ID:        orderDetailID,
-			OrderID:   orderID,
-			BookID:    bookID,
-			Quantity:  int32(quantity),
-			CreatedAt: now,
-			UpdatedAt: now,
-		}, errInvalidReq).Times(0)
-
-		mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(querier.Book{
-			ID:          bookID,
-			Title:       title,
-			Description: description,
-			Author:      author,
-			Price:       price,
-			CreatedAt:   now,
-			UpdatedAt:   now,
-		}, errInvalidReq).Times(0)
-
-		mockRepo.EXPECT().UpdateOrderByID(gomock.Any(), querier.UpdateOrderByIDParams{
-			ID:         orderID,
-			TotalPrice: totalPrice,
-		}).Return(querier.Order{
-			ID:         orderID,
-			UserID:     userID,
-			Date:       now,
-			TotalPrice: totalPrice,
-			Status:     status,
-			CreatedAt:  now,
-			UpdatedAt:  now,
-		}, errInvalidReq).Times(0)
+		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Times(0)
 
 		assert.PanicsWithValue(t, utils.AppError{
 			StatusCode: 400,
@@ -586,44 +382,42 @@ func TestCreateOrder(t *testing.T) {
 	t.Run("invalid bookID", func(t *testing.T) {
 		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
 
-		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateOrderParams{})).DoAndReturn(func(_ any, params querier.CreateOrderParams) (querier.Order, error) {
-			assert.Equal(t, userID, params.UserID)
-
-			return querier.Order{
-				ID:        orderID,
-				UserID:    userID,
-				Date:      now,
-				Status:    status,
-				CreatedAt: now,
-				UpdatedAt: now,
-			}, nil
-		}).Times(1)
+		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Times(0)
 
-		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(false, errInvalidReq).Times(0)
-
-		mockRepo.EXPECT().CreateOrderDetail(gomock.Any(), querier.CreateOrderDetailParams{
-			OrderID:  orderID,
-			BookID:   bookID,
-			Quantity: int32(quantity),
-		}).Return(querier.OrderDetail{
-			ID:        orderDetailID,
-			OrderID:   orderID,
-			BookID:    bookID,
-			Quantity:  int32(quantity),
-			CreatedAt: now,
-			UpdatedAt: now,
-		}, errInvalidReq).Times(0)
-
-		mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(querier.Book{
-			ID:          bookID,
-			Title:       title,
-			Description: description,
-			Author:      author,
-			Price:       price,
-			CreatedAt:   now,
-			UpdatedAt:   now,
-		}, errInvalidReq).Times(0)
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("%s|%s", InvalidBookID, InvalidBookID),
+		}, func() {
+			resp := orderSvcMock.CreateOrder(ctx, invalidEmptyBookIDReq)
+			assert.Empty(t, resp)
+		})
+	})
 
+	idempotentReq := req
+	idempotentReq.IdempotencyKey = "retry-key"
+	idempotentRes := dto.CreateOrderRes{
+		OrderId:    orderID.String(),
+		Date:       now.Format(constant.TimeFormat),
+		TotalPrice: totalPrice,
+		Status:     status,
+	}
+	idempotentResJSON, _ := json.Marshal(idempotentRes)
+	findIdempotencyParams := querier.FindIdempotencyKeyParams{
+		UserID: userID,
+		Key:    idempotentReq.IdempotencyKey,
+	}
+	storedIdempotencyKey := querier.IdempotencyKey{
+		UserID:      userID,
+		Key:         idempotentReq.IdempotencyKey,
+		RequestHash: hashOrderRequest(idempotentReq),
+		Response:    idempotentResJSON,
+		CreatedAt:   now,
+	}
+	expectCreateOrderFlow := func() {
+		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(order, nil).Times(1)
+		mockRepo.EXPECT().ReserveBooksStock(gomock.Any(), reserveParams).Return([]querier.Book{book}, nil).Times(1)
+		mockRepo.EXPECT().CreateStockMovements(gomock.Any(), stockMovementsParams).Return(nil).Times(1)
+		mockRepo.EXPECT().CreateOrderDetails(gomock.Any(), orderDetailsParams).Return(nil).Times(1)
 		mockRepo.EXPECT().UpdateOrderByID(gomock.Any(), querier.UpdateOrderByIDParams{
 			ID:         orderID,
 			TotalPrice: totalPrice,
@@ -633,83 +427,169 @@ func TestCreateOrder(t *testing.T) {
 			Date:       now,
 			TotalPrice: totalPrice,
 			Status:     status,
-			CreatedAt:  now,
-			UpdatedAt:  now,
-		}, errInvalidReq).Times(0)
+		}, nil).Times(1)
+	}
+
+	t.Run("success create order with new idempotency key", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		mockRepo.EXPECT().FindIdempotencyKey(gomock.Any(), findIdempotencyParams).Return(querier.IdempotencyKey{}, pgx.ErrNoRows).Times(1)
+		expectCreateOrderFlow()
+		mockRepo.EXPECT().CreateIdempotencyKey(gomock.Any(), querier.CreateIdempotencyKeyParams{
+			UserID:      userID,
+			Key:         idempotentReq.IdempotencyKey,
+			RequestHash: storedIdempotencyKey.RequestHash,
+			Response:    idempotentResJSON,
+		}).Return(storedIdempotencyKey, nil).Times(1)
+
+		resp := orderSvcMock.CreateOrder(ctx, idempotentReq)
+
+		assert.Equal(t, idempotentRes, resp)
+	})
+
+	t.Run("success replay order for used idempotency key", func(t *testing.T) {
+		mockRepo.EXPECT().FindIdempotencyKey(gomock.Any(), findIdempotencyParams).Return(storedIdempotencyKey, nil).Times(1)
+
+		resp := orderSvcMock.CreateOrder(ctx, idempotentReq)
+
+		assert.Equal(t, idempotentRes, resp)
+	})
+
+	t.Run("success replay order after concurrent idempotency key insert", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		gomock.InOrder(
+			mockRepo.EXPECT().FindIdempotencyKey(gomock.Any(), findIdempotencyParams).Return(querier.IdempotencyKey{}, pgx.ErrNoRows),
+			mockRepo.EXPECT().FindIdempotencyKey(gomock.Any(), findIdempotencyParams).Return(storedIdempotencyKey, nil),
+		)
+		expectCreateOrderFlow()
+		mockRepo.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(querier.IdempotencyKey{}, &pgconn.PgError{
+			Code: uniqueViolationCode,
+		}).Times(1)
+
+		resp := orderSvcMock.CreateOrder(ctx, idempotentReq)
+
+		assert.Equal(t, idempotentRes, resp)
+	})
+
+	t.Run("idempotency key reused with different request", func(t *testing.T) {
+		reusedKey := storedIdempotencyKey
+		reusedKey.RequestHash = hashOrderRequest(duplicateBookReq)
+		mockRepo.EXPECT().FindIdempotencyKey(gomock.Any(), findIdempotencyParams).Return(reusedKey, nil).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("%s|%s", IdempotencyKeyReused, IdempotencyKeyReused),
+		}, func() {
+			resp := orderSvcMock.CreateOrder(ctx, idempotentReq)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("failed to find idempotency key", func(t *testing.T) {
+		mockRepo.EXPECT().FindIdempotencyKey(gomock.Any(), findIdempotencyParams).Return(querier.IdempotencyKey{}, errInvalidReq).Times(1)
 
 		assert.PanicsWithValue(t, utils.AppError{
 			StatusCode: 400,
-			Message:    fmt.Sprintf("%s|%s", InvalidBookID, InvalidBookID),
+			Message:    fmt.Sprintf("invalid request|%s", FailedToFindIdempotency),
 		}, func() {
-			resp := orderSvcMock.CreateOrder(ctx, invalidEmptyBookIDReq)
+			resp := orderSvcMock.CreateOrder(ctx, idempotentReq)
 			assert.Empty(t, resp)
 		})
 	})
 
-	t.Run("failed to create order", func(t *testing.T) {
+	t.Run("failed to save idempotency key", func(t *testing.T) {
 		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
 
-		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateOrderParams{})).DoAndReturn(func(_ any, params querier.CreateOrderParams) (querier.Order, error) {
-			assert.Equal(t, userID, params.UserID)
+		mockRepo.EXPECT().FindIdempotencyKey(gomock.Any(), findIdempotencyParams).Return(querier.IdempotencyKey{}, pgx.ErrNoRows).Times(1)
+		expectCreateOrderFlow()
+		mockRepo.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(querier.IdempotencyKey{}, errInvalidReq).Times(1)
 
-			return querier.Order{
-				ID:        orderID,
-				UserID:    userID,
-				Date:      now,
-				Status:    status,
-				CreatedAt: now,
-				UpdatedAt: now,
-			}, errInvalidReq
-		}).Times(1)
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToSaveIdempotency),
+		}, func() {
+			resp := orderSvcMock.CreateOrder(ctx, idempotentReq)
+			assert.Empty(t, resp)
+		})
+	})
 
-		mockRepo.EXPECT().CheckBookExists(gomock.Any(), bookID).Return(false, errInvalidReq).Times(0)
-
-		mockRepo.EXPECT().CreateOrderDetail(gomock.Any(), querier.CreateOrderDetailParams{
-			OrderID:  orderID,
-			BookID:   bookID,
-			Quantity: int32(quantity),
-		}).Return(querier.OrderDetail{
-			ID:        orderDetailID,
-			OrderID:   orderID,
-			BookID:    bookID,
-			Quantity:  int32(quantity),
-			CreatedAt: now,
-			UpdatedAt: now,
-		}, errInvalidReq).Times(0)
+	t.Run("failed to create order", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
 
-		mockRepo.EXPECT().FindBookByID(gomock.Any(), bookID).Return(querier.Book{
-			ID:          bookID,
-			Title:       title,
-			Description: description,
-			Author:      author,
-			Price:       price,
-			CreatedAt:   now,
-			UpdatedAt:   now,
-		}, errInvalidReq).Times(0)
-
-		mockRepo.EXPECT().UpdateOrderByID(gomock.Any(), querier.UpdateOrderByIDParams{
-			ID:         orderID,
-			TotalPrice: totalPrice,
-		}).Return(querier.Order{
-			ID:         orderID,
-			UserID:     userID,
-			Date:       now,
-			TotalPrice: totalPrice,
-			Status:     status,
-			CreatedAt:  now,
-			UpdatedAt:  now,
-		}, errInvalidReq).Times(0)
+		mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(querier.Order{}, errInvalidReq).Times(1)
 
 		assert.PanicsWithValue(t, utils.AppError{
 			StatusCode: 422,
 			Message:    fmt.Sprintf("invalid request|%s", FailedToCreateOrder),
 		}, func() {
-			resp := orderSvcMock.CreateOrder(ctx, invalidEmptyBookIDReq)
+			resp := orderSvcMock.CreateOrder(ctx, req)
 			assert.Empty(t, resp)
 		})
 	})
 }
 
+// BenchmarkCreateOrder measures CreateOrder for a large order against the
+// mock repository and reports how many repository calls each order makes.
+func BenchmarkCreateOrder(b *testing.B) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(b)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer redisServer.Close()
+	cacheSvc := utils.NewCacheSvc(config, redis.NewClient(&redis.Options{
+		Addr: redisServer.Addr(),
+	}))
+
+	mockRepo := mockrepo.NewMockRepository(ctrl)
+	orderSvc := NewOrderSvc(mockRepo, config, cacheSvc)
+
+	const itemCount = 200
+	req := dto.CreateOrderReq{}
+	books := make([]querier.Book, 0, itemCount)
+	for i := 0; i < itemCount; i++ {
+		book := querier.Book{
+			ID:     uuid.New(),
+			Title:  "Hello",
+			Author: "Giri Putra Adhittana",
+			Price:  decimal.NewFromInt(10),
+			Stock:  100,
+		}
+		books = append(books, book)
+		req.OrderDetail = append(req.OrderDetail, dto.OrderDetailReq{
+			BookID:   book.ID.String(),
+			Quantity: 1,
+		})
+	}
+
+	pgxMock := mockrepo.NewMockPGXPool(ctrl)
+	txMock := mockrepo.NewMockPgxIface(ctrl)
+	mockRepo.EXPECT().GetDB().Return(pgxMock).AnyTimes()
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(mockRepo).AnyTimes()
+	pgxMock.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(txMock, nil).AnyTimes()
+	txMock.EXPECT().Commit(gomock.Any()).Return(nil).AnyTimes()
+
+	calls := 0
+	count := func(_ any, _ any) { calls++ }
+	mockRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Do(count).Return(querier.Order{ID: uuid.New()}, nil).AnyTimes()
+	mockRepo.EXPECT().ReserveBooksStock(gomock.Any(), gomock.Any()).Do(count).Return(books, nil).AnyTimes()
+	mockRepo.EXPECT().CreateStockMovements(gomock.Any(), gomock.Any()).Do(count).Return(nil).AnyTimes()
+	mockRepo.EXPECT().CreateOrderDetails(gomock.Any(), gomock.Any()).Do(count).Return(nil).AnyTimes()
+	mockRepo.EXPECT().UpdateOrderByID(gomock.Any(), gomock.Any()).Do(count).Return(querier.Order{}, nil).AnyTimes()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		orderSvc.CreateOrder(ctx, req)
+	}
+	b.ReportMetric(float64(calls)/float64(b.N), "queries/op")
+}
+
 func TestGetOrder(t *testing.T) {
 	ctx := utils.SetRequestContext(userID.String())
 	ctrl := gomock.NewController(t)
@@ -725,7 +605,7 @@ func TestGetOrder(t *testing.T) {
 		Limit: limit,
 	}
 	now := time.Now()
-	totalPrice := float64(100)
+	totalPrice := decimal.NewFromInt(100)
 	status := "pending"
 	orderID := uuid.New()
 	totalCount := 20
@@ -796,6 +676,68 @@ func TestGetOrder(t *testing.T) {
 		}, resp)
 	})
 
+	t.Run("success get order by cursor", func(t *testing.T) {
+		cursor := dto.Cursor{Time: now, ID: uuid.New()}
+		orders := []querier.Order{
+			{ID: uuid.New(), UserID: userID, Date: now.Add(-time.Minute), Status: status},
+			{ID: uuid.New(), UserID: userID, Date: now.Add(-2 * time.Minute), Status: status},
+		}
+
+		mockRepo.EXPECT().FindOrderByUserIDAfterCursor(gomock.Any(), querier.FindOrderByUserIDAfterCursorParams{
+			UserID: userID,
+			CursorDate: sql.NullTime{
+				Time:  cursor.Time,
+				Valid: true,
+			},
+			CursorID: uuid.NullUUID{
+				UUID:  cursor.ID,
+				Valid: true,
+			},
+			Limit: 3,
+		}).Return(orders, nil).Times(1)
+		mockRepo.EXPECT().GetOrderCountByUserId(gomock.Any(), gomock.Any()).Times(0)
+
+		resp := orderSvcMock.GetOrder(ctx, dto.GetOrderReq{
+			Limit:      2,
+			CursorMode: true,
+			Cursor:     cursor,
+		})
+
+		assert.Equal(t, -1, resp.Total)
+		assert.False(t, resp.IsLoadMore)
+		assert.Len(t, resp.Data, 2)
+		assert.Empty(t, resp.NextCursor)
+		assert.Equal(t, dto.EncodeCursor(dto.Cursor{
+			Time:     orders[0].Date,
+			ID:       orders[0].ID,
+			Backward: true,
+		}), resp.PrevCursor)
+	})
+
+	t.Run("failed get order by cursor", func(t *testing.T) {
+		cursor := dto.Cursor{Time: now, ID: uuid.New(), Backward: true}
+		mockRepo.EXPECT().FindOrderByUserIDBeforeCursor(gomock.Any(), querier.FindOrderByUserIDBeforeCursorParams{
+			UserID:     userID,
+			CursorDate: cursor.Time,
+			CursorID:   cursor.ID,
+			Limit:      3,
+		}).Return(nil, errInvalidReq).Times(1)
+		mockRepo.EXPECT().GetOrderCountByUserId(gomock.Any(), userID).Return(int64(totalCount), nil).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToGetOrder),
+		}, func() {
+			resp := orderSvcMock.GetOrder(ctx, dto.GetOrderReq{
+				Limit:      2,
+				CursorMode: true,
+				Cursor:     cursor,
+				WithTotal:  true,
+			})
+			assert.Empty(t, resp)
+		})
+	})
+
 	t.Run("failed get order count by user ID", func(t *testing.T) {
 		mockCache.DelByPrefix(ctx, constant.OrderCacheKey)
 
@@ -873,12 +815,12 @@ func TestGetOrderDetail(t *testing.T) {
 	description := "World"
 	author := "Giri Putra Adhittana"
 	quantity := 10
-	price := float64(10)
+	price := decimal.NewFromInt(10)
 	req := dto.GetOrderDetailReq{
 		OrderID: orderID,
 	}
 	now := time.Now()
-	totalPrice := float64(100)
+	totalPrice := decimal.NewFromInt(100)
 	status := "pending"
 
 	t.Run("success get order detail", func(t *testing.T) {
@@ -911,7 +853,8 @@ func TestGetOrderDetail(t *testing.T) {
 				Description: description,
 				Author:      author,
 				Quantity:    int32(quantity),
-				Price:       price,
+				UnitPrice:   price,
+				LineTotal:   totalPrice,
 			},
 		}, nil).Times(1)
 
@@ -931,6 +874,8 @@ func TestGetOrderDetail(t *testing.T) {
 					Description:   description,
 					Author:        author,
 					Quantity:      quantity,
+					UnitPrice:     price,
+					LineTotal:     totalPrice,
 				},
 			},
 		}, resp)
@@ -953,6 +898,8 @@ func TestGetOrderDetail(t *testing.T) {
 					Description:   description,
 					Author:        author,
 					Quantity:      quantity,
+					UnitPrice:     price,
+					LineTotal:     totalPrice,
 				},
 			},
 		}, resp)
@@ -990,7 +937,8 @@ func TestGetOrderDetail(t *testing.T) {
 				Description: description,
 				Author:      author,
 				Quantity:    int32(quantity),
-				Price:       price,
+				UnitPrice:   price,
+				LineTotal:   totalPrice,
 			},
 		}, errInvalidReq).Times(1)
 
@@ -1035,7 +983,8 @@ func TestGetOrderDetail(t *testing.T) {
 				Description: description,
 				Author:      author,
 				Quantity:    int32(quantity),
-				Price:       price,
+				UnitPrice:   price,
+				LineTotal:   totalPrice,
 			},
 		}, errInvalidReq).Times(0)
 
@@ -1080,7 +1029,8 @@ func TestGetOrderDetail(t *testing.T) {
 				Description: description,
 				Author:      author,
 				Quantity:    int32(quantity),
-				Price:       price,
+				UnitPrice:   price,
+				LineTotal:   totalPrice,
 			},
 		}, errInvalidReq).Times(0)
 
@@ -1125,7 +1075,8 @@ func TestGetOrderDetail(t *testing.T) {
 				Description: description,
 				Author:      author,
 				Quantity:    int32(quantity),
-				Price:       price,
+				UnitPrice:   price,
+				LineTotal:   totalPrice,
 			},
 		}, errInvalidReq).Times(0)
 
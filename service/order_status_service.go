@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gadhittana-01/book-go/constant"
+	querier "github.com/gadhittana-01/book-go/db/repository"
+	"github.com/gadhittana-01/book-go/dto"
+	utilsConstant "github.com/gadhittana01/go-modules/constant"
+	"github.com/gadhittana01/go-modules/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/lo"
+)
+
+const (
+	FailedToUpdateOrderStatus        = "Failed to update order status"
+	FailedToCreateOrderStatusHistory = "Failed to create order status history"
+	FailedToFindOrderItems           = "Failed to find order items"
+	OrderNotFound                    = "Order not found"
+	InvalidOrderStatusTransition     = "Order status cannot change from %s to %s"
+)
+
+// orderStatusTransitions lists, for every status, the statuses an order may
+// move to next. Delivered and cancelled orders are final.
+var orderStatusTransitions = map[string][]string{
+	constant.OrderStatusPending:   {constant.OrderStatusConfirmed, constant.OrderStatusCancelled},
+	constant.OrderStatusConfirmed: {constant.OrderStatusShipped, constant.OrderStatusCancelled},
+	constant.OrderStatusShipped:   {constant.OrderStatusDelivered},
+}
+
+func CanTransitionOrderStatus(from string, to string) bool {
+	return lo.Contains(orderStatusTransitions[from], to)
+}
+
+type OrderStatusSvc interface {
+	CancelOrder(ctx context.Context, input dto.CancelOrderReq) dto.UpdateOrderStatusRes
+	UpdateOrderStatus(ctx context.Context, input dto.UpdateOrderStatusReq) dto.UpdateOrderStatusRes
+}
+
+type OrderStatusSvcImpl struct {
+	repo     querier.Repository
+	config   *utils.BaseConfig
+	cacheSvc utils.CacheSvc
+}
+
+func NewOrderStatusSvc(
+	repo querier.Repository,
+	config *utils.BaseConfig,
+	cacheSvc utils.CacheSvc,
+) OrderStatusSvc {
+	return &OrderStatusSvcImpl{
+		repo:     repo,
+		config:   config,
+		cacheSvc: cacheSvc,
+	}
+}
+
+// CancelOrder lets customers cancel one of their own orders.
+func (s *OrderStatusSvcImpl) CancelOrder(ctx context.Context, input dto.CancelOrderReq) dto.UpdateOrderStatusRes {
+	return s.transitionOrder(ctx, input.OrderID, constant.OrderStatusCancelled, true)
+}
+
+// UpdateOrderStatus moves any order to the requested status and is meant
+// for admin and staff endpoints.
+func (s *OrderStatusSvcImpl) UpdateOrderStatus(ctx context.Context, input dto.UpdateOrderStatusReq) dto.UpdateOrderStatusRes {
+	return s.transitionOrder(ctx, input.OrderID, input.Status, false)
+}
+
+func (s *OrderStatusSvcImpl) transitionOrder(
+	ctx context.Context,
+	orderID uuid.UUID,
+	status string,
+	isOwnerOnly bool,
+) dto.UpdateOrderStatusRes {
+	var order querier.Order
+	authPayload := utils.GetRequestCtx(ctx, utilsConstant.UserSession)
+
+	actorID, err := uuid.Parse(authPayload.UserID)
+	utils.PanicIfAppError(err, FailedToParseStringToUUID, 400)
+
+	err = utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		repoTx := s.repo.WithTx(tx)
+
+		order, err = repoTx.FindOrderByIDForUpdate(ctx, orderID)
+		if err == pgx.ErrNoRows {
+			return utils.CustomError(OrderNotFound, 404)
+		}
+
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToFindOrderByID, 400)
+		}
+
+		// someone else's order is reported as missing rather than forbidden
+		if isOwnerOnly && order.UserID != actorID {
+			return utils.CustomError(OrderNotFound, 404)
+		}
+
+		if !CanTransitionOrderStatus(order.Status, status) {
+			return utils.CustomError(fmt.Sprintf(InvalidOrderStatusTransition, order.Status, status), 409)
+		}
+
+		fromStatus := order.Status
+		order, err = repoTx.UpdateOrderStatusByID(ctx, querier.UpdateOrderStatusByIDParams{
+			ID:     order.ID,
+			Status: status,
+		})
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToUpdateOrderStatus, 422)
+		}
+
+		_, err = repoTx.CreateOrderStatusHistory(ctx, querier.CreateOrderStatusHistoryParams{
+			OrderID:    order.ID,
+			FromStatus: fromStatus,
+			ToStatus:   status,
+			ChangedBy:  actorID,
+		})
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToCreateOrderStatusHistory, 422)
+		}
+
+		if status == constant.OrderStatusCancelled {
+			return s.restockOrder(ctx, repoTx, order.ID, actorID)
+		}
+
+		return nil
+	})
+	utils.PanicIfError(err)
+	s.cacheSvc.ClearCaches([]string{constant.OrderCacheKey}, order.UserID.String())
+	if status == constant.OrderStatusCancelled {
+		s.cacheSvc.ClearCaches([]string{constant.BookCacheKey}, "")
+	}
+
+	return dto.UpdateOrderStatusRes{
+		OrderId:    order.ID.String(),
+		Date:       order.Date.Format(constant.TimeFormat),
+		TotalPrice: order.TotalPrice,
+		Status:     order.Status,
+	}
+}
+
+// restockOrder gives the stock reserved by a cancelled order back to its books.
+func (s *OrderStatusSvcImpl) restockOrder(
+	ctx context.Context,
+	repoTx querier.Querier,
+	orderID uuid.UUID,
+	actorID uuid.UUID,
+) error {
+	items, err := repoTx.FindOrderItemsByOrderID(ctx, orderID)
+	if err != nil {
+		return utils.CustomErrorWithTrace(err, FailedToFindOrderItems, 400)
+	}
+
+	for _, item := range items {
+		_, err = repoTx.AdjustBookStock(ctx, querier.AdjustBookStockParams{
+			ID:       item.BookID,
+			Quantity: item.Quantity,
+		})
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToAdjustBookStock, 422)
+		}
+
+		_, err = repoTx.CreateStockMovement(ctx, querier.CreateStockMovementParams{
+			BookID:    item.BookID,
+			Quantity:  item.Quantity,
+			Reason:    constant.StockReasonOrderCancelled,
+			CreatedBy: actorID,
+		})
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToCreateStockMovement, 422)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gadhittana-01/book-go/constant"
+	querier "github.com/gadhittana-01/book-go/db/repository"
+	mockrepo "github.com/gadhittana-01/book-go/db/repository/mock"
+	"github.com/gadhittana-01/book-go/dto"
+	"github.com/gadhittana01/go-modules/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func initOrderStatusSvc(
+	t *testing.T,
+	ctrl *gomock.Controller,
+	config *utils.BaseConfig,
+) (OrderStatusSvc, *mockrepo.MockRepository, utils.CacheSvc) {
+	mockRepo := mockrepo.NewMockRepository(ctrl)
+	cacheSvc := utils.InitCacheSvc(t, config)
+	return NewOrderStatusSvc(mockRepo, config, cacheSvc), mockRepo, cacheSvc
+}
+
+func TestCanTransitionOrderStatus(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want bool
+	}{
+		{from: constant.OrderStatusPending, to: constant.OrderStatusConfirmed, want: true},
+		{from: constant.OrderStatusPending, to: constant.OrderStatusCancelled, want: true},
+		{from: constant.OrderStatusPending, to: constant.OrderStatusShipped, want: false},
+		{from: constant.OrderStatusConfirmed, to: constant.OrderStatusShipped, want: true},
+		{from: constant.OrderStatusConfirmed, to: constant.OrderStatusCancelled, want: true},
+		{from: constant.OrderStatusShipped, to: constant.OrderStatusDelivered, want: true},
+		{from: constant.OrderStatusShipped, to: constant.OrderStatusCancelled, want: false},
+		{from: constant.OrderStatusDelivered, to: constant.OrderStatusCancelled, want: false},
+		{from: constant.OrderStatusCancelled, to: constant.OrderStatusPending, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s to %s", tt.from, tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.want, CanTransitionOrderStatus(tt.from, tt.to))
+		})
+	}
+}
+
+func TestCancelOrder(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	orderStatusSvcMock, mockRepo, _ := initOrderStatusSvc(t, ctrl, config)
+
+	orderID := uuid.New()
+	bookID := uuid.New()
+	quantity := int32(2)
+	now := time.Now()
+	totalPrice := decimal.NewFromInt(100)
+
+	req := dto.CancelOrderReq{
+		OrderID: orderID,
+	}
+
+	order := querier.Order{
+		ID:         orderID,
+		UserID:     userID,
+		Date:       now,
+		TotalPrice: totalPrice,
+		Status:     constant.OrderStatusPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	cancelledOrder := order
+	cancelledOrder.Status = constant.OrderStatusCancelled
+
+	t.Run("success cancel order", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		mockRepo.EXPECT().FindOrderByIDForUpdate(gomock.Any(), orderID).Return(order, nil).Times(1)
+		mockRepo.EXPECT().UpdateOrderStatusByID(gomock.Any(), querier.UpdateOrderStatusByIDParams{
+			ID:     orderID,
+			Status: constant.OrderStatusCancelled,
+		}).Return(cancelledOrder, nil).Times(1)
+		mockRepo.EXPECT().CreateOrderStatusHistory(gomock.Any(), querier.CreateOrderStatusHistoryParams{
+			OrderID:    orderID,
+			FromStatus: constant.OrderStatusPending,
+			ToStatus:   constant.OrderStatusCancelled,
+			ChangedBy:  userID,
+		}).Return(querier.OrderStatusHistory{}, nil).Times(1)
+		mockRepo.EXPECT().FindOrderItemsByOrderID(gomock.Any(), orderID).Return([]querier.OrderDetail{
+			{
+				OrderID:  orderID,
+				BookID:   bookID,
+				Quantity: quantity,
+			},
+		}, nil).Times(1)
+		mockRepo.EXPECT().AdjustBookStock(gomock.Any(), querier.AdjustBookStockParams{
+			ID:       bookID,
+			Quantity: quantity,
+		}).Return(querier.Book{ID: bookID, Stock: quantity}, nil).Times(1)
+		mockRepo.EXPECT().CreateStockMovement(gomock.Any(), querier.CreateStockMovementParams{
+			BookID:    bookID,
+			Quantity:  quantity,
+			Reason:    constant.StockReasonOrderCancelled,
+			CreatedBy: userID,
+		}).Return(querier.StockMovement{}, nil).Times(1)
+
+		resp := orderStatusSvcMock.CancelOrder(ctx, req)
+
+		assert.Equal(t, dto.UpdateOrderStatusRes{
+			OrderId:    orderID.String(),
+			Date:       now.Format(constant.TimeFormat),
+			TotalPrice: totalPrice,
+			Status:     constant.OrderStatusCancelled,
+		}, resp)
+	})
+
+	t.Run("order not found", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindOrderByIDForUpdate(gomock.Any(), orderID).Return(querier.Order{}, pgx.ErrNoRows).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("%s|%s", OrderNotFound, OrderNotFound),
+		}, func() {
+			resp := orderStatusSvcMock.CancelOrder(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("order owned by another user", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		otherOrder := order
+		otherOrder.UserID = uuid.New()
+		mockRepo.EXPECT().FindOrderByIDForUpdate(gomock.Any(), orderID).Return(otherOrder, nil).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("%s|%s", OrderNotFound, OrderNotFound),
+		}, func() {
+			resp := orderStatusSvcMock.CancelOrder(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("order already shipped", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		shippedOrder := order
+		shippedOrder.Status = constant.OrderStatusShipped
+		mockRepo.EXPECT().FindOrderByIDForUpdate(gomock.Any(), orderID).Return(shippedOrder, nil).Times(1)
+
+		message := fmt.Sprintf(InvalidOrderStatusTransition, constant.OrderStatusShipped, constant.OrderStatusCancelled)
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 409,
+			Message:    fmt.Sprintf("%s|%s", message, message),
+		}, func() {
+			resp := orderStatusSvcMock.CancelOrder(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("failed restock cancelled order", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindOrderByIDForUpdate(gomock.Any(), orderID).Return(order, nil).Times(1)
+		mockRepo.EXPECT().UpdateOrderStatusByID(gomock.Any(), gomock.Any()).Return(cancelledOrder, nil).Times(1)
+		mockRepo.EXPECT().CreateOrderStatusHistory(gomock.Any(), gomock.Any()).Return(querier.OrderStatusHistory{}, nil).Times(1)
+		mockRepo.EXPECT().FindOrderItemsByOrderID(gomock.Any(), orderID).Return([]querier.OrderDetail{
+			{
+				OrderID:  orderID,
+				BookID:   bookID,
+				Quantity: quantity,
+			},
+		}, nil).Times(1)
+		mockRepo.EXPECT().AdjustBookStock(gomock.Any(), gomock.Any()).Return(querier.Book{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToAdjustBookStock),
+		}, func() {
+			resp := orderStatusSvcMock.CancelOrder(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("failed find order items", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindOrderByIDForUpdate(gomock.Any(), orderID).Return(order, nil).Times(1)
+		mockRepo.EXPECT().UpdateOrderStatusByID(gomock.Any(), gomock.Any()).Return(cancelledOrder, nil).Times(1)
+		mockRepo.EXPECT().CreateOrderStatusHistory(gomock.Any(), gomock.Any()).Return(querier.OrderStatusHistory{}, nil).Times(1)
+		mockRepo.EXPECT().FindOrderItemsByOrderID(gomock.Any(), orderID).Return(nil, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToFindOrderItems),
+		}, func() {
+			resp := orderStatusSvcMock.CancelOrder(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("failed find order", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindOrderByIDForUpdate(gomock.Any(), orderID).Return(querier.Order{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToFindOrderByID),
+		}, func() {
+			resp := orderStatusSvcMock.CancelOrder(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+}
+
+func TestUpdateOrderStatus(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	orderStatusSvcMock, mockRepo, _ := initOrderStatusSvc(t, ctrl, config)
+
+	orderID := uuid.New()
+	customerID := uuid.New()
+	now := time.Now()
+	totalPrice := decimal.NewFromInt(100)
+
+	req := dto.UpdateOrderStatusReq{
+		OrderID: orderID,
+		Status:  constant.OrderStatusConfirmed,
+	}
+
+	order := querier.Order{
+		ID:         orderID,
+		UserID:     customerID,
+		Date:       now,
+		TotalPrice: totalPrice,
+		Status:     constant.OrderStatusPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	confirmedOrder := order
+	confirmedOrder.Status = constant.OrderStatusConfirmed
+
+	t.Run("success update order status", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		mockRepo.EXPECT().FindOrderByIDForUpdate(gomock.Any(), orderID).Return(order, nil).Times(1)
+		mockRepo.EXPECT().UpdateOrderStatusByID(gomock.Any(), querier.UpdateOrderStatusByIDParams{
+			ID:     orderID,
+			Status: constant.OrderStatusConfirmed,
+		}).Return(confirmedOrder, nil).Times(1)
+		mockRepo.EXPECT().CreateOrderStatusHistory(gomock.Any(), querier.CreateOrderStatusHistoryParams{
+			OrderID:    orderID,
+			FromStatus: constant.OrderStatusPending,
+			ToStatus:   constant.OrderStatusConfirmed,
+			ChangedBy:  userID,
+		}).Return(querier.OrderStatusHistory{}, nil).Times(1)
+
+		resp := orderStatusSvcMock.UpdateOrderStatus(ctx, req)
+
+		assert.Equal(t, dto.UpdateOrderStatusRes{
+			OrderId:    orderID.String(),
+			Date:       now.Format(constant.TimeFormat),
+			TotalPrice: totalPrice,
+			Status:     constant.OrderStatusConfirmed,
+		}, resp)
+	})
+
+	t.Run("invalid transition", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindOrderByIDForUpdate(gomock.Any(), orderID).Return(order, nil).Times(1)
+
+		deliverReq := dto.UpdateOrderStatusReq{
+			OrderID: orderID,
+			Status:  constant.OrderStatusDelivered,
+		}
+		message := fmt.Sprintf(InvalidOrderStatusTransition, constant.OrderStatusPending, constant.OrderStatusDelivered)
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 409,
+			Message:    fmt.Sprintf("%s|%s", message, message),
+		}, func() {
+			resp := orderStatusSvcMock.UpdateOrderStatus(ctx, deliverReq)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("failed update order status", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindOrderByIDForUpdate(gomock.Any(), orderID).Return(order, nil).Times(1)
+		mockRepo.EXPECT().UpdateOrderStatusByID(gomock.Any(), gomock.Any()).Return(querier.Order{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToUpdateOrderStatus),
+		}, func() {
+			resp := orderStatusSvcMock.UpdateOrderStatus(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+
+	t.Run("failed create order status history", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindOrderByIDForUpdate(gomock.Any(), orderID).Return(order, nil).Times(1)
+		mockRepo.EXPECT().UpdateOrderStatusByID(gomock.Any(), gomock.Any()).Return(confirmedOrder, nil).Times(1)
+		mockRepo.EXPECT().CreateOrderStatusHistory(gomock.Any(), gomock.Any()).Return(querier.OrderStatusHistory{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToCreateOrderStatusHistory),
+		}, func() {
+			resp := orderStatusSvcMock.UpdateOrderStatus(ctx, req)
+			assert.Empty(t, resp)
+		})
+	})
+}
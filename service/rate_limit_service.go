@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gadhittana-01/book-go/constant"
+	"github.com/gadhittana01/go-modules/utils"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimit is the outcome of counting one request against a limit.
+type RateLimit struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the oldest counted request leaves the window
+	// and frees a slot again.
+	Reset time.Duration
+}
+
+type RateLimitSvc interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (RateLimit, error)
+}
+
+// RateLimitSvcImpl is a sliding window limiter. Every allowed request is kept
+// in a Redis sorted set scored by its time, so a client gets at most limit
+// requests in any window, not twice that across the edge of fixed windows.
+type RateLimitSvcImpl struct {
+	client utils.RedisClient
+}
+
+func NewRateLimitSvc(client utils.RedisClient) RateLimitSvc {
+	return &RateLimitSvcImpl{
+		client: client,
+	}
+}
+
+func (s *RateLimitSvcImpl) Allow(ctx context.Context, key string, limit int, window time.Duration) (RateLimit, error) {
+	now := time.Now()
+	redisKey := rateLimitKey(key)
+	member := uuid.NewString()
+
+	var count *redis.IntCmd
+	var oldest *redis.ZSliceCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, redisKey, "-inf", strconv.FormatInt(now.Add(-window).UnixMicro(), 10))
+		pipe.ZAdd(ctx, redisKey, redis.Z{Score: float64(now.UnixMicro()), Member: member})
+		count = pipe.ZCard(ctx, redisKey)
+		oldest = pipe.ZRangeWithScores(ctx, redisKey, 0, 0)
+		pipe.PExpire(ctx, redisKey, window)
+		return nil
+	})
+	if err != nil {
+		return RateLimit{}, err
+	}
+
+	result := RateLimit{
+		Allowed:   count.Val() <= int64(limit),
+		Limit:     limit,
+		Remaining: max(limit-int(count.Val()), 0),
+	}
+
+	if !result.Allowed {
+		// a rejected request must not use up the quota, or a client that
+		// keeps retrying would never get through again
+		if err := s.client.ZRem(ctx, redisKey, member).Err(); err != nil {
+			return RateLimit{}, err
+		}
+	}
+
+	if len(oldest.Val()) > 0 {
+		oldestAt := time.UnixMicro(int64(oldest.Val()[0].Score))
+		result.Reset = max(oldestAt.Add(window).Sub(now), 0)
+	}
+
+	return result, nil
+}
+
+func rateLimitKey(key string) string {
+	return fmt.Sprintf("%s:%s", constant.RateLimitKey, key)
+}
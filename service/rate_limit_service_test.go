@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func initRateLimitSvc(t *testing.T) (RateLimitSvc, *miniredis.Miniredis) {
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(redisServer.Close)
+
+	return NewRateLimitSvc(redis.NewClient(&redis.Options{
+		Addr: redisServer.Addr(),
+	})), redisServer
+}
+
+func TestRateLimitAllow(t *testing.T) {
+	ctx := context.Background()
+	rateLimitSvc, redisServer := initRateLimitSvc(t)
+	key := "order:user:1"
+
+	t.Run("allows requests up to the limit", func(t *testing.T) {
+		for i := 1; i <= 3; i++ {
+			rateLimit, err := rateLimitSvc.Allow(ctx, key, 3, time.Minute)
+			assert.NoError(t, err)
+			assert.True(t, rateLimit.Allowed)
+			assert.Equal(t, 3, rateLimit.Limit)
+			assert.Equal(t, 3-i, rateLimit.Remaining)
+			assert.InDelta(t, time.Minute, rateLimit.Reset, float64(time.Second))
+		}
+	})
+
+	t.Run("rejects requests over the limit without counting them", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			rateLimit, err := rateLimitSvc.Allow(ctx, key, 3, time.Minute)
+			assert.NoError(t, err)
+			assert.False(t, rateLimit.Allowed)
+			assert.Equal(t, 0, rateLimit.Remaining)
+			assert.Positive(t, rateLimit.Reset)
+		}
+
+		members, err := redisServer.ZMembers(rateLimitKey(key))
+		assert.NoError(t, err)
+		assert.Len(t, members, 3)
+		assert.Equal(t, time.Minute, redisServer.TTL(rateLimitKey(key)))
+	})
+
+	t.Run("keys are limited separately", func(t *testing.T) {
+		rateLimit, err := rateLimitSvc.Allow(ctx, "order:user:2", 3, time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, rateLimit.Allowed)
+		assert.Equal(t, 2, rateLimit.Remaining)
+	})
+}
+
+func TestRateLimitSlidingWindow(t *testing.T) {
+	ctx := context.Background()
+	rateLimitSvc, redisServer := initRateLimitSvc(t)
+	key := "sign_up:ip:203.0.113.7"
+	now := time.Now()
+
+	// one request that already left the window and one that is about to
+	_, err := redisServer.ZAdd(rateLimitKey(key), float64(now.Add(-2*time.Minute).UnixMicro()), "expired")
+	assert.NoError(t, err)
+	_, err = redisServer.ZAdd(rateLimitKey(key), float64(now.Add(-50*time.Second).UnixMicro()), "leaving")
+	assert.NoError(t, err)
+
+	rateLimit, err := rateLimitSvc.Allow(ctx, key, 2, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, rateLimit.Allowed)
+	assert.Equal(t, 0, rateLimit.Remaining)
+	assert.InDelta(t, 10*time.Second, rateLimit.Reset, float64(time.Second))
+
+	rateLimit, err = rateLimitSvc.Allow(ctx, key, 2, time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, rateLimit.Allowed)
+	assert.InDelta(t, 10*time.Second, rateLimit.Reset, float64(time.Second))
+
+	members, err := redisServer.ZMembers(rateLimitKey(key))
+	assert.NoError(t, err)
+	assert.Len(t, members, 2)
+	assert.NotContains(t, members, "expired")
+}
+
+func TestRateLimitRedisError(t *testing.T) {
+	rateLimitSvc, redisServer := initRateLimitSvc(t)
+	redisServer.Close()
+
+	_, err := rateLimitSvc.Allow(context.Background(), "book:user:1", 3, time.Minute)
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis"
+	"github.com/gadhittana01/go-modules/utils"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func initTokenRevocationSvc(t *testing.T) (TokenRevocationSvc, *miniredis.Miniredis) {
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(redisServer.Close)
+
+	return NewTokenRevocationSvc(redis.NewClient(&redis.Options{
+		Addr: redisServer.Addr(),
+	}), config), redisServer
+}
+
+func TestRevokeAccessToken(t *testing.T) {
+	ctx := context.Background()
+	tokenRevocationSvc, _ := initTokenRevocationSvc(t)
+
+	t.Run("revoked token is reported as revoked", func(t *testing.T) {
+		err := tokenRevocationSvc.RevokeAccessToken(ctx, "token-1")
+		assert.NoError(t, err)
+
+		isRevoked, err := tokenRevocationSvc.IsAccessTokenRevoked(ctx, "token-1")
+		assert.NoError(t, err)
+		assert.True(t, isRevoked)
+	})
+
+	t.Run("other token is not revoked", func(t *testing.T) {
+		isRevoked, err := tokenRevocationSvc.IsAccessTokenRevoked(ctx, "token-2")
+		assert.NoError(t, err)
+		assert.False(t, isRevoked)
+	})
+}
+
+func TestRevokeUserAccessTokens(t *testing.T) {
+	ctx := context.Background()
+	tokenRevocationSvc, redisServer := initTokenRevocationSvc(t)
+
+	t.Run("revokes every tracked token of the user", func(t *testing.T) {
+		assert.NoError(t, tokenRevocationSvc.TrackAccessToken(ctx, userID.String(), "token-1"))
+		assert.NoError(t, tokenRevocationSvc.TrackAccessToken(ctx, userID.String(), "token-2"))
+		assert.NoError(t, tokenRevocationSvc.TrackAccessToken(ctx, "other-user", "token-3"))
+
+		err := tokenRevocationSvc.RevokeUserAccessTokens(ctx, userID.String())
+		assert.NoError(t, err)
+
+		for token, expected := range map[string]bool{
+			"token-1": true,
+			"token-2": true,
+			"token-3": false,
+		} {
+			isRevoked, err := tokenRevocationSvc.IsAccessTokenRevoked(ctx, token)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, isRevoked, token)
+		}
+		assert.False(t, redisServer.Exists(userAccessTokensKey(userID.String())))
+	})
+
+	t.Run("user without tracked tokens", func(t *testing.T) {
+		err := tokenRevocationSvc.RevokeUserAccessTokens(ctx, "unknown-user")
+		assert.NoError(t, err)
+	})
+
+	t.Run("redis unavailable", func(t *testing.T) {
+		redisServer.Close()
+
+		err := tokenRevocationSvc.RevokeUserAccessTokens(ctx, userID.String())
+		assert.Error(t, err)
+
+		_, err = tokenRevocationSvc.IsAccessTokenRevoked(ctx, "token-1")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gadhittana-01/book-go/config"
+	"github.com/gadhittana-01/book-go/constant"
+	"github.com/gadhittana01/go-modules/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+type TwoFactorChallengeSvc interface {
+	CreateChallenge(ctx context.Context, userID string) (string, int64, error)
+	FindChallenge(ctx context.Context, token string) (string, error)
+	RecordFailure(ctx context.Context, token string) error
+	DeleteChallenge(ctx context.Context, token string) error
+}
+
+// TwoFactorChallengeSvcImpl keeps the challenges handed out by a password
+// sign-in of an account with two-factor authentication in Redis, keyed by the
+// hash of the challenge token. A challenge lives for
+// TWO_FACTOR_CHALLENGE_DURATION and is dropped after TWO_FACTOR_MAX_ATTEMPTS
+// wrong codes, so the password has to be entered again.
+type TwoFactorChallengeSvcImpl struct {
+	client    utils.RedisClient
+	appConfig *config.AppConfig
+}
+
+func NewTwoFactorChallengeSvc(
+	client utils.RedisClient,
+	appConfig *config.AppConfig,
+) TwoFactorChallengeSvc {
+	return &TwoFactorChallengeSvcImpl{
+		client:    client,
+		appConfig: appConfig,
+	}
+}
+
+// CreateChallenge returns a new challenge token for the user and the unix
+// time it expires at.
+func (s *TwoFactorChallengeSvcImpl) CreateChallenge(ctx context.Context, userID string) (string, int64, error) {
+	token, err := generateOpaqueToken()
+	if err != nil {
+		return "", 0, err
+	}
+
+	expiresAt := time.Now().Add(s.appConfig.TwoFactorChallengeDuration)
+
+	err = s.client.Set(ctx, twoFactorChallengeKey(hashToken(token)), userID, s.appConfig.TwoFactorChallengeDuration).Err()
+	if err != nil {
+		return "", 0, err
+	}
+
+	return token, expiresAt.Unix(), nil
+}
+
+// FindChallenge returns the user ID the challenge was created for, or an
+// empty string when it does not exist or has expired.
+func (s *TwoFactorChallengeSvcImpl) FindChallenge(ctx context.Context, token string) (string, error) {
+	var userID *redis.StringCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		userID = pipe.Get(ctx, twoFactorChallengeKey(hashToken(token)))
+		return nil
+	})
+	if err == redis.Nil {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return userID.Val(), nil
+}
+
+func (s *TwoFactorChallengeSvcImpl) RecordFailure(ctx context.Context, token string) error {
+	tokenHash := hashToken(token)
+	key := twoFactorAttemptKey(tokenHash)
+
+	var attempts *redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		attempts = pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, s.appConfig.TwoFactorChallengeDuration)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if attempts.Val() < int64(s.appConfig.TwoFactorMaxAttempts) {
+		return nil
+	}
+
+	return s.deleteChallenge(ctx, tokenHash)
+}
+
+func (s *TwoFactorChallengeSvcImpl) DeleteChallenge(ctx context.Context, token string) error {
+	return s.deleteChallenge(ctx, hashToken(token))
+}
+
+func (s *TwoFactorChallengeSvcImpl) deleteChallenge(ctx context.Context, tokenHash string) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, twoFactorChallengeKey(tokenHash), twoFactorAttemptKey(tokenHash))
+		return nil
+	})
+
+	return err
+}
+
+func twoFactorChallengeKey(tokenHash string) string {
+	return fmt.Sprintf("%s:%s", constant.TwoFactorChallengeKey, tokenHash)
+}
+
+func twoFactorAttemptKey(tokenHash string) string {
+	return fmt.Sprintf("%s:%s", constant.TwoFactorAttemptKey, tokenHash)
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	appConfig "github.com/gadhittana-01/book-go/config"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func initTwoFactorChallengeSvc(t *testing.T) (TwoFactorChallengeSvc, *miniredis.Miniredis) {
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(redisServer.Close)
+
+	return NewTwoFactorChallengeSvc(redis.NewClient(&redis.Options{
+		Addr: redisServer.Addr(),
+	}), &appConfig.AppConfig{
+		TwoFactorChallengeDuration: 5 * time.Minute,
+		TwoFactorMaxAttempts:       3,
+	}), redisServer
+}
+
+func TestTwoFactorChallenge(t *testing.T) {
+	ctx := context.Background()
+	twoFactorChallengeSvc, redisServer := initTwoFactorChallengeSvc(t)
+	userID := "8f3b6a5e-2b1c-4c8e-9a57-1d2e3f4a5b6c"
+
+	token, expChallenge, err := twoFactorChallengeSvc.CreateChallenge(ctx, userID)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.InDelta(t, time.Now().Add(5*time.Minute).Unix(), expChallenge, 1)
+
+	t.Run("only the token hash is stored", func(t *testing.T) {
+		assert.False(t, redisServer.Exists(twoFactorChallengeKey(token)))
+		assert.True(t, redisServer.Exists(twoFactorChallengeKey(hashToken(token))))
+		assert.Equal(t, 5*time.Minute, redisServer.TTL(twoFactorChallengeKey(hashToken(token))))
+	})
+
+	t.Run("find challenge", func(t *testing.T) {
+		res, err := twoFactorChallengeSvc.FindChallenge(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, res)
+	})
+
+	t.Run("unknown challenge", func(t *testing.T) {
+		res, err := twoFactorChallengeSvc.FindChallenge(ctx, "unknown")
+		assert.NoError(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("challenge survives failures below the limit", func(t *testing.T) {
+		assert.NoError(t, twoFactorChallengeSvc.RecordFailure(ctx, token))
+		assert.NoError(t, twoFactorChallengeSvc.RecordFailure(ctx, token))
+
+		res, err := twoFactorChallengeSvc.FindChallenge(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, res)
+	})
+
+	t.Run("challenge is dropped at the limit", func(t *testing.T) {
+		assert.NoError(t, twoFactorChallengeSvc.RecordFailure(ctx, token))
+
+		res, err := twoFactorChallengeSvc.FindChallenge(ctx, token)
+		assert.NoError(t, err)
+		assert.Empty(t, res)
+		assert.False(t, redisServer.Exists(twoFactorAttemptKey(hashToken(token))))
+	})
+}
+
+func TestTwoFactorChallengeDelete(t *testing.T) {
+	ctx := context.Background()
+	twoFactorChallengeSvc, _ := initTwoFactorChallengeSvc(t)
+
+	token, _, err := twoFactorChallengeSvc.CreateChallenge(ctx, "user-id")
+	assert.NoError(t, err)
+
+	assert.NoError(t, twoFactorChallengeSvc.DeleteChallenge(ctx, token))
+
+	res, err := twoFactorChallengeSvc.FindChallenge(ctx, token)
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+}
+
+func TestTwoFactorChallengeExpires(t *testing.T) {
+	ctx := context.Background()
+	twoFactorChallengeSvc, redisServer := initTwoFactorChallengeSvc(t)
+
+	token, _, err := twoFactorChallengeSvc.CreateChallenge(ctx, "user-id")
+	assert.NoError(t, err)
+
+	redisServer.FastForward(5 * time.Minute)
+
+	res, err := twoFactorChallengeSvc.FindChallenge(ctx, token)
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+}
@@ -2,52 +2,180 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/gadhittana-01/book-go/config"
+	"github.com/gadhittana-01/book-go/constant"
 	querier "github.com/gadhittana-01/book-go/db/repository"
 	"github.com/gadhittana-01/book-go/dto"
+	"github.com/gadhittana-01/book-go/mailer"
+	"github.com/gadhittana-01/book-go/metrics"
+	"github.com/gadhittana-01/book-go/totp"
+	utilsConstant "github.com/gadhittana01/go-modules/constant"
 	"github.com/gadhittana01/go-modules/utils"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/samber/lo"
 )
 
 const (
-	FailedToParseDate        = "Failed to parse date"
-	FailedToCreateUser       = "Failed to create user"
-	EmailAlreadyExist        = "Email already exist"
-	FailedToFindUser         = "Failed to find user"
-	FailedToCheckEmailExists = "Failed to check email exists"
-	FailedToHashPassword     = "Failed to hash password"
-	WrongCredentials         = "Wrong credentials"
-	FailedToGenerateToken    = "Failed to generate token"
-	UserNotFound             = "User not found"
+	FailedToParseDate           = "Failed to parse date"
+	FailedToCreateUser          = "Failed to create user"
+	EmailAlreadyExist           = "Email already exist"
+	FailedToFindUser            = "Failed to find user"
+	FailedToCheckEmailExists    = "Failed to check email exists"
+	FailedToHashPassword        = "Failed to hash password"
+	InvalidCredentials          = "Invalid credentials"
+	FailedToGenerateToken       = "Failed to generate token"
+	UserNotFound                = "User not found"
+	FailedToUpdateUserRole      = "Failed to update user role"
+	AdminPasswordRequired       = "ADMIN_PASSWORD is required to bootstrap a new admin"
+	FailedToGenerateRefresh     = "Failed to generate refresh token"
+	FailedToCreateSession       = "Failed to create session"
+	FailedToFindSession         = "Failed to find session"
+	FailedToRevokeSession       = "Failed to revoke session"
+	FailedToTrackAccessToken    = "Failed to track access token"
+	FailedToRevokeToken         = "Failed to revoke access token"
+	InvalidRefreshToken         = "Invalid refresh token"
+	RefreshTokenExpired         = "Refresh token has expired"
+	FailedToGenerateUserToken   = "Failed to generate user token"
+	FailedToCreateUserToken     = "Failed to create user token"
+	FailedToFindUserToken       = "Failed to find user token"
+	FailedToInvalidateTokens    = "Failed to invalidate user tokens"
+	FailedToUseUserToken        = "Failed to use user token"
+	InvalidUserToken            = "Invalid or already used token"
+	UserTokenExpired            = "Token has expired"
+	FailedToUpdatePassword      = "Failed to update password"
+	FailedToVerifyEmail         = "Failed to verify email"
+	EmailAlreadyVerified        = "Email is already verified"
+	FailedToSendMail            = "Failed to send mail"
+	FailedToCheckSignInAttempts = "Failed to check sign-in attempts"
+	FailedToRecordSignInAttempt = "Failed to record sign-in attempt"
+	TooManySignInAttempts       = "Too many failed sign-in attempts, please try again later"
+	FailedToGenerateTOTPSecret  = "Failed to generate two-factor secret"
+	FailedToEnrollTwoFactor     = "Failed to enroll two-factor authentication"
+	FailedToEnableTwoFactor     = "Failed to enable two-factor authentication"
+	FailedToGenerateRecovery    = "Failed to generate recovery codes"
+	FailedToSaveRecoveryCodes   = "Failed to save recovery codes"
+	FailedToCheckTwoFactorCode  = "Failed to check two-factor code"
+	FailedToCreateChallenge     = "Failed to create two-factor challenge"
+	FailedToFindChallenge       = "Failed to find two-factor challenge"
+	FailedToDeleteChallenge     = "Failed to delete two-factor challenge"
+	FailedToRecordTwoFactor     = "Failed to record two-factor attempt"
+	TwoFactorAlreadyEnabled     = "Two-factor authentication is already enabled"
+	TwoFactorNotEnrolled        = "Two-factor authentication has not been enrolled"
+	InvalidTwoFactorCode        = "Invalid two-factor code"
+	InvalidChallengeToken       = "Invalid or expired challenge token"
+	FailedToUpdateProfile       = "Failed to update profile"
+	IncorrectPassword           = "Old password is incorrect"
+	CurrentPasswordRequired     = "Current password is required to change the email"
+	IncorrectCurrentPassword    = "Current password is incorrect"
+	FailedToDeleteAccount       = "Failed to delete account"
 )
 
+// dummyPasswordHash is compared against when signing in with an unknown email.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, err := utils.HashPassword("dummy-password")
+	if err != nil {
+		panic(err)
+	}
+
+	return hash
+})
+
+// opaqueTokenBytes is the amount of randomness in a refresh, password reset,
+// email verification or two-factor challenge token before encoding.
+const opaqueTokenBytes = 32
+
+const (
+	// recoveryCodeCount is how many recovery codes enabling two-factor
+	// authentication hands out.
+	recoveryCodeCount = 10
+	// recoveryCodeLength is the number of base32 characters in a recovery
+	// code, 50 bits of randomness.
+	recoveryCodeLength = 10
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
 type UserSvc interface {
 	SignUp(ctx context.Context, input dto.SignUpReq) dto.SignUpRes
 	SignIn(ctx context.Context, input dto.SignInReq) dto.SignInRes
+	RefreshToken(ctx context.Context, input dto.RefreshTokenReq) dto.RefreshTokenRes
+	SignOut(ctx context.Context, input dto.SignOutReq)
+	SignOutAll(ctx context.Context, input dto.SignOutAllReq)
+	ForgotPassword(ctx context.Context, input dto.ForgotPasswordReq)
+	ResetPassword(ctx context.Context, input dto.ResetPasswordReq)
+	VerifyEmail(ctx context.Context, input dto.VerifyEmailReq)
+	ResendVerificationEmail(ctx context.Context)
+	EnrollTwoFactor(ctx context.Context) dto.EnrollTwoFactorRes
+	VerifyTwoFactor(ctx context.Context, input dto.VerifyTwoFactorReq) dto.VerifyTwoFactorRes
+	SignInTwoFactor(ctx context.Context, input dto.SignInTwoFactorReq) dto.SignInRes
+	GetProfile(ctx context.Context) dto.ProfileRes
+	UpdateProfile(ctx context.Context, input dto.UpdateProfileReq) dto.ProfileRes
+	ChangePassword(ctx context.Context, input dto.ChangePasswordReq)
+	ExportData(ctx context.Context) dto.ExportDataRes
+	DeleteAccount(ctx context.Context, input dto.DeleteAccountReq)
 }
 
 type UserSvcImpl struct {
-	repo   querier.Repository
-	config *utils.BaseConfig
-	token  utils.TokenClient
+	repo                  querier.Repository
+	config                *utils.BaseConfig
+	appConfig             *config.AppConfig
+	token                 utils.TokenClient
+	tokenRevocationSvc    TokenRevocationSvc
+	loginAttemptSvc       LoginAttemptSvc
+	twoFactorChallengeSvc TwoFactorChallengeSvc
+	mailer                mailer.Mailer
+	cacheSvc              utils.CacheSvc
 }
 
 func NewUserSvc(
 	repo querier.Repository,
 	config *utils.BaseConfig,
+	appConfig *config.AppConfig,
 	token utils.TokenClient,
+	tokenRevocationSvc TokenRevocationSvc,
+	loginAttemptSvc LoginAttemptSvc,
+	twoFactorChallengeSvc TwoFactorChallengeSvc,
+	mailer mailer.Mailer,
+	cacheSvc utils.CacheSvc,
 ) UserSvc {
 	return &UserSvcImpl{
-		repo:   repo,
-		config: config,
-		token:  token,
+		repo:                  repo,
+		config:                config,
+		appConfig:             appConfig,
+		token:                 token,
+		tokenRevocationSvc:    tokenRevocationSvc,
+		loginAttemptSvc:       loginAttemptSvc,
+		twoFactorChallengeSvc: twoFactorChallengeSvc,
+		mailer:                mailer,
+		cacheSvc:              cacheSvc,
 	}
 }
 
+// sessionTokens is the access and refresh token pair handed out on sign up,
+// sign in and refresh.
+type sessionTokens struct {
+	token           string
+	expToken        int64
+	refreshToken    string
+	expRefreshToken int64
+}
+
 func (s *UserSvcImpl) SignUp(ctx context.Context, input dto.SignUpReq) dto.SignUpRes {
 	var resp dto.SignUpRes
 	var user querier.User
-	var token utils.GenerateTokenResp
+	var tokens sessionTokens
+	var verificationToken string
 
 	err := utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
 		repoTx := s.repo.WithTx(tx)
@@ -70,27 +198,38 @@ func (s *UserSvcImpl) SignUp(ctx context.Context, input dto.SignUpReq) dto.SignU
 			Name:     input.Name,
 			Email:    input.Email,
 			Password: pwd,
+			Role:     constant.RoleCustomer,
 		})
 		if err != nil {
 			return utils.CustomErrorWithTrace(err, FailedToCreateUser, 422)
 		}
 
-		token, err = s.token.GenerateToken(utils.GenerateTokenReq{
-			UserID: user.ID.String(),
-		})
+		verificationToken, err = s.createUserToken(ctx, repoTx, user.ID, constant.UserTokenEmailVerification, s.appConfig.EmailVerificationTokenDuration)
 		if err != nil {
-			return utils.CustomErrorWithTrace(err, FailedToGenerateToken, 400)
+			return err
 		}
 
-		return nil
+		tokens, err = s.issueSessionTokens(ctx, repoTx, user.ID)
+		return err
 	})
 	utils.PanicIfError(err)
+	metrics.RecordSignUp()
+
+	// the account already exists at this point, so a mail failure must not
+	// fail the sign up; the user can ask for a new verification email
+	err = s.mailer.Send(ctx, emailVerificationMessage(user, s.appConfig.AppURL, verificationToken))
+	if err != nil {
+		utils.LogInfo(fmt.Sprintf("%s: %v", FailedToSendMail, err))
+	}
 
 	resp = dto.SignUpRes{
-		ID:       user.ID.String(),
-		Name:     user.Name,
-		Token:    token.Token,
-		ExpToken: token.ExpToken,
+		ID:              user.ID.String(),
+		Name:            user.Name,
+		Role:            user.Role,
+		Token:           tokens.token,
+		ExpToken:        tokens.expToken,
+		RefreshToken:    tokens.refreshToken,
+		ExpRefreshToken: tokens.expRefreshToken,
 	}
 
 	return resp
@@ -101,30 +240,927 @@ func (s *UserSvcImpl) SignIn(ctx context.Context, input dto.SignInReq) dto.SignI
 	var user querier.User
 	var err error
 
+	isLockedOut, err := s.loginAttemptSvc.IsLockedOut(ctx, input.Email, input.IP)
+	utils.PanicIfAppError(err, FailedToCheckSignInAttempts, 500)
+
+	if isLockedOut {
+		metrics.RecordFailedLogin(constant.LoginFailureLockedOut)
+		utils.PanicAppError(TooManySignInAttempts, 429)
+	}
+
 	user, err = s.repo.FindUserByEmail(ctx, input.Email)
 	if err != nil && err != pgx.ErrNoRows {
 		utils.PanicIfAppError(err, FailedToFindUser, 400)
 	}
 
+	// unknown emails still pay for a bcrypt comparison and get the same
+	// answer as a wrong password, so neither the message nor the response
+	// time tells whether an account exists
+	isUnknownEmail := err == pgx.ErrNoRows
+	passwordHash := user.Password
+	if isUnknownEmail {
+		passwordHash = dummyPasswordHash()
+	}
+
+	if !utils.IsCorrectPassword(input.Password, passwordHash) || isUnknownEmail {
+		metrics.RecordFailedLogin(constant.LoginFailureInvalidCredentials)
+		err = s.loginAttemptSvc.RecordFailure(ctx, input.Email, input.IP)
+		utils.PanicIfAppError(err, FailedToRecordSignInAttempt, 500)
+
+		utils.PanicAppError(InvalidCredentials, 400)
+	}
+
+	// the password alone is not enough; the caller gets a challenge to
+	// exchange for the tokens together with a code at SignInTwoFactor, and
+	// the failed attempts are only reset once that code was right
+	if user.TotpEnabledAt.Valid {
+		challengeToken, expChallengeToken, err := s.twoFactorChallengeSvc.CreateChallenge(ctx, user.ID.String())
+		utils.PanicIfAppError(err, FailedToCreateChallenge, 500)
+
+		return dto.SignInRes{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+			ExpChallengeToken: expChallengeToken,
+		}
+	}
+
+	err = s.loginAttemptSvc.Reset(ctx, input.Email)
+	utils.PanicIfAppError(err, FailedToRecordSignInAttempt, 500)
+
+	tokens, err := s.issueSessionTokens(ctx, s.repo, user.ID)
+	utils.PanicIfError(err)
+
+	resp = dto.SignInRes{
+		ID:              user.ID.String(),
+		Name:            user.Name,
+		Role:            user.Role,
+		Token:           tokens.token,
+		ExpToken:        tokens.expToken,
+		RefreshToken:    tokens.refreshToken,
+		ExpRefreshToken: tokens.expRefreshToken,
+	}
+
+	return resp
+}
+
+// SignInTwoFactor finishes the sign-in of an account with two-factor
+// authentication. It takes the challenge token from SignIn and either a code
+// from the authenticator app or an unused recovery code. Too many wrong codes
+// drop the challenge, so the password has to be given again, and every wrong
+// code counts against the email and IP lockout of SignIn, so fresh challenges
+// do not give unlimited guesses.
+func (s *UserSvcImpl) SignInTwoFactor(ctx context.Context, input dto.SignInTwoFactorReq) dto.SignInRes {
+	challengeUserID, err := s.twoFactorChallengeSvc.FindChallenge(ctx, input.ChallengeToken)
+	utils.PanicIfAppError(err, FailedToFindChallenge, 500)
+
+	if challengeUserID == "" {
+		utils.PanicAppError(InvalidChallengeToken, 401)
+	}
+
+	userID, err := uuid.Parse(challengeUserID)
+	utils.PanicIfAppError(err, FailedToParseStringToUUID, 400)
+
+	user, err := s.repo.FindUserByID(ctx, userID)
+	if err == pgx.ErrNoRows {
+		utils.PanicAppError(InvalidChallengeToken, 401)
+	}
+	utils.PanicIfAppError(err, FailedToFindUser, 400)
+
+	isLockedOut, err := s.loginAttemptSvc.IsLockedOut(ctx, user.Email, input.IP)
+	utils.PanicIfAppError(err, FailedToCheckSignInAttempts, 500)
+
+	if isLockedOut {
+		metrics.RecordFailedLogin(constant.LoginFailureLockedOut)
+		utils.PanicAppError(TooManySignInAttempts, 429)
+	}
+
+	isValid := false
+	if user.TotpEnabledAt.Valid {
+		isValid, err = s.checkTwoFactorCode(ctx, user, input.Code)
+		utils.PanicIfAppError(err, FailedToCheckTwoFactorCode, 422)
+	}
+
+	if !isValid {
+		metrics.RecordFailedLogin(constant.LoginFailureInvalidTwoFactor)
+		err = s.twoFactorChallengeSvc.RecordFailure(ctx, input.ChallengeToken)
+		utils.PanicIfAppError(err, FailedToRecordTwoFactor, 500)
+
+		err = s.loginAttemptSvc.RecordFailure(ctx, user.Email, input.IP)
+		utils.PanicIfAppError(err, FailedToRecordSignInAttempt, 500)
+
+		utils.PanicAppError(InvalidTwoFactorCode, 401)
+	}
+
+	err = s.twoFactorChallengeSvc.DeleteChallenge(ctx, input.ChallengeToken)
+	utils.PanicIfAppError(err, FailedToDeleteChallenge, 500)
+
+	err = s.loginAttemptSvc.Reset(ctx, user.Email)
+	utils.PanicIfAppError(err, FailedToRecordSignInAttempt, 500)
+
+	tokens, err := s.issueSessionTokens(ctx, s.repo, user.ID)
+	utils.PanicIfError(err)
+
+	return dto.SignInRes{
+		ID:              user.ID.String(),
+		Name:            user.Name,
+		Role:            user.Role,
+		Token:           tokens.token,
+		ExpToken:        tokens.expToken,
+		RefreshToken:    tokens.refreshToken,
+		ExpRefreshToken: tokens.expRefreshToken,
+	}
+}
+
+// RefreshToken rotates a refresh token: the presented session is revoked and
+// a new access and refresh token pair is issued in its place.
+func (s *UserSvcImpl) RefreshToken(ctx context.Context, input dto.RefreshTokenReq) dto.RefreshTokenRes {
+	var tokens sessionTokens
+	var isReused bool
+
+	err := utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		repoTx := s.repo.WithTx(tx)
+
+		session, err := repoTx.FindUserSessionByRefreshTokenHashForUpdate(ctx, hashToken(input.RefreshToken))
+		if err == pgx.ErrNoRows {
+			return utils.CustomError(InvalidRefreshToken, 401)
+		}
+
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToFindSession, 400)
+		}
+
+		// a revoked refresh token coming back means it has leaked, so every
+		// session of the user is ended instead of only this one. The
+		// revocation has to commit, hence the flag rather than an error.
+		if session.RevokedAt.Valid {
+			isReused = true
+			return s.revokeAllSessions(ctx, repoTx, session.UserID)
+		}
+
+		if time.Now().After(session.ExpiresAt) {
+			return utils.CustomError(RefreshTokenExpired, 401)
+		}
+
+		err = repoTx.RevokeUserSessionByID(ctx, session.ID)
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToRevokeSession, 422)
+		}
+
+		tokens, err = s.issueSessionTokens(ctx, repoTx, session.UserID)
+		return err
+	})
+	utils.PanicIfError(err)
+
+	if isReused {
+		utils.PanicAppError(InvalidRefreshToken, 401)
+	}
+
+	return dto.RefreshTokenRes{
+		Token:           tokens.token,
+		ExpToken:        tokens.expToken,
+		RefreshToken:    tokens.refreshToken,
+		ExpRefreshToken: tokens.expRefreshToken,
+	}
+}
+
+// SignOut ends the session of the given refresh token and revokes the access
+// token the request was made with.
+func (s *UserSvcImpl) SignOut(ctx context.Context, input dto.SignOutReq) {
+	authPayload := utils.GetRequestCtx(ctx, utilsConstant.UserSession)
+
+	userID, err := uuid.Parse(authPayload.UserID)
+	utils.PanicIfAppError(err, FailedToParseStringToUUID, 400)
+
+	err = s.repo.RevokeUserSessionByRefreshTokenHash(ctx, querier.RevokeUserSessionByRefreshTokenHashParams{
+		UserID:           userID,
+		RefreshTokenHash: hashToken(input.RefreshToken),
+	})
+	utils.PanicIfAppError(err, FailedToRevokeSession, 422)
+
+	err = s.tokenRevocationSvc.RevokeAccessToken(ctx, input.AccessToken)
+	utils.PanicIfAppError(err, FailedToRevokeToken, 500)
+}
+
+// SignOutAll ends every session of the caller and revokes all of their
+// access tokens, including the one the request was made with.
+func (s *UserSvcImpl) SignOutAll(ctx context.Context, input dto.SignOutAllReq) {
+	authPayload := utils.GetRequestCtx(ctx, utilsConstant.UserSession)
+
+	userID, err := uuid.Parse(authPayload.UserID)
+	utils.PanicIfAppError(err, FailedToParseStringToUUID, 400)
+
+	err = s.revokeAllSessions(ctx, s.repo, userID)
+	utils.PanicIfError(err)
+
+	// tokens issued before tracking existed are not in the tracked set, so
+	// the current one is revoked explicitly
+	err = s.tokenRevocationSvc.RevokeAccessToken(ctx, input.AccessToken)
+	utils.PanicIfAppError(err, FailedToRevokeToken, 500)
+}
+
+// ForgotPassword mails a password reset link to the account of the given
+// email. Unknown emails are answered the same way, so the endpoint cannot be
+// used to find out who has an account.
+func (s *UserSvcImpl) ForgotPassword(ctx context.Context, input dto.ForgotPasswordReq) {
+	user, err := s.repo.FindUserByEmail(ctx, input.Email)
+	if err == pgx.ErrNoRows {
+		return
+	}
+	utils.PanicIfAppError(err, FailedToFindUser, 400)
+
+	var resetToken string
+	err = utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		var err error
+		resetToken, err = s.createUserToken(ctx, s.repo.WithTx(tx), user.ID, constant.UserTokenPasswordReset, s.appConfig.PasswordResetTokenDuration)
+		return err
+	})
+	utils.PanicIfError(err)
+
+	// a mail failure is only logged, since answering differently than for an
+	// unknown email would tell that the account exists
+	err = s.mailer.Send(ctx, passwordResetMessage(user, s.appConfig.AppURL, resetToken))
+	if err != nil {
+		utils.LogInfo(fmt.Sprintf("%s: %v", FailedToSendMail, err))
+	}
+}
+
+// ResetPassword sets a new password using a token from ForgotPassword and
+// ends every session of the account, so whoever knew the old password is
+// signed out.
+func (s *UserSvcImpl) ResetPassword(ctx context.Context, input dto.ResetPasswordReq) {
+	pwd, err := utils.HashPassword(input.Password)
+	utils.PanicIfAppError(err, FailedToHashPassword, 400)
+
+	err = utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		repoTx := s.repo.WithTx(tx)
+
+		userToken, err := useUserToken(ctx, repoTx, input.Token, constant.UserTokenPasswordReset)
+		if err != nil {
+			return err
+		}
+
+		err = repoTx.UpdateUserPasswordByID(ctx, querier.UpdateUserPasswordByIDParams{
+			ID:       userToken.UserID,
+			Password: pwd,
+		})
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToUpdatePassword, 422)
+		}
+
+		// the token was delivered to the account's inbox, which proves
+		// ownership just as well as a verification token
+		err = repoTx.VerifyUserEmailByID(ctx, userToken.UserID)
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToVerifyEmail, 422)
+		}
+
+		return s.revokeAllSessions(ctx, repoTx, userToken.UserID)
+	})
+	utils.PanicIfError(err)
+}
+
+func (s *UserSvcImpl) VerifyEmail(ctx context.Context, input dto.VerifyEmailReq) {
+	err := utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		repoTx := s.repo.WithTx(tx)
+
+		userToken, err := useUserToken(ctx, repoTx, input.Token, constant.UserTokenEmailVerification)
+		if err != nil {
+			return err
+		}
+
+		err = repoTx.VerifyUserEmailByID(ctx, userToken.UserID)
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToVerifyEmail, 422)
+		}
+
+		return nil
+	})
+	utils.PanicIfError(err)
+}
+
+// ResendVerificationEmail mails the caller a new verification link. Links sent
+// earlier stop working.
+func (s *UserSvcImpl) ResendVerificationEmail(ctx context.Context) {
+	user := s.findCurrentUser(ctx)
+
+	if user.EmailVerifiedAt.Valid {
+		utils.PanicAppError(EmailAlreadyVerified, 400)
+	}
+
+	var verificationToken string
+	err := utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		var err error
+		verificationToken, err = s.createUserToken(ctx, s.repo.WithTx(tx), user.ID, constant.UserTokenEmailVerification, s.appConfig.EmailVerificationTokenDuration)
+		return err
+	})
+	utils.PanicIfError(err)
+
+	err = s.mailer.Send(ctx, emailVerificationMessage(user, s.appConfig.AppURL, verificationToken))
+	utils.PanicIfAppError(err, FailedToSendMail, 500)
+}
+
+// EnrollTwoFactor generates a new TOTP secret for the caller. Two-factor
+// authentication is only enabled once VerifyTwoFactor sees a matching code, so
+// enrolling again before that simply replaces the secret.
+func (s *UserSvcImpl) EnrollTwoFactor(ctx context.Context) dto.EnrollTwoFactorRes {
+	user := s.findCurrentUser(ctx)
+
+	if user.TotpEnabledAt.Valid {
+		utils.PanicAppError(TwoFactorAlreadyEnabled, 400)
+	}
+
+	secret, err := totp.GenerateSecret()
+	utils.PanicIfAppError(err, FailedToGenerateTOTPSecret, 500)
+
+	err = s.repo.SetUserTotpSecretByID(ctx, querier.SetUserTotpSecretByIDParams{
+		ID:         user.ID,
+		TotpSecret: sql.NullString{String: secret, Valid: true},
+	})
+	utils.PanicIfAppError(err, FailedToEnrollTwoFactor, 422)
+
+	return dto.EnrollTwoFactorRes{
+		Secret:     secret,
+		OtpauthURI: totp.URI(s.appConfig.TOTPIssuer, user.Email, secret),
+	}
+}
+
+// VerifyTwoFactor enables two-factor authentication once the caller shows a
+// code from the enrolled secret, and returns the recovery codes for when the
+// authenticator app is lost. Only their hashes are stored, so they are shown
+// this once.
+func (s *UserSvcImpl) VerifyTwoFactor(ctx context.Context, input dto.VerifyTwoFactorReq) dto.VerifyTwoFactorRes {
+	user := s.findCurrentUser(ctx)
+
+	if user.TotpEnabledAt.Valid {
+		utils.PanicAppError(TwoFactorAlreadyEnabled, 400)
+	}
+
+	if !user.TotpSecret.Valid {
+		utils.PanicAppError(TwoFactorNotEnrolled, 400)
+	}
+
+	step, ok := totp.Validate(user.TotpSecret.String, strings.TrimSpace(input.Code), time.Now())
+	if !ok {
+		utils.PanicAppError(InvalidTwoFactorCode, 400)
+	}
+
+	recoveryCodes, codeHashes, err := generateRecoveryCodes()
+	utils.PanicIfAppError(err, FailedToGenerateRecovery, 500)
+
+	err = utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		repoTx := s.repo.WithTx(tx)
+
+		// the verified step counts as used, so the same code cannot also
+		// complete a sign-in
+		err := repoTx.EnableUserTotpByID(ctx, querier.EnableUserTotpByIDParams{
+			ID:           user.ID,
+			TotpLastStep: step,
+		})
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToEnableTwoFactor, 422)
+		}
+
+		err = repoTx.DeleteUserRecoveryCodesByUserID(ctx, user.ID)
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToSaveRecoveryCodes, 422)
+		}
+
+		err = repoTx.CreateUserRecoveryCodes(ctx, querier.CreateUserRecoveryCodesParams{
+			UserID:     user.ID,
+			CodeHashes: codeHashes,
+		})
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToSaveRecoveryCodes, 422)
+		}
+
+		return nil
+	})
+	utils.PanicIfError(err)
+
+	return dto.VerifyTwoFactorRes{
+		RecoveryCodes: recoveryCodes,
+	}
+}
+
+func (s *UserSvcImpl) GetProfile(ctx context.Context) dto.ProfileRes {
+	return profileRes(s.findCurrentUser(ctx))
+}
+
+// UpdateProfile changes the caller's name and email. A new email has to be
+// verified again, so a verification link is mailed to it.
+func (s *UserSvcImpl) UpdateProfile(ctx context.Context, input dto.UpdateProfileReq) dto.ProfileRes {
+	user := s.findCurrentUser(ctx)
+
+	oldEmail := user.Email
+	email := lo.FromPtrOr(input.Email, user.Email)
+	isEmailChanged := normalizeEmail(email) != oldEmail
+
+	// together with ForgotPassword a new email hands over the account, so it
+	// takes the password and not just an access token
+	if isEmailChanged {
+		if input.CurrentPassword == "" {
+			utils.PanicAppError(CurrentPasswordRequired, 400)
+		}
+
+		s.confirmPassword(ctx, user, input.CurrentPassword, IncorrectCurrentPassword)
+	}
+
+	var verificationToken string
+	err := utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		repoTx := s.repo.WithTx(tx)
+
+		if isEmailChanged {
+			isExists, err := repoTx.CheckEmailExists(ctx, email)
+			if err != nil {
+				return utils.CustomErrorWithTrace(err, FailedToCheckEmailExists, 400)
+			}
+
+			if isExists {
+				return utils.CustomError(EmailAlreadyExist, 400)
+			}
+		}
+
+		var err error
+		user, err = repoTx.UpdateUserProfileByID(ctx, querier.UpdateUserProfileByIDParams{
+			ID:    user.ID,
+			Name:  strings.TrimSpace(lo.FromPtrOr(input.Name, user.Name)),
+			Email: email,
+		})
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToUpdateProfile, 422)
+		}
+
+		if isEmailChanged {
+			verificationToken, err = s.createUserToken(ctx, repoTx, user.ID, constant.UserTokenEmailVerification, s.appConfig.EmailVerificationTokenDuration)
+			return err
+		}
+
+		return nil
+	})
+	utils.PanicIfError(err)
+
+	// the change is already saved, so as on sign up a mail failure only gets
+	// logged; the user can ask for a new verification email
+	if isEmailChanged {
+		err = s.loginAttemptSvc.Reset(ctx, oldEmail)
+		utils.PanicIfAppError(err, FailedToRecordSignInAttempt, 500)
+
+		err = s.mailer.Send(ctx, emailChangedMessage(user, oldEmail))
+		if err != nil {
+			utils.LogInfo(fmt.Sprintf("%s: %v", FailedToSendMail, err))
+		}
+
+		err = s.mailer.Send(ctx, emailVerificationMessage(user, s.appConfig.AppURL, verificationToken))
+		if err != nil {
+			utils.LogInfo(fmt.Sprintf("%s: %v", FailedToSendMail, err))
+		}
+	}
+
+	return profileRes(user)
+}
+
+// ChangePassword sets a new password once the caller confirms the current one
+// and ends every session of the account, the current one included.
+func (s *UserSvcImpl) ChangePassword(ctx context.Context, input dto.ChangePasswordReq) {
+	user := s.findCurrentUser(ctx)
+
+	s.confirmPassword(ctx, user, input.OldPassword, IncorrectPassword)
+
+	pwd, err := utils.HashPassword(input.NewPassword)
+	utils.PanicIfAppError(err, FailedToHashPassword, 400)
+
+	err = utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		repoTx := s.repo.WithTx(tx)
+
+		err := repoTx.UpdateUserPasswordByID(ctx, querier.UpdateUserPasswordByIDParams{
+			ID:       user.ID,
+			Password: pwd,
+		})
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToUpdatePassword, 422)
+		}
+
+		return s.revokeAllSessions(ctx, repoTx, user.ID)
+	})
+	utils.PanicIfError(err)
+
+	err = s.loginAttemptSvc.Reset(ctx, user.Email)
+	utils.PanicIfAppError(err, FailedToRecordSignInAttempt, 500)
+}
+
+// confirmPassword panics with incorrectMsg unless password is the one of user.
+// Wrong passwords count towards the sign-in lockout of the email, so a stolen
+// access token cannot be used to guess the password.
+func (s *UserSvcImpl) confirmPassword(ctx context.Context, user querier.User, password string, incorrectMsg string) {
+	isLockedOut, err := s.loginAttemptSvc.IsLockedOut(ctx, user.Email, "")
+	utils.PanicIfAppError(err, FailedToCheckSignInAttempts, 500)
+
+	if isLockedOut {
+		utils.PanicAppError(TooManySignInAttempts, 429)
+	}
+
+	if !utils.IsCorrectPassword(password, user.Password) {
+		err = s.loginAttemptSvc.RecordFailure(ctx, user.Email, "")
+		utils.PanicIfAppError(err, FailedToRecordSignInAttempt, 500)
+
+		utils.PanicAppError(incorrectMsg, 400)
+	}
+}
+
+// ExportData returns everything stored about the caller: the profile and
+// every order with its details.
+func (s *UserSvcImpl) ExportData(ctx context.Context) dto.ExportDataRes {
+	user := s.findCurrentUser(ctx)
+
+	orders, err := s.repo.FindAllOrderByUserID(ctx, user.ID)
+	utils.PanicIfAppError(err, FailedToGetOrder, 400)
+
+	items, err := s.repo.FindOrderItemsByUserID(ctx, user.ID)
+	utils.PanicIfAppError(err, FailedToFindOrderItems, 400)
+
+	itemsByOrderID := lo.GroupBy(items, func(item querier.FindOrderItemsByUserIDRow) uuid.UUID {
+		return item.OrderID
+	})
+
+	return dto.ExportDataRes{
+		Profile: profileRes(user),
+		Orders: lo.Map(orders, func(order querier.Order, _ int) dto.GetOrderDetailRes {
+			return dto.GetOrderDetailRes{
+				OrderId:    order.ID.String(),
+				Date:       order.Date.Format(constant.TimeFormat),
+				TotalPrice: order.TotalPrice,
+				Status:     order.Status,
+				OrderDetail: lo.Map(itemsByOrderID[order.ID], func(item querier.FindOrderItemsByUserIDRow, _ int) dto.OrderDetail {
+					return dto.OrderDetail{
+						OrderDetailID: item.ID.String(),
+						BookID:        item.BookID.String(),
+						Title:         item.Title,
+						Description:   item.Description,
+						Author:        item.Author,
+						Quantity:      int(item.Quantity),
+						UnitPrice:     item.UnitPrice,
+						LineTotal:     item.LineTotal,
+					}
+				}),
+			}
+		}),
+		ExportedAt: time.Now().Format(constant.TimeFormat),
+	}
+}
+
+// DeleteAccount anonymizes the caller's personal fields, signs them out
+// everywhere, voids pending password reset and email verification links and
+// drops the cart. The user row and the orders stay, since the order history
+// has to be kept for accounting.
+func (s *UserSvcImpl) DeleteAccount(ctx context.Context, input dto.DeleteAccountReq) {
+	user := s.findCurrentUser(ctx)
+
+	err := utils.ExecTxPool(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		repoTx := s.repo.WithTx(tx)
+
+		err := repoTx.AnonymizeUserByID(ctx, user.ID)
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToDeleteAccount, 422)
+		}
+
+		err = repoTx.DeleteUserRecoveryCodesByUserID(ctx, user.ID)
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToDeleteAccount, 422)
+		}
+
+		for _, purpose := range []string{constant.UserTokenPasswordReset, constant.UserTokenEmailVerification} {
+			err = repoTx.InvalidateUserTokens(ctx, querier.InvalidateUserTokensParams{
+				UserID:  user.ID,
+				Purpose: purpose,
+			})
+			if err != nil {
+				return utils.CustomErrorWithTrace(err, FailedToInvalidateTokens, 422)
+			}
+		}
+
+		err = repoTx.DeleteCartByUserID(ctx, user.ID)
+		if err != nil {
+			return utils.CustomErrorWithTrace(err, FailedToDeleteAccount, 422)
+		}
+
+		return s.revokeAllSessions(ctx, repoTx, user.ID)
+	})
+	utils.PanicIfError(err)
+
+	// tokens issued before tracking existed are not in the tracked set, so
+	// the current one is revoked explicitly
+	err = s.tokenRevocationSvc.RevokeAccessToken(ctx, input.AccessToken)
+	utils.PanicIfAppError(err, FailedToRevokeToken, 500)
+
+	s.cacheSvc.ClearCaches([]string{constant.OrderCacheKey}, user.ID.String())
+}
+
+// findCurrentUser returns the user the request is authenticated as.
+func (s *UserSvcImpl) findCurrentUser(ctx context.Context) querier.User {
+	authPayload := utils.GetRequestCtx(ctx, utilsConstant.UserSession)
+
+	userID, err := uuid.Parse(authPayload.UserID)
+	utils.PanicIfAppError(err, FailedToParseStringToUUID, 400)
+
+	user, err := s.repo.FindUserByID(ctx, userID)
 	if err == pgx.ErrNoRows {
-		utils.PanicIfAppError(err, UserNotFound, 400)
+		utils.PanicAppError(UserNotFound, 404)
 	}
+	utils.PanicIfAppError(err, FailedToFindUser, 400)
+
+	return user
+}
 
-	if !utils.IsCorrectPassword(input.Password, user.Password) {
-		utils.PanicAppError(WrongCredentials, 400)
+// checkTwoFactorCode accepts a TOTP code or an unused recovery code. A TOTP
+// code only counts for a later step than the last one accepted and a recovery
+// code is spent, so neither can be replayed.
+func (s *UserSvcImpl) checkTwoFactorCode(ctx context.Context, user querier.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	if step, ok := totp.Validate(user.TotpSecret.String, code, time.Now()); ok {
+		rows, err := s.repo.UpdateUserTotpLastStepByID(ctx, querier.UpdateUserTotpLastStepByIDParams{
+			ID:           user.ID,
+			TotpLastStep: step,
+		})
+		if err != nil {
+			return false, err
+		}
+
+		return rows > 0, nil
+	}
+
+	rows, err := s.repo.UseUserRecoveryCode(ctx, querier.UseUserRecoveryCodeParams{
+		UserID:   user.ID,
+		CodeHash: hashToken(normalizeRecoveryCode(code)),
+	})
+	if err != nil {
+		return false, err
 	}
 
+	return rows > 0, nil
+}
+
+// createUserToken invalidates the user's outstanding tokens of the same
+// purpose and stores the hash of a new one, which is returned in plain text
+// so it can be mailed.
+func (s *UserSvcImpl) createUserToken(ctx context.Context, repoTx querier.Querier, userID uuid.UUID, purpose string, duration time.Duration) (string, error) {
+	err := repoTx.InvalidateUserTokens(ctx, querier.InvalidateUserTokensParams{
+		UserID:  userID,
+		Purpose: purpose,
+	})
+	if err != nil {
+		return "", utils.CustomErrorWithTrace(err, FailedToInvalidateTokens, 422)
+	}
+
+	token, err := generateOpaqueToken()
+	if err != nil {
+		return "", utils.CustomErrorWithTrace(err, FailedToGenerateUserToken, 500)
+	}
+
+	_, err = repoTx.CreateUserToken(ctx, querier.CreateUserTokenParams{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(duration),
+	})
+	if err != nil {
+		return "", utils.CustomErrorWithTrace(err, FailedToCreateUserToken, 422)
+	}
+
+	return token, nil
+}
+
+// useUserToken locks the token row, checks it is unused and unexpired and
+// marks it used, so concurrent requests cannot redeem it twice.
+func useUserToken(ctx context.Context, repoTx querier.Querier, token string, purpose string) (querier.UserToken, error) {
+	userToken, err := repoTx.FindUserTokenByHashForUpdate(ctx, querier.FindUserTokenByHashForUpdateParams{
+		TokenHash: hashToken(token),
+		Purpose:   purpose,
+	})
+	if err == pgx.ErrNoRows {
+		return querier.UserToken{}, utils.CustomError(InvalidUserToken, 400)
+	}
+
+	if err != nil {
+		return querier.UserToken{}, utils.CustomErrorWithTrace(err, FailedToFindUserToken, 400)
+	}
+
+	if userToken.UsedAt.Valid {
+		return querier.UserToken{}, utils.CustomError(InvalidUserToken, 400)
+	}
+
+	if time.Now().After(userToken.ExpiresAt) {
+		return querier.UserToken{}, utils.CustomError(UserTokenExpired, 400)
+	}
+
+	err = repoTx.MarkUserTokenUsedByID(ctx, userToken.ID)
+	if err != nil {
+		return querier.UserToken{}, utils.CustomErrorWithTrace(err, FailedToUseUserToken, 422)
+	}
+
+	return userToken, nil
+}
+
+func profileRes(user querier.User) dto.ProfileRes {
+	return dto.ProfileRes{
+		ID:               user.ID.String(),
+		Name:             user.Name,
+		Email:            user.Email,
+		Role:             user.Role,
+		EmailVerified:    user.EmailVerifiedAt.Valid,
+		TwoFactorEnabled: user.TotpEnabledAt.Valid,
+		CreatedAt:        user.CreatedAt.Format(constant.TimeFormat),
+		UpdatedAt:        user.UpdatedAt.Format(constant.TimeFormat),
+	}
+}
+
+func passwordResetMessage(user querier.User, appURL string, token string) mailer.Message {
+	return mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Use the link below to choose a new password. It can only be used once.\n\n"+
+			"%s/reset-password?token=%s\n\n"+
+			"If you did not ask for a password reset you can ignore this email.",
+			user.Name, appURL, token),
+	}
+}
+
+// emailChangedMessage warns the previous address, which would otherwise never
+// learn that the account moved to another inbox.
+func emailChangedMessage(user querier.User, oldEmail string) mailer.Message {
+	return mailer.Message{
+		To:      oldEmail,
+		Subject: "Your email was changed",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"The email of your account was changed to %s.\n\n"+
+			"If you did not make this change, please contact us right away.",
+			user.Name, user.Email),
+	}
+}
+
+func emailVerificationMessage(user querier.User, appURL string, token string) mailer.Message {
+	return mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Please confirm your email address by opening the link below.\n\n"+
+			"%s/verify-email?token=%s",
+			user.Name, appURL, token),
+	}
+}
+
+func (s *UserSvcImpl) issueSessionTokens(ctx context.Context, repoTx querier.Querier, userID uuid.UUID) (sessionTokens, error) {
 	token, err := s.token.GenerateToken(utils.GenerateTokenReq{
-		UserID: user.ID.String(),
+		UserID: userID.String(),
 	})
-	utils.PanicIfAppError(err, FailedToGenerateToken, 400)
+	if err != nil {
+		return sessionTokens{}, utils.CustomErrorWithTrace(err, FailedToGenerateToken, 400)
+	}
 
-	resp = dto.SignInRes{
-		ID:       user.ID.String(),
-		Name:     user.Name,
-		Token:    token.Token,
-		ExpToken: token.ExpToken,
+	refreshToken, err := generateOpaqueToken()
+	if err != nil {
+		return sessionTokens{}, utils.CustomErrorWithTrace(err, FailedToGenerateRefresh, 500)
 	}
 
-	return resp
+	session, err := repoTx.CreateUserSession(ctx, querier.CreateUserSessionParams{
+		UserID:           userID,
+		RefreshTokenHash: hashToken(refreshToken),
+		ExpiresAt:        time.Now().Add(s.appConfig.RefreshTokenDuration),
+	})
+	if err != nil {
+		return sessionTokens{}, utils.CustomErrorWithTrace(err, FailedToCreateSession, 422)
+	}
+
+	err = s.tokenRevocationSvc.TrackAccessToken(ctx, userID.String(), token.Token)
+	if err != nil {
+		return sessionTokens{}, utils.CustomErrorWithTrace(err, FailedToTrackAccessToken, 500)
+	}
+
+	return sessionTokens{
+		token:           token.Token,
+		expToken:        token.ExpToken,
+		refreshToken:    refreshToken,
+		expRefreshToken: session.ExpiresAt.Unix(),
+	}, nil
+}
+
+func (s *UserSvcImpl) revokeAllSessions(ctx context.Context, repoTx querier.Querier, userID uuid.UUID) error {
+	err := repoTx.RevokeUserSessionsByUserID(ctx, userID)
+	if err != nil {
+		return utils.CustomErrorWithTrace(err, FailedToRevokeSession, 422)
+	}
+
+	err = s.tokenRevocationSvc.RevokeUserAccessTokens(ctx, userID.String())
+	if err != nil {
+		return utils.CustomErrorWithTrace(err, FailedToRevokeToken, 500)
+	}
+
+	return nil
+}
+
+// generateOpaqueToken returns an opaque random token. Only its hash is
+// stored, so it cannot be recovered from the database.
+func generateOpaqueToken() (string, error) {
+	buf := make([]byte, opaqueTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// generateRecoveryCodes returns recovery codes formatted for display, e.g.
+// "abcde-fghij", together with the hashes to store.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	codeHashes := make([]string, 0, recoveryCodeCount)
+
+	// 7 bytes encode to 12 base32 characters, enough for one code
+	buf := make([]byte, 7)
+	for i := 0; i < recoveryCodeCount; i++ {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(buf)[:recoveryCodeLength])
+		codes = append(codes, code[:recoveryCodeLength/2]+"-"+code[recoveryCodeLength/2:])
+		codeHashes = append(codeHashes, hashToken(code))
+	}
+
+	return codes, codeHashes, nil
+}
+
+// normalizeEmail mirrors the LOWER(TRIM(...)) the user queries apply to
+// emails.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// normalizeRecoveryCode lets a recovery code be typed in upper case or
+// without the dash.
+func normalizeRecoveryCode(code string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(code)), "-", "")
+}
+
+// BootstrapAdmin makes sure the account configured through ADMIN_EMAIL exists
+// and has the admin role. A created account is marked as verified, since no
+// verification mail is sent for it and unverified accounts cannot order. An
+// existing account is promoted without touching its password. It does nothing
+// when ADMIN_EMAIL is empty.
+func BootstrapAdmin(ctx context.Context, repo querier.Repository, appConfig *config.AppConfig) error {
+	if appConfig.AdminEmail == "" {
+		return nil
+	}
+
+	user, err := repo.FindUserByEmail(ctx, appConfig.AdminEmail)
+	if err == pgx.ErrNoRows {
+		if appConfig.AdminPassword == "" {
+			return errors.New(AdminPasswordRequired)
+		}
+
+		pwd, err := utils.HashPassword(appConfig.AdminPassword)
+		if err != nil {
+			return fmt.Errorf("%s: %w", FailedToHashPassword, err)
+		}
+
+		return utils.ExecTxPool(ctx, repo.GetDB(), func(tx pgx.Tx) error {
+			repoTx := repo.WithTx(tx)
+
+			user, err := repoTx.CreateUser(ctx, querier.CreateUserParams{
+				Name:     appConfig.AdminName,
+				Email:    appConfig.AdminEmail,
+				Password: pwd,
+				Role:     constant.RoleAdmin,
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", FailedToCreateUser, err)
+			}
+
+			err = repoTx.VerifyUserEmailByID(ctx, user.ID)
+			if err != nil {
+				return fmt.Errorf("%s: %w", FailedToVerifyEmail, err)
+			}
+
+			return nil
+		})
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", FailedToFindUser, err)
+	}
+
+	if user.Role == constant.RoleAdmin {
+		return nil
+	}
+
+	err = repo.UpdateUserRoleByID(ctx, querier.UpdateUserRoleByIDParams{
+		ID:   user.ID,
+		Role: constant.RoleAdmin,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", FailedToUpdateUserRole, err)
+	}
+
+	return nil
 }
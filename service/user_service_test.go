@@ -2,31 +2,148 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/alicebob/miniredis"
+	appConfig "github.com/gadhittana-01/book-go/config"
+	"github.com/gadhittana-01/book-go/constant"
 	querier "github.com/gadhittana-01/book-go/db/repository"
 	mockrepo "github.com/gadhittana-01/book-go/db/repository/mock"
 	"github.com/gadhittana-01/book-go/dto"
+	"github.com/gadhittana-01/book-go/mailer"
+	mockmailer "github.com/gadhittana-01/book-go/mailer/mock"
+	"github.com/gadhittana-01/book-go/totp"
 	"github.com/gadhittana01/go-modules/utils"
 	mockutl "github.com/gadhittana01/go-modules/utils/mock"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
 var userID = uuid.New()
 var errInvalidReq = errors.New("invalid request")
 
+// initUserSvc backs the token revocation list, the sign-in attempt counters,
+// the two-factor challenges and the cache with miniredis, so the tests
+// assert on the Redis state and simulate an outage by closing the server.
 func initUserSvc(
+	t *testing.T,
 	ctrl *gomock.Controller,
 	config *utils.BaseConfig,
-) (UserSvc, *mockrepo.MockRepository, *mockutl.MockTokenClient) {
+) (UserSvc, *mockrepo.MockRepository, *mockutl.MockTokenClient, *mockmailer.MockMailer, *miniredis.Miniredis) {
 	mockRepo := mockrepo.NewMockRepository(ctrl)
 	mockToken := mockutl.NewMockTokenClient(ctrl)
-	return NewUserSvc(mockRepo, config, mockToken), mockRepo, mockToken
+	mockMailer := mockmailer.NewMockMailer(ctrl)
+	tokenRevocationSvc, redisServer := initTokenRevocationSvc(t)
+	appCfg := &appConfig.AppConfig{}
+	appConfig.LoadAppConfig("../config", "test", appCfg)
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: redisServer.Addr(),
+	})
+	loginAttemptSvc := NewLoginAttemptSvc(redisClient, appCfg)
+	twoFactorChallengeSvc := NewTwoFactorChallengeSvc(redisClient, appCfg)
+	cacheSvc := utils.NewCacheSvc(config, redisClient)
+
+	return NewUserSvc(mockRepo, config, appCfg, mockToken, tokenRevocationSvc, loginAttemptSvc, twoFactorChallengeSvc, mockMailer, cacheSvc), mockRepo, mockToken, mockMailer, redisServer
+}
+
+// createTwoFactorChallenge stores a challenge for userID the way SignIn does
+// and returns its token.
+func createTwoFactorChallenge(t *testing.T, redisServer *miniredis.Miniredis) string {
+	twoFactorChallengeSvc := NewTwoFactorChallengeSvc(redis.NewClient(&redis.Options{
+		Addr: redisServer.Addr(),
+	}), &appConfig.AppConfig{
+		TwoFactorChallengeDuration: 5 * time.Minute,
+	})
+
+	token, _, err := twoFactorChallengeSvc.CreateChallenge(context.Background(), userID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func isTwoFactorChallengeStored(redisServer *miniredis.Miniredis, token string) bool {
+	return redisServer.Exists(twoFactorChallengeKey(hashToken(token)))
+}
+
+// withRedisDown runs fn while the miniredis server is stopped.
+func withRedisDown(t *testing.T, redisServer *miniredis.Miniredis, fn func()) {
+	redisServer.Close()
+	defer func() {
+		if err := redisServer.Restart(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	fn()
+}
+
+func isAccessTokenTracked(redisServer *miniredis.Miniredis, token string) bool {
+	isMember, _ := redisServer.IsMember(userAccessTokensKey(userID.String()), hashToken(token))
+	return isMember
+}
+
+func isAccessTokenRevoked(redisServer *miniredis.Miniredis, token string) bool {
+	return redisServer.Exists(revokedAccessTokenKey(hashToken(token)))
+}
+
+// expectCreateUserSession expects a new session for userID and reports the
+// refresh token hash it was created with.
+func expectCreateUserSession(t *testing.T, mockRepo *mockrepo.MockRepository, expiresAt time.Time, refreshTokenHash *string) {
+	mockRepo.EXPECT().CreateUserSession(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateUserSessionParams{})).DoAndReturn(func(_ any, params querier.CreateUserSessionParams) (querier.UserSession, error) {
+		assert.Equal(t, userID, params.UserID)
+		assert.Len(t, params.RefreshTokenHash, 64)
+		assert.WithinDuration(t, time.Now().Add(720*time.Hour), params.ExpiresAt, time.Minute)
+		*refreshTokenHash = params.RefreshTokenHash
+
+		return querier.UserSession{
+			ID:               uuid.New(),
+			UserID:           params.UserID,
+			RefreshTokenHash: params.RefreshTokenHash,
+			ExpiresAt:        expiresAt,
+		}, nil
+	}).Times(1)
+}
+
+// expectCreateUserToken expects the outstanding tokens of purpose to be
+// invalidated and a new one created for userID, and reports its hash.
+func expectCreateUserToken(t *testing.T, mockRepo *mockrepo.MockRepository, purpose string, duration time.Duration, tokenHash *string) {
+	mockRepo.EXPECT().InvalidateUserTokens(gomock.Any(), querier.InvalidateUserTokensParams{
+		UserID:  userID,
+		Purpose: purpose,
+	}).Return(nil).Times(1)
+	mockRepo.EXPECT().CreateUserToken(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateUserTokenParams{})).DoAndReturn(func(_ any, params querier.CreateUserTokenParams) (querier.UserToken, error) {
+		assert.Equal(t, userID, params.UserID)
+		assert.Equal(t, purpose, params.Purpose)
+		assert.Len(t, params.TokenHash, 64)
+		assert.WithinDuration(t, time.Now().Add(duration), params.ExpiresAt, time.Minute)
+		*tokenHash = params.TokenHash
+
+		return querier.UserToken{
+			ID:        uuid.New(),
+			UserID:    params.UserID,
+			Purpose:   params.Purpose,
+			TokenHash: params.TokenHash,
+			ExpiresAt: params.ExpiresAt,
+		}, nil
+	}).Times(1)
+}
+
+// mailedToken extracts the token from the link of a mailed message.
+func mailedToken(msg mailer.Message) string {
+	_, token, _ := strings.Cut(msg.Body, "?token=")
+	token, _, _ = strings.Cut(token, "\n")
+	return token
 }
 
 func TestSignUp(t *testing.T) {
@@ -35,13 +152,14 @@ func TestSignUp(t *testing.T) {
 	defer ctrl.Finish()
 	config := &utils.BaseConfig{}
 	utils.LoadBaseConfig("../config", "test", config)
-	userSvcMock, mockRepo, mockToken := initUserSvc(ctrl, config)
+	userSvcMock, mockRepo, mockToken, mockMailer, redisServer := initUserSvc(t, ctrl, config)
 
 	name := "Giri Putra Adhittana"
 	email := "test@gmail.com"
 	password := "123"
 	token := "dmytoken"
 	expToken := int64(1000)
+	expRefreshToken := time.Now().Add(720 * time.Hour).Truncate(time.Second)
 	req := dto.SignUpReq{
 		Name:     name,
 		Email:    email,
@@ -56,15 +174,20 @@ func TestSignUp(t *testing.T) {
 		mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateUserParams{})).DoAndReturn(func(_ any, params querier.CreateUserParams) (querier.User, error) {
 			assert.Equal(t, name, params.Name)
 			assert.Equal(t, email, params.Email)
+			assert.Equal(t, constant.RoleCustomer, params.Role)
 
 			return querier.User{
 				ID:       userID,
 				Name:     name,
 				Email:    email,
 				Password: password,
+				Role:     params.Role,
 			}, nil
 		}).Times(1)
 
+		var verificationTokenHash string
+		expectCreateUserToken(t, mockRepo, constant.UserTokenEmailVerification, 24*time.Hour, &verificationTokenHash)
+
 		mockToken.EXPECT().GenerateToken(utils.GenerateTokenReq{
 			UserID: userID.String(),
 		}).Return(utils.GenerateTokenResp{
@@ -72,15 +195,133 @@ func TestSignUp(t *testing.T) {
 			ExpToken: expToken,
 		}, nil).Times(1)
 
+		var refreshTokenHash string
+		expectCreateUserSession(t, mockRepo, expRefreshToken, &refreshTokenHash)
+
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.AssignableToTypeOf(mailer.Message{})).DoAndReturn(func(_ any, msg mailer.Message) error {
+			assert.Equal(t, email, msg.To)
+			assert.Contains(t, msg.Body, "http://localhost:3000/verify-email?token=")
+			assert.Equal(t, verificationTokenHash, hashToken(mailedToken(msg)))
+
+			return nil
+		}).Times(1)
+
 		resp := userSvcMock.SignUp(ctx, req)
 
-		assert.NotEmpty(t, resp)
+		assert.NotEmpty(t, resp.RefreshToken)
+		assert.Equal(t, refreshTokenHash, hashToken(resp.RefreshToken))
+		assert.True(t, isAccessTokenTracked(redisServer, token))
 		assert.Equal(t, dto.SignUpRes{
-			ID:       userID.String(),
-			Name:     name,
+			ID:              userID.String(),
+			Name:            name,
+			Role:            constant.RoleCustomer,
+			Token:           token,
+			ExpToken:        expToken,
+			RefreshToken:    resp.RefreshToken,
+			ExpRefreshToken: expRefreshToken.Unix(),
+		}, resp)
+	})
+
+	t.Run("failed to send verification email", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		mockRepo.EXPECT().CheckEmailExists(gomock.Any(), email).Return(false, nil).Times(1)
+		mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateUserParams{})).Return(querier.User{
+			ID:    userID,
+			Name:  name,
+			Email: email,
+			Role:  constant.RoleCustomer,
+		}, nil).Times(1)
+		var verificationTokenHash string
+		expectCreateUserToken(t, mockRepo, constant.UserTokenEmailVerification, 24*time.Hour, &verificationTokenHash)
+
+		mockToken.EXPECT().GenerateToken(utils.GenerateTokenReq{
+			UserID: userID.String(),
+		}).Return(utils.GenerateTokenResp{
 			Token:    token,
 			ExpToken: expToken,
-		}, resp)
+		}, nil).Times(1)
+
+		var refreshTokenHash string
+		expectCreateUserSession(t, mockRepo, expRefreshToken, &refreshTokenHash)
+
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errInvalidReq).Times(1)
+
+		resp := userSvcMock.SignUp(ctx, req)
+
+		assert.Equal(t, userID.String(), resp.ID)
+		assert.Equal(t, token, resp.Token)
+	})
+
+	t.Run("failed to create verification token", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().CheckEmailExists(gomock.Any(), email).Return(false, nil).Times(1)
+		mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateUserParams{})).Return(querier.User{
+			ID: userID,
+		}, nil).Times(1)
+		mockRepo.EXPECT().InvalidateUserTokens(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockRepo.EXPECT().CreateUserToken(gomock.Any(), gomock.Any()).Return(querier.UserToken{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToCreateUserToken),
+		}, func() {
+			userSvcMock.SignUp(ctx, req)
+		})
+	})
+
+	t.Run("failed to create session", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().CheckEmailExists(gomock.Any(), email).Return(false, nil).Times(1)
+		mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateUserParams{})).Return(querier.User{
+			ID: userID,
+		}, nil).Times(1)
+		var verificationTokenHash string
+		expectCreateUserToken(t, mockRepo, constant.UserTokenEmailVerification, 24*time.Hour, &verificationTokenHash)
+
+		mockToken.EXPECT().GenerateToken(utils.GenerateTokenReq{
+			UserID: userID.String(),
+		}).Return(utils.GenerateTokenResp{
+			Token:    token,
+			ExpToken: expToken,
+		}, nil).Times(1)
+		mockRepo.EXPECT().CreateUserSession(gomock.Any(), gomock.Any()).Return(querier.UserSession{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToCreateSession),
+		}, func() {
+			userSvcMock.SignUp(ctx, req)
+		})
+	})
+
+	t.Run("failed to track access token", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().CheckEmailExists(gomock.Any(), email).Return(false, nil).Times(1)
+		mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateUserParams{})).Return(querier.User{
+			ID: userID,
+		}, nil).Times(1)
+		var verificationTokenHash string
+		expectCreateUserToken(t, mockRepo, constant.UserTokenEmailVerification, 24*time.Hour, &verificationTokenHash)
+
+		mockToken.EXPECT().GenerateToken(utils.GenerateTokenReq{
+			UserID: userID.String(),
+		}).Return(utils.GenerateTokenResp{
+			Token:    token,
+			ExpToken: expToken,
+		}, nil).Times(1)
+
+		var refreshTokenHash string
+		expectCreateUserSession(t, mockRepo, expRefreshToken, &refreshTokenHash)
+
+		withRedisDown(t, redisServer, func() {
+			assert.Panics(t, func() {
+				userSvcMock.SignUp(ctx, req)
+			})
+		})
 	})
 
 	t.Run("failed to generate token", func(t *testing.T) {
@@ -100,6 +341,9 @@ func TestSignUp(t *testing.T) {
 			}, nil
 		}).Times(1)
 
+		var verificationTokenHash string
+		expectCreateUserToken(t, mockRepo, constant.UserTokenEmailVerification, 24*time.Hour, &verificationTokenHash)
+
 		mockToken.EXPECT().GenerateToken(utils.GenerateTokenReq{
 			UserID: userID.String(),
 		}).Return(utils.GenerateTokenResp{
@@ -222,12 +466,13 @@ func TestSignIn(t *testing.T) {
 	defer ctrl.Finish()
 	config := &utils.BaseConfig{}
 	utils.LoadBaseConfig("../config", "test", config)
-	userSvcMock, mockRepo, mockToken := initUserSvc(ctrl, config)
+	userSvcMock, mockRepo, mockToken, _, redisServer := initUserSvc(t, ctrl, config)
 
 	email := "test@gmail.com"
 	name := "Giri Putra Adhittana"
 	token := "dmytoken"
 	expToken := int64(1000)
+	expRefreshToken := time.Now().Add(720 * time.Hour).Truncate(time.Second)
 	password := "123"
 	hashPassword, _ := utils.HashPassword(password)
 	req := dto.SignInReq{
@@ -241,6 +486,7 @@ func TestSignIn(t *testing.T) {
 			Name:     name,
 			Email:    email,
 			Password: hashPassword,
+			Role:     constant.RoleAdmin,
 		}, nil).Times(1)
 
 		mockToken.EXPECT().GenerateToken(utils.GenerateTokenReq{
@@ -250,17 +496,47 @@ func TestSignIn(t *testing.T) {
 			ExpToken: expToken,
 		}, nil).Times(1)
 
+		var refreshTokenHash string
+		expectCreateUserSession(t, mockRepo, expRefreshToken, &refreshTokenHash)
+
 		resp := userSvcMock.SignIn(ctx, req)
 
-		assert.NotEmpty(t, resp)
+		assert.NotEmpty(t, resp.RefreshToken)
+		assert.Equal(t, refreshTokenHash, hashToken(resp.RefreshToken))
+		assert.True(t, isAccessTokenTracked(redisServer, token))
 		assert.Equal(t, dto.SignInRes{
-			ID:       userID.String(),
-			Name:     name,
-			Token:    token,
-			ExpToken: expToken,
+			ID:              userID.String(),
+			Name:            name,
+			Role:            constant.RoleAdmin,
+			Token:           token,
+			ExpToken:        expToken,
+			RefreshToken:    resp.RefreshToken,
+			ExpRefreshToken: expRefreshToken.Unix(),
 		}, resp)
 	})
 
+	t.Run("two-factor enabled returns a challenge", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), email).Return(querier.User{
+			ID:            userID,
+			Name:          name,
+			Email:         email,
+			Password:      hashPassword,
+			TotpSecret:    sql.NullString{String: "SECRET", Valid: true},
+			TotpEnabledAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}, nil).Times(1)
+		mockToken.EXPECT().GenerateToken(gomock.Any()).Times(0)
+		mockRepo.EXPECT().CreateUserSession(gomock.Any(), gomock.Any()).Times(0)
+
+		resp := userSvcMock.SignIn(ctx, req)
+
+		assert.True(t, resp.TwoFactorRequired)
+		assert.NotEmpty(t, resp.ChallengeToken)
+		assert.InDelta(t, time.Now().Add(5*time.Minute).Unix(), resp.ExpChallengeToken, 1)
+		assert.Empty(t, resp.Token)
+		assert.Empty(t, resp.RefreshToken)
+		assert.True(t, isTwoFactorChallengeStored(redisServer, resp.ChallengeToken))
+	})
+
 	t.Run("failed to generate token", func(t *testing.T) {
 		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), email).Return(querier.User{
 			ID:       userID,
@@ -302,7 +578,7 @@ func TestSignIn(t *testing.T) {
 
 		assert.PanicsWithValue(t, utils.AppError{
 			StatusCode: 400,
-			Message:    fmt.Sprintf("%s|%s", WrongCredentials, WrongCredentials),
+			Message:    fmt.Sprintf("%s|%s", InvalidCredentials, InvalidCredentials),
 		}, func() {
 			resp := userSvcMock.SignIn(ctx, req)
 			assert.Empty(t, resp)
@@ -326,7 +602,7 @@ func TestSignIn(t *testing.T) {
 
 		assert.PanicsWithValue(t, utils.AppError{
 			StatusCode: 400,
-			Message:    fmt.Sprintf("%s|%s", pgx.ErrNoRows.Error(), UserNotFound),
+			Message:    fmt.Sprintf("%s|%s", InvalidCredentials, InvalidCredentials),
 		}, func() {
 			resp := userSvcMock.SignIn(ctx, req)
 			assert.Empty(t, resp)
@@ -357,4 +633,1665 @@ func TestSignIn(t *testing.T) {
 		})
 	})
 
+	t.Run("locked out after repeated failures", func(t *testing.T) {
+		redisServer.FlushAll()
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), email).Return(querier.User{}, pgx.ErrNoRows).Times(5)
+
+		for i := 0; i < 5; i++ {
+			assert.Panics(t, func() {
+				userSvcMock.SignIn(ctx, req)
+			})
+		}
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 429,
+			Message:    fmt.Sprintf("%s|%s", TooManySignInAttempts, TooManySignInAttempts),
+		}, func() {
+			userSvcMock.SignIn(ctx, req)
+		})
+		redisServer.FlushAll()
+	})
+
+	t.Run("failed to check sign-in attempts", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), gomock.Any()).Times(0)
+
+		withRedisDown(t, redisServer, func() {
+			assert.Panics(t, func() {
+				userSvcMock.SignIn(ctx, req)
+			})
+		})
+	})
+}
+
+func TestRefreshToken(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	userSvcMock, mockRepo, mockToken, _, redisServer := initUserSvc(t, ctrl, config)
+
+	token := "dmytoken"
+	expToken := int64(1000)
+	expRefreshToken := time.Now().Add(720 * time.Hour).Truncate(time.Second)
+	refreshToken := "refresh-token"
+	req := dto.RefreshTokenReq{
+		RefreshToken: refreshToken,
+	}
+	session := querier.UserSession{
+		ID:               uuid.New(),
+		UserID:           userID,
+		RefreshTokenHash: hashToken(refreshToken),
+		ExpiresAt:        time.Now().Add(time.Hour),
+	}
+
+	t.Run("success refresh token", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		mockRepo.EXPECT().FindUserSessionByRefreshTokenHashForUpdate(gomock.Any(), hashToken(refreshToken)).Return(session, nil).Times(1)
+		mockRepo.EXPECT().RevokeUserSessionByID(gomock.Any(), session.ID).Return(nil).Times(1)
+		mockToken.EXPECT().GenerateToken(utils.GenerateTokenReq{
+			UserID: userID.String(),
+		}).Return(utils.GenerateTokenResp{
+			Token:    token,
+			ExpToken: expToken,
+		}, nil).Times(1)
+
+		var refreshTokenHash string
+		expectCreateUserSession(t, mockRepo, expRefreshToken, &refreshTokenHash)
+
+		resp := userSvcMock.RefreshToken(ctx, req)
+
+		assert.NotEqual(t, refreshToken, resp.RefreshToken)
+		assert.Equal(t, refreshTokenHash, hashToken(resp.RefreshToken))
+		assert.True(t, isAccessTokenTracked(redisServer, token))
+		assert.Equal(t, dto.RefreshTokenRes{
+			Token:           token,
+			ExpToken:        expToken,
+			RefreshToken:    resp.RefreshToken,
+			ExpRefreshToken: expRefreshToken.Unix(),
+		}, resp)
+	})
+
+	t.Run("unknown refresh token", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindUserSessionByRefreshTokenHashForUpdate(gomock.Any(), hashToken(refreshToken)).Return(querier.UserSession{}, pgx.ErrNoRows).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 401,
+			Message:    fmt.Sprintf("%s|%s", InvalidRefreshToken, InvalidRefreshToken),
+		}, func() {
+			userSvcMock.RefreshToken(ctx, req)
+		})
+	})
+
+	t.Run("failed to find session", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindUserSessionByRefreshTokenHashForUpdate(gomock.Any(), hashToken(refreshToken)).Return(querier.UserSession{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToFindSession),
+		}, func() {
+			userSvcMock.RefreshToken(ctx, req)
+		})
+	})
+
+	t.Run("expired refresh token", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		expiredSession := session
+		expiredSession.ExpiresAt = time.Now().Add(-time.Minute)
+		mockRepo.EXPECT().FindUserSessionByRefreshTokenHashForUpdate(gomock.Any(), hashToken(refreshToken)).Return(expiredSession, nil).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 401,
+			Message:    fmt.Sprintf("%s|%s", RefreshTokenExpired, RefreshTokenExpired),
+		}, func() {
+			userSvcMock.RefreshToken(ctx, req)
+		})
+	})
+
+	t.Run("reused refresh token revokes every session", func(t *testing.T) {
+		// the revocation is committed even though the request fails
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		revokedSession := session
+		revokedSession.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		mockRepo.EXPECT().FindUserSessionByRefreshTokenHashForUpdate(gomock.Any(), hashToken(refreshToken)).Return(revokedSession, nil).Times(1)
+		mockRepo.EXPECT().RevokeUserSessionsByUserID(gomock.Any(), userID).Return(nil).Times(1)
+		_, err := redisServer.SetAdd(userAccessTokensKey(userID.String()), hashToken("issued-token"))
+		assert.NoError(t, err)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 401,
+			Message:    fmt.Sprintf("%s|%s", InvalidRefreshToken, InvalidRefreshToken),
+		}, func() {
+			userSvcMock.RefreshToken(ctx, req)
+		})
+		assert.True(t, isAccessTokenRevoked(redisServer, "issued-token"))
+	})
+
+	t.Run("failed to revoke session", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindUserSessionByRefreshTokenHashForUpdate(gomock.Any(), hashToken(refreshToken)).Return(session, nil).Times(1)
+		mockRepo.EXPECT().RevokeUserSessionByID(gomock.Any(), session.ID).Return(errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToRevokeSession),
+		}, func() {
+			userSvcMock.RefreshToken(ctx, req)
+		})
+	})
+}
+
+func TestSignOut(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	userSvcMock, mockRepo, _, _, redisServer := initUserSvc(t, ctrl, config)
+
+	req := dto.SignOutReq{
+		AccessToken:  "dmytoken",
+		RefreshToken: "refresh-token",
+	}
+	params := querier.RevokeUserSessionByRefreshTokenHashParams{
+		UserID:           userID,
+		RefreshTokenHash: hashToken(req.RefreshToken),
+	}
+
+	t.Run("success sign out", func(t *testing.T) {
+		mockRepo.EXPECT().RevokeUserSessionByRefreshTokenHash(gomock.Any(), params).Return(nil).Times(1)
+
+		assert.NotPanics(t, func() {
+			userSvcMock.SignOut(ctx, req)
+		})
+		assert.True(t, isAccessTokenRevoked(redisServer, req.AccessToken))
+	})
+
+	t.Run("failed to revoke session", func(t *testing.T) {
+		mockRepo.EXPECT().RevokeUserSessionByRefreshTokenHash(gomock.Any(), params).Return(errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToRevokeSession),
+		}, func() {
+			userSvcMock.SignOut(ctx, req)
+		})
+	})
+
+	t.Run("failed to revoke access token", func(t *testing.T) {
+		mockRepo.EXPECT().RevokeUserSessionByRefreshTokenHash(gomock.Any(), params).Return(nil).Times(1)
+
+		withRedisDown(t, redisServer, func() {
+			assert.Panics(t, func() {
+				userSvcMock.SignOut(ctx, req)
+			})
+		})
+	})
+
+	t.Run("invalid user session", func(t *testing.T) {
+		assert.Panics(t, func() {
+			userSvcMock.SignOut(utils.SetRequestContext("123"), req)
+		})
+	})
+}
+
+func TestSignOutAll(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	userSvcMock, mockRepo, _, _, redisServer := initUserSvc(t, ctrl, config)
+
+	req := dto.SignOutAllReq{
+		AccessToken: "dmytoken",
+	}
+
+	t.Run("success sign out all", func(t *testing.T) {
+		mockRepo.EXPECT().RevokeUserSessionsByUserID(gomock.Any(), userID).Return(nil).Times(1)
+		_, err := redisServer.SetAdd(userAccessTokensKey(userID.String()), hashToken("other-device-token"))
+		assert.NoError(t, err)
+
+		assert.NotPanics(t, func() {
+			userSvcMock.SignOutAll(ctx, req)
+		})
+		assert.True(t, isAccessTokenRevoked(redisServer, req.AccessToken))
+		assert.True(t, isAccessTokenRevoked(redisServer, "other-device-token"))
+	})
+
+	t.Run("failed to revoke sessions", func(t *testing.T) {
+		mockRepo.EXPECT().RevokeUserSessionsByUserID(gomock.Any(), userID).Return(errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToRevokeSession),
+		}, func() {
+			userSvcMock.SignOutAll(ctx, req)
+		})
+	})
+
+	t.Run("failed to revoke user access tokens", func(t *testing.T) {
+		mockRepo.EXPECT().RevokeUserSessionsByUserID(gomock.Any(), userID).Return(nil).Times(1)
+
+		withRedisDown(t, redisServer, func() {
+			assert.Panics(t, func() {
+				userSvcMock.SignOutAll(ctx, req)
+			})
+		})
+	})
+}
+
+func TestForgotPassword(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	userSvcMock, mockRepo, _, mockMailer, _ := initUserSvc(t, ctrl, config)
+
+	email := "test@gmail.com"
+	req := dto.ForgotPasswordReq{
+		Email: email,
+	}
+	user := querier.User{
+		ID:    userID,
+		Name:  "Giri Putra Adhittana",
+		Email: email,
+	}
+
+	t.Run("success forgot password", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), email).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		var resetTokenHash string
+		expectCreateUserToken(t, mockRepo, constant.UserTokenPasswordReset, time.Hour, &resetTokenHash)
+
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.AssignableToTypeOf(mailer.Message{})).DoAndReturn(func(_ any, msg mailer.Message) error {
+			assert.Equal(t, email, msg.To)
+			assert.Contains(t, msg.Body, "http://localhost:3000/reset-password?token=")
+			assert.Equal(t, resetTokenHash, hashToken(mailedToken(msg)))
+
+			return nil
+		}).Times(1)
+
+		assert.NotPanics(t, func() {
+			userSvcMock.ForgotPassword(ctx, req)
+		})
+	})
+
+	t.Run("unknown email", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), email).Return(querier.User{}, pgx.ErrNoRows).Times(1)
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+
+		assert.NotPanics(t, func() {
+			userSvcMock.ForgotPassword(ctx, req)
+		})
+	})
+
+	t.Run("failed to find user", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), email).Return(querier.User{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToFindUser),
+		}, func() {
+			userSvcMock.ForgotPassword(ctx, req)
+		})
+	})
+
+	t.Run("failed to invalidate user tokens", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), email).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+		mockRepo.EXPECT().InvalidateUserTokens(gomock.Any(), gomock.Any()).Return(errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToInvalidateTokens),
+		}, func() {
+			userSvcMock.ForgotPassword(ctx, req)
+		})
+	})
+
+	t.Run("failed to send mail answers like an unknown email", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), email).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		var resetTokenHash string
+		expectCreateUserToken(t, mockRepo, constant.UserTokenPasswordReset, time.Hour, &resetTokenHash)
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errInvalidReq).Times(1)
+
+		assert.NotPanics(t, func() {
+			userSvcMock.ForgotPassword(ctx, req)
+		})
+	})
+}
+
+func TestResetPassword(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	userSvcMock, mockRepo, _, _, redisServer := initUserSvc(t, ctrl, config)
+
+	req := dto.ResetPasswordReq{
+		Token:    "reset-token",
+		Password: "new-password",
+	}
+	findParams := querier.FindUserTokenByHashForUpdateParams{
+		TokenHash: hashToken(req.Token),
+		Purpose:   constant.UserTokenPasswordReset,
+	}
+	userToken := querier.UserToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Purpose:   constant.UserTokenPasswordReset,
+		TokenHash: hashToken(req.Token),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	t.Run("success reset password", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		mockRepo.EXPECT().FindUserTokenByHashForUpdate(gomock.Any(), findParams).Return(userToken, nil).Times(1)
+		mockRepo.EXPECT().MarkUserTokenUsedByID(gomock.Any(), userToken.ID).Return(nil).Times(1)
+		mockRepo.EXPECT().UpdateUserPasswordByID(gomock.Any(), gomock.AssignableToTypeOf(querier.UpdateUserPasswordByIDParams{})).DoAndReturn(func(_ any, params querier.UpdateUserPasswordByIDParams) error {
+			assert.Equal(t, userID, params.ID)
+			assert.True(t, utils.IsCorrectPassword(req.Password, params.Password))
+
+			return nil
+		}).Times(1)
+		mockRepo.EXPECT().VerifyUserEmailByID(gomock.Any(), userID).Return(nil).Times(1)
+		mockRepo.EXPECT().RevokeUserSessionsByUserID(gomock.Any(), userID).Return(nil).Times(1)
+		_, err := redisServer.SetAdd(userAccessTokensKey(userID.String()), hashToken("old-token"))
+		assert.NoError(t, err)
+
+		assert.NotPanics(t, func() {
+			userSvcMock.ResetPassword(ctx, req)
+		})
+		assert.True(t, isAccessTokenRevoked(redisServer, "old-token"))
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindUserTokenByHashForUpdate(gomock.Any(), findParams).Return(querier.UserToken{}, pgx.ErrNoRows).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("%s|%s", InvalidUserToken, InvalidUserToken),
+		}, func() {
+			userSvcMock.ResetPassword(ctx, req)
+		})
+	})
+
+	t.Run("token already used", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		usedToken := userToken
+		usedToken.UsedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		mockRepo.EXPECT().FindUserTokenByHashForUpdate(gomock.Any(), findParams).Return(usedToken, nil).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("%s|%s", InvalidUserToken, InvalidUserToken),
+		}, func() {
+			userSvcMock.ResetPassword(ctx, req)
+		})
+	})
+
+	t.Run("token expired", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		expiredToken := userToken
+		expiredToken.ExpiresAt = time.Now().Add(-time.Minute)
+		mockRepo.EXPECT().FindUserTokenByHashForUpdate(gomock.Any(), findParams).Return(expiredToken, nil).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("%s|%s", UserTokenExpired, UserTokenExpired),
+		}, func() {
+			userSvcMock.ResetPassword(ctx, req)
+		})
+	})
+
+	t.Run("failed to find user token", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindUserTokenByHashForUpdate(gomock.Any(), findParams).Return(querier.UserToken{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToFindUserToken),
+		}, func() {
+			userSvcMock.ResetPassword(ctx, req)
+		})
+	})
+
+	t.Run("failed to update password", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindUserTokenByHashForUpdate(gomock.Any(), findParams).Return(userToken, nil).Times(1)
+		mockRepo.EXPECT().MarkUserTokenUsedByID(gomock.Any(), userToken.ID).Return(nil).Times(1)
+		mockRepo.EXPECT().UpdateUserPasswordByID(gomock.Any(), gomock.Any()).Return(errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToUpdatePassword),
+		}, func() {
+			userSvcMock.ResetPassword(ctx, req)
+		})
+	})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	userSvcMock, mockRepo, _, _, _ := initUserSvc(t, ctrl, config)
+
+	req := dto.VerifyEmailReq{
+		Token: "verification-token",
+	}
+	findParams := querier.FindUserTokenByHashForUpdateParams{
+		TokenHash: hashToken(req.Token),
+		Purpose:   constant.UserTokenEmailVerification,
+	}
+	userToken := querier.UserToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Purpose:   constant.UserTokenEmailVerification,
+		TokenHash: hashToken(req.Token),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	t.Run("success verify email", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		mockRepo.EXPECT().FindUserTokenByHashForUpdate(gomock.Any(), findParams).Return(userToken, nil).Times(1)
+		mockRepo.EXPECT().MarkUserTokenUsedByID(gomock.Any(), userToken.ID).Return(nil).Times(1)
+		mockRepo.EXPECT().VerifyUserEmailByID(gomock.Any(), userID).Return(nil).Times(1)
+
+		assert.NotPanics(t, func() {
+			userSvcMock.VerifyEmail(ctx, req)
+		})
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindUserTokenByHashForUpdate(gomock.Any(), findParams).Return(querier.UserToken{}, pgx.ErrNoRows).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("%s|%s", InvalidUserToken, InvalidUserToken),
+		}, func() {
+			userSvcMock.VerifyEmail(ctx, req)
+		})
+	})
+
+	t.Run("failed to mark token used", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindUserTokenByHashForUpdate(gomock.Any(), findParams).Return(userToken, nil).Times(1)
+		mockRepo.EXPECT().MarkUserTokenUsedByID(gomock.Any(), userToken.ID).Return(errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToUseUserToken),
+		}, func() {
+			userSvcMock.VerifyEmail(ctx, req)
+		})
+	})
+
+	t.Run("failed to verify email", func(t *testing.T) {
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().FindUserTokenByHashForUpdate(gomock.Any(), findParams).Return(userToken, nil).Times(1)
+		mockRepo.EXPECT().MarkUserTokenUsedByID(gomock.Any(), userToken.ID).Return(nil).Times(1)
+		mockRepo.EXPECT().VerifyUserEmailByID(gomock.Any(), userID).Return(errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToVerifyEmail),
+		}, func() {
+			userSvcMock.VerifyEmail(ctx, req)
+		})
+	})
+}
+
+func TestResendVerificationEmail(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	userSvcMock, mockRepo, _, mockMailer, _ := initUserSvc(t, ctrl, config)
+
+	user := querier.User{
+		ID:    userID,
+		Name:  "Giri Putra Adhittana",
+		Email: "test@gmail.com",
+	}
+
+	t.Run("success resend verification email", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		var verificationTokenHash string
+		expectCreateUserToken(t, mockRepo, constant.UserTokenEmailVerification, 24*time.Hour, &verificationTokenHash)
+
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.AssignableToTypeOf(mailer.Message{})).DoAndReturn(func(_ any, msg mailer.Message) error {
+			assert.Equal(t, user.Email, msg.To)
+			assert.Equal(t, verificationTokenHash, hashToken(mailedToken(msg)))
+
+			return nil
+		}).Times(1)
+
+		assert.NotPanics(t, func() {
+			userSvcMock.ResendVerificationEmail(ctx)
+		})
+	})
+
+	t.Run("email already verified", func(t *testing.T) {
+		verifiedUser := user
+		verifiedUser.EmailVerifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(verifiedUser, nil).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("%s|%s", EmailAlreadyVerified, EmailAlreadyVerified),
+		}, func() {
+			userSvcMock.ResendVerificationEmail(ctx)
+		})
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(querier.User{}, pgx.ErrNoRows).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("%s|%s", UserNotFound, UserNotFound),
+		}, func() {
+			userSvcMock.ResendVerificationEmail(ctx)
+		})
+	})
+
+	t.Run("failed to send mail", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		var verificationTokenHash string
+		expectCreateUserToken(t, mockRepo, constant.UserTokenEmailVerification, 24*time.Hour, &verificationTokenHash)
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 500,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToSendMail),
+		}, func() {
+			userSvcMock.ResendVerificationEmail(ctx)
+		})
+	})
+}
+
+func TestEnrollTwoFactor(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	userSvcMock, mockRepo, _, _, _ := initUserSvc(t, ctrl, config)
+
+	user := querier.User{
+		ID:    userID,
+		Name:  "Giri Putra Adhittana",
+		Email: "test@gmail.com",
+	}
+
+	t.Run("success enroll two-factor", func(t *testing.T) {
+		var secret string
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockRepo.EXPECT().SetUserTotpSecretByID(gomock.Any(), gomock.AssignableToTypeOf(querier.SetUserTotpSecretByIDParams{})).DoAndReturn(func(_ any, params querier.SetUserTotpSecretByIDParams) error {
+			assert.Equal(t, userID, params.ID)
+			assert.True(t, params.TotpSecret.Valid)
+			secret = params.TotpSecret.String
+
+			return nil
+		}).Times(1)
+
+		resp := userSvcMock.EnrollTwoFactor(ctx)
+
+		assert.Equal(t, secret, resp.Secret)
+		assert.Equal(t, totp.URI("Book Store", user.Email, secret), resp.OtpauthURI)
+	})
+
+	t.Run("already enabled", func(t *testing.T) {
+		enabledUser := user
+		enabledUser.TotpEnabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(enabledUser, nil).Times(1)
+		mockRepo.EXPECT().SetUserTotpSecretByID(gomock.Any(), gomock.Any()).Times(0)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("%s|%s", TwoFactorAlreadyEnabled, TwoFactorAlreadyEnabled),
+		}, func() {
+			userSvcMock.EnrollTwoFactor(ctx)
+		})
+	})
+
+	t.Run("failed to store secret", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockRepo.EXPECT().SetUserTotpSecretByID(gomock.Any(), gomock.Any()).Return(errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToEnrollTwoFactor),
+		}, func() {
+			userSvcMock.EnrollTwoFactor(ctx)
+		})
+	})
+}
+
+func TestVerifyTwoFactor(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	userSvcMock, mockRepo, _, _, _ := initUserSvc(t, ctrl, config)
+
+	secret, _ := totp.GenerateSecret()
+	user := querier.User{
+		ID:         userID,
+		Email:      "test@gmail.com",
+		TotpSecret: sql.NullString{String: secret, Valid: true},
+	}
+	step := totp.Step(time.Now())
+	code, _ := totp.Code(secret, step)
+
+	t.Run("success verify two-factor", func(t *testing.T) {
+		var codeHashes []string
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+		mockRepo.EXPECT().EnableUserTotpByID(gomock.Any(), querier.EnableUserTotpByIDParams{
+			ID:           userID,
+			TotpLastStep: step,
+		}).Return(nil).Times(1)
+		mockRepo.EXPECT().DeleteUserRecoveryCodesByUserID(gomock.Any(), userID).Return(nil).Times(1)
+		mockRepo.EXPECT().CreateUserRecoveryCodes(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateUserRecoveryCodesParams{})).DoAndReturn(func(_ any, params querier.CreateUserRecoveryCodesParams) error {
+			assert.Equal(t, userID, params.UserID)
+			codeHashes = params.CodeHashes
+
+			return nil
+		}).Times(1)
+
+		resp := userSvcMock.VerifyTwoFactor(ctx, dto.VerifyTwoFactorReq{Code: code})
+
+		assert.Len(t, resp.RecoveryCodes, 10)
+		for i, recoveryCode := range resp.RecoveryCodes {
+			assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, recoveryCode)
+			assert.Equal(t, codeHashes[i], hashToken(normalizeRecoveryCode(recoveryCode)))
+		}
+	})
+
+	t.Run("not enrolled", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(querier.User{ID: userID}, nil).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("%s|%s", TwoFactorNotEnrolled, TwoFactorNotEnrolled),
+		}, func() {
+			userSvcMock.VerifyTwoFactor(ctx, dto.VerifyTwoFactorReq{Code: code})
+		})
+	})
+
+	t.Run("already enabled", func(t *testing.T) {
+		enabledUser := user
+		enabledUser.TotpEnabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(enabledUser, nil).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("%s|%s", TwoFactorAlreadyEnabled, TwoFactorAlreadyEnabled),
+		}, func() {
+			userSvcMock.VerifyTwoFactor(ctx, dto.VerifyTwoFactorReq{Code: code})
+		})
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockRepo.EXPECT().EnableUserTotpByID(gomock.Any(), gomock.Any()).Times(0)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("%s|%s", InvalidTwoFactorCode, InvalidTwoFactorCode),
+		}, func() {
+			userSvcMock.VerifyTwoFactor(ctx, dto.VerifyTwoFactorReq{Code: "abcdef"})
+		})
+	})
+
+	t.Run("failed to save recovery codes", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+		mockRepo.EXPECT().EnableUserTotpByID(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockRepo.EXPECT().DeleteUserRecoveryCodesByUserID(gomock.Any(), userID).Return(nil).Times(1)
+		mockRepo.EXPECT().CreateUserRecoveryCodes(gomock.Any(), gomock.Any()).Return(errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToSaveRecoveryCodes),
+		}, func() {
+			userSvcMock.VerifyTwoFactor(ctx, dto.VerifyTwoFactorReq{Code: code})
+		})
+	})
+}
+
+func TestSignInTwoFactor(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	userSvcMock, mockRepo, mockToken, _, redisServer := initUserSvc(t, ctrl, config)
+
+	secret, _ := totp.GenerateSecret()
+	password := "123"
+	hashPassword, _ := utils.HashPassword(password)
+	user := querier.User{
+		ID:            userID,
+		Name:          "Giri Putra Adhittana",
+		Email:         "test@gmail.com",
+		Password:      hashPassword,
+		Role:          constant.RoleCustomer,
+		TotpSecret:    sql.NullString{String: secret, Valid: true},
+		TotpEnabledAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+	step := totp.Step(time.Now())
+	code, _ := totp.Code(secret, step)
+	token := "dmytoken"
+	expToken := int64(1000)
+	expRefreshToken := time.Now().Add(720 * time.Hour).Truncate(time.Second)
+
+	expectSignedIn := func() {
+		mockToken.EXPECT().GenerateToken(utils.GenerateTokenReq{
+			UserID: userID.String(),
+		}).Return(utils.GenerateTokenResp{
+			Token:    token,
+			ExpToken: expToken,
+		}, nil).Times(1)
+
+		var refreshTokenHash string
+		expectCreateUserSession(t, mockRepo, expRefreshToken, &refreshTokenHash)
+	}
+
+	t.Run("success with authenticator code", func(t *testing.T) {
+		challengeToken := createTwoFactorChallenge(t, redisServer)
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockRepo.EXPECT().UpdateUserTotpLastStepByID(gomock.Any(), querier.UpdateUserTotpLastStepByIDParams{
+			ID:           userID,
+			TotpLastStep: step,
+		}).Return(int64(1), nil).Times(1)
+		expectSignedIn()
+
+		resp := userSvcMock.SignInTwoFactor(ctx, dto.SignInTwoFactorReq{
+			ChallengeToken: challengeToken,
+			Code:           code,
+		})
+
+		assert.Equal(t, dto.SignInRes{
+			ID:              userID.String(),
+			Name:            user.Name,
+			Role:            user.Role,
+			Token:           token,
+			ExpToken:        expToken,
+			RefreshToken:    resp.RefreshToken,
+			ExpRefreshToken: expRefreshToken.Unix(),
+		}, resp)
+		assert.False(t, isTwoFactorChallengeStored(redisServer, challengeToken))
+	})
+
+	t.Run("success with recovery code", func(t *testing.T) {
+		challengeToken := createTwoFactorChallenge(t, redisServer)
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockRepo.EXPECT().UseUserRecoveryCode(gomock.Any(), querier.UseUserRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: hashToken("abcdefghij"),
+		}).Return(int64(1), nil).Times(1)
+		expectSignedIn()
+
+		resp := userSvcMock.SignInTwoFactor(ctx, dto.SignInTwoFactorReq{
+			ChallengeToken: challengeToken,
+			Code:           " ABCDE-FGHIJ ",
+		})
+
+		assert.Equal(t, token, resp.Token)
+		assert.False(t, isTwoFactorChallengeStored(redisServer, challengeToken))
+	})
+
+	t.Run("replayed authenticator code", func(t *testing.T) {
+		challengeToken := createTwoFactorChallenge(t, redisServer)
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockRepo.EXPECT().UpdateUserTotpLastStepByID(gomock.Any(), gomock.Any()).Return(int64(0), nil).Times(1)
+		mockToken.EXPECT().GenerateToken(gomock.Any()).Times(0)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 401,
+			Message:    fmt.Sprintf("%s|%s", InvalidTwoFactorCode, InvalidTwoFactorCode),
+		}, func() {
+			userSvcMock.SignInTwoFactor(ctx, dto.SignInTwoFactorReq{
+				ChallengeToken: challengeToken,
+				Code:           code,
+			})
+		})
+		assert.True(t, isTwoFactorChallengeStored(redisServer, challengeToken))
+	})
+
+	t.Run("too many wrong codes drop the challenge", func(t *testing.T) {
+		redisServer.FlushAll()
+		challengeToken := createTwoFactorChallenge(t, redisServer)
+		req := dto.SignInTwoFactorReq{
+			ChallengeToken: challengeToken,
+			Code:           "wrong",
+		}
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(5)
+		mockRepo.EXPECT().UseUserRecoveryCode(gomock.Any(), gomock.Any()).Return(int64(0), nil).Times(5)
+
+		for i := 0; i < 5; i++ {
+			assert.PanicsWithValue(t, utils.AppError{
+				StatusCode: 401,
+				Message:    fmt.Sprintf("%s|%s", InvalidTwoFactorCode, InvalidTwoFactorCode),
+			}, func() {
+				userSvcMock.SignInTwoFactor(ctx, req)
+			})
+		}
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 401,
+			Message:    fmt.Sprintf("%s|%s", InvalidChallengeToken, InvalidChallengeToken),
+		}, func() {
+			userSvcMock.SignInTwoFactor(ctx, req)
+		})
+		redisServer.FlushAll()
+	})
+
+	t.Run("wrong codes across new challenges lock the sign-in out", func(t *testing.T) {
+		redisServer.FlushAll()
+		ip := "10.0.0.1"
+		signInReq := dto.SignInReq{
+			IP:       ip,
+			Email:    user.Email,
+			Password: password,
+		}
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), user.Email).Return(user, nil).Times(5)
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(5)
+		mockRepo.EXPECT().UseUserRecoveryCode(gomock.Any(), gomock.Any()).Return(int64(0), nil).Times(5)
+		mockToken.EXPECT().GenerateToken(gomock.Any()).Times(0)
+
+		// the password is right every time, so each round gets a fresh
+		// challenge with its own attempts left
+		for i := 0; i < 5; i++ {
+			resp := userSvcMock.SignIn(ctx, signInReq)
+			assert.True(t, resp.TwoFactorRequired)
+
+			assert.PanicsWithValue(t, utils.AppError{
+				StatusCode: 401,
+				Message:    fmt.Sprintf("%s|%s", InvalidTwoFactorCode, InvalidTwoFactorCode),
+			}, func() {
+				userSvcMock.SignInTwoFactor(ctx, dto.SignInTwoFactorReq{
+					IP:             ip,
+					ChallengeToken: resp.ChallengeToken,
+					Code:           "wrong",
+				})
+			})
+		}
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 429,
+			Message:    fmt.Sprintf("%s|%s", TooManySignInAttempts, TooManySignInAttempts),
+		}, func() {
+			userSvcMock.SignIn(ctx, signInReq)
+		})
+		assert.True(t, redisServer.Exists(loginAttemptKey("ip:"+ip)))
+		redisServer.FlushAll()
+	})
+
+	t.Run("locked out challenge", func(t *testing.T) {
+		redisServer.FlushAll()
+		challengeToken := createTwoFactorChallenge(t, redisServer)
+		redisServer.Set(loginLockoutKey(emailSubjectKey(user.Email)), "1")
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockRepo.EXPECT().UpdateUserTotpLastStepByID(gomock.Any(), gomock.Any()).Times(0)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 429,
+			Message:    fmt.Sprintf("%s|%s", TooManySignInAttempts, TooManySignInAttempts),
+		}, func() {
+			userSvcMock.SignInTwoFactor(ctx, dto.SignInTwoFactorReq{
+				ChallengeToken: challengeToken,
+				Code:           code,
+			})
+		})
+		redisServer.FlushAll()
+	})
+
+	t.Run("unknown challenge token", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), gomock.Any()).Times(0)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 401,
+			Message:    fmt.Sprintf("%s|%s", InvalidChallengeToken, InvalidChallengeToken),
+		}, func() {
+			userSvcMock.SignInTwoFactor(ctx, dto.SignInTwoFactorReq{
+				ChallengeToken: "unknown",
+				Code:           code,
+			})
+		})
+	})
+
+	t.Run("failed to check code", func(t *testing.T) {
+		challengeToken := createTwoFactorChallenge(t, redisServer)
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockRepo.EXPECT().UseUserRecoveryCode(gomock.Any(), gomock.Any()).Return(int64(0), errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToCheckTwoFactorCode),
+		}, func() {
+			userSvcMock.SignInTwoFactor(ctx, dto.SignInTwoFactorReq{
+				ChallengeToken: challengeToken,
+				Code:           "abcde-fghij",
+			})
+		})
+	})
+}
+
+func TestGetProfile(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	userSvcMock, mockRepo, _, _, _ := initUserSvc(t, ctrl, config)
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	user := querier.User{
+		ID:              userID,
+		Name:            "Giri Putra Adhittana",
+		Email:           "test@gmail.com",
+		Role:            constant.RoleCustomer,
+		CreatedAt:       createdAt,
+		UpdatedAt:       createdAt.Add(time.Hour),
+		EmailVerifiedAt: sql.NullTime{Time: createdAt, Valid: true},
+	}
+
+	t.Run("success get profile", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+
+		resp := userSvcMock.GetProfile(ctx)
+
+		assert.Equal(t, dto.ProfileRes{
+			ID:               userID.String(),
+			Name:             user.Name,
+			Email:            user.Email,
+			Role:             constant.RoleCustomer,
+			EmailVerified:    true,
+			TwoFactorEnabled: false,
+			CreatedAt:        "2024-01-02 03:04:05",
+			UpdatedAt:        "2024-01-02 04:04:05",
+		}, resp)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(querier.User{}, pgx.ErrNoRows).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("%s|%s", UserNotFound, UserNotFound),
+		}, func() {
+			userSvcMock.GetProfile(ctx)
+		})
+	})
+}
+
+func TestUpdateProfile(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	userSvcMock, mockRepo, _, mockMailer, redisServer := initUserSvc(t, ctrl, config)
+
+	password := "123"
+	hashPassword, _ := utils.HashPassword(password)
+	user := querier.User{
+		ID:              userID,
+		Name:            "Giri Putra Adhittana",
+		Email:           "test@gmail.com",
+		Password:        hashPassword,
+		EmailVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+	name := " Giri "
+	newEmail := "New@Gmail.com"
+
+	t.Run("success update name", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		updatedUser := user
+		updatedUser.Name = "Giri"
+		mockRepo.EXPECT().CheckEmailExists(gomock.Any(), gomock.Any()).Times(0)
+		mockRepo.EXPECT().UpdateUserProfileByID(gomock.Any(), querier.UpdateUserProfileByIDParams{
+			ID:    userID,
+			Name:  "Giri",
+			Email: user.Email,
+		}).Return(updatedUser, nil).Times(1)
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+
+		resp := userSvcMock.UpdateProfile(ctx, dto.UpdateProfileReq{
+			Name: &name,
+		})
+
+		assert.Equal(t, "Giri", resp.Name)
+		assert.True(t, resp.EmailVerified)
+	})
+
+	t.Run("same email in another case is not a change", func(t *testing.T) {
+		sameEmail := " TEST@gmail.com"
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		mockRepo.EXPECT().CheckEmailExists(gomock.Any(), gomock.Any()).Times(0)
+		mockRepo.EXPECT().UpdateUserProfileByID(gomock.Any(), querier.UpdateUserProfileByIDParams{
+			ID:    userID,
+			Name:  user.Name,
+			Email: sameEmail,
+		}).Return(user, nil).Times(1)
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+
+		resp := userSvcMock.UpdateProfile(ctx, dto.UpdateProfileReq{
+			Email: &sameEmail,
+		})
+
+		assert.True(t, resp.EmailVerified)
+	})
+
+	t.Run("success update email", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		updatedUser := user
+		updatedUser.Email = "new@gmail.com"
+		updatedUser.EmailVerifiedAt = sql.NullTime{}
+		mockRepo.EXPECT().CheckEmailExists(gomock.Any(), newEmail).Return(false, nil).Times(1)
+		mockRepo.EXPECT().UpdateUserProfileByID(gomock.Any(), querier.UpdateUserProfileByIDParams{
+			ID:    userID,
+			Name:  user.Name,
+			Email: newEmail,
+		}).Return(updatedUser, nil).Times(1)
+
+		var verificationTokenHash string
+		expectCreateUserToken(t, mockRepo, constant.UserTokenEmailVerification, 24*time.Hour, &verificationTokenHash)
+
+		var messages []mailer.Message
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.AssignableToTypeOf(mailer.Message{})).DoAndReturn(func(_ any, msg mailer.Message) error {
+			messages = append(messages, msg)
+
+			return nil
+		}).Times(2)
+
+		resp := userSvcMock.UpdateProfile(ctx, dto.UpdateProfileReq{
+			Email:           &newEmail,
+			CurrentPassword: password,
+		})
+
+		assert.Equal(t, updatedUser.Email, resp.Email)
+		assert.False(t, resp.EmailVerified)
+		if assert.Len(t, messages, 2) {
+			assert.Equal(t, user.Email, messages[0].To)
+			assert.Contains(t, messages[0].Body, updatedUser.Email)
+			assert.Equal(t, updatedUser.Email, messages[1].To)
+			assert.Equal(t, verificationTokenHash, hashToken(mailedToken(messages[1])))
+		}
+	})
+
+	t.Run("email change without current password", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockRepo.EXPECT().UpdateUserProfileByID(gomock.Any(), gomock.Any()).Times(0)
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("%s|%s", CurrentPasswordRequired, CurrentPasswordRequired),
+		}, func() {
+			userSvcMock.UpdateProfile(ctx, dto.UpdateProfileReq{
+				Email: &newEmail,
+			})
+		})
+	})
+
+	t.Run("email change with wrong current password", func(t *testing.T) {
+		redisServer.FlushAll()
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockRepo.EXPECT().UpdateUserProfileByID(gomock.Any(), gomock.Any()).Times(0)
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("%s|%s", IncorrectCurrentPassword, IncorrectCurrentPassword),
+		}, func() {
+			userSvcMock.UpdateProfile(ctx, dto.UpdateProfileReq{
+				Email:           &newEmail,
+				CurrentPassword: "wrong",
+			})
+		})
+		assert.True(t, redisServer.Exists(loginAttemptKey(emailSubjectKey(user.Email))))
+		redisServer.FlushAll()
+	})
+
+	t.Run("mail failure does not fail the update", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		mockRepo.EXPECT().CheckEmailExists(gomock.Any(), newEmail).Return(false, nil).Times(1)
+		mockRepo.EXPECT().UpdateUserProfileByID(gomock.Any(), gomock.Any()).Return(user, nil).Times(1)
+
+		var verificationTokenHash string
+		expectCreateUserToken(t, mockRepo, constant.UserTokenEmailVerification, 24*time.Hour, &verificationTokenHash)
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errInvalidReq).Times(2)
+
+		assert.NotPanics(t, func() {
+			userSvcMock.UpdateProfile(ctx, dto.UpdateProfileReq{
+				Email:           &newEmail,
+				CurrentPassword: password,
+			})
+		})
+	})
+
+	t.Run("email already exist", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().CheckEmailExists(gomock.Any(), newEmail).Return(true, nil).Times(1)
+		mockRepo.EXPECT().UpdateUserProfileByID(gomock.Any(), gomock.Any()).Times(0)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("%s|%s", EmailAlreadyExist, EmailAlreadyExist),
+		}, func() {
+			userSvcMock.UpdateProfile(ctx, dto.UpdateProfileReq{
+				Email:           &newEmail,
+				CurrentPassword: password,
+			})
+		})
+	})
+
+	t.Run("failed to update profile", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().UpdateUserProfileByID(gomock.Any(), gomock.Any()).Return(querier.User{}, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToUpdateProfile),
+		}, func() {
+			userSvcMock.UpdateProfile(ctx, dto.UpdateProfileReq{
+				Name: &name,
+			})
+		})
+	})
+}
+
+func TestChangePassword(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	userSvcMock, mockRepo, _, _, redisServer := initUserSvc(t, ctrl, config)
+
+	pwd, err := utils.HashPassword("OldPassword1")
+	assert.NoError(t, err)
+
+	user := querier.User{
+		ID:       userID,
+		Name:     "Giri Putra Adhittana",
+		Email:    "test@gmail.com",
+		Password: pwd,
+	}
+	req := dto.ChangePasswordReq{
+		OldPassword: "OldPassword1",
+		NewPassword: "NewPassword1",
+	}
+	wrongReq := dto.ChangePasswordReq{
+		OldPassword: "WrongPassword1",
+		NewPassword: "NewPassword1",
+	}
+
+	t.Run("success change password", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		mockRepo.EXPECT().UpdateUserPasswordByID(gomock.Any(), gomock.AssignableToTypeOf(querier.UpdateUserPasswordByIDParams{})).DoAndReturn(func(_ any, params querier.UpdateUserPasswordByIDParams) error {
+			assert.Equal(t, userID, params.ID)
+			assert.True(t, utils.IsCorrectPassword(req.NewPassword, params.Password))
+
+			return nil
+		}).Times(1)
+		mockRepo.EXPECT().RevokeUserSessionsByUserID(gomock.Any(), userID).Return(nil).Times(1)
+		_, err := redisServer.SetAdd(userAccessTokensKey(userID.String()), hashToken("current-token"))
+		assert.NoError(t, err)
+
+		assert.NotPanics(t, func() {
+			userSvcMock.ChangePassword(ctx, req)
+		})
+		assert.True(t, isAccessTokenRevoked(redisServer, "current-token"))
+	})
+
+	t.Run("incorrect old password", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockRepo.EXPECT().UpdateUserPasswordByID(gomock.Any(), gomock.Any()).Times(0)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("%s|%s", IncorrectPassword, IncorrectPassword),
+		}, func() {
+			userSvcMock.ChangePassword(ctx, wrongReq)
+		})
+	})
+
+	t.Run("locked out after too many incorrect passwords", func(t *testing.T) {
+		for i := 0; i < 4; i++ {
+			mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+
+			assert.Panics(t, func() {
+				userSvcMock.ChangePassword(ctx, wrongReq)
+			})
+		}
+
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockRepo.EXPECT().UpdateUserPasswordByID(gomock.Any(), gomock.Any()).Times(0)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 429,
+			Message:    fmt.Sprintf("%s|%s", TooManySignInAttempts, TooManySignInAttempts),
+		}, func() {
+			userSvcMock.ChangePassword(ctx, req)
+		})
+
+		redisServer.FlushAll()
+	})
+
+	t.Run("failed to update password", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().UpdateUserPasswordByID(gomock.Any(), gomock.Any()).Return(errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToUpdatePassword),
+		}, func() {
+			userSvcMock.ChangePassword(ctx, req)
+		})
+	})
+
+	t.Run("failed to check sign-in attempts", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+
+		withRedisDown(t, redisServer, func() {
+			assert.Panics(t, func() {
+				userSvcMock.ChangePassword(ctx, req)
+			})
+		})
+	})
+}
+
+func TestExportData(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	userSvcMock, mockRepo, _, _, _ := initUserSvc(t, ctrl, config)
+	date := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	user := querier.User{
+		ID:    userID,
+		Name:  "Giri Putra Adhittana",
+		Email: "test@gmail.com",
+		Role:  constant.RoleCustomer,
+	}
+	orders := []querier.Order{
+		{
+			ID:         uuid.New(),
+			UserID:     userID,
+			Date:       date,
+			TotalPrice: decimal.NewFromInt(24),
+			Status:     constant.OrderStatusDelivered,
+		},
+		{
+			ID:         uuid.New(),
+			UserID:     userID,
+			Date:       date.Add(-time.Hour),
+			TotalPrice: decimal.NewFromInt(0),
+			Status:     constant.OrderStatusCancelled,
+		},
+	}
+	items := []querier.FindOrderItemsByUserIDRow{
+		{
+			ID:          uuid.New(),
+			OrderID:     orders[0].ID,
+			BookID:      uuid.New(),
+			Title:       "Hello",
+			Description: "Hello World",
+			Author:      "Giri Putra Adhittana",
+			Quantity:    2,
+			UnitPrice:   decimal.NewFromInt(12),
+			LineTotal:   decimal.NewFromInt(24),
+		},
+	}
+
+	t.Run("success export data", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockRepo.EXPECT().FindAllOrderByUserID(gomock.Any(), userID).Return(orders, nil).Times(1)
+		mockRepo.EXPECT().FindOrderItemsByUserID(gomock.Any(), userID).Return(items, nil).Times(1)
+
+		resp := userSvcMock.ExportData(ctx)
+
+		assert.Equal(t, user.Email, resp.Profile.Email)
+		assert.NotEmpty(t, resp.ExportedAt)
+		assert.Equal(t, []dto.GetOrderDetailRes{
+			{
+				OrderId:    orders[0].ID.String(),
+				Date:       "2024-01-02 03:04:05",
+				TotalPrice: decimal.NewFromInt(24),
+				Status:     constant.OrderStatusDelivered,
+				OrderDetail: []dto.OrderDetail{
+					{
+						OrderDetailID: items[0].ID.String(),
+						BookID:        items[0].BookID.String(),
+						Title:         "Hello",
+						Description:   "Hello World",
+						Author:        "Giri Putra Adhittana",
+						Quantity:      2,
+						UnitPrice:     decimal.NewFromInt(12),
+						LineTotal:     decimal.NewFromInt(24),
+					},
+				},
+			},
+			{
+				OrderId:     orders[1].ID.String(),
+				Date:        "2024-01-02 02:04:05",
+				TotalPrice:  decimal.NewFromInt(0),
+				Status:      constant.OrderStatusCancelled,
+				OrderDetail: []dto.OrderDetail{},
+			},
+		}, resp.Orders)
+	})
+
+	t.Run("user without orders", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockRepo.EXPECT().FindAllOrderByUserID(gomock.Any(), userID).Return([]querier.Order{}, nil).Times(1)
+		mockRepo.EXPECT().FindOrderItemsByUserID(gomock.Any(), userID).Return([]querier.FindOrderItemsByUserIDRow{}, nil).Times(1)
+
+		resp := userSvcMock.ExportData(ctx)
+
+		assert.NotNil(t, resp.Orders)
+		assert.Empty(t, resp.Orders)
+	})
+
+	t.Run("failed to find orders", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockRepo.EXPECT().FindAllOrderByUserID(gomock.Any(), userID).Return(nil, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToGetOrder),
+		}, func() {
+			userSvcMock.ExportData(ctx)
+		})
+	})
+
+	t.Run("failed to find order items", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockRepo.EXPECT().FindAllOrderByUserID(gomock.Any(), userID).Return(orders, nil).Times(1)
+		mockRepo.EXPECT().FindOrderItemsByUserID(gomock.Any(), userID).Return(nil, errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 400,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToFindOrderItems),
+		}, func() {
+			userSvcMock.ExportData(ctx)
+		})
+	})
+}
+
+func TestDeleteAccount(t *testing.T) {
+	ctx := utils.SetRequestContext(userID.String())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := &utils.BaseConfig{}
+	utils.LoadBaseConfig("../config", "test", config)
+	userSvcMock, mockRepo, _, _, redisServer := initUserSvc(t, ctrl, config)
+	cacheSvc := utils.NewCacheSvc(config, redis.NewClient(&redis.Options{
+		Addr: redisServer.Addr(),
+	}))
+	orderCacheKey := utils.BuildCacheKey(constant.OrderCacheKey, userID.String(), "GetOrder", dto.GetOrderReq{})
+
+	user := querier.User{
+		ID:    userID,
+		Name:  "Giri Putra Adhittana",
+		Email: "test@gmail.com",
+	}
+	req := dto.DeleteAccountReq{
+		AccessToken: "current-token",
+	}
+
+	t.Run("success delete account", func(t *testing.T) {
+		_, err := utils.GetOrSetData(cacheSvc, orderCacheKey, func() (string, error) {
+			return "cached", nil
+		})
+		assert.NoError(t, err)
+		_, err = redisServer.SetAdd(userAccessTokensKey(userID.String()), hashToken("other-token"))
+		assert.NoError(t, err)
+
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+		mockRepo.EXPECT().AnonymizeUserByID(gomock.Any(), userID).Return(nil).Times(1)
+		mockRepo.EXPECT().DeleteUserRecoveryCodesByUserID(gomock.Any(), userID).Return(nil).Times(1)
+		mockRepo.EXPECT().InvalidateUserTokens(gomock.Any(), querier.InvalidateUserTokensParams{
+			UserID:  userID,
+			Purpose: constant.UserTokenPasswordReset,
+		}).Return(nil).Times(1)
+		mockRepo.EXPECT().InvalidateUserTokens(gomock.Any(), querier.InvalidateUserTokensParams{
+			UserID:  userID,
+			Purpose: constant.UserTokenEmailVerification,
+		}).Return(nil).Times(1)
+		mockRepo.EXPECT().DeleteCartByUserID(gomock.Any(), userID).Return(nil).Times(1)
+		mockRepo.EXPECT().RevokeUserSessionsByUserID(gomock.Any(), userID).Return(nil).Times(1)
+
+		assert.NotPanics(t, func() {
+			userSvcMock.DeleteAccount(ctx, req)
+		})
+		assert.True(t, isAccessTokenRevoked(redisServer, "other-token"))
+		assert.True(t, isAccessTokenRevoked(redisServer, req.AccessToken))
+
+		resp, err := utils.GetOrSetData(cacheSvc, orderCacheKey, func() (string, error) {
+			return "fresh", nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "fresh", resp)
+	})
+
+	t.Run("failed to anonymize user", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+		mockRepo.EXPECT().AnonymizeUserByID(gomock.Any(), userID).Return(errInvalidReq).Times(1)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToDeleteAccount),
+		}, func() {
+			userSvcMock.DeleteAccount(ctx, req)
+		})
+	})
+
+	t.Run("failed to invalidate user tokens", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+		mockRepo.EXPECT().AnonymizeUserByID(gomock.Any(), userID).Return(nil).Times(1)
+		mockRepo.EXPECT().DeleteUserRecoveryCodesByUserID(gomock.Any(), userID).Return(nil).Times(1)
+		mockRepo.EXPECT().InvalidateUserTokens(gomock.Any(), gomock.Any()).Return(errInvalidReq).Times(1)
+		mockRepo.EXPECT().DeleteCartByUserID(gomock.Any(), gomock.Any()).Times(0)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToInvalidateTokens),
+		}, func() {
+			userSvcMock.DeleteAccount(ctx, req)
+		})
+	})
+
+	t.Run("failed to delete cart", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+		mockRepo.EXPECT().AnonymizeUserByID(gomock.Any(), userID).Return(nil).Times(1)
+		mockRepo.EXPECT().DeleteUserRecoveryCodesByUserID(gomock.Any(), userID).Return(nil).Times(1)
+		mockRepo.EXPECT().InvalidateUserTokens(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		mockRepo.EXPECT().DeleteCartByUserID(gomock.Any(), userID).Return(errInvalidReq).Times(1)
+		mockRepo.EXPECT().RevokeUserSessionsByUserID(gomock.Any(), gomock.Any()).Times(0)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 422,
+			Message:    fmt.Sprintf("invalid request|%s", FailedToDeleteAccount),
+		}, func() {
+			userSvcMock.DeleteAccount(ctx, req)
+		})
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(querier.User{}, pgx.ErrNoRows).Times(1)
+		mockRepo.EXPECT().AnonymizeUserByID(gomock.Any(), gomock.Any()).Times(0)
+
+		assert.PanicsWithValue(t, utils.AppError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("%s|%s", UserNotFound, UserNotFound),
+		}, func() {
+			userSvcMock.DeleteAccount(ctx, req)
+		})
+	})
+}
+
+func TestBootstrapAdmin(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mockrepo.NewMockRepository(ctrl)
+	appCfg := &appConfig.AppConfig{}
+	appConfig.LoadAppConfig("../config", "test", appCfg)
+
+	t.Run("admin not configured", func(t *testing.T) {
+		err := BootstrapAdmin(ctx, mockRepo, &appConfig.AppConfig{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("success create admin", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), appCfg.AdminEmail).Return(querier.User{}, pgx.ErrNoRows).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo)
+
+		mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.AssignableToTypeOf(querier.CreateUserParams{})).DoAndReturn(func(_ any, params querier.CreateUserParams) (querier.User, error) {
+			assert.Equal(t, appCfg.AdminName, params.Name)
+			assert.Equal(t, appCfg.AdminEmail, params.Email)
+			assert.Equal(t, constant.RoleAdmin, params.Role)
+			assert.True(t, utils.IsCorrectPassword(appCfg.AdminPassword, params.Password))
+
+			return querier.User{
+				ID:    userID,
+				Email: params.Email,
+				Role:  params.Role,
+			}, nil
+		}).Times(1)
+
+		mockRepo.EXPECT().VerifyUserEmailByID(gomock.Any(), userID).Return(nil).Times(1)
+
+		err := BootstrapAdmin(ctx, mockRepo, appCfg)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed verify admin email", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), appCfg.AdminEmail).Return(querier.User{}, pgx.ErrNoRows).Times(1)
+		mockrepo.SetupMockTxPool(ctrl, mockRepo, true)
+
+		mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(querier.User{ID: userID}, nil).Times(1)
+		mockRepo.EXPECT().VerifyUserEmailByID(gomock.Any(), userID).Return(errInvalidReq).Times(1)
+
+		err := BootstrapAdmin(ctx, mockRepo, appCfg)
+		assert.ErrorIs(t, err, errInvalidReq)
+	})
+
+	t.Run("success promote existing user", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), appCfg.AdminEmail).Return(querier.User{
+			ID:    userID,
+			Email: appCfg.AdminEmail,
+			Role:  constant.RoleCustomer,
+		}, nil).Times(1)
+
+		mockRepo.EXPECT().UpdateUserRoleByID(gomock.Any(), querier.UpdateUserRoleByIDParams{
+			ID:   userID,
+			Role: constant.RoleAdmin,
+		}).Return(nil).Times(1)
+
+		err := BootstrapAdmin(ctx, mockRepo, appCfg)
+		assert.NoError(t, err)
+	})
+
+	t.Run("admin already exists", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), appCfg.AdminEmail).Return(querier.User{
+			ID:    userID,
+			Email: appCfg.AdminEmail,
+			Role:  constant.RoleAdmin,
+		}, nil).Times(1)
+
+		err := BootstrapAdmin(ctx, mockRepo, appCfg)
+		assert.NoError(t, err)
+	})
+
+	t.Run("admin password not configured", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), appCfg.AdminEmail).Return(querier.User{}, pgx.ErrNoRows).Times(1)
+
+		err := BootstrapAdmin(ctx, mockRepo, &appConfig.AppConfig{
+			AdminEmail: appCfg.AdminEmail,
+		})
+		assert.EqualError(t, err, AdminPasswordRequired)
+	})
+
+	t.Run("failed find user", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), appCfg.AdminEmail).Return(querier.User{}, errInvalidReq).Times(1)
+
+		err := BootstrapAdmin(ctx, mockRepo, appCfg)
+		assert.ErrorIs(t, err, errInvalidReq)
+	})
+
+	t.Run("failed update user role", func(t *testing.T) {
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), appCfg.AdminEmail).Return(querier.User{
+			ID:   userID,
+			Role: constant.RoleStaff,
+		}, nil).Times(1)
+
+		mockRepo.EXPECT().UpdateUserRoleByID(gomock.Any(), gomock.Any()).Return(errInvalidReq).Times(1)
+
+		err := BootstrapAdmin(ctx, mockRepo, appCfg)
+		assert.ErrorIs(t, err, errInvalidReq)
+	})
 }
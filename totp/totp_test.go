@@ -0,0 +1,99 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the SHA1 seed of the RFC 6238 test vectors.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// the RFC lists 8 digit codes; a 6 digit code is their last 6 digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			code, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, code)
+		})
+	}
+
+	t.Run("invalid secret", func(t *testing.T) {
+		_, err := Code("not base32!", 1)
+		assert.Error(t, err)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := Step(now)
+
+	t.Run("current step", func(t *testing.T) {
+		code, _ := Code(rfcSecret, step)
+
+		matched, ok := Validate(rfcSecret, code, now)
+		assert.True(t, ok)
+		assert.Equal(t, step, matched)
+	})
+
+	t.Run("adjacent steps are accepted", func(t *testing.T) {
+		previous, _ := Code(rfcSecret, step-1)
+		next, _ := Code(rfcSecret, step+1)
+
+		matched, ok := Validate(rfcSecret, previous, now)
+		assert.True(t, ok)
+		assert.Equal(t, step-1, matched)
+
+		matched, ok = Validate(rfcSecret, next, now)
+		assert.True(t, ok)
+		assert.Equal(t, step+1, matched)
+	})
+
+	t.Run("older steps are rejected", func(t *testing.T) {
+		code, _ := Code(rfcSecret, step-2)
+
+		_, ok := Validate(rfcSecret, code, now)
+		assert.False(t, ok)
+	})
+
+	t.Run("malformed code", func(t *testing.T) {
+		_, ok := Validate(rfcSecret, "12345", now)
+		assert.False(t, ok)
+	})
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	other, err := GenerateSecret()
+	assert.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(URI("Book Store", "test@gmail.com", "SECRET"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Book Store:test@gmail.com", uri.Path)
+	assert.Equal(t, "SECRET", uri.Query().Get("secret"))
+	assert.Equal(t, "Book Store", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+}
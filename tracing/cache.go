@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/gadhittana01/go-modules/utils"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// cacheSvc traces reads and writes of a CacheSvc. CacheSvc methods take no
+// context, so the wrapper carries the one of the request it serves.
+type cacheSvc struct {
+	utils.CacheSvc
+	ctx context.Context
+}
+
+// WithCacheSvc returns cache whose Get and Set are traced as children of the
+// span in ctx. It is meant to live for a single lookup.
+func WithCacheSvc(ctx context.Context, cache utils.CacheSvc) utils.CacheSvc {
+	return &cacheSvc{
+		CacheSvc: cache,
+		ctx:      ctx,
+	}
+}
+
+func (c *cacheSvc) Get(key string) (string, bool) {
+	_, span := Tracer().Start(c.ctx, "cache.get")
+	defer span.End()
+
+	value, ok := c.CacheSvc.Get(key)
+	span.SetAttributes(
+		attribute.String("cache.key", key),
+		attribute.Bool("cache.hit", ok),
+	)
+
+	return value, ok
+}
+
+func (c *cacheSvc) Set(key string, value string) {
+	_, span := Tracer().Start(c.ctx, "cache.set")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("cache.key", key))
+	c.CacheSvc.Set(key, value)
+}
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis"
+	"github.com/gadhittana-01/book-go/config"
+	"github.com/gadhittana01/go-modules/utils"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetup(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("none exporter", func(t *testing.T) {
+		shutdown, err := Setup(ctx, &config.AppConfig{TracingExporter: ExporterNone})
+		assert.NoError(t, err)
+		assert.NoError(t, shutdown(ctx))
+	})
+
+	t.Run("stdout exporter", func(t *testing.T) {
+		shutdown, err := Setup(ctx, &config.AppConfig{
+			TracingExporter:    ExporterStdout,
+			TracingServiceName: "book-service",
+			TracingSampleRatio: 1,
+		})
+		assert.NoError(t, err)
+		assert.IsType(t, &sdktrace.TracerProvider{}, otel.GetTracerProvider())
+		assert.NoError(t, shutdown(ctx))
+	})
+
+	t.Run("otlp exporter", func(t *testing.T) {
+		shutdown, err := Setup(ctx, &config.AppConfig{
+			TracingExporter:    ExporterOTLP,
+			TracingServiceName: "book-service",
+			TracingSampleRatio: 1,
+			OTLPEndpoint:       "localhost:4318",
+			OTLPInsecure:       true,
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, shutdown(ctx))
+	})
+
+	t.Run("unknown exporter", func(t *testing.T) {
+		_, err := Setup(ctx, &config.AppConfig{TracingExporter: "zipkin"})
+		assert.Error(t, err)
+	})
+}
+
+func TestWithCacheSvc(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer redisServer.Close()
+
+	cache := utils.NewCacheSvc(&utils.BaseConfig{}, redis.NewClient(&redis.Options{
+		Addr: redisServer.Addr(),
+	}))
+
+	ctx, parent := Tracer().Start(context.Background(), "GET /v1/book")
+	tracedCache := WithCacheSvc(ctx, cache)
+
+	_, ok := tracedCache.Get("book:key")
+	assert.False(t, ok)
+	tracedCache.Set("book:key", "value")
+	parent.End()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+	assert.Equal(t, "cache.get", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.Bool("cache.hit", false))
+	assert.Equal(t, "cache.set", spans[1].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[1].Parent().SpanID())
+}
@@ -8,8 +8,12 @@ package main
 
 import (
 	"github.com/gadhittana-01/book-go/app"
+	"github.com/gadhittana-01/book-go/cache"
+	"github.com/gadhittana-01/book-go/config"
 	"github.com/gadhittana-01/book-go/db/repository"
 	"github.com/gadhittana-01/book-go/handler"
+	"github.com/gadhittana-01/book-go/mailer"
+	"github.com/gadhittana-01/book-go/middleware"
 	"github.com/gadhittana-01/book-go/service"
 	"github.com/gadhittana01/go-modules/utils"
 	"github.com/go-chi/chi"
@@ -19,30 +23,49 @@ import (
 
 // Injectors from injector.go:
 
-func InitializeApp(route *chi.Mux, DB utils.PGXPool, config *utils.BaseConfig) (app.App, error) {
+func InitializeApp(route *chi.Mux, DB utils.PGXPool, config2 *utils.BaseConfig, appCfg *config.AppConfig) (app.App, error) {
 	repository := querier.NewRepository(DB)
-	tokenClient := utils.NewToken(config)
-	userSvc := service.NewUserSvc(repository, config, tokenClient)
-	userHandler := handler.NewUserHandler(userSvc)
-	client := utils.NewRedisClient(config)
-	cacheSvc := utils.NewCacheSvc(config, client)
-	orderSvc := service.NewOrderSvc(repository, config, cacheSvc)
-	authMiddleware := utils.NewAuthMiddleware(config, tokenClient)
-	orderHandler := handler.NewOrderHandler(orderSvc, authMiddleware)
-	bookSvc := service.NewBookSvc(repository, config, cacheSvc)
-	bookHandler := handler.NewBookHandler(bookSvc, authMiddleware)
-	appApp := app.NewApp(route, config, userHandler, orderHandler, bookHandler)
+	tokenClient := utils.NewToken(config2)
+	client := utils.NewRedisClient(config2)
+	tokenRevocationSvc := service.NewTokenRevocationSvc(client, config2)
+	loginAttemptSvc := service.NewLoginAttemptSvc(client, appCfg)
+	twoFactorChallengeSvc := service.NewTwoFactorChallengeSvc(client, appCfg)
+	mailerMailer := mailer.NewMailer(appCfg)
+	cacheSvc := cache.NewResilientCacheSvc(config2, appCfg, client)
+	userSvc := service.NewUserSvc(repository, config2, appCfg, tokenClient, tokenRevocationSvc, loginAttemptSvc, twoFactorChallengeSvc, mailerMailer, cacheSvc)
+	authMiddleware := middleware.NewAuthMiddleware(config2, tokenClient, tokenRevocationSvc)
+	rateLimitSvc := service.NewRateLimitSvc(client)
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(rateLimitSvc, appCfg)
+	userHandler := handler.NewUserHandler(userSvc, authMiddleware, rateLimitMiddleware, appCfg)
+	orderSvc := service.NewOrderSvc(repository, config2, cacheSvc)
+	orderStatusSvc := service.NewOrderStatusSvc(repository, config2, cacheSvc)
+	roleMiddleware := middleware.NewRoleMiddleware(repository)
+	emailVerificationMiddleware := middleware.NewEmailVerificationMiddleware(repository)
+	orderHandler := handler.NewOrderHandler(orderSvc, orderStatusSvc, authMiddleware, roleMiddleware, emailVerificationMiddleware, rateLimitMiddleware)
+	bookSvc := service.NewBookSvc(repository, config2, cacheSvc)
+	bookHandler := handler.NewBookHandler(bookSvc, authMiddleware, roleMiddleware, rateLimitMiddleware)
+	cartSvc := service.NewCartSvc(repository, config2, cacheSvc)
+	cartHandler := handler.NewCartHandler(cartSvc, authMiddleware, emailVerificationMiddleware, rateLimitMiddleware)
+	healthSvc := service.NewHealthSvc(DB, client, config2, appCfg)
+	healthHandler := handler.NewHealthHandler(healthSvc)
+	appApp := app.NewApp(route, config2, appCfg, DB, client, userHandler, orderHandler, bookHandler, cartHandler, healthHandler)
 	return appApp, nil
 }
 
 // injector.go:
 
-var userHandlerSet = wire.NewSet(querier.NewRepository, utils.NewToken, handler.NewUserHandler, service.NewUserSvc)
+var userHandlerSet = wire.NewSet(querier.NewRepository, utils.NewToken, handler.NewUserHandler, service.NewUserSvc, service.NewTokenRevocationSvc, service.NewLoginAttemptSvc, service.NewTwoFactorChallengeSvc, mailer.NewMailer)
 
-var orderHandlerSet = wire.NewSet(handler.NewOrderHandler, service.NewOrderSvc)
+var orderHandlerSet = wire.NewSet(handler.NewOrderHandler, service.NewOrderSvc, service.NewOrderStatusSvc)
 
 var bookHandlerSet = wire.NewSet(handler.NewBookHandler, service.NewBookSvc)
 
-var authMiddlewareSet = wire.NewSet(utils.NewAuthMiddleware)
+var cartHandlerSet = wire.NewSet(handler.NewCartHandler, service.NewCartSvc)
 
-var cacheSet = wire.NewSet(wire.Bind(new(utils.RedisClient), new(*redis.Client)), utils.NewRedisClient, utils.NewCacheSvc)
+var healthHandlerSet = wire.NewSet(handler.NewHealthHandler, service.NewHealthSvc)
+
+var authMiddlewareSet = wire.NewSet(middleware.NewAuthMiddleware, middleware.NewRoleMiddleware, middleware.NewEmailVerificationMiddleware)
+
+var rateLimitMiddlewareSet = wire.NewSet(middleware.NewRateLimitMiddleware, service.NewRateLimitSvc)
+
+var cacheSet = wire.NewSet(wire.Bind(new(utils.RedisClient), new(*redis.Client)), utils.NewRedisClient, cache.NewResilientCacheSvc)